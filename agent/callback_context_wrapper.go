@@ -248,6 +248,10 @@ func (c *callbackContextWrapper) UserContent() *genai.Content {
 	return c.context.UserContent()
 }
 
+func (c *callbackContextWrapper) Principal() *Principal {
+	return c.context.Principal()
+}
+
 // UserID implements [Context].
 func (c *callbackContextWrapper) UserID() string {
 	return c.context.UserID()
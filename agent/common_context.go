@@ -328,6 +328,11 @@ func (c *commonContext) UserContent() *genai.Content {
 	return c.invocationContext.UserContent()
 }
 
+func (c *commonContext) Principal() *Principal {
+	principal, _ := PrincipalFromContext(c)
+	return principal
+}
+
 func (c *commonContext) AppName() string {
 	return c.invocationContext.Session().AppName()
 }
@@ -469,6 +474,9 @@ type trackedArtifacts struct {
 }
 
 func (a *trackedArtifacts) Save(ctx context.Context, name string, data *genai.Part) (*artifact.SaveResponse, error) {
+	if a.Artifacts == nil {
+		return nil, fmt.Errorf("cannot save artifact %q: artifact service is not configured", name)
+	}
 	resp, err := a.Artifacts.Save(ctx, name, data)
 	if err != nil {
 		return resp, err
@@ -186,6 +186,11 @@ func (c *ContextMock) UserContent() *genai.Content {
 	return nil
 }
 
+func (c *ContextMock) Principal() *Principal {
+	principal, _ := PrincipalFromContext(c)
+	return principal
+}
+
 // UserID implements [Context].
 func (c *ContextMock) UserID() string {
 	return ""
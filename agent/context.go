@@ -133,6 +133,9 @@ type ReadonlyContext interface {
 	SessionID() string
 	// Branch of the current invocation.
 	Branch() string
+	// Principal is the authenticated identity the invocation runs for.
+	// It is nil if the request was not authenticated.
+	Principal() *Principal
 }
 
 // Context is the unified context passed to user callbacks during agent
@@ -107,6 +107,9 @@ func (m *StrictContextMock) WithContext(ctx context.Context) InvocationContext {
 // UserContent implements [Context].
 func (m *StrictContextMock) UserContent() *genai.Content { panic("not implemented") }
 
+// Principal implements [Context].
+func (m *StrictContextMock) Principal() *Principal { panic("not implemented") }
+
 // InvocationID implements [Context].
 func (m *StrictContextMock) InvocationID() string { panic("not implemented") }
 
@@ -71,6 +71,8 @@ func New(cfg Config) (agent.Agent, error) {
 		beforeToolCallbacks:   beforeToolCallbacks,
 		afterToolCallbacks:    afterToolCallbacks,
 		onToolErrorCallbacks:  onToolErrorCallback,
+		toolExecution:         cfg.ToolExecution,
+		toolLimits:            &llminternal.ToolLimits{},
 		instruction:           cfg.Instruction,
 		inputSchema:           cfg.InputSchema,
 		outputSchema:          cfg.OutputSchema,
@@ -328,6 +330,11 @@ type Config struct {
 
 	OnToolErrorCallbacks []OnToolErrorCallback
 
+	// ToolExecution controls how the function calls of the model responses
+	// are executed: in parallel by default. The tools can also set their
+	// own tool.ExecutionOptions.
+	ToolExecution ToolExecution
+
 	// OutputKey is an optional parameter to specify the key in session state for the agent output.
 	//
 	// Typical uses cases are:
@@ -346,6 +353,10 @@ type Config struct {
 	Mode Mode
 }
 
+// ToolExecution controls how the function calls of a model response are
+// executed. See [Config.ToolExecution].
+type ToolExecution = llminternal.ToolExecution
+
 // Mode is the delegation mode of an LLMAgent. See [Config.Mode] for details.
 type Mode = llminternal.Mode
 
@@ -431,6 +442,10 @@ type llmAgent struct {
 	afterToolCallbacks   []llminternal.AfterToolCallback
 	onToolErrorCallbacks []llminternal.OnToolErrorCallback
 
+	toolExecution llminternal.ToolExecution
+	// toolLimits are shared by the invocations of the agent.
+	toolLimits *llminternal.ToolLimits
+
 	inputSchema  *genai.Schema
 	outputSchema *genai.Schema
 }
@@ -451,6 +466,8 @@ func (a *llmAgent) run(ctx agent.InvocationContext) iter.Seq2[*session.Event, er
 		BeforeToolCallbacks:   a.beforeToolCallbacks,
 		AfterToolCallbacks:    a.afterToolCallbacks,
 		OnToolErrorCallbacks:  a.onToolErrorCallbacks,
+		ToolExecution:         a.toolExecution,
+		ToolLimits:            a.toolLimits,
 	}
 
 	return func(yield func(*session.Event, error) bool) {
@@ -486,6 +503,8 @@ func (a *llmAgent) RunLive(ctx agent.InvocationContext) (agent.LiveSession, iter
 		BeforeToolCallbacks:   a.beforeToolCallbacks,
 		AfterToolCallbacks:    a.afterToolCallbacks,
 		OnToolErrorCallbacks:  a.onToolErrorCallbacks,
+		ToolExecution:         a.toolExecution,
+		ToolLimits:            a.toolLimits,
 	}
 
 	sess, innerIter, err := f.RunLive(ctx)
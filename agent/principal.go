@@ -0,0 +1,47 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import "context"
+
+// Principal is the authenticated identity on whose behalf an invocation runs.
+// It is established by server authentication middleware and can be read by
+// agents, tools and plugins with [ReadonlyContext] Principal.
+type Principal struct {
+	// UserID is the ADK user ID the principal is mapped to. Sessions, memory
+	// and artifacts accessed on behalf of the principal are scoped to it.
+	UserID string
+	// Subject is the identity established by the authenticator, for example
+	// an API key name, a token "sub" claim or a client certificate subject.
+	Subject string
+	// Method is the authentication method, for example "api_key", "jwt" or "mtls".
+	Method string
+	// Claims holds additional verified attributes, such as token claims.
+	Claims map[string]any
+}
+
+type principalKey struct{}
+
+// NewContextWithPrincipal returns a copy of ctx carrying the principal.
+func NewContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal attached to ctx with
+// [NewContextWithPrincipal], if any.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/a2aproject/a2a-go/v2/a2aclient"
 	"github.com/a2aproject/a2a-go/v2/a2aclient/agentcard"
 	"github.com/a2aproject/a2a-go/v2/log"
+	"github.com/google/uuid"
 
 	"google.golang.org/adk/v2/agent"
 	agentinternal "google.golang.org/adk/v2/internal/agent"
@@ -149,6 +150,11 @@ type A2AConfig struct {
 	// The context passed to this callback is the original context, but with Err() removed by context.WithoutCancel.
 	// If no callback is provided the default behavior is to make a cancel RPC request with 5 second timeout.
 	RemoteTaskCleanupCallback A2ARemoteTaskCleanupCallback
+
+	// PushNotifications enables asynchronous consumption of long-running remote tasks when the remote agent
+	// card declares push notification support. See [PushNotificationConfig] for details.
+	// Request callbacks and Converter receive a nil request when an invocation is resumed by a notification.
+	PushNotifications *PushNotificationConfig
 }
 
 // NewA2A creates a remote A2A agent. A2A (Agent-To-Agent) protocol is used for communication with an
@@ -211,6 +217,13 @@ func (a *a2aAgent) run(ctx agent.InvocationContext, cfg A2AConfig) iter.Seq2[*se
 		}
 		defer destroy(ctx, sender)
 
+		if cfg.PushNotifications != nil {
+			if pending, ok := getPushResume(ctx); ok {
+				a.resumeFromPush(ctx, cfg, sender, pending, yield)
+				return
+			}
+		}
+
 		msg, err := newMessage(ctx, cfg)
 		if err != nil {
 			yield(toErrorEvent(ctx, fmt.Errorf("message creation failed: %w", err)), nil)
@@ -218,6 +231,20 @@ func (a *a2aAgent) run(ctx agent.InvocationContext, cfg A2AConfig) iter.Seq2[*se
 		}
 
 		req := &a2a.SendMessageRequest{Message: msg, Config: cfg.MessageSendConfig}
+		pushConfig, pushNonce, err := newPushConfig(ctx, cfg, card)
+		if err != nil {
+			yield(toErrorEvent(ctx, fmt.Errorf("push config creation failed: %w", err)), nil)
+			return
+		}
+		if pushConfig != nil {
+			sendConfig := &a2a.SendMessageConfig{}
+			if cfg.MessageSendConfig != nil {
+				*sendConfig = *cfg.MessageSendConfig
+			}
+			sendConfig.ReturnImmediately = true
+			sendConfig.PushConfig = pushConfig
+			req.Config = sendConfig
+		}
 		processor := newRunProcessor(cfg, req)
 
 		if bcbResp, bcbErr := processor.runBeforeA2ARequestCallbacks(ctx); bcbResp != nil || bcbErr != nil {
@@ -246,7 +273,11 @@ func (a *a2aAgent) run(ctx agent.InvocationContext, cfg A2AConfig) iter.Seq2[*se
 		}
 
 		var lastEvent a2a.Event
+		awaitingPush := false
 		defer func() {
+			if awaitingPush {
+				return
+			}
 			err := lastErr
 			if err == nil && ctx.Err() != nil {
 				err = context.Cause(ctx)
@@ -289,6 +320,18 @@ func (a *a2aAgent) run(ctx agent.InvocationContext, cfg A2AConfig) iter.Seq2[*se
 			return true
 		}
 
+		if pushConfig != nil {
+			a2aEvent, a2aErr := sender.SendMessage(ctx, req)
+			if !processEvent(a2aEvent, a2aErr) || a2aErr != nil {
+				return
+			}
+			if isAwaitingTaskEvent(a2aEvent) {
+				awaitingPush = true
+				yield(newAwaitTaskEvent(ctx, a2aEvent.TaskInfo(), pushNonce), nil)
+			}
+			return
+		}
+
 		if ctx.RunConfig().StreamingMode == agent.StreamingModeNone {
 			a2aEvent, a2aErr := sender.SendMessage(ctx, req)
 			processEvent(a2aEvent, a2aErr)
@@ -303,6 +346,75 @@ func (a *a2aAgent) run(ctx agent.InvocationContext, cfg A2AConfig) iter.Seq2[*se
 	}
 }
 
+// resumeFromPush fetches the remote task a push notification was received for and emits its result.
+func (a *a2aAgent) resumeFromPush(ctx agent.InvocationContext, cfg A2AConfig, client A2AClient, pending pendingTask, yield func(*session.Event, error) bool) {
+	getter, ok := client.(taskGetter)
+	if !ok {
+		yield(toErrorEvent(ctx, fmt.Errorf("A2A client does not support task retrieval")), nil)
+		return
+	}
+
+	processor := newRunProcessor(cfg, nil)
+	task, err := getter.GetTask(ctx, &a2a.GetTaskRequest{ID: pending.TaskID})
+	if err == nil && isAwaitingTaskEvent(task) {
+		// the notification was premature, keep waiting
+		yield(newAwaitTaskEvent(ctx, task.TaskInfo(), pending.Nonce), nil)
+		return
+	}
+
+	var event *session.Event
+	if cfg.Converter != nil {
+		event, err = cfg.Converter(ctx, nil, task, err)
+	} else {
+		event, err = processor.convertToSessionEvent(ctx, task, err)
+	}
+	if cbResp, cbErr := processor.runAfterA2ARequestCallbacks(ctx, event, err); cbResp != nil || cbErr != nil {
+		event, err = cbResp, cbErr
+	}
+	if err != nil {
+		yield(nil, err)
+		return
+	}
+	if event == nil {
+		event = adka2a.NewRemoteAgentEvent(ctx)
+		event.TurnComplete = true
+	}
+	if event.Actions.StateDelta == nil {
+		event.Actions.StateDelta = map[string]any{}
+	}
+	event.Actions.StateDelta[pendingTaskStateKey(ctx.Agent().Name())] = pending.consumedState()
+	yield(event, nil)
+}
+
+// newPushConfig returns a push config to register with the remote task and the nonce of its token, or nil
+// if push notifications are not enabled or not supported by the remote agent.
+func newPushConfig(ctx agent.InvocationContext, cfg A2AConfig, card *a2a.AgentCard) (*a2a.PushConfig, string, error) {
+	if cfg.PushNotifications == nil || !card.Capabilities.PushNotifications {
+		return nil, "", nil
+	}
+	ttl := cfg.PushNotifications.TokenTTL
+	if ttl <= 0 {
+		ttl = defaultPushTokenTTL
+	}
+	nonce := uuid.NewString()
+	token, err := newPushToken(cfg.PushNotifications.TokenKey, pushTarget{
+		AppName:   ctx.Session().AppName(),
+		UserID:    ctx.Session().UserID(),
+		SessionID: ctx.Session().ID(),
+		AgentName: ctx.Agent().Name(),
+		Nonce:     nonce,
+		Expires:   time.Now().Add(ttl).Unix(),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return &a2a.PushConfig{
+		URL:   cfg.PushNotifications.CallbackURL,
+		Token: token,
+		Auth:  cfg.PushNotifications.Auth,
+	}, nonce, nil
+}
+
 func cleanupRemoteTask(ctx context.Context, cfg A2AConfig, card *a2a.AgentCard, client A2AClient, lastEvent a2a.Event, cause error) {
 	if lastEvent == nil {
 		return
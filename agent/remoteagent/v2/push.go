@@ -0,0 +1,414 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteagent
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/v2/a2a"
+	"github.com/a2aproject/a2a-go/v2/log"
+	"github.com/google/uuid"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/server/adka2a/v2"
+	"google.golang.org/adk/v2/session"
+)
+
+// AwaitRemoteTaskFunctionName is the name of the long-running function call emitted by a remote agent while
+// it waits for a push notification about a remote task.
+const AwaitRemoteTaskFunctionName = "adk_await_remote_task"
+
+const (
+	maxPushNotificationSize = 10 << 20
+	defaultPushTokenTTL     = 24 * time.Hour
+)
+
+// PushNotificationConfig enables asynchronous consumption of long-running remote tasks.
+//
+// When it is set and the remote agent card declares push notification support, the remote agent registers
+// CallbackURL with the task, returns without waiting for the task to finish and records the pending task ID
+// in session state. The invocation is resumed by a handler created with [NewPushNotificationHandler] when a
+// notification about the task reaching a terminal or input-required state is delivered to CallbackURL.
+type PushNotificationConfig struct {
+	// CallbackURL is the URL served by a handler created with [NewPushNotificationHandler].
+	CallbackURL string
+	// TokenKey is used to sign the notification token which routes notifications back to the waiting session.
+	// The same key must be provided to [PushNotificationHandlerConfig].
+	TokenKey []byte
+	// TokenTTL is how long notification tokens are accepted after the task was started. Defaults to 24 hours.
+	TokenTTL time.Duration
+	// Auth is optional authentication the remote server should use when calling CallbackURL.
+	Auth *a2a.PushAuthInfo
+}
+
+// PushNotificationHandlerConfig is used to create a push notification handler.
+type PushNotificationHandlerConfig struct {
+	// Runner is used to resume invocations waiting for remote tasks. It must be configured with the same
+	// SessionService and AppName as the runner which started the invocation; notifications for sessions of
+	// other apps are rejected.
+	Runner *runner.Runner
+	// SessionService is used to look up tasks pending in a session.
+	SessionService session.Service
+	// TokenKey must match the key used in [PushNotificationConfig].
+	TokenKey []byte
+	// SignatureKey is an optional key used to verify notification payload signatures with [adka2a.VerifyPushSignature].
+	// Notifications without a valid signature are rejected when the key is set.
+	SignatureKey []byte
+	// SignatureTolerance is the maximum accepted age of a signature. Defaults to 5 minutes.
+	SignatureTolerance time.Duration
+	// RunConfig is passed to [runner.Runner.Run] when an invocation is resumed.
+	RunConfig agent.RunConfig
+	// AfterResumeCallback is called after a resumed invocation finished. It receives the events produced
+	// by the invocation and the first error, if any.
+	AfterResumeCallback func(ctx context.Context, userID, sessionID string, events []*session.Event, err error)
+}
+
+type pushNotificationHandler struct {
+	config PushNotificationHandlerConfig
+
+	mu      sync.Mutex
+	resumes map[string]bool
+}
+
+// NewPushNotificationHandler creates an [http.Handler] which receives A2A push notifications about tasks
+// started by remote agents configured with [PushNotificationConfig] and resumes the waiting invocations.
+//
+// Notifications are acknowledged as soon as they are validated and the invocation is resumed in background.
+// Notifications which arrive before the pending task was recorded in the session are answered with
+// 425 Too Early, so that senders created with [adka2a.NewPushSender] retry the delivery. Each token is only
+// valid for the task it was issued for: once that task resumed its invocation, or another task is pending in
+// its place, notifications with the token are answered with 410 Gone.
+func NewPushNotificationHandler(config PushNotificationHandlerConfig) (http.Handler, error) {
+	if config.Runner == nil {
+		return nil, fmt.Errorf("runner is required")
+	}
+	if config.SessionService == nil {
+		return nil, fmt.Errorf("session service is required")
+	}
+	if len(config.TokenKey) == 0 {
+		return nil, fmt.Errorf("token key is required")
+	}
+	if config.SignatureTolerance == 0 {
+		config.SignatureTolerance = 5 * time.Minute
+	}
+	return &pushNotificationHandler{config: config, resumes: make(map[string]bool)}, nil
+}
+
+func (h *pushNotificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPushNotificationSize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if len(h.config.SignatureKey) > 0 {
+		if err := adka2a.VerifyPushSignature(h.config.SignatureKey, r.Header, body, h.config.SignatureTolerance); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+	target, err := parsePushToken(h.config.TokenKey, r.Header.Get(adka2a.PushTokenHeader), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if target.AppName != h.config.Runner.AppName() {
+		http.Error(w, "notification token is for another app", http.StatusForbidden)
+		return
+	}
+
+	var notification a2a.StreamResponse
+	if err := json.Unmarshal(body, &notification); err != nil {
+		http.Error(w, fmt.Sprintf("invalid notification payload: %v", err), http.StatusBadRequest)
+		return
+	}
+	if notification.Event == nil {
+		http.Error(w, "notification has no event", http.StatusBadRequest)
+		return
+	}
+	if !isResumableTaskEvent(notification.Event) {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	resp, err := h.config.SessionService.Get(ctx, &session.GetRequest{
+		AppName:   target.AppName,
+		UserID:    target.UserID,
+		SessionID: target.SessionID,
+	})
+	if err != nil {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+	state := resp.Session.State()
+	pending, ok := getPendingTask(state, target.AgentName)
+	if (ok && pending.Nonce != target.Nonce) || (!ok && consumedPushNonce(state, target.AgentName) == target.Nonce) {
+		http.Error(w, "notification token is no longer valid", http.StatusGone)
+		return
+	}
+	if !ok || pending.TaskID != notification.Event.TaskInfo().TaskID {
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "task is not pending", http.StatusTooEarly)
+		return
+	}
+
+	resumeKey := target.SessionID + "/" + pending.CallID
+	h.mu.Lock()
+	if h.resumes[resumeKey] {
+		h.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	h.resumes[resumeKey] = true
+	h.mu.Unlock()
+
+	go h.resume(context.WithoutCancel(ctx), target, pending, resumeKey)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *pushNotificationHandler) resume(ctx context.Context, target pushTarget, pending pendingTask, resumeKey string) {
+	defer func() {
+		h.mu.Lock()
+		delete(h.resumes, resumeKey)
+		h.mu.Unlock()
+	}()
+
+	msg := genai.NewContentFromParts([]*genai.Part{{
+		FunctionResponse: &genai.FunctionResponse{
+			ID:       pending.CallID,
+			Name:     AwaitRemoteTaskFunctionName,
+			Response: map[string]any{"task_id": string(pending.TaskID)},
+		},
+	}}, genai.RoleUser)
+
+	var events []*session.Event
+	var runErr error
+	for event, err := range h.config.Runner.Run(ctx, target.UserID, target.SessionID, msg, h.config.RunConfig) {
+		if err != nil {
+			runErr = err
+			break
+		}
+		if event != nil && !event.Partial {
+			events = append(events, event)
+		}
+	}
+	if runErr != nil {
+		log.Warn(ctx, "resumed invocation failed", "session_id", target.SessionID, "task_id", pending.TaskID, "error", runErr)
+	}
+	if h.config.AfterResumeCallback != nil {
+		h.config.AfterResumeCallback(ctx, target.UserID, target.SessionID, events, runErr)
+	}
+}
+
+// isResumableTaskEvent returns true if the event describes a task which requires the attention of a waiting invocation.
+func isResumableTaskEvent(event a2a.Event) bool {
+	state, ok := taskEventState(event)
+	return ok && (state.Terminal() || state == a2a.TaskStateInputRequired)
+}
+
+// isAwaitingTaskEvent returns true if the event describes a task which is still being worked on.
+func isAwaitingTaskEvent(event a2a.Event) bool {
+	state, ok := taskEventState(event)
+	return ok && !state.Terminal() && state != a2a.TaskStateInputRequired
+}
+
+func taskEventState(event a2a.Event) (a2a.TaskState, bool) {
+	switch v := event.(type) {
+	case *a2a.Task:
+		if v != nil {
+			return v.Status.State, true
+		}
+	case *a2a.TaskStatusUpdateEvent:
+		if v != nil {
+			return v.Status.State, true
+		}
+	}
+	return "", false
+}
+
+// pendingTask is stored in session state while a remote agent waits for a push notification. Nonce is the
+// nonce of the notification token registered with the task.
+type pendingTask struct {
+	TaskID    a2a.TaskID
+	ContextID string
+	CallID    string
+	Nonce     string
+}
+
+func pendingTaskStateKey(agentName string) string {
+	return adka2a.ToADKMetaKey("pending_task:" + agentName)
+}
+
+func (p pendingTask) toState() map[string]any {
+	return map[string]any{
+		"task_id":    string(p.TaskID),
+		"context_id": p.ContextID,
+		"call_id":    p.CallID,
+		"nonce":      p.Nonce,
+	}
+}
+
+// consumedState is stored in session state in place of the pending task once it resumed the invocation, so
+// that its notification token can't be used again.
+func (p pendingTask) consumedState() map[string]any {
+	return map[string]any{"nonce": p.Nonce}
+}
+
+// consumedPushNonce returns the token nonce of the last task which resumed the agent, if any.
+func consumedPushNonce(state session.State, agentName string) string {
+	v, err := state.Get(pendingTaskStateKey(agentName))
+	if err != nil {
+		return ""
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return ""
+	}
+	if taskID, _ := m["task_id"].(string); taskID != "" {
+		return ""
+	}
+	nonce, _ := m["nonce"].(string)
+	return nonce
+}
+
+func getPendingTask(state session.State, agentName string) (pendingTask, bool) {
+	v, err := state.Get(pendingTaskStateKey(agentName))
+	if err != nil {
+		return pendingTask{}, false
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return pendingTask{}, false
+	}
+	taskID, _ := m["task_id"].(string)
+	contextID, _ := m["context_id"].(string)
+	callID, _ := m["call_id"].(string)
+	nonce, _ := m["nonce"].(string)
+	if taskID == "" || callID == "" {
+		return pendingTask{}, false
+	}
+	return pendingTask{TaskID: a2a.TaskID(taskID), ContextID: contextID, CallID: callID, Nonce: nonce}, true
+}
+
+// newAwaitTaskEvent creates a long-running function call event which pauses the invocation until
+// the remote task progresses. nonce is the nonce of the notification token registered with the task.
+func newAwaitTaskEvent(ctx agent.InvocationContext, taskInfo a2a.TaskInfo, nonce string) *session.Event {
+	pending := pendingTask{TaskID: taskInfo.TaskID, ContextID: taskInfo.ContextID, CallID: uuid.NewString(), Nonce: nonce}
+	event := adka2a.NewRemoteAgentEvent(ctx)
+	event.Content = genai.NewContentFromParts([]*genai.Part{{
+		FunctionCall: &genai.FunctionCall{
+			ID:   pending.CallID,
+			Name: AwaitRemoteTaskFunctionName,
+			Args: map[string]any{"task_id": string(taskInfo.TaskID)},
+		},
+	}}, genai.RoleModel)
+	event.LongRunningToolIDs = []string{pending.CallID}
+	event.CustomMetadata = adka2a.ToCustomMetadata(taskInfo.TaskID, taskInfo.ContextID)
+	event.Actions.StateDelta = map[string]any{pendingTaskStateKey(ctx.Agent().Name()): pending.toState()}
+	event.TurnComplete = true
+	return event
+}
+
+// getPushResume returns the pending task if the invocation was started by a push notification handler
+// to resume the current agent.
+func getPushResume(ctx agent.InvocationContext) (pendingTask, bool) {
+	pending, ok := getPendingTask(ctx.Session().State(), ctx.Agent().Name())
+	if !ok {
+		return pendingTask{}, false
+	}
+	events := ctx.Session().Events()
+	if events.Len() == 0 {
+		return pendingTask{}, false
+	}
+	last := events.At(events.Len() - 1)
+	if last.Author != "user" {
+		return pendingTask{}, false
+	}
+	callID, ok := getFunctionResponseCallID(last)
+	if !ok || callID != pending.CallID {
+		return pendingTask{}, false
+	}
+	return pending, true
+}
+
+type taskGetter interface {
+	GetTask(ctx context.Context, req *a2a.GetTaskRequest) (*a2a.Task, error)
+}
+
+// pushTarget is the payload of a notification token. Nonce ties the token to the task it is registered with
+// and Expires is the Unix time after which the token is rejected.
+type pushTarget struct {
+	AppName   string `json:"a"`
+	UserID    string `json:"u"`
+	SessionID string `json:"s"`
+	AgentName string `json:"n"`
+	Nonce     string `json:"o"`
+	Expires   int64  `json:"e"`
+}
+
+func newPushToken(key []byte, target pushTarget) (string, error) {
+	payload, err := json.Marshal(target)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + signPushToken(key, encoded), nil
+}
+
+func parsePushToken(key []byte, token string, now time.Time) (pushTarget, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return pushTarget{}, errors.New("malformed notification token")
+	}
+	if !hmac.Equal([]byte(signature), []byte(signPushToken(key, encoded))) {
+		return pushTarget{}, errors.New("invalid notification token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return pushTarget{}, errors.New("malformed notification token")
+	}
+	var target pushTarget
+	if err := json.Unmarshal(payload, &target); err != nil {
+		return pushTarget{}, errors.New("malformed notification token")
+	}
+	if now.Unix() > target.Expires {
+		return pushTarget{}, errors.New("expired notification token")
+	}
+	return target, nil
+}
+
+func signPushToken(key []byte, encoded string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,261 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteagent
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/v2/a2a"
+	"github.com/a2aproject/a2a-go/v2/a2asrv"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/utils"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/server/adka2a/v2"
+	"google.golang.org/adk/v2/session"
+)
+
+type resumeResult struct {
+	events []*session.Event
+	err    error
+}
+
+func TestRemoteAgent_PushNotifications(t *testing.T) {
+	ctx := t.Context()
+	tokenKey, signingKey := []byte("token-key"), []byte("signing-key")
+
+	// Remote server: a slow agent exposed via A2A with push notifications enabled
+	release := make(chan struct{})
+	remoteAgent := utils.Must(agent.New(agent.Config{
+		Name: "slow-agent",
+		Run: func(ic agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				select {
+				case <-release:
+				case <-ic.Done():
+					return
+				}
+				event := session.NewEvent(ic, ic.InvocationID())
+				event.Content = genai.NewContentFromText("report ready", genai.RoleModel)
+				yield(event, nil)
+			}
+		},
+	}))
+	executor := newAgentExecutor(remoteAgent, nil, adka2a.OutputArtifactPerRun)
+	handler := a2asrv.NewHandler(executor, adka2a.WithPushNotifications(adka2a.PushNotificationConfig{
+		SenderConfig: adka2a.PushSenderConfig{SigningKey: signingKey, InitialBackoff: 10 * time.Millisecond, MaxAttempts: 10, AllowPrivateNetworks: true},
+	}))
+	a2aServer := httptest.NewServer(a2asrv.NewJSONRPCHandler(handler))
+	defer a2aServer.Close()
+
+	// Client: a remote agent which waits for the task asynchronously
+	callbackMux := http.NewServeMux()
+	callbackServer := httptest.NewServer(callbackMux)
+	defer callbackServer.Close()
+
+	card := &a2a.AgentCard{
+		SupportedInterfaces: []*a2a.AgentInterface{a2a.NewAgentInterface(a2aServer.URL, a2a.TransportProtocolJSONRPC)},
+		Capabilities:        a2a.AgentCapabilities{Streaming: true, PushNotifications: true},
+	}
+	clientAgent := utils.Must(NewA2A(A2AConfig{
+		Name:      "remote",
+		AgentCard: card,
+		PushNotifications: &PushNotificationConfig{
+			CallbackURL: callbackServer.URL,
+			TokenKey:    tokenKey,
+		},
+	}))
+	sessionService := session.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "app", Agent: clientAgent, SessionService: sessionService})
+	if err != nil {
+		t.Fatalf("runner.New() error = %v", err)
+	}
+	sess := createSession(t, sessionService)
+
+	resumed := make(chan resumeResult, 1)
+	pushHandler, err := NewPushNotificationHandler(PushNotificationHandlerConfig{
+		Runner:         r,
+		SessionService: sessionService,
+		TokenKey:       tokenKey,
+		SignatureKey:   signingKey,
+		AfterResumeCallback: func(ctx context.Context, userID, sessionID string, events []*session.Event, err error) {
+			resumed <- resumeResult{events: events, err: err}
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewPushNotificationHandler() error = %v", err)
+	}
+	callbackMux.Handle("/", pushHandler)
+
+	// The first invocation returns as soon as the task is submitted
+	var firstRun []*session.Event
+	for event, err := range r.Run(ctx, "user", sess.ID(), genai.NewContentFromText("prepare a report", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("runner.Run() error = %v", err)
+		}
+		firstRun = append(firstRun, event)
+	}
+	if len(firstRun) == 0 {
+		t.Fatalf("runner.Run() produced no events")
+	}
+	awaitEvent := firstRun[len(firstRun)-1]
+	if len(awaitEvent.LongRunningToolIDs) != 1 {
+		t.Fatalf("await event LongRunningToolIDs = %v, want a single ID", awaitEvent.LongRunningToolIDs)
+	}
+	if call := awaitEvent.Content.Parts[0].FunctionCall; call == nil || call.Name != AwaitRemoteTaskFunctionName {
+		t.Fatalf("await event part = %+v, want %s function call", awaitEvent.Content.Parts[0], AwaitRemoteTaskFunctionName)
+	}
+	pending, ok := getPendingTask(getSession(t, sessionService, sess.ID()).State(), "remote")
+	if !ok {
+		t.Fatalf("pending task not found in session state")
+	}
+	if pending.CallID != awaitEvent.LongRunningToolIDs[0] {
+		t.Errorf("pending.CallID = %q, want %q", pending.CallID, awaitEvent.LongRunningToolIDs[0])
+	}
+
+	// Let the remote task finish and wait for the notification to resume the invocation
+	close(release)
+	var result resumeResult
+	select {
+	case result = <-resumed:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("invocation was not resumed")
+	}
+	if result.err != nil {
+		t.Fatalf("resumed invocation error = %v", result.err)
+	}
+	var texts []string
+	for _, event := range result.events {
+		if event.Content == nil {
+			continue
+		}
+		for _, part := range event.Content.Parts {
+			if part.Text != "" {
+				texts = append(texts, part.Text)
+			}
+		}
+	}
+	if got := strings.Join(texts, ""); got != "report ready" {
+		t.Errorf("resumed invocation text = %q, want %q", got, "report ready")
+	}
+
+	if _, ok := getPendingTask(getSession(t, sessionService, sess.ID()).State(), "remote"); ok {
+		t.Errorf("pending task still present in session state after resume")
+	}
+}
+
+func TestPushNotificationHandler_RejectsInvalidRequests(t *testing.T) {
+	tokenKey := []byte("token-key")
+	sessionService := session.InMemoryService()
+	clientAgent := utils.Must(NewA2A(A2AConfig{Name: "remote", AgentCard: &a2a.AgentCard{}}))
+	r, err := runner.New(runner.Config{AppName: "app", Agent: clientAgent, SessionService: sessionService})
+	if err != nil {
+		t.Fatalf("runner.New() error = %v", err)
+	}
+	sess := createSession(t, sessionService)
+	handler, err := NewPushNotificationHandler(PushNotificationHandlerConfig{
+		Runner:         r,
+		SessionService: sessionService,
+		TokenKey:       tokenKey,
+		SignatureKey:   []byte("signing-key"),
+	})
+	if err != nil {
+		t.Fatalf("NewPushNotificationHandler() error = %v", err)
+	}
+	consumed := &session.Event{Actions: session.EventActions{StateDelta: map[string]any{
+		pendingTaskStateKey("remote"): pendingTask{Nonce: "consumed"}.consumedState(),
+	}}}
+	if err := sessionService.AppendEvent(t.Context(), sess, consumed); err != nil {
+		t.Fatalf("AppendEvent() error = %v", err)
+	}
+	target := pushTarget{AppName: "app", UserID: "user", SessionID: sess.ID(), AgentName: "remote", Nonce: "pending", Expires: time.Now().Add(time.Hour).Unix()}
+	newToken := func(key []byte, modify func(*pushTarget)) string {
+		t.Helper()
+		target := target
+		modify(&target)
+		token, err := newPushToken(key, target)
+		if err != nil {
+			t.Fatalf("newPushToken() error = %v", err)
+		}
+		return token
+	}
+	validToken := newToken(tokenKey, func(*pushTarget) {})
+	forgedToken := newToken([]byte("other"), func(*pushTarget) {})
+	expiredToken := newToken(tokenKey, func(p *pushTarget) { p.Expires = time.Now().Add(-time.Minute).Unix() })
+	otherAppToken := newToken(tokenKey, func(p *pushTarget) { p.AppName = "other" })
+	consumedToken := newToken(tokenKey, func(p *pushTarget) { p.Nonce = "consumed" })
+	task := &a2a.Task{ID: a2a.NewTaskID(), ContextID: a2a.NewContextID()}
+	completed := a2a.NewStatusUpdateEvent(task, a2a.TaskStateCompleted, nil)
+
+	testCases := []struct {
+		name       string
+		token      string
+		signingKey []byte
+		wantStatus int
+	}{
+		{name: "unsigned", token: validToken, wantStatus: http.StatusUnauthorized},
+		{name: "wrong signature", token: validToken, signingKey: []byte("other"), wantStatus: http.StatusUnauthorized},
+		{name: "forged token", token: forgedToken, signingKey: []byte("signing-key"), wantStatus: http.StatusUnauthorized},
+		{name: "expired token", token: expiredToken, signingKey: []byte("signing-key"), wantStatus: http.StatusUnauthorized},
+		{name: "token for another app", token: otherAppToken, signingKey: []byte("signing-key"), wantStatus: http.StatusForbidden},
+		{name: "consumed token", token: consumedToken, signingKey: []byte("signing-key"), wantStatus: http.StatusGone},
+		{name: "task not pending", token: validToken, signingKey: []byte("signing-key"), wantStatus: http.StatusTooEarly},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotStatus int
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				gotStatus = rec.Code
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer receiver.Close()
+
+			sender := adka2a.NewPushSender(adka2a.PushSenderConfig{SigningKey: tc.signingKey, MaxAttempts: 1, FailOnError: true, AllowPrivateNetworks: true})
+			if err := sender.SendPush(t.Context(), &a2a.PushConfig{URL: receiver.URL, Token: tc.token}, completed); err != nil {
+				t.Fatalf("SendPush() error = %v", err)
+			}
+			if gotStatus != tc.wantStatus {
+				t.Errorf("handler status = %d, want %d", gotStatus, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func createSession(t *testing.T, service session.Service) session.Session {
+	t.Helper()
+	resp, err := service.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatalf("sessionService.Create() error = %v", err)
+	}
+	return resp.Session
+}
+
+func getSession(t *testing.T, service session.Service, sessionID string) session.Session {
+	t.Helper()
+	resp, err := service.Get(t.Context(), &session.GetRequest{AppName: "app", UserID: "user", SessionID: sessionID})
+	if err != nil {
+		t.Fatalf("sessionService.Get() error = %v", err)
+	}
+	return resp.Session
+}
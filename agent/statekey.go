@@ -0,0 +1,100 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"google.golang.org/adk/v2/session"
+)
+
+// StateKey is a session state key holding values of type T.
+//
+// Values read back from a session service which stores state as JSON
+// are converted to T. Workflows check the keys their nodes declare in
+// NodeConfig.StateKeys against their state schema when they are built.
+type StateKey[T any] struct {
+	name string
+}
+
+// NewStateKey returns the state key with the given name, which may
+// carry one of the session.KeyPrefix* prefixes.
+func NewStateKey[T any](name string) StateKey[T] {
+	return StateKey[T]{name: name}
+}
+
+// Name returns the name of the key in session state.
+func (k StateKey[T]) Name() string { return k.name }
+
+// Schema returns the JSON schema of the values of the key, inferred
+// from T.
+func (k StateKey[T]) Schema() (*jsonschema.Schema, error) {
+	return jsonschema.For[T](nil)
+}
+
+// Get returns the value of the key in the state of ctx. It returns an
+// error wrapping session.ErrStateKeyNotExist if the key isn't set, and
+// an error if its value can't be converted to T.
+func (k StateKey[T]) Get(ctx ReadonlyContext) (T, error) {
+	var zero T
+	v, err := ctx.ReadonlyState().Get(k.name)
+	if err != nil {
+		return zero, fmt.Errorf("state key %q: %w", k.name, err)
+	}
+	return k.convert(v)
+}
+
+// Lookup is like Get, but reports an unset key with ok false instead of
+// an error.
+func (k StateKey[T]) Lookup(ctx ReadonlyContext) (value T, ok bool, err error) {
+	value, err = k.Get(ctx)
+	if errors.Is(err, session.ErrStateKeyNotExist) {
+		return value, false, nil
+	}
+	return value, err == nil, err
+}
+
+// Set sets the value of the key in the state of ctx.
+func (k StateKey[T]) Set(ctx Context, v T) error {
+	return ctx.State().Set(k.name, v)
+}
+
+func (k StateKey[T]) convert(v any) (T, error) {
+	if t, ok := v.(T); ok {
+		return t, nil
+	}
+	var t T
+	b, err := json.Marshal(v)
+	if err != nil {
+		return t, fmt.Errorf("state key %q: %w", k.name, err)
+	}
+	if err := json.Unmarshal(b, &t); err != nil {
+		return t, fmt.Errorf("state key %q: value of type %T is not a %T: %w", k.name, v, t, err)
+	}
+	return t, nil
+}
+
+// StateKeyDescriptor describes a state key independently of the type of
+// its values. Every StateKey implements it.
+type StateKeyDescriptor interface {
+	Name() string
+	Schema() (*jsonschema.Schema, error)
+}
+
+var _ StateKeyDescriptor = StateKey[any]{}
@@ -0,0 +1,94 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"errors"
+	"iter"
+	"maps"
+	"slices"
+	"testing"
+
+	"google.golang.org/adk/v2/session"
+)
+
+type mapState map[string]any
+
+func (s mapState) Get(key string) (any, error) {
+	v, ok := s[key]
+	if !ok {
+		return nil, session.ErrStateKeyNotExist
+	}
+	return v, nil
+}
+
+func (s mapState) Set(key string, v any) error {
+	s[key] = v
+	return nil
+}
+
+func (s mapState) All() iter.Seq2[string, any] { return maps.All(s) }
+
+type stateContext struct {
+	StrictContextMock
+	state mapState
+}
+
+func (c *stateContext) State() session.State                 { return c.state }
+func (c *stateContext) ReadonlyState() session.ReadonlyState { return c.state }
+
+func TestStateKey_GetSet(t *testing.T) {
+	type profile struct {
+		Name  string `json:"name"`
+		Score int    `json:"score"`
+	}
+	ctx := &stateContext{StrictContextMock{Ctx: t.Context()}, mapState{}}
+	key := NewStateKey[profile]("profile")
+
+	if _, ok, err := key.Lookup(ctx); ok || err != nil {
+		t.Errorf("Lookup() of unset key = %v, %v, want not found", ok, err)
+	}
+	if _, err := key.Get(ctx); !errors.Is(err, session.ErrStateKeyNotExist) {
+		t.Errorf("Get() of unset key error = %v, want %v", err, session.ErrStateKeyNotExist)
+	}
+
+	if err := key.Set(ctx, profile{Name: "ada", Score: 3}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if got, err := key.Get(ctx); err != nil || got != (profile{Name: "ada", Score: 3}) {
+		t.Errorf("Get() = %+v, %v, want the value set", got, err)
+	}
+
+	// Values read back from JSON-backed session services are converted.
+	ctx.state["profile"] = map[string]any{"name": "bob", "score": float64(5)}
+	if got, ok, err := key.Lookup(ctx); !ok || err != nil || got != (profile{Name: "bob", Score: 5}) {
+		t.Errorf("Lookup() of decoded value = %+v, %v, %v, want converted value", got, ok, err)
+	}
+
+	ctx.state["profile"] = "not a profile"
+	if _, err := key.Get(ctx); err == nil {
+		t.Error("Get() of a value of the wrong type succeeded, want error")
+	}
+}
+
+func TestStateKey_Schema(t *testing.T) {
+	s, err := NewStateKey[[]int]("ids").Schema()
+	if err != nil {
+		t.Fatalf("Schema: %v", err)
+	}
+	if !slices.Contains(append(s.Types, s.Type), "array") || s.Items == nil || s.Items.Type != "integer" {
+		t.Errorf("Schema() = %+v, want array of integers", s)
+	}
+}
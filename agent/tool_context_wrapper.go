@@ -234,6 +234,10 @@ func (c *toolContextWrapper) UserContent() *genai.Content {
 	return c.context.UserContent()
 }
 
+func (c *toolContextWrapper) Principal() *Principal {
+	return c.context.Principal()
+}
+
 // UserID implements [Context].
 func (c *toolContextWrapper) UserID() string {
 	return c.context.UserID()
@@ -36,6 +36,16 @@ type Config struct {
 	BeforeAgentCallbacks []agent.BeforeAgentCallback
 	AfterAgentCallbacks  []agent.AfterAgentCallback
 	Edges                []workflow.Edge
+	// Durable checkpoints runs to the session as nodes complete, so
+	// that a run interrupted by a process crash can be continued with
+	// runner.Runner.RecoverRun. See workflow.WithDurableExecution.
+	Durable bool
+	// Version is the version of the workflow's graph, recorded with
+	// its runs. A run paused or interrupted under another version is
+	// passed to Migration, or refused when Migration is nil. See
+	// workflow.WithVersion.
+	Version   string
+	Migration workflow.MigrationFunc
 }
 
 // New creates a new Workflow agent. A single returned agent
@@ -45,7 +55,7 @@ type Config struct {
 // FunctionResponse targeting the InterruptID emitted by the
 // paused node.
 func New(cfg Config) (agent.Agent, error) {
-	w, err := workflow.New(cfg.Name, cfg.Edges)
+	w, err := newWorkflow(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +88,41 @@ func New(cfg Config) (agent.Agent, error) {
 	return wfAgent, nil
 }
 
+// WorkflowOf returns the workflow run by an agent created with New, e.g.
+// to render it with workflow.Workflow.DOT. It reports false for other
+// agents.
+func WorkflowOf(a agent.Agent) (*workflow.Workflow, bool) {
+	internalAgent, ok := a.(agentinternal.Agent)
+	if !ok {
+		return nil, false
+	}
+	state := agentinternal.Reveal(internalAgent)
+	cfg, ok := state.Config.(Config)
+	if !ok || state.AgentType != agentinternal.TypeWorkflowAgent {
+		return nil, false
+	}
+	// The config was validated by New, so rebuilding can't fail.
+	w, err := newWorkflow(cfg)
+	if err != nil {
+		return nil, false
+	}
+	return w, true
+}
+
+func newWorkflow(cfg Config) (*workflow.Workflow, error) {
+	var opts []workflow.Option
+	if cfg.Durable {
+		opts = append(opts, workflow.WithDurableExecution())
+	}
+	if cfg.Version != "" {
+		opts = append(opts, workflow.WithVersion(cfg.Version))
+	}
+	if cfg.Migration != nil {
+		opts = append(opts, workflow.WithMigration(cfg.Migration))
+	}
+	return workflow.New(cfg.Name, cfg.Edges, opts...)
+}
+
 // workflowAgent is the wrapper that dispatches between
 // Workflow.Run (fresh turn) and Workflow.Resume (resume turn).
 // The dispatch decision is made by inspecting ctx.UserContent for
@@ -91,8 +136,9 @@ type workflowAgent struct {
 
 // run is the agent.Config.Run callback. It dispatches between
 // Workflow.Resume (when the inbound user content carries a
-// FunctionResponse to a previously-emitted RequestInput) and
-// Workflow.Run (every other turn).
+// FunctionResponse to a previously-emitted RequestInput),
+// Workflow.Recover (when a durable run of the invocation was
+// interrupted) and Workflow.Run (every other turn).
 func (a *workflowAgent) run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
 	return func(yield func(*session.Event, error) bool) {
 		responses, state, ok, err := a.detectResume(ctx)
@@ -109,6 +155,24 @@ func (a *workflowAgent) run(ctx agent.InvocationContext) iter.Seq2[*session.Even
 			}
 			return
 		}
+		// A durable run whose checkpoint is still in progress was
+		// interrupted: the runner re-invokes it with the same
+		// invocation ID to continue from the checkpoint.
+		if a.workflow.Durable() {
+			cp, err := workflow.LoadCheckpoint(ctx.Session().State(), a.workflow.Name(), ctx.InvocationID())
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if cp != nil && cp.Status == workflow.RunInProgress {
+				for ev, err := range a.workflow.Recover(ctx, cp) {
+					if !yield(ev, err) {
+						return
+					}
+				}
+				return
+			}
+		}
 		for ev, err := range a.workflow.Run(ctx) {
 			if !yield(ev, err) {
 				return
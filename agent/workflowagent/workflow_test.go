@@ -170,6 +170,38 @@ func TestWorkflowAgent(t *testing.T) {
 	}
 }
 
+func TestWorkflowOf(t *testing.T) {
+	node := workflow.NewFunctionNode("upper", func(ctx agent.Context, input string) (string, error) {
+		return strings.ToUpper(input), nil
+	}, defaultNodeConfig)
+	wfAgent, err := New(Config{
+		Name:  "test_workflow",
+		Edges: workflow.Chain(workflow.Start, node),
+	})
+	if err != nil {
+		t.Fatalf("failed to create workflow agent: %v", err)
+	}
+
+	wf, ok := WorkflowOf(wfAgent)
+	if !ok {
+		t.Fatalf("WorkflowOf() = false, want true")
+	}
+	if wf.Name() != "test_workflow" {
+		t.Errorf("WorkflowOf().Name() = %q, want %q", wf.Name(), "test_workflow")
+	}
+	if got := wf.DOT(nil); !strings.Contains(got, `"START" -> "upper";`) {
+		t.Errorf("WorkflowOf().DOT() = %s, want edge START -> upper", got)
+	}
+
+	plain, err := agent.New(agent.Config{Name: "plain"})
+	if err != nil {
+		t.Fatalf("agent.New() error = %v", err)
+	}
+	if _, ok := WorkflowOf(plain); ok {
+		t.Errorf("WorkflowOf(plain agent) = true, want false")
+	}
+}
+
 func TestDecodeWorkflowInputResponse(t *testing.T) {
 	tests := []struct {
 		name string
@@ -19,9 +19,11 @@ package loopagent
 import (
 	"fmt"
 	"iter"
+	"time"
 
 	"google.golang.org/adk/v2/agent"
 	agentinternal "google.golang.org/adk/v2/internal/agent"
+	"google.golang.org/adk/v2/loop"
 	"google.golang.org/adk/v2/session"
 )
 
@@ -33,6 +35,13 @@ type Config struct {
 	// If MaxIterations == 0, then LoopAgent runs indefinitely or until any
 	// sub-agent escalates.
 	MaxIterations uint
+
+	// Conditions are checked after each iteration; the loop stops on
+	// the first one which holds. When set, the loop also ends each
+	// iteration with an event carrying its loop.Summary, in which the
+	// last one records why the loop stopped: a condition, MaxIterations
+	// or an escalation.
+	Conditions []loop.Condition
 }
 
 // New creates a LoopAgent.
@@ -49,6 +58,7 @@ func New(cfg Config) (agent.Agent, error) {
 
 	loopAgentImpl := &loopAgent{
 		maxIterations: cfg.MaxIterations,
+		conditions:    cfg.Conditions,
 	}
 	cfg.AgentConfig.Run = loopAgentImpl.Run
 
@@ -70,9 +80,13 @@ func New(cfg Config) (agent.Agent, error) {
 
 type loopAgent struct {
 	maxIterations uint
+	conditions    []loop.Condition
 }
 
 func (a *loopAgent) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+	if len(a.conditions) > 0 {
+		return a.runWithConditions(ctx)
+	}
 	count := a.maxIterations
 
 	return func(yield func(*session.Event, error) bool) {
@@ -103,3 +117,55 @@ func (a *loopAgent) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, e
 		}
 	}
 }
+
+// runWithConditions runs the loop checking its conditions after each
+// iteration, and summarizing each iteration in an event.
+func (a *loopAgent) runWithConditions(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		start := time.Now()
+		var prev *loop.Summary
+		for {
+			before := loop.Fingerprint(ctx.Session().State())
+			var events []*session.Event
+			var stop *loop.Stop
+			for _, subAgent := range ctx.Agent().SubAgents() {
+				for event, err := range subAgent.Run(ctx) {
+					if !yield(event, err) {
+						return
+					}
+					if event == nil {
+						continue
+					}
+					events = append(events, event)
+					if event.Actions.Escalate {
+						stop = &loop.Stop{Reason: loop.ReasonEscalated}
+					}
+				}
+				if stop != nil {
+					break
+				}
+			}
+
+			state := ctx.Session().State()
+			it := loop.NewIteration(prev, start, events, before, loop.Fingerprint(state), state)
+			if stop == nil {
+				var err error
+				if stop, err = loop.Check(ctx, it, a.conditions); err != nil {
+					yield(nil, err)
+					return
+				}
+			}
+			if stop == nil && a.maxIterations > 0 && uint(it.Number) >= a.maxIterations {
+				stop = &loop.Stop{Reason: loop.ReasonMaxIterations}
+			}
+			prev = it.Summary(stop)
+			ev := session.NewEvent(ctx, ctx.InvocationID())
+			ev.Author = ctx.Agent().Name()
+			ev.Branch = ctx.Branch()
+			ev.CustomMetadata = map[string]any{loop.SummaryKey: prev}
+			if !yield(ev, nil) || stop != nil {
+				return
+			}
+		}
+	}
+}
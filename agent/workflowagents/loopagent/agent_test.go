@@ -27,6 +27,7 @@ import (
 	"google.golang.org/adk/v2/agent"
 	"google.golang.org/adk/v2/agent/llmagent"
 	"google.golang.org/adk/v2/agent/workflowagents/loopagent"
+	"google.golang.org/adk/v2/loop"
 	"google.golang.org/adk/v2/model"
 	"google.golang.org/adk/v2/runner"
 	"google.golang.org/adk/v2/session"
@@ -376,3 +377,102 @@ func (f *FakeLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, st
 		}
 	}
 }
+
+// counterAgent increments the "count" state key up to max, and then
+// keeps it unchanged.
+func newCounterAgent(t *testing.T, max int) agent.Agent {
+	t.Helper()
+	a, err := agent.New(agent.Config{
+		Name: "counter",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				count, _ := ctx.Session().State().Get("count")
+				n, _ := count.(int)
+				ev := session.NewEvent(ctx, ctx.InvocationID())
+				ev.Author = "counter"
+				ev.Actions.StateDelta = map[string]any{"count": min(n+1, max)}
+				yield(ev, nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestLoopAgent_Conditions(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxIterations uint
+		conditions    []loop.Condition
+		wantSummaries int
+		wantReason    loop.Reason
+	}{
+		{
+			name: "state predicate",
+			conditions: []loop.Condition{loop.UntilState(func(s session.ReadonlyState) (bool, error) {
+				v, _ := s.Get("count")
+				return v == 2, nil
+			})},
+			wantSummaries: 2,
+			wantReason:    loop.ReasonState,
+		},
+		{
+			name:          "no state change",
+			conditions:    []loop.Condition{loop.UntilStable(2)},
+			wantSummaries: 5,
+			wantReason:    loop.ReasonStable,
+		},
+		{
+			name:          "max iterations",
+			maxIterations: 2,
+			conditions:    []loop.Condition{loop.UntilStable(2)},
+			wantSummaries: 2,
+			wantReason:    loop.ReasonMaxIterations,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loopAgent, err := loopagent.New(loopagent.Config{
+				MaxIterations: tt.maxIterations,
+				Conditions:    tt.conditions,
+				AgentConfig: agent.Config{
+					Name:      "refine",
+					SubAgents: []agent.Agent{newCounterAgent(t, 3)},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := runner.NewInMemory("test_app", loopAgent)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var summaries []*loop.Summary
+			for ev, err := range r.Run(t.Context(), "user_id", "session_id", genai.NewContentFromText("go", genai.RoleUser), agent.RunConfig{}) {
+				if err != nil {
+					t.Fatalf("Run: %v", err)
+				}
+				if s, ok := loop.SummaryOf(ev); ok {
+					if ev.Author != "refine" {
+						t.Errorf("summary author = %q, want %q", ev.Author, "refine")
+					}
+					summaries = append(summaries, s)
+				}
+			}
+			if len(summaries) != tt.wantSummaries {
+				t.Fatalf("got %d iteration summaries, want %d", len(summaries), tt.wantSummaries)
+			}
+			for i, s := range summaries[:len(summaries)-1] {
+				if s.Iteration != i+1 || s.Stop != nil {
+					t.Errorf("summary %d = %+v, want iteration %d without stop", i, s, i+1)
+				}
+			}
+			if last := summaries[len(summaries)-1]; last.Stop == nil || last.Stop.Reason != tt.wantReason {
+				t.Errorf("last summary = %+v, want stopped with reason %q", last, tt.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,417 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package approval defines policies for human approval steps: who may
+// approve, how many approvals are needed, when a request expires and
+// who is reminded of it.
+//
+// workflow.ApprovalNode applies a Policy to a workflow run, and
+// tool.WithApproval to the calls of tools run by an LLM agent. Both
+// record every request and decision in an Audit.
+package approval
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/typeutil"
+	"google.golang.org/adk/v2/session"
+)
+
+// ErrInvalidPolicy is returned for a Policy which can't be applied.
+var ErrInvalidPolicy = errors.New("approval: invalid policy")
+
+// Outcome is the outcome of an approval request.
+type Outcome string
+
+const (
+	// Pending means more decisions are needed.
+	Pending Outcome = "pending"
+	// Approved means the request was approved.
+	Approved Outcome = "approved"
+	// Rejected means the request was rejected.
+	Rejected Outcome = "rejected"
+)
+
+// RoleResolver returns the roles of a user.
+type RoleResolver func(ctx context.Context, userID string) ([]string, error)
+
+// Quorum decides when the decisions on a request settle it.
+type Quorum struct {
+	// Approvals is the number of approvals approving the request.
+	// <= 0 means 1.
+	Approvals int
+	// All additionally requires the approval of every user of
+	// Policy.Users.
+	All bool
+	// Rejections is the number of rejections rejecting the request.
+	// <= 0 means 1: any approver can veto the request.
+	Rejections int
+}
+
+// Reminder is a notification sent for a request still pending some
+// time after it was made.
+type Reminder struct {
+	// After is the time since the request after which the reminder is
+	// due.
+	After time.Duration
+	// EscalateTo are users notified besides the approvers, e.g. their
+	// managers. The reminder is an escalation when it's set.
+	EscalateTo []string
+}
+
+// Policy configures an approval step.
+type Policy struct {
+	// Users are the IDs of the users who may decide. When both Users
+	// and Roles are empty, the user of the session decides.
+	Users []string
+	// Roles are the roles whose users may decide. They are resolved
+	// with RoleResolver, which is then required.
+	Roles        []string
+	RoleResolver RoleResolver
+	// Quorum decides when the decisions settle the request.
+	Quorum Quorum
+	// Message is the text/template of the message shown to the
+	// approvers, executed with the subject of the request, e.g. the
+	// input of an approval node, as dot.
+	Message string
+	// PayloadSchema is the schema of the payload approvers may attach
+	// to their decision, e.g. an edited draft. Any payload is accepted
+	// when it's nil.
+	PayloadSchema *jsonschema.Schema
+	// Expiry, when > 0, is the time after which a pending request
+	// expires with ExpiryOutcome.
+	Expiry time.Duration
+	// ExpiryOutcome is the outcome of an expired request. Defaults to
+	// Rejected.
+	ExpiryOutcome Outcome
+	// Reminders are sent by Sweep for pending requests.
+	Reminders []Reminder
+	// Notifier is notified of requests, reminders and outcomes. Nothing
+	// is sent when it's nil.
+	Notifier Notifier
+}
+
+// Validate reports whether the policy can be applied.
+func (p *Policy) Validate() error {
+	if len(p.Roles) > 0 && p.RoleResolver == nil {
+		return fmt.Errorf("%w: roles %v without a RoleResolver", ErrInvalidPolicy, p.Roles)
+	}
+	switch p.ExpiryOutcome {
+	case "", Approved, Rejected:
+	default:
+		return fmt.Errorf("%w: expiry outcome %q, want %q or %q", ErrInvalidPolicy, p.ExpiryOutcome, Approved, Rejected)
+	}
+	if p.Quorum.All && len(p.Users) == 0 {
+		return fmt.Errorf("%w: quorum of all users without users", ErrInvalidPolicy)
+	}
+	if _, err := p.template(); err != nil {
+		return fmt.Errorf("%w: message: %w", ErrInvalidPolicy, err)
+	}
+	if p.PayloadSchema != nil {
+		if _, err := p.PayloadSchema.Resolve(nil); err != nil {
+			return fmt.Errorf("%w: payload schema: %w", ErrInvalidPolicy, err)
+		}
+	}
+	return nil
+}
+
+func (p *Policy) template() (*template.Template, error) {
+	return template.New("approval").Option("missingkey=zero").Parse(p.Message)
+}
+
+// Request is a request for approval.
+type Request struct {
+	// ID identifies the request, e.g. the interrupt the approvers
+	// answer.
+	ID string `json:"id"`
+	// Source is the name of the node or tool which made the request.
+	Source string `json:"source"`
+	// Message is the rendered message of the policy.
+	Message string `json:"message"`
+	// Users and Roles are the approvers of the policy.
+	Users []string `json:"users,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+	// RequestedAt is when the request was first made. Later rounds of
+	// the same request keep it.
+	RequestedAt time.Time `json:"requestedAt"`
+	// ExpiresAt is when the request expires, if it does.
+	ExpiresAt time.Time `json:"expiresAt,omitzero"`
+}
+
+// NewRequest makes the request with the given ID for subject, rendering
+// the message of the policy.
+func (p *Policy) NewRequest(id, source string, subject any, now time.Time) (Request, error) {
+	msg := "Approval required."
+	if p.Message != "" {
+		tmpl, err := p.template()
+		if err != nil {
+			return Request{}, fmt.Errorf("%w: message: %w", ErrInvalidPolicy, err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, subject); err != nil {
+			return Request{}, fmt.Errorf("approval: render message: %w", err)
+		}
+		msg = b.String()
+	}
+	req := Request{
+		ID:          id,
+		Source:      source,
+		Message:     msg,
+		Users:       p.Users,
+		Roles:       p.Roles,
+		RequestedAt: now,
+	}
+	if p.Expiry > 0 {
+		req.ExpiresAt = now.Add(p.Expiry)
+	}
+	return req, nil
+}
+
+// ResponseSchema returns the schema of the answers to a request: a
+// Decision without the fields set on receipt.
+func (p *Policy) ResponseSchema() *jsonschema.Schema {
+	payload := p.PayloadSchema
+	if payload == nil {
+		payload = &jsonschema.Schema{}
+	}
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"approved": {Type: "boolean", Description: "Whether the request is approved."},
+			"approver": {Type: "string", Description: "The ID of the user deciding. Defaults to, and must be, the user answering."},
+			"comment":  {Type: "string"},
+			"payload":  payload,
+		},
+		Required: []string{"approved"},
+	}
+}
+
+// ExpiredAnswer is an answer to the requests returned by Sweep. Requests
+// expire only by their ExpiresAt, so any answer of an approver received
+// after it expires the request; ExpiredAnswer merely decides nothing.
+func ExpiredAnswer() map[string]any {
+	return map[string]any{"approved": false}
+}
+
+// Decision is a decision on a request.
+type Decision struct {
+	Approved bool   `json:"approved"`
+	Approver string `json:"approver,omitempty"`
+	// Verified is set when the approver was authenticated.
+	Verified bool   `json:"verified,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+	Payload  any    `json:"payload,omitempty"`
+	// Expired is set when the request expired, in which case Approved
+	// is the expiry outcome of the policy.
+	Expired   bool      `json:"expired,omitempty"`
+	DecidedAt time.Time `json:"decidedAt,omitzero"`
+	// Ignored is why the decision doesn't count, e.g. because the
+	// approver may not decide. Empty when it counts.
+	Ignored string `json:"ignored,omitempty"`
+}
+
+// Identity is the identity of the user answering a request.
+type Identity struct {
+	// User is the ID of the user.
+	User string
+	// Verified is set when User was authenticated rather than taken
+	// from the session.
+	Verified bool
+}
+
+// IdentityOf returns the identity of the user of ctx: the authenticated
+// principal, identified by its Subject or, without one, its UserID, or
+// else the unverified user of the session.
+func IdentityOf(ctx agent.ReadonlyContext) Identity {
+	if p := ctx.Principal(); p != nil {
+		return Identity{User: cmp.Or(p.Subject, p.UserID), Verified: true}
+	}
+	return Identity{User: ctx.UserID()}
+}
+
+// Decide turns answer, an answer to req by who, into a decision, given
+// the decisions made before. The approver defaults to who. The
+// decisions of users who may not decide, or who claim to be another
+// user, are recorded but ignored. Answers of approvers
+// received once req.ExpiresAt has passed expire the request with the
+// expiry outcome of the policy; otherwise the decisions of approvers
+// who already decided are ignored too.
+func (p *Policy) Decide(ctx context.Context, req Request, prior []Decision, answer any, who Identity, now time.Time) (Decision, error) {
+	d, err := typeutil.ConvertToWithJSONSchema[any, Decision](answer, nil)
+	if err != nil {
+		return Decision{}, fmt.Errorf("approval: invalid answer to request %q: %w", req.ID, err)
+	}
+	// Only Decide sets these: an answer can't expire a request or mark
+	// itself.
+	d.Expired, d.Ignored = false, ""
+	d.DecidedAt, d.Verified = now, who.Verified
+	if d.Approver == "" {
+		d.Approver = who.User
+	}
+	// Without this, a single unauthenticated caller could answer as
+	// each of the approvers in turn and satisfy any quorum alone.
+	if d.Approver != who.User {
+		d.Ignored = fmt.Sprintf("%q answered as %q", who.User, d.Approver)
+		return d, nil
+	}
+	ok, err := p.mayDecide(ctx, d.Approver)
+	if err != nil {
+		return Decision{}, err
+	}
+	if !ok {
+		d.Ignored = fmt.Sprintf("%q is not an approver", d.Approver)
+		return d, nil
+	}
+	if !req.ExpiresAt.IsZero() && !now.Before(req.ExpiresAt) {
+		return Decision{Approved: p.ExpiryOutcome == Approved, Approver: d.Approver, Verified: d.Verified, Expired: true, DecidedAt: now}, nil
+	}
+	if p.PayloadSchema != nil && d.Payload != nil {
+		resolved, err := p.PayloadSchema.Resolve(nil)
+		if err != nil {
+			return Decision{}, fmt.Errorf("%w: payload schema: %w", ErrInvalidPolicy, err)
+		}
+		if err := resolved.Validate(d.Payload); err != nil {
+			return Decision{}, fmt.Errorf("approval: invalid payload in answer to request %q: %w", req.ID, err)
+		}
+	}
+	if slices.ContainsFunc(prior, func(o Decision) bool { return o.Ignored == "" && o.Approver == d.Approver }) {
+		d.Ignored = fmt.Sprintf("%q already decided", d.Approver)
+	}
+	return d, nil
+}
+
+func (p *Policy) mayDecide(ctx context.Context, user string) (bool, error) {
+	if len(p.Users) == 0 && len(p.Roles) == 0 {
+		return true, nil
+	}
+	if slices.Contains(p.Users, user) {
+		return true, nil
+	}
+	if len(p.Roles) == 0 || user == "" {
+		return false, nil
+	}
+	roles, err := p.RoleResolver(ctx, user)
+	if err != nil {
+		return false, fmt.Errorf("approval: resolve roles of %q: %w", user, err)
+	}
+	return slices.ContainsFunc(roles, func(r string) bool { return slices.Contains(p.Roles, r) }), nil
+}
+
+// Tally is the state of a request given its decisions.
+type Tally struct {
+	Outcome    Outcome    `json:"outcome"`
+	Approvals  int        `json:"approvals"`
+	Rejections int        `json:"rejections"`
+	Decisions  []Decision `json:"decisions,omitempty"`
+}
+
+// Tally counts decisions, in the order they were made, against the
+// quorum of the policy.
+func (p *Policy) Tally(decisions []Decision) Tally {
+	t := Tally{Outcome: Pending, Decisions: decisions}
+	approvedBy := map[string]bool{}
+	for _, d := range decisions {
+		if d.Ignored != "" {
+			continue
+		}
+		if d.Expired {
+			t.Outcome = Rejected
+			if d.Approved {
+				t.Outcome = Approved
+			}
+			return t
+		}
+		if d.Approved {
+			t.Approvals++
+			approvedBy[d.Approver] = true
+		} else {
+			t.Rejections++
+		}
+	}
+	if t.Rejections >= max(p.Quorum.Rejections, 1) {
+		t.Outcome = Rejected
+		return t
+	}
+	if t.Approvals < max(p.Quorum.Approvals, 1) {
+		return t
+	}
+	if p.Quorum.All && slices.ContainsFunc(p.Users, func(u string) bool { return !approvedBy[u] }) {
+		return t
+	}
+	t.Outcome = Approved
+	return t
+}
+
+// AuditKey is the Event.CustomMetadata key of the Audit recorded on the
+// events of an approval step.
+const AuditKey = "approval"
+
+// Audit records a request for approval with either a decision on it or,
+// on the request itself and on the final outcome, none.
+type Audit struct {
+	Request  Request   `json:"request"`
+	Decision *Decision `json:"decision,omitempty"`
+	Tally    Tally     `json:"tally"`
+	// NotifyError is the error of the notifier, if it failed.
+	NotifyError string `json:"notifyError,omitempty"`
+}
+
+// AuditOf returns the Audit recorded on ev, if any. It accepts events
+// read back from a session service which stores metadata in its
+// JSON-decoded form.
+func AuditOf(ev *session.Event) (*Audit, bool) {
+	if ev == nil {
+		return nil, false
+	}
+	return AuditFrom(ev.CustomMetadata[AuditKey])
+}
+
+// AuditFrom returns the Audit v holds, either as an *Audit or in its
+// JSON-decoded form, e.g. as read back from session state.
+func AuditFrom(v any) (*Audit, bool) {
+	return typeutil.Decode[Audit](v)
+}
+
+// OpenRequests returns the requests recorded in events which were not
+// answered, i.e. no event carries a FunctionResponse with their ID.
+func OpenRequests(events session.Events) []Request {
+	answered := map[string]bool{}
+	var requests []Request
+	for ev := range events.All() {
+		if ev == nil {
+			continue
+		}
+		if ev.Content != nil {
+			for _, p := range ev.Content.Parts {
+				if p != nil && p.FunctionResponse != nil {
+					answered[p.FunctionResponse.ID] = true
+				}
+			}
+		}
+		if a, ok := AuditOf(ev); ok && a.Decision == nil && a.Tally.Outcome == Pending {
+			requests = append(requests, a.Request)
+		}
+	}
+	return slices.DeleteFunc(requests, func(r Request) bool { return answered[r.ID] })
+}
@@ -0,0 +1,202 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approval
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+func TestPolicy_Decide(t *testing.T) {
+	p := &Policy{
+		Users: []string{"alice"},
+		Roles: []string{"admin"},
+		RoleResolver: func(_ context.Context, user string) ([]string, error) {
+			if user == "root" {
+				return []string{"admin"}, nil
+			}
+			return nil, nil
+		},
+		PayloadSchema: &jsonschema.Schema{Type: "string"},
+	}
+	now := time.Now()
+	req, err := p.NewRequest("r", "n", nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		answer      map[string]any
+		who         Identity
+		wantIgnored bool
+	}{
+		{map[string]any{"approved": true}, Identity{User: "alice"}, false},
+		{map[string]any{"approved": true, "approver": "alice"}, Identity{User: "alice"}, false},
+		{map[string]any{"approved": true, "approver": "root"}, Identity{User: "alice"}, true},
+		{map[string]any{"approved": true, "approver": "root"}, Identity{User: "alice", Verified: true}, true},
+		{map[string]any{"approved": true}, Identity{User: "root", Verified: true}, false},
+		{map[string]any{"approved": true}, Identity{User: "bob"}, true},
+	} {
+		d, err := p.Decide(t.Context(), req, nil, tt.answer, tt.who, now)
+		if err != nil {
+			t.Fatalf("Decide(%v): %v", tt.answer, err)
+		}
+		if got := d.Ignored != ""; got != tt.wantIgnored {
+			t.Errorf("Decide(%v) by %+v ignored = %v, want %v", tt.answer, tt.who, got, tt.wantIgnored)
+		}
+	}
+
+	prior := []Decision{{Approved: true, Approver: "alice"}}
+	if d, _ := p.Decide(t.Context(), req, prior, map[string]any{"approved": true}, Identity{User: "alice"}, now); d.Ignored == "" {
+		t.Error("second decision of alice counted")
+	}
+	if _, err := p.Decide(t.Context(), req, nil, map[string]any{"approved": true, "payload": 1}, Identity{User: "alice"}, now); err == nil {
+		t.Error("Decide() accepted a payload not matching the schema")
+	}
+}
+
+func TestPolicy_DecideUnverifiedQuorum(t *testing.T) {
+	p := &Policy{Users: []string{"alice", "bob"}, Quorum: Quorum{Approvals: 2}}
+	now := time.Now()
+	req, err := p.NewRequest("r", "n", nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decisions []Decision
+	for _, approver := range []string{"alice", "bob"} {
+		d, err := p.Decide(t.Context(), req, decisions, map[string]any{"approved": true, "approver": approver}, Identity{User: "alice"}, now)
+		if err != nil {
+			t.Fatalf("Decide() as %q: %v", approver, err)
+		}
+		decisions = append(decisions, d)
+	}
+	if got := p.Tally(decisions).Outcome; got != Pending {
+		t.Errorf("outcome after alice answered as alice and bob = %v, want %v", got, Pending)
+	}
+}
+
+func TestPolicy_Tally(t *testing.T) {
+	p := &Policy{Users: []string{"a", "b"}, Quorum: Quorum{Approvals: 1, All: true, Rejections: 2}}
+	for _, tt := range []struct {
+		decisions []Decision
+		want      Outcome
+	}{
+		{nil, Pending},
+		{[]Decision{{Approved: true, Approver: "a"}}, Pending},
+		{[]Decision{{Approved: true, Approver: "a"}, {Approved: true, Approver: "b"}}, Approved},
+		{[]Decision{{Approved: false, Approver: "a"}}, Pending},
+		{[]Decision{{Approved: false, Approver: "a"}, {Approved: false, Approver: "b"}}, Rejected},
+		{[]Decision{{Approved: true, Approver: "a"}, {Approved: true, Approver: "b", Ignored: "x"}}, Pending},
+		{[]Decision{{Expired: true}}, Rejected},
+	} {
+		if got := p.Tally(tt.decisions).Outcome; got != tt.want {
+			t.Errorf("Tally(%+v) = %v, want %v", tt.decisions, got, tt.want)
+		}
+	}
+}
+
+func TestPolicy_Expiry(t *testing.T) {
+	p := &Policy{Expiry: time.Minute, ExpiryOutcome: Approved}
+	now := time.Now()
+	req, err := p.NewRequest("r", "n", nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := p.Decide(t.Context(), req, nil, map[string]any{"approved": false}, Identity{User: "u"}, now.Add(2*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Expired || !d.Approved {
+		t.Errorf("late decision = %+v, want expired with the expiry outcome", d)
+	}
+}
+
+func TestPolicy_ExpiryNotClaimable(t *testing.T) {
+	p := &Policy{Users: []string{"alice"}, Expiry: time.Minute, ExpiryOutcome: Approved}
+	now := time.Now()
+	req, err := p.NewRequest("r", "n", nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claim := map[string]any{"approved": false, "expired": true}
+	for _, tt := range []struct {
+		name string
+		who  Identity
+		at   time.Time
+	}{
+		{"non-approver before expiry", Identity{User: "mallory", Verified: true}, now},
+		{"non-approver after expiry", Identity{User: "mallory", Verified: true}, now.Add(2 * time.Minute)},
+		{"approver before expiry", Identity{User: "alice", Verified: true}, now},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := p.Decide(t.Context(), req, nil, claim, tt.who, tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.Expired {
+				t.Errorf("Decide() = %+v, want the expired claim of the answer ignored", d)
+			}
+			if got := p.Tally([]Decision{d}).Outcome; got == Approved {
+				t.Errorf("Tally() = %v, want the request not approved", got)
+			}
+		})
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	for _, p := range []*Policy{
+		{Roles: []string{"admin"}},
+		{ExpiryOutcome: "maybe"},
+		{Quorum: Quorum{All: true}},
+		{Message: "{{.Broken"},
+	} {
+		if err := p.Validate(); !errors.Is(err, ErrInvalidPolicy) {
+			t.Errorf("Validate(%+v) = %v, want %v", p, err, ErrInvalidPolicy)
+		}
+	}
+}
+
+func TestPolicy_Sweep(t *testing.T) {
+	var sent []NotificationKind
+	p := &Policy{
+		Expiry:    time.Hour,
+		Reminders: []Reminder{{After: 10 * time.Minute}, {After: 20 * time.Minute, EscalateTo: []string{"boss"}}},
+		Notifier: NotifierFunc(func(_ context.Context, n Notification) error {
+			sent = append(sent, n.Kind)
+			return nil
+		}),
+	}
+	start := time.Now()
+	req, _ := p.NewRequest("r", "n", nil, start)
+
+	if _, err := p.Sweep(t.Context(), []Request{req}, start, start.Add(15*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Sweep(t.Context(), []Request{req}, start.Add(15*time.Minute), start.Add(30*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if want := []NotificationKind{Reminded, Escalated}; !slices.Equal(sent, want) {
+		t.Errorf("notifications = %v, want %v", sent, want)
+	}
+	expired, err := p.Sweep(t.Context(), []Request{req}, start.Add(30*time.Minute), start.Add(time.Hour))
+	if err != nil || len(expired) != 1 {
+		t.Errorf("Sweep() = %v, %v, want the request expired", expired, err)
+	}
+}
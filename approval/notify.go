@@ -0,0 +1,97 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approval
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// NotificationKind is the kind of a Notification.
+type NotificationKind string
+
+const (
+	// Requested is sent when a request is made.
+	Requested NotificationKind = "requested"
+	// Reminded is sent for a Reminder without EscalateTo.
+	Reminded NotificationKind = "reminded"
+	// Escalated is sent for a Reminder with EscalateTo.
+	Escalated NotificationKind = "escalated"
+	// Decided is sent when a request is approved, rejected or expires.
+	Decided NotificationKind = "decided"
+)
+
+// Notification is sent to a Notifier about a request.
+type Notification struct {
+	Kind    NotificationKind
+	Request Request
+	// Tally is the state of the request.
+	Tally Tally
+	// EscalateTo are the users to notify besides the approvers of the
+	// request, for Escalated notifications.
+	EscalateTo []string
+}
+
+// Notifier delivers notifications to approvers, e.g. by email or chat.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// NotifierFunc adapts a function to a Notifier.
+type NotifierFunc func(ctx context.Context, n Notification) error
+
+// Notify calls f.
+func (f NotifierFunc) Notify(ctx context.Context, n Notification) error {
+	return f(ctx, n)
+}
+
+// Notify sends n to the notifier of the policy, if any.
+func (p *Policy) Notify(ctx context.Context, n Notification) error {
+	if p.Notifier == nil {
+		return nil
+	}
+	return p.Notifier.Notify(ctx, n)
+}
+
+// Sweep sends the reminders of the policy which became due between
+// since and now for the given pending requests, e.g. those returned by
+// OpenRequests, and returns the requests which expired by now. Callers
+// run it periodically, passing the time of the previous sweep as since,
+// and answer the expired requests with ExpiredAnswer.
+func (p *Policy) Sweep(ctx context.Context, requests []Request, since, now time.Time) ([]Request, error) {
+	var errs []error
+	var expired []Request
+	for _, req := range requests {
+		for _, r := range p.Reminders {
+			due := req.RequestedAt.Add(r.After)
+			if !due.After(since) || due.After(now) {
+				continue
+			}
+			n := Notification{Kind: Reminded, Request: req, Tally: Tally{Outcome: Pending}}
+			if len(r.EscalateTo) > 0 {
+				n.Kind = Escalated
+				n.EscalateTo = r.EscalateTo
+			}
+			if err := p.Notify(ctx, n); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if !req.ExpiresAt.IsZero() && !req.ExpiresAt.After(now) {
+			expired = append(expired, req)
+		}
+	}
+	return expired, errors.Join(errs...)
+}
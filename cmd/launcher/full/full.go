@@ -18,16 +18,19 @@ package full
 import (
 	"google.golang.org/adk/v2/cmd/launcher"
 	"google.golang.org/adk/v2/cmd/launcher/console"
+	"google.golang.org/adk/v2/cmd/launcher/mcp"
 	"google.golang.org/adk/v2/cmd/launcher/universal"
 	"google.golang.org/adk/v2/cmd/launcher/web"
 	"google.golang.org/adk/v2/cmd/launcher/web/a2a"
 	"google.golang.org/adk/v2/cmd/launcher/web/api"
+	"google.golang.org/adk/v2/cmd/launcher/web/triggers/cron"
 	"google.golang.org/adk/v2/cmd/launcher/web/triggers/eventarc"
 	"google.golang.org/adk/v2/cmd/launcher/web/triggers/pubsub"
+	"google.golang.org/adk/v2/cmd/launcher/web/triggers/webhook"
 	"google.golang.org/adk/v2/cmd/launcher/web/webui"
 )
 
 // NewLauncher returnes the most versatile universal launcher with all options built-in.
 func NewLauncher() launcher.Launcher {
-	return universal.NewLauncher(console.NewLauncher(), web.NewLauncher(webui.NewLauncher(), a2a.NewLauncher(), pubsub.NewLauncher(), eventarc.NewLauncher(), api.NewLauncher()))
+	return universal.NewLauncher(console.NewLauncher(), web.NewLauncher(webui.NewLauncher(), a2a.NewLauncher(), pubsub.NewLauncher(), eventarc.NewLauncher(), webhook.NewLauncher(), cron.NewLauncher(), api.NewLauncher()), mcp.NewLauncher())
 }
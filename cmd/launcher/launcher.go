@@ -24,8 +24,11 @@ import (
 	"google.golang.org/adk/v2/artifact"
 	"google.golang.org/adk/v2/memory"
 	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/server/authn"
+	"google.golang.org/adk/v2/server/ratelimit"
 	"google.golang.org/adk/v2/session"
 	"google.golang.org/adk/v2/telemetry"
+	"google.golang.org/adk/v2/tool"
 )
 
 // Launcher is the main interface for running an ADK application.
@@ -63,4 +66,13 @@ type Config struct {
 	A2AOptions       []a2asrv.RequestHandlerOption
 	PluginConfig     runner.PluginConfig
 	TelemetryOptions []telemetry.Option
+	// Authentication configures inbound authentication of the servers started by web sublaunchers.
+	// Requests are not authenticated when Authenticator is nil.
+	Authentication authn.MiddlewareConfig
+	// RateLimiter limits agent runs started through the REST API and the Pub/Sub and Eventarc triggers.
+	// Runs are not limited when it's nil.
+	RateLimiter *ratelimit.Limiter
+	// MCPTools are served as MCP tools of their own by the mcp sublauncher, next to the agents.
+	// See mcpserver.Config.Tools.
+	MCPTools []tool.Tool
 }
@@ -0,0 +1,180 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mcp provides a launcher serving agents over the Model Context
+// Protocol, on stdio or streamable HTTP. See package
+// google.golang.org/adk/v2/server/mcpserver.
+package mcp
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/v2/cmd/launcher"
+	"google.golang.org/adk/v2/cmd/launcher/internal/telemetry"
+	"google.golang.org/adk/v2/cmd/launcher/universal"
+	"google.golang.org/adk/v2/internal/cli/util"
+	"google.golang.org/adk/v2/server/authn"
+	"google.golang.org/adk/v2/server/mcpserver"
+)
+
+const (
+	transportStdio = "stdio"
+	transportHTTP  = "http"
+)
+
+// mcpConfig contains command-line params for the MCP launcher.
+type mcpConfig struct {
+	transport       string
+	port            int
+	path            string
+	shutdownTimeout time.Duration
+	otelToCloud     bool
+}
+
+// mcpLauncher serves agents over MCP.
+type mcpLauncher struct {
+	flags  *flag.FlagSet
+	config *mcpConfig
+}
+
+// NewLauncher creates a new MCP launcher.
+func NewLauncher() launcher.SubLauncher {
+	config := &mcpConfig{}
+
+	fs := flag.NewFlagSet("mcp", flag.ContinueOnError)
+	fs.StringVar(&config.transport, "transport", transportStdio, fmt.Sprintf("MCP transport (%s|%s)", transportStdio, transportHTTP))
+	fs.IntVar(&config.port, "port", 8080, "Localhost port for the streamable HTTP transport")
+	fs.StringVar(&config.path, "path", "/mcp", "Path of the MCP endpoint for the streamable HTTP transport")
+	fs.DurationVar(&config.shutdownTimeout, "shutdown-timeout", 15*time.Second, "Server shutdown timeout (i.e. '10s', '2m' - see time.ParseDuration for details) - for waiting for active requests to finish during shutdown")
+	fs.BoolVar(&config.otelToCloud, "otel_to_cloud", false, "Enables/disables OpenTelemetry export to GCP: telemetry.googleapis.com. See adk-go/telemetry package for details about supported options, credentials and environment variables.")
+	return &mcpLauncher{config: config, flags: fs}
+}
+
+// Run implements launcher.SubLauncher. It serves the agents until ctx is
+// done or, on stdio, the client disconnects.
+func (l *mcpLauncher) Run(ctx context.Context, config *launcher.Config) error {
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	telemetryService, err := telemetry.InitAndSetGlobalOtelProviders(ctx, config, l.config.otelToCloud)
+	if err != nil {
+		return fmt.Errorf("telemetry initialization failed: %v", err)
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), l.config.shutdownTimeout)
+		defer cancel()
+		if err := telemetryService.Shutdown(shutdownCtx); err != nil {
+			log.Printf("telemetry shutdown failed: %v", err)
+		}
+	}()
+
+	serverConfig := mcpserver.Config{
+		AgentLoader:     config.AgentLoader,
+		SessionService:  config.SessionService,
+		ArtifactService: config.ArtifactService,
+		MemoryService:   config.MemoryService,
+		PluginConfig:    config.PluginConfig,
+		Tools:           config.MCPTools,
+	}
+	server, err := mcpserver.New(serverConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create MCP server: %w", err)
+	}
+
+	if l.config.transport == transportStdio {
+		// stdout carries the protocol; logs go to stderr.
+		if err := server.Run(ctx, &mcpsdk.StdioTransport{}); err != nil && !errors.Is(err, context.Canceled) {
+			return fmt.Errorf("MCP server failed: %w", err)
+		}
+		return nil
+	}
+
+	handler := mcpserver.HTTPHandler(server)
+	if config.Authentication.Authenticator != nil {
+		handler = authn.Middleware(config.Authentication)(handler)
+	}
+	mux := http.NewServeMux()
+	mux.Handle(l.config.path, handler)
+	srv := &http.Server{Addr: fmt.Sprintf(":%d", l.config.port), Handler: mux}
+
+	errChan := make(chan error, 1)
+	go func() {
+		log.Printf("Serving MCP on http://localhost:%d%s", l.config.port, l.config.path)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errChan <- err
+		}
+		close(errChan)
+	}()
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), l.config.shutdownTimeout)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	case err, ok := <-errChan:
+		if !ok {
+			return nil
+		}
+		return fmt.Errorf("server failed: %v", err)
+	}
+}
+
+// Parse implements launcher.SubLauncher. After parsing MCP-specific
+// arguments returns remaining un-parsed arguments.
+func (l *mcpLauncher) Parse(args []string) ([]string, error) {
+	err := l.flags.Parse(args)
+	if err != nil || !l.flags.Parsed() {
+		return nil, fmt.Errorf("failed to parse flags: %v", err)
+	}
+	if l.config.transport != transportStdio && l.config.transport != transportHTTP {
+		return nil, fmt.Errorf("invalid transport: %v. Should be (%s|%s)", l.config.transport, transportStdio, transportHTTP)
+	}
+	return l.flags.Args(), nil
+}
+
+// Keyword implements launcher.SubLauncher.
+func (l *mcpLauncher) Keyword() string {
+	return "mcp"
+}
+
+// CommandLineSyntax implements launcher.SubLauncher.
+func (l *mcpLauncher) CommandLineSyntax() string {
+	return util.FormatFlagUsage(l.flags)
+}
+
+// SimpleDescription implements launcher.SubLauncher.
+func (l *mcpLauncher) SimpleDescription() string {
+	return "serves agents as tools of an MCP server, on stdio or streamable HTTP."
+}
+
+// Execute implements launcher.Launcher. It parses arguments and runs the launcher.
+func (l *mcpLauncher) Execute(ctx context.Context, config *launcher.Config, args []string) error {
+	remainingArgs, err := l.Parse(args)
+	if err != nil {
+		return fmt.Errorf("cannot parse args: %w", err)
+	}
+	if err := universal.ErrorOnUnparsedArgs(remainingArgs); err != nil {
+		return fmt.Errorf("cannot parse all the arguments: %w", err)
+	}
+	return l.Run(ctx, config)
+}
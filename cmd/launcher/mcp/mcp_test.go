@@ -0,0 +1,54 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    mcpConfig
+		rest    []string
+		wantErr bool
+	}{
+		{name: "defaults", want: mcpConfig{transport: transportStdio, port: 8080, path: "/mcp"}},
+		{name: "http", args: []string{"-transport", "http", "-port", "9000", "-path", "/agents", "web"}, want: mcpConfig{transport: transportHTTP, port: 9000, path: "/agents"}, rest: []string{"web"}},
+		{name: "invalid transport", args: []string{"-transport", "sse"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLauncher().(*mcpLauncher)
+			rest, err := l.Parse(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := *l.config
+			got.shutdownTimeout = 0
+			if got != tt.want {
+				t.Errorf("Parse() config = %+v, want %+v", got, tt.want)
+			}
+			if !slices.Equal(rest, tt.rest) {
+				t.Errorf("Parse() rest = %v, want %v", rest, tt.rest)
+			}
+		})
+	}
+}
@@ -16,9 +16,14 @@
 package a2a
 
 import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
 	"flag"
 	"fmt"
 	"net/url"
+	"os"
 
 	a2acore "github.com/a2aproject/a2a-go/v2/a2a"
 	"github.com/a2aproject/a2a-go/v2/a2acompat/a2av0"
@@ -30,6 +35,7 @@ import (
 	"google.golang.org/adk/v2/internal/cli/util"
 	"google.golang.org/adk/v2/runner"
 	"google.golang.org/adk/v2/server/adka2a/v2"
+	"google.golang.org/adk/v2/server/authn"
 )
 
 // compatAPIPath is a suffix used to build an A2A invocation URL for 0.3
@@ -40,7 +46,9 @@ const apiPath = "/a2a/v1/invoke"
 
 // a2aConfig contains parameters for launching ADK A2A server
 type a2aConfig struct {
-	agentURL string // user-provided url which will be used in the agent card to specify url for invoking A2A
+	agentURL          string // user-provided url which will be used in the agent card to specify url for invoking A2A
+	pushNotifications bool   // enables webhook delivery of task updates to clients which registered a push config
+	cardSigningKey    string // path to a PEM-encoded private key used for signing the agent card
 }
 
 type a2aLauncher struct {
@@ -55,6 +63,8 @@ func NewLauncher() web.Sublauncher {
 	fs := flag.NewFlagSet("a2a", flag.ContinueOnError)
 
 	fs.StringVar(&config.agentURL, "a2a_agent_url", "http://localhost:8080", "A2A host URL as advertised in the public agent card. It is used by A2A clients as a connection endpoint.")
+	fs.StringVar(&config.cardSigningKey, "a2a_card_signing_key", "", "Path to a PEM-encoded ECDSA P-256, Ed25519 or RSA private key. When set, the published agent card is signed with it.")
+	fs.BoolVar(&config.pushNotifications, "a2a_push_notifications", false, "Enables A2A push notifications. Task push configs are kept in memory, use A2AOptions in launcher config to provide a durable store or a signing sender.")
 
 	return &a2aLauncher{
 		config: config,
@@ -92,12 +102,25 @@ func (a *a2aLauncher) SetupSubrouters(router *mux.Router, config *launcher.Confi
 		return err
 	}
 
+	var signer *adka2a.AgentCardSigner
+	if a.config.cardSigningKey != "" {
+		key, err := loadSigningKey(a.config.cardSigningKey)
+		if err != nil {
+			return fmt.Errorf("failed to load agent card signing key: %w", err)
+		}
+		signer = &adka2a.AgentCardSigner{Signer: key}
+	}
+
+	authenticator := config.Authentication.Authenticator
+	var securityProviders []adka2a.SecuritySchemeProvider
+	if provider, ok := authenticator.(adka2a.SecuritySchemeProvider); ok {
+		securityProviders = append(securityProviders, provider)
+	}
+
 	rootAgent := config.AgentLoader.RootAgent()
-	agentCard := &a2acore.AgentCard{
-		Name:               rootAgent.Name(),
-		Description:        rootAgent.Description(),
-		DefaultInputModes:  []string{"text/plain"},
-		DefaultOutputModes: []string{"text/plain"},
+	cardConfig := adka2a.AgentCardConfig{
+		Agent:   rootAgent,
+		Version: "2.0.0",
 		SupportedInterfaces: []*a2acore.AgentInterface{
 			{
 				URL:             publicURL,
@@ -110,9 +133,17 @@ func (a *a2aLauncher) SetupSubrouters(router *mux.Router, config *launcher.Confi
 				ProtocolVersion: a2av0.Version,
 			},
 		},
-		Version:      "2.0.0",
-		Skills:       adka2a.BuildAgentSkills(rootAgent),
-		Capabilities: a2acore.AgentCapabilities{Streaming: true},
+		Capabilities:            a2acore.AgentCapabilities{Streaming: true, PushNotifications: a.config.pushNotifications},
+		SecuritySchemeProviders: securityProviders,
+		Signer:                  signer,
+	}
+	if authenticator != nil {
+		// authenticated clients can fetch the card which is not served publicly
+		cardConfig.Capabilities.ExtendedAgentCard = true
+	}
+	agentCard, err := adka2a.BuildAgentCard(context.Background(), cardConfig)
+	if err != nil {
+		return fmt.Errorf("failed to build agent card: %w", err)
 	}
 
 	compatProducer := a2av0.NewStaticAgentCardProducer(agentCard)
@@ -129,14 +160,58 @@ func (a *a2aLauncher) SetupSubrouters(router *mux.Router, config *launcher.Confi
 			PluginConfig:    config.PluginConfig,
 		},
 	})
-	reqHandler := a2asrv.NewHandler(executor, config.A2AOptions...)
-
-	router.Handle(apiPath, a2asrv.NewJSONRPCHandler(reqHandler))
-	router.Handle(compatAPIPath, a2av0.NewJSONRPCHandler(reqHandler))
+	var options []a2asrv.RequestHandlerOption
+	if a.config.pushNotifications {
+		// user-provided options are applied last and can override the default push setup
+		options = append(options, adka2a.WithPushNotifications(adka2a.PushNotificationConfig{}))
+	}
+	if authenticator != nil {
+		options = append(options, adka2a.WithPrincipalUser(), adka2a.WithExtendedAgentCard(cardConfig))
+	}
+	options = append(options, config.A2AOptions...)
+	reqHandler := a2asrv.NewHandler(executor, options...)
+
+	handler, compatHandler := a2asrv.NewJSONRPCHandler(reqHandler), a2av0.NewJSONRPCHandler(reqHandler)
+	if authenticator != nil {
+		// the public agent card stays reachable so that clients can discover the required security schemes
+		authenticate := authn.Middleware(config.Authentication)
+		handler, compatHandler = authenticate(handler), authenticate(compatHandler)
+	}
+	router.Handle(apiPath, handler)
+	router.Handle(compatAPIPath, compatHandler)
 
 	return nil
 }
 
+// loadSigningKey reads a PEM-encoded PKCS #8, SEC 1 or PKCS #1 private key.
+func loadSigningKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+	var key any
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
 // SimpleDescription implements web.Sublauncher
 func (a *a2aLauncher) SimpleDescription() string {
 	return fmt.Sprintf("starts A2A server which handles jsonrpc requests on %s path", apiPath)
@@ -0,0 +1,240 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cron provides a sublauncher that runs agents on a schedule within ADK web server.
+package cron
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gorilla/mux"
+	"gopkg.in/yaml.v3"
+
+	"google.golang.org/adk/v2/cmd/launcher"
+	"google.golang.org/adk/v2/cmd/launcher/web"
+	"google.golang.org/adk/v2/internal/cli/util"
+	"google.golang.org/adk/v2/server/adkrest/controllers/triggers"
+)
+
+type cronConfig struct {
+	schedulesFile     string
+	schedule          string
+	message           string
+	appName           string
+	userID            string
+	sessionID         string
+	timezone          string
+	triggerMaxRetries int
+	triggerBaseDelay  time.Duration
+	triggerMaxDelay   time.Duration
+	triggerMaxRuns    int
+}
+
+// schedulesFile is the format of the file passed with -schedules_file.
+type schedulesFile struct {
+	Schedules []struct {
+		Name      string `yaml:"name"`
+		AppName   string `yaml:"app_name"`
+		Spec      string `yaml:"spec"`
+		Timezone  string `yaml:"timezone"`
+		Message   string `yaml:"message"`
+		UserID    string `yaml:"user_id"`
+		SessionID string `yaml:"session_id"`
+	} `yaml:"schedules"`
+}
+
+type cronLauncher struct {
+	flags     *flag.FlagSet
+	config    *cronConfig
+	scheduler *triggers.Scheduler
+}
+
+// NewLauncher creates a new cron launcher. It extends Web launcher.
+func NewLauncher() web.Sublauncher {
+	config := &cronConfig{}
+
+	fs := flag.NewFlagSet("cron", flag.ContinueOnError)
+	fs.StringVar(&config.schedulesFile, "schedules_file", "", "Path to a YAML file with a 'schedules' list. Entries have 'spec' and 'message' fields, and optional 'name', 'app_name', 'timezone', 'user_id' and 'session_id' fields.")
+	fs.StringVar(&config.schedule, "schedule", "", "Schedule of the root agent runs: a 5-field cron expression such as '0 9 * * MON-FRI', a descriptor such as '@hourly', or an interval such as '@every 15m'.")
+	fs.StringVar(&config.message, "message", "", "Message sent to the agent on each run started with -schedule.")
+	fs.StringVar(&config.appName, "app_name", "", "Name of the agent run with -schedule, and the default for entries of -schedules_file. Defaults to the root agent.")
+	fs.StringVar(&config.userID, "user_id", "", "ID of the user the agent runs with -schedule for. Defaults to 'scheduler'.")
+	fs.StringVar(&config.sessionID, "session_id", "", "ID of the session reused by runs started with -schedule. By default each run creates a new session.")
+	fs.StringVar(&config.timezone, "timezone", "", "IANA time zone cron expressions are evaluated in, e.g. 'Europe/Warsaw'. Defaults to the local time zone.")
+	fs.IntVar(&config.triggerMaxRetries, "trigger_max_retries", 3, "Maximum retries for HTTP 429 errors from triggers")
+	fs.DurationVar(&config.triggerBaseDelay, "trigger_base_delay", 1*time.Second, "Base delay for trigger retry exponential backoff")
+	fs.DurationVar(&config.triggerMaxDelay, "trigger_max_delay", 10*time.Second, "Maximum delay for trigger retry exponential backoff")
+	fs.IntVar(&config.triggerMaxRuns, "trigger_max_concurrent_runs", 100, "Maximum concurrent trigger runs")
+
+	return &cronLauncher{
+		config: config,
+		flags:  fs,
+	}
+}
+
+// Keyword implements web.Sublauncher. Returns the command-line keyword for cron launcher.
+func (l *cronLauncher) Keyword() string {
+	return "cron"
+}
+
+// Parse parses the command-line arguments for the cron launcher.
+func (l *cronLauncher) Parse(args []string) ([]string, error) {
+	err := l.flags.Parse(args)
+	if err != nil || !l.flags.Parsed() {
+		return nil, fmt.Errorf("failed to parse cron flags: %v", err)
+	}
+	if l.config.schedule == "" && l.config.schedulesFile == "" {
+		return nil, fmt.Errorf("either schedule or schedules_file is required")
+	}
+	if l.config.schedule != "" && l.config.message == "" {
+		return nil, fmt.Errorf("message is required with schedule")
+	}
+	if l.config.triggerMaxRetries <= 0 {
+		return nil, fmt.Errorf("trigger_max_retries must be > 0")
+	}
+	if l.config.triggerBaseDelay < 0 {
+		return nil, fmt.Errorf("trigger_base_delay must be >= 0")
+	}
+	if l.config.triggerMaxDelay <= 0 {
+		return nil, fmt.Errorf("trigger_max_delay must be > 0")
+	}
+	if l.config.triggerMaxRuns <= 0 {
+		return nil, fmt.Errorf("trigger_max_concurrent_runs must be > 0")
+	}
+	return l.flags.Args(), nil
+}
+
+// CommandLineSyntax returns the command-line syntax for the cron launcher.
+func (l *cronLauncher) CommandLineSyntax() string {
+	return util.FormatFlagUsage(l.flags)
+}
+
+// SimpleDescription implements web.Sublauncher.
+func (l *cronLauncher) SimpleDescription() string {
+	return "runs agents on a schedule"
+}
+
+// SetupSubrouters implements web.Sublauncher. It doesn't add routes, but prepares the scheduler
+// started with RunBackground.
+func (l *cronLauncher) SetupSubrouters(router *mux.Router, config *launcher.Config) error {
+	schedules, err := l.schedules(config)
+	if err != nil {
+		return err
+	}
+	l.scheduler, err = triggers.NewScheduler(
+		config.SessionService,
+		config.AgentLoader,
+		config.MemoryService,
+		config.ArtifactService,
+		config.PluginConfig,
+		triggers.TriggerConfig{
+			MaxRetries:        l.config.triggerMaxRetries,
+			BaseDelay:         l.config.triggerBaseDelay,
+			MaxDelay:          l.config.triggerMaxDelay,
+			MaxConcurrentRuns: l.config.triggerMaxRuns,
+			RateLimiter:       config.RateLimiter,
+		},
+		schedules,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create scheduler: %w", err)
+	}
+	return nil
+}
+
+// schedules collects schedules from the command line and the schedules file.
+func (l *cronLauncher) schedules(config *launcher.Config) ([]triggers.Schedule, error) {
+	defaultAppName := l.config.appName
+	if defaultAppName == "" && config.AgentLoader != nil {
+		defaultAppName = config.AgentLoader.RootAgent().Name()
+	}
+	defaultLocation, err := loadLocation(l.config.timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedules []triggers.Schedule
+	if l.config.schedule != "" {
+		schedules = append(schedules, triggers.Schedule{
+			AppName:   defaultAppName,
+			Spec:      l.config.schedule,
+			Location:  defaultLocation,
+			Message:   l.config.message,
+			UserID:    l.config.userID,
+			SessionID: l.config.sessionID,
+		})
+	}
+	if l.config.schedulesFile == "" {
+		return schedules, nil
+	}
+
+	data, err := os.ReadFile(l.config.schedulesFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedules file: %w", err)
+	}
+	var file schedulesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse schedules file: %w", err)
+	}
+	for _, entry := range file.Schedules {
+		location := defaultLocation
+		if entry.Timezone != "" {
+			if location, err = loadLocation(entry.Timezone); err != nil {
+				return nil, err
+			}
+		}
+		appName := entry.AppName
+		if appName == "" {
+			appName = defaultAppName
+		}
+		schedules = append(schedules, triggers.Schedule{
+			Name:      entry.Name,
+			AppName:   appName,
+			Spec:      entry.Spec,
+			Location:  location,
+			Message:   entry.Message,
+			UserID:    entry.UserID,
+			SessionID: entry.SessionID,
+		})
+	}
+	return schedules, nil
+}
+
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+	return location, nil
+}
+
+// RunBackground implements web.BackgroundSublauncher. It runs agents on their schedules.
+func (l *cronLauncher) RunBackground(ctx context.Context) error {
+	if l.scheduler == nil {
+		return fmt.Errorf("scheduler is not set up")
+	}
+	return l.scheduler.Run(ctx)
+}
+
+// UserMessage implements web.Sublauncher.
+func (l *cronLauncher) UserMessage(webURL string, printer func(v ...any)) {
+	printer("       cron:  agents run on configured schedules")
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cron
+
+import (
+	"iter"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/cmd/launcher"
+	"google.golang.org/adk/v2/session"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "schedule", args: []string{"-schedule=@every 1h", "-message=tick"}},
+		{name: "schedules file", args: []string{"-schedules_file=schedules.yaml"}},
+		{name: "no schedules", args: []string{}, wantErr: true},
+		{name: "no message", args: []string{"-schedule=@hourly"}, wantErr: true},
+		{name: "invalid retry count", args: []string{"-schedule=@hourly", "-message=tick", "-trigger_max_retries=0"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLauncher().(*cronLauncher)
+			if _, err := l.Parse(tt.args); (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchedules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules.yaml")
+	err := os.WriteFile(path, []byte(`
+schedules:
+  - name: report
+    spec: "0 9 * * MON-FRI"
+    timezone: UTC
+    message: Prepare the report
+    session_id: reports
+  - app_name: other
+    spec: "@every 15m"
+    message: Check the queue
+    user_id: ops
+`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	l := NewLauncher().(*cronLauncher)
+	if _, err := l.Parse([]string{"-schedules_file=" + path, "-schedule=@hourly", "-message=tick"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	rootAgent, err := agent.New(agent.Config{
+		Name: "root",
+		Run: func(agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(func(*session.Event, error) bool) {}
+		},
+	})
+	if err != nil {
+		t.Fatalf("agent.New() error = %v", err)
+	}
+	config := &launcher.Config{SessionService: session.InMemoryService(), AgentLoader: agent.NewSingleLoader(rootAgent)}
+
+	schedules, err := l.schedules(config)
+	if err != nil {
+		t.Fatalf("schedules() error = %v", err)
+	}
+	if len(schedules) != 3 {
+		t.Fatalf("schedules() = %d schedules, want 3", len(schedules))
+	}
+	if schedules[0].AppName != "root" || schedules[0].Spec != "@hourly" {
+		t.Errorf("command line schedule = %+v, want root agent run @hourly", schedules[0])
+	}
+	if schedules[1].Name != "report" || schedules[1].AppName != "root" || schedules[1].SessionID != "reports" || schedules[1].Location.String() != "UTC" {
+		t.Errorf("file schedule = %+v, want report of root agent in session reports", schedules[1])
+	}
+	if schedules[2].AppName != "other" || schedules[2].UserID != "ops" {
+		t.Errorf("file schedule = %+v, want other agent run for ops", schedules[2])
+	}
+
+	if err := l.SetupSubrouters(mux.NewRouter(), config); err != nil {
+		t.Errorf("SetupSubrouters() error = %v", err)
+	}
+}
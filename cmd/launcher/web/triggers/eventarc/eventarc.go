@@ -110,6 +110,7 @@ func (e *eventarcLauncher) SetupSubrouters(router *mux.Router, config *launcher.
 		BaseDelay:         e.config.triggerBaseDelay,
 		MaxDelay:          e.config.triggerMaxDelay,
 		MaxConcurrentRuns: e.config.triggerMaxRuns,
+		RateLimiter:       config.RateLimiter,
 	}
 
 	controller := triggers.NewEventarcController(
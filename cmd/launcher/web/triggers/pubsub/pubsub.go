@@ -110,6 +110,7 @@ func (p *pubsubLauncher) SetupSubrouters(router *mux.Router, config *launcher.Co
 		BaseDelay:         p.config.triggerBaseDelay,
 		MaxDelay:          p.config.triggerMaxDelay,
 		MaxConcurrentRuns: p.config.triggerMaxRuns,
+		RateLimiter:       config.RateLimiter,
 	}
 
 	controller := triggers.NewPubSubController(
@@ -0,0 +1,185 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook provides a sublauncher that adds a generic HTTP webhook trigger to ADK web server.
+package webhook
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/v2/cmd/launcher"
+	"google.golang.org/adk/v2/cmd/launcher/web"
+	"google.golang.org/adk/v2/internal/cli/util"
+	"google.golang.org/adk/v2/server/adkrest/controllers/triggers"
+)
+
+type webhookConfig struct {
+	pathPrefix        string
+	triggerMaxRetries int
+	triggerBaseDelay  time.Duration
+	triggerMaxDelay   time.Duration
+	triggerMaxRuns    int
+	messageTemplate   string
+	userIDTemplate    string
+	secretEnv         string
+	insecure          bool
+	signatureHeader   string
+	idempotencyHeader string
+	idempotencyTTL    time.Duration
+	maxBodyBytes      int64
+}
+
+type webhookLauncher struct {
+	flags  *flag.FlagSet
+	config *webhookConfig
+}
+
+// NewLauncher creates a new webhook launcher. It extends Web launcher.
+func NewLauncher() web.Sublauncher {
+	config := &webhookConfig{}
+
+	fs := flag.NewFlagSet("webhook", flag.ContinueOnError)
+	fs.StringVar(&config.pathPrefix, "path_prefix", "/api", "Path prefix for the webhook trigger endpoint. Default is '/api'.")
+	fs.IntVar(&config.triggerMaxRetries, "trigger_max_retries", 3, "Maximum retries for HTTP 429 errors from triggers")
+	fs.DurationVar(&config.triggerBaseDelay, "trigger_base_delay", 1*time.Second, "Base delay for trigger retry exponential backoff")
+	fs.DurationVar(&config.triggerMaxDelay, "trigger_max_delay", 10*time.Second, "Maximum delay for trigger retry exponential backoff")
+	fs.IntVar(&config.triggerMaxRuns, "trigger_max_concurrent_runs", 100, "Maximum concurrent trigger runs")
+	fs.StringVar(&config.messageTemplate, "message_template", "", "Go text/template rendering the agent message from the request, e.g. '{{.Body.issue.title}}'. Templates can use .Body, .RawBody, .Headers, .Query and .AppName. Defaults to the raw request body.")
+	fs.StringVar(&config.userIDTemplate, "user_id_template", "", "Go text/template rendering the user ID from the request. Defaults to 'webhook-caller'.")
+	fs.StringVar(&config.secretEnv, "secret_env", "", "Name of the environment variable holding the HMAC-SHA256 secret used to verify request signatures. Required unless -insecure is set.")
+	fs.BoolVar(&config.insecure, "insecure", false, "Accept unsigned requests when no secret is set, letting anyone who can reach the endpoint run the agent.")
+	fs.StringVar(&config.signatureHeader, "signature_header", "X-Signature-256", "Header carrying the hex-encoded HMAC-SHA256 signature of the request body, optionally prefixed with 'sha256='.")
+	fs.StringVar(&config.idempotencyHeader, "idempotency_header", "Idempotency-Key", "Header carrying the idempotency key of the request.")
+	fs.DurationVar(&config.idempotencyTTL, "idempotency_ttl", 24*time.Hour, "How long idempotency keys of successful runs are remembered.")
+	fs.Int64Var(&config.maxBodyBytes, "max_body_bytes", 1<<20, "Maximum size of request bodies in bytes.")
+
+	return &webhookLauncher{
+		config: config,
+		flags:  fs,
+	}
+}
+
+// Keyword implements web.Sublauncher. Returns the command-line keyword for webhook launcher.
+func (l *webhookLauncher) Keyword() string {
+	return "webhook"
+}
+
+// Parse parses the command-line arguments for the webhook launcher.
+func (l *webhookLauncher) Parse(args []string) ([]string, error) {
+	err := l.flags.Parse(args)
+	if err != nil || !l.flags.Parsed() {
+		return nil, fmt.Errorf("failed to parse webhook flags: %v", err)
+	}
+	if l.config.triggerMaxRetries <= 0 {
+		return nil, fmt.Errorf("trigger_max_retries must be > 0")
+	}
+	if l.config.triggerBaseDelay < 0 {
+		return nil, fmt.Errorf("trigger_base_delay must be >= 0")
+	}
+	if l.config.triggerMaxDelay <= 0 {
+		return nil, fmt.Errorf("trigger_max_delay must be > 0")
+	}
+	if l.config.triggerMaxRuns <= 0 {
+		return nil, fmt.Errorf("trigger_max_concurrent_runs must be > 0")
+	}
+	if l.config.idempotencyTTL <= 0 {
+		return nil, fmt.Errorf("idempotency_ttl must be > 0")
+	}
+	if l.config.maxBodyBytes <= 0 {
+		return nil, fmt.Errorf("max_body_bytes must be > 0")
+	}
+	if l.config.secretEnv == "" && !l.config.insecure {
+		return nil, fmt.Errorf("secret_env is required unless -insecure is set")
+	}
+
+	prefix := l.config.pathPrefix
+	if !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	l.config.pathPrefix = strings.TrimSuffix(prefix, "/")
+
+	return l.flags.Args(), nil
+}
+
+// CommandLineSyntax returns the command-line syntax for the webhook launcher.
+func (l *webhookLauncher) CommandLineSyntax() string {
+	return util.FormatFlagUsage(l.flags)
+}
+
+// SimpleDescription implements web.Sublauncher.
+func (l *webhookLauncher) SimpleDescription() string {
+	return "starts ADK generic webhook trigger endpoint server"
+}
+
+// SetupSubrouters adds the webhook trigger endpoint to the parent router.
+func (l *webhookLauncher) SetupSubrouters(router *mux.Router, config *launcher.Config) error {
+	triggerConfig := triggers.TriggerConfig{
+		MaxRetries:        l.config.triggerMaxRetries,
+		BaseDelay:         l.config.triggerBaseDelay,
+		MaxDelay:          l.config.triggerMaxDelay,
+		MaxConcurrentRuns: l.config.triggerMaxRuns,
+		RateLimiter:       config.RateLimiter,
+	}
+
+	var secret []byte
+	if l.config.secretEnv != "" {
+		value := os.Getenv(l.config.secretEnv)
+		if value == "" {
+			return fmt.Errorf("webhook secret environment variable %s is not set", l.config.secretEnv)
+		}
+		secret = []byte(value)
+	}
+
+	controller, err := triggers.NewWebhookController(
+		config.SessionService,
+		config.AgentLoader,
+		config.MemoryService,
+		config.ArtifactService,
+		config.PluginConfig,
+		triggerConfig,
+		triggers.WebhookConfig{
+			MessageTemplate:   l.config.messageTemplate,
+			UserIDTemplate:    l.config.userIDTemplate,
+			Secret:            secret,
+			Insecure:          l.config.insecure,
+			SignatureHeader:   l.config.signatureHeader,
+			IdempotencyHeader: l.config.idempotencyHeader,
+			IdempotencyTTL:    l.config.idempotencyTTL,
+			MaxBodyBytes:      l.config.maxBodyBytes,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook controller: %w", err)
+	}
+
+	subrouter := router
+	if l.config.pathPrefix != "" && l.config.pathPrefix != "/" {
+		subrouter = router.PathPrefix(l.config.pathPrefix).Subrouter()
+	}
+
+	subrouter.HandleFunc("/apps/{app_name}/trigger/webhook", controller.WebhookTriggerHandler).Methods(http.MethodPost)
+	return nil
+}
+
+// UserMessage implements web.Sublauncher.
+func (l *webhookLauncher) UserMessage(webURL string, printer func(v ...any)) {
+	printer(fmt.Sprintf("       webhook:  webhook trigger endpoint is available at %s%s/apps/{app_name}/trigger/webhook", webURL, l.config.pathPrefix))
+}
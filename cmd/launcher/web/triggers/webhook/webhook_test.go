@@ -0,0 +1,103 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/v2/cmd/launcher"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantPrefix string
+		wantErr    bool
+	}{
+		{
+			name:       "default values",
+			args:       []string{"-secret_env=WEBHOOK_SECRET"},
+			wantPrefix: "/api",
+		},
+		{
+			name:       "custom prefix and template",
+			args:       []string{"-insecure", "-path_prefix=hooks/", "-message_template={{.Body.text}}"},
+			wantPrefix: "/hooks",
+		},
+		{
+			name:    "invalid idempotency ttl",
+			args:    []string{"-insecure", "-idempotency_ttl=0s"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid max body bytes",
+			args:    []string{"-insecure", "-max_body_bytes=0"},
+			wantErr: true,
+		},
+		{
+			name:    "no secret without insecure",
+			args:    []string{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLauncher().(*webhookLauncher)
+			_, err := l.Parse(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if l.config.pathPrefix != tt.wantPrefix {
+				t.Errorf("Parse() pathPrefix = %v, want %v", l.config.pathPrefix, tt.wantPrefix)
+			}
+		})
+	}
+}
+
+func TestSetupSubrouters(t *testing.T) {
+	l := NewLauncher().(*webhookLauncher)
+	_, _ = l.Parse([]string{"-insecure", "-path_prefix=/api"})
+
+	router := mux.NewRouter()
+	if err := l.SetupSubrouters(router, &launcher.Config{}); err != nil {
+		t.Fatalf("SetupSubrouters() failed: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/apps/my-app/trigger/webhook", nil)
+	var match mux.RouteMatch
+	if !router.Match(req, &match) {
+		t.Errorf("SetupSubrouters() did not register expected route")
+	}
+}
+
+func TestSetupSubrouters_MissingSecret(t *testing.T) {
+	t.Setenv("ADK_TEST_WEBHOOK_SECRET", "")
+	l := NewLauncher().(*webhookLauncher)
+	if _, err := l.Parse([]string{"-secret_env=ADK_TEST_WEBHOOK_SECRET"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if err := l.SetupSubrouters(mux.NewRouter(), &launcher.Config{}); err == nil {
+		t.Error("SetupSubrouters() error = nil, want error for unset secret")
+	}
+}
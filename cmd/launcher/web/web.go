@@ -86,6 +86,15 @@ type Sublauncher interface {
 	UserMessage(webURL string, printer func(v ...any))
 }
 
+// BackgroundSublauncher is implemented by sublaunchers which do work outside of request handling,
+// such as running agents on a schedule.
+type BackgroundSublauncher interface {
+	Sublauncher
+	// RunBackground is started after SetupSubrouters and runs for as long as the web server.
+	// It must return when ctx is done.
+	RunBackground(ctx context.Context) error
+}
+
 // CommandLineSyntax implements launcher.Launcher.
 func (w *webLauncher) CommandLineSyntax() string {
 	var b strings.Builder
@@ -198,6 +207,19 @@ func (w *webLauncher) Run(ctx context.Context, config *launcher.Config) error {
 		return fmt.Errorf("telemetry initialization failed: %v", err)
 	}
 
+	backgroundCtx, cancelBackground := context.WithCancel(ctx)
+	defer cancelBackground()
+	backgroundErrChan := make(chan error, len(w.activeSublaunchers))
+	for _, l := range w.activeSublaunchers {
+		if bl, ok := l.(BackgroundSublauncher); ok {
+			go func() {
+				if err := bl.RunBackground(backgroundCtx); err != nil && !errors.Is(err, context.Canceled) {
+					backgroundErrChan <- fmt.Errorf("%s background task failed: %w", bl.Keyword(), err)
+				}
+			}()
+		}
+	}
+
 	select {
 	case <-ctx.Done():
 		log.Println("Shutting down the web server...")
@@ -206,6 +228,10 @@ func (w *webLauncher) Run(ctx context.Context, config *launcher.Config) error {
 		serverErr := srv.Shutdown(shutdownCtx)
 		telemetryErr := telemetryService.Shutdown(shutdownCtx)
 		return errors.Join(serverErr, telemetryErr)
+	case err := <-backgroundErrChan:
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), w.config.shutdownTimeout)
+		defer cancel()
+		return errors.Join(err, srv.Shutdown(shutdownCtx), telemetryService.Shutdown(shutdownCtx))
 	case err, ok := <-errChan:
 		if !ok {
 			return nil
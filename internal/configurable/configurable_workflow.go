@@ -126,7 +126,7 @@ func parseEdges(ctx context.Context, parentPath string, nodes []yaml.Node) ([]wo
 		}
 
 		var chainNodes []workflow.Node
-		var routeMaps []map[string]string
+		var routes []routeYAMLConfig
 
 		for _, item := range edgeNode.Content {
 			switch item.Kind {
@@ -137,17 +137,17 @@ func parseEdges(ctx context.Context, parentPath string, nodes []yaml.Node) ([]wo
 				}
 				chainNodes = append(chainNodes, n)
 			case yaml.MappingNode:
-				var m map[string]string
-				if err := item.Decode(&m); err != nil {
-					return nil, fmt.Errorf("invalid route map format: %w", err)
+				r, err := parseRoutes(item)
+				if err != nil {
+					return nil, err
 				}
-				routeMaps = append(routeMaps, m)
+				routes = append(routes, r...)
 			default:
 				return nil, fmt.Errorf("unsupported YAML node kind in edge chain: %v", item.Kind)
 			}
 		}
 
-		if len(routeMaps) == 0 {
+		if len(routes) == 0 {
 			if len(chainNodes) < 2 {
 				return nil, fmt.Errorf("workflow edge chain must have at least 2 nodes")
 			}
@@ -162,26 +162,17 @@ func parseEdges(ctx context.Context, parentPath string, nodes []yaml.Node) ([]wo
 
 			routerNode := chainNodes[len(chainNodes)-1]
 
-			for _, routeMap := range routeMaps {
-				for routeVal, targetRef := range routeMap {
-					targetNode, err := resolveNodeLike(ctx, parentPath, targetRef)
-					if err != nil {
-						return nil, err
-					}
-
-					var route workflow.Route
-					if strings.EqualFold(routeVal, "default") {
-						route = workflow.Default
-					} else {
-						route = workflow.StringRoute(routeVal)
-					}
-
-					edges = append(edges, workflow.Edge{
-						From:  routerNode,
-						To:    targetNode,
-						Route: route,
-					})
+			for _, r := range routes {
+				targetNode, err := resolveNodeLike(ctx, parentPath, r.target)
+				if err != nil {
+					return nil, err
 				}
+
+				edges = append(edges, workflow.Edge{
+					From:  routerNode,
+					To:    targetNode,
+					Route: r.route,
+				})
 			}
 		}
 	}
@@ -189,6 +180,49 @@ func parseEdges(ctx context.Context, parentPath string, nodes []yaml.Node) ([]wo
 	return edges, nil
 }
 
+// routeYAMLConfig is a routed edge of an edge chain: the route and
+// the reference to its target node.
+type routeYAMLConfig struct {
+	route  workflow.Route
+	target string
+}
+
+// parseRoutes converts a route map of an edge chain into routes. A map
+// either maps route values, or "default", to targets:
+//
+//	ALPHA: agent_alpha.yaml
+//	default: fallback_fn
+//
+// or holds exactly the keys "when" and "to", routing to the target when
+// the expression holds (see workflow.ExprRoute):
+//
+//	when: output.score > 0.8 && state.tier == "gold"
+//	to: escalate_fn
+func parseRoutes(item *yaml.Node) ([]routeYAMLConfig, error) {
+	var m map[string]string
+	if err := item.Decode(&m); err != nil {
+		return nil, fmt.Errorf("invalid route map format: %w", err)
+	}
+
+	expr, hasWhen := m["when"]
+	target, hasTo := m["to"]
+	if hasWhen && hasTo && len(m) == 2 {
+		return []routeYAMLConfig{{route: workflow.ExprRoute(expr), target: target}}, nil
+	}
+
+	var routes []routeYAMLConfig
+	for routeVal, targetRef := range m {
+		var route workflow.Route
+		if strings.EqualFold(routeVal, "default") {
+			route = workflow.Default
+		} else {
+			route = workflow.StringRoute(routeVal)
+		}
+		routes = append(routes, routeYAMLConfig{route: route, target: targetRef})
+	}
+	return routes, nil
+}
+
 // resolveNodeLike maps a YAML identifier to a concrete workflow.Node.
 func resolveNodeLike(ctx context.Context, parentPath, ref string) (workflow.Node, error) {
 	if ref == "START" {
@@ -16,6 +16,7 @@ package configurable
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"iter"
 	"os"
@@ -28,6 +29,7 @@ import (
 	"google.golang.org/adk/v2/agent"
 	"google.golang.org/adk/v2/session"
 	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/workflow"
 )
 
 type mockSession struct{}
@@ -242,6 +244,74 @@ edges:
 	}
 }
 
+func TestLoadWorkflowWithExpressionRoutesYAML(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "expr_workflow.yaml")
+
+	yamlContent := `
+name: expr_wf
+agent_class: Workflow
+edges:
+  - - START
+    - upper_fn
+    - when: output.startsWith("ROUT") && size(output) > 4
+      to: suffix_fn
+    - when: output == "ALPHA"
+      to: alpha_fn
+`
+	if err := os.WriteFile(configPath, []byte(yamlContent), 0o644); err != nil {
+		t.Fatalf("failed to write temp expression workflow: %v", err)
+	}
+
+	ag, err := FromConfig(t.Context(), configPath)
+	if err != nil {
+		t.Fatalf("FromConfig failed: %v", err)
+	}
+
+	for input, want := range map[string]string{"routing": "ROUTING done", "alpha": "alpha: ALPHA"} {
+		mockCtx := &MockInvocationContext{
+			Context: t.Context(),
+			sess:    &mockSession{},
+			userContent: &genai.Content{
+				Parts: []*genai.Part{{Text: input}},
+			},
+		}
+		var outputs []any
+		for ev, err := range ag.Run(mockCtx) {
+			if err != nil {
+				t.Fatalf("run failed for %q: %v", input, err)
+			}
+			if ev.Output != nil {
+				outputs = append(outputs, ev.Output)
+			}
+		}
+		if len(outputs) != 2 || outputs[1] != want {
+			t.Errorf("outputs for %q = %+v, want final output %q", input, outputs, want)
+		}
+	}
+}
+
+func TestLoadWorkflowWithInvalidExpressionRouteYAML(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "bad_expr_workflow.yaml")
+	yamlContent := `
+name: bad_expr_wf
+agent_class: Workflow
+edges:
+  - - START
+    - upper_fn
+    - when: output ==
+      to: suffix_fn
+`
+	if err := os.WriteFile(configPath, []byte(yamlContent), 0o644); err != nil {
+		t.Fatalf("failed to write temp expression workflow: %v", err)
+	}
+
+	_, err := FromConfig(t.Context(), configPath)
+	if !errors.Is(err, workflow.ErrInvalidExpression) {
+		t.Errorf("FromConfig() error = %v, want %v", err, workflow.ErrInvalidExpression)
+	}
+}
+
 func alphaFn(ctx agent.Context, input string) (string, error) {
 	return "alpha: " + input, nil
 }
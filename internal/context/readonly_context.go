@@ -70,3 +70,8 @@ func (c *ReadonlyContext) InvocationID() string {
 func (c *ReadonlyContext) UserContent() *genai.Content {
 	return c.InvocationContext.UserContent()
 }
+
+func (c *ReadonlyContext) Principal() *agent.Principal {
+	principal, _ := agent.PrincipalFromContext(c)
+	return principal
+}
@@ -73,6 +73,11 @@ type Flow struct {
 	BeforeToolCallbacks   []BeforeToolCallback
 	AfterToolCallbacks    []AfterToolCallback
 	OnToolErrorCallbacks  []OnToolErrorCallback
+
+	// ToolExecution controls how the function calls are executed.
+	ToolExecution ToolExecution
+	// ToolLimits holds the semaphores of the tools with a MaxConcurrency.
+	ToolLimits *ToolLimits
 }
 
 var (
@@ -1059,7 +1064,6 @@ func (c *cancelledToolContext) Value(key any) any {
 // handleFunctionCalls calls the functions and returns the function response event.
 //
 // TODO: accept filters to include/exclude function calls.
-// TODO: check feasibility of running tool.Run concurrently.
 func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[string]tool.Tool, resp *model.LLMResponse, toolConfirmations map[string]*toolconfirmation.ToolConfirmation, liveSess agent.LiveSession) (mergedEvent *session.Event, err error) {
 	fnCalls := utils.FunctionCalls(resp.Content)
 	toolNames := slices.Collect(maps.Keys(toolsDict))
@@ -1086,6 +1090,28 @@ func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[st
 				Args:     fnCall.Args,
 			})
 			defer span.End()
+
+			// Wait for the concurrency limits of the function tools, and
+			// set the deadline of their calls.
+			release, timeout := func() {}, time.Duration(0)
+			if funcTool, ok := limitedTool(toolsDict, fnCall.Name); ok {
+				opts := tool.ExecutionOptionsOf(funcTool)
+				timeout = opts.Timeout
+				if timeout <= 0 {
+					timeout = f.ToolExecution.Timeout
+				}
+				// On cancellation, call the tool anyway to get its response.
+				if r, err := f.acquireToolSlots(sctx, funcTool, opts); err == nil {
+					release = r
+				}
+				if timeout > 0 {
+					var cancel context.CancelFunc
+					sctx, cancel = context.WithTimeout(sctx, timeout)
+					defer cancel()
+				}
+			}
+			defer func() { release() }()
+
 			toolCallCtx := ctx.WithContext(sctx)
 			var confirmation *toolconfirmation.ToolConfirmation
 			if toolConfirmations != nil {
@@ -1095,6 +1121,8 @@ func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[st
 
 			var result map[string]any
 			var curTool tool.Tool
+			// abandoned is set when the call timed out while still running.
+			var abandoned bool
 			if fnCall.Name == "stop_streaming" {
 				funcToStop, _ := fnCall.Args["function_name"].(string)
 				var status string
@@ -1174,6 +1202,9 @@ func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[st
 					if err != nil {
 						result = map[string]any{"error": err.Error()}
 					}
+				} else if timeout > 0 {
+					result, abandoned = f.callToolWithTimeout(toolCtx, funcTool, fnCall.Args, timeout, release)
+					release = func() {}
 				} else {
 					result = f.callTool(toolCtx, funcTool, fnCall.Args)
 				}
@@ -1188,6 +1219,11 @@ func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[st
 				}
 			}
 
+			responseParts, _ := result[toolinternal.ResponsePartsKey].([]*genai.FunctionResponsePart)
+			delete(result, toolinternal.ResponsePartsKey)
+			cacheHit, _ := result[toolinternal.CacheHitKey].(bool)
+			delete(result, toolinternal.CacheHitKey)
+
 			ev := session.NewEvent(ctx, ctx.InvocationID())
 			ev.LLMResponse = model.LLMResponse{
 				Content: &genai.Content{
@@ -1198,14 +1234,21 @@ func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[st
 								ID:       fnCall.ID,
 								Name:     fnCall.Name,
 								Response: result,
+								Parts:    responseParts,
 							},
 						},
 					},
 				},
 			}
+			if cacheHit {
+				ev.CustomMetadata = map[string]any{toolinternal.CacheHitsMetadataKey: []string{fnCall.ID}}
+			}
 			ev.Author = ctx.Agent().Name()
 			ev.Branch = ctx.Branch()
-			ev.Actions = *toolCtx.Actions()
+			if !abandoned {
+				// The actions of abandoned calls may still be changing.
+				ev.Actions = *toolCtx.Actions()
+			}
 
 			traceTool := curTool
 			if traceTool == nil {
@@ -1229,7 +1272,7 @@ func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[st
 			fnResponseEvents[i] = ev
 		}
 	}
-	platform.RunTasks(ctx, tasks)
+	f.runToolTasks(ctx, fnCalls, toolsDict, tasks)
 	mergedEvent, err = mergeParallelFunctionResponseEvents(fnResponseEvents)
 	if err != nil {
 		return mergedEvent, err
@@ -1351,6 +1394,7 @@ func mergeParallelFunctionResponseEvents(events []*session.Event) (*session.Even
 	}
 	var parts []*genai.Part
 	var actions *session.EventActions
+	var cacheHits []string
 	var result *session.Event // first non-nil event, reused as the merged result
 	for _, ev := range events {
 		if ev == nil || ev.LLMResponse.Content == nil {
@@ -1360,6 +1404,8 @@ func mergeParallelFunctionResponseEvents(events []*session.Event) (*session.Even
 			result = ev
 		}
 		parts = append(parts, ev.LLMResponse.Content.Parts...)
+		hits, _ := ev.CustomMetadata[toolinternal.CacheHitsMetadataKey].([]string)
+		cacheHits = append(cacheHits, hits...)
 		actions = mergeEventActions(actions, &ev.Actions)
 	}
 	// All entries were nil (e.g. every call was long-running/deferred).
@@ -1372,6 +1418,9 @@ func mergeParallelFunctionResponseEvents(events []*session.Event) (*session.Even
 			Parts: parts,
 		},
 	}
+	if len(cacheHits) > 0 {
+		result.CustomMetadata = map[string]any{toolinternal.CacheHitsMetadataKey: cacheHits}
+	}
 	result.Actions = *actions
 	return result, nil
 }
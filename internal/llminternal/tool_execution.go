@@ -0,0 +1,172 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/platform"
+	"google.golang.org/adk/v2/tool"
+)
+
+// ToolExecution controls how the function calls of a model response are
+// executed.
+type ToolExecution struct {
+	// Sequential runs the function calls one at a time, in the order of
+	// the response, instead of in parallel.
+	Sequential bool
+	// MaxParallelCalls, if positive, bounds the number of function calls
+	// of a response running at once.
+	MaxParallelCalls int
+	// Timeout, if positive, bounds the duration of the calls of the tools
+	// without a timeout of their own.
+	Timeout time.Duration
+}
+
+// ToolLimits holds the semaphores of the tools with a MaxConcurrency,
+// shared by the invocations of an agent.
+type ToolLimits struct {
+	mu         sync.Mutex
+	semaphores map[string]toolinternal.Semaphore
+}
+
+// semaphore returns the semaphore of the calls of the named tool, letting
+// n calls run at once.
+func (l *ToolLimits) semaphore(name string, n int) toolinternal.Semaphore {
+	if l == nil || n <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if s, ok := l.semaphores[name]; ok && cap(s) == n {
+		return s
+	}
+	// The limit of the tool changed: the calls holding the previous
+	// semaphore release it.
+	if l.semaphores == nil {
+		l.semaphores = map[string]toolinternal.Semaphore{}
+	}
+	s := toolinternal.NewSemaphore(n)
+	l.semaphores[name] = s
+	return s
+}
+
+// runToolTasks runs the tasks calling the function calls of a response:
+// in parallel, except for the calls of the sequential tools, which run
+// alone, in the order of the response.
+func (f *Flow) runToolTasks(ctx context.Context, fnCalls []*genai.FunctionCall, toolsDict map[string]tool.Tool, tasks []func(context.Context)) {
+	if f.ToolExecution.Sequential {
+		for _, task := range tasks {
+			task(ctx)
+		}
+		return
+	}
+	limit := toolinternal.NewSemaphore(f.ToolExecution.MaxParallelCalls)
+	var batch []func(context.Context)
+	for i, task := range tasks {
+		if tool.ExecutionOptionsOf(toolsDict[fnCalls[i].Name]).Sequential {
+			platform.RunTasks(ctx, batch)
+			batch = nil
+			task(ctx)
+			continue
+		}
+		batch = append(batch, func(taskCtx context.Context) {
+			// On cancellation, run the task anyway to get its response.
+			if err := limit.Acquire(taskCtx); err == nil {
+				defer limit.Release()
+			}
+			task(taskCtx)
+		})
+	}
+	platform.RunTasks(ctx, batch)
+}
+
+// limitedTool returns the function tool of a call, if its execution is
+// controlled by the execution options: streaming tools aren't.
+func limitedTool(toolsDict map[string]tool.Tool, name string) (toolinternal.FunctionTool, bool) {
+	t, ok := toolsDict[name].(toolinternal.FunctionTool)
+	if !ok || name == "stop_streaming" {
+		return nil, false
+	}
+	if _, streaming := t.(toolinternal.StreamingFunctionTool); streaming {
+		return nil, false
+	}
+	return t, true
+}
+
+// acquireToolSlots waits for the runner and the tool to let the call of
+// the tool run, and returns the function releasing its slots.
+func (f *Flow) acquireToolSlots(ctx context.Context, t tool.Tool, opts tool.ExecutionOptions) (release func(), err error) {
+	global := toolinternal.CallSemaphoreFromContext(ctx)
+	if err := global.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	perTool := f.ToolLimits.semaphore(t.Name(), opts.MaxConcurrency)
+	if err := perTool.Acquire(ctx); err != nil {
+		global.Release()
+		return nil, err
+	}
+	return func() {
+		perTool.Release()
+		global.Release()
+	}, nil
+}
+
+// callToolWithTimeout calls the tool, giving up when toolCtx, carrying the
+// deadline of the call, times out. The call then keeps running in the
+// background, until it honors the cancellation of its context; release is
+// called when it returns. abandoned reports whether the call timed out
+// while still running.
+func (f *Flow) callToolWithTimeout(toolCtx agent.Context, t toolinternal.FunctionTool, args map[string]any, timeout time.Duration, release func()) (result map[string]any, abandoned bool) {
+	done := make(chan map[string]any, 1)
+	go func() {
+		defer release()
+		done <- f.callTool(toolCtx, t, args)
+	}()
+	select {
+	case result = <-done:
+	case <-toolCtx.Done():
+		if !errors.Is(toolCtx.Err(), context.DeadlineExceeded) {
+			// Cancelled: the tool returns on its own.
+			return <-done, false
+		}
+		select {
+		case result = <-done:
+		default:
+			return timeoutResponse(t.Name(), timeout), true
+		}
+	}
+	if errors.Is(toolCtx.Err(), context.DeadlineExceeded) && result["error"] != nil {
+		// The tool failed because of the deadline.
+		return timeoutResponse(t.Name(), timeout), false
+	}
+	return result, false
+}
+
+// timeoutResponse is the function response of the calls which timed out.
+func timeoutResponse(name string, timeout time.Duration) map[string]any {
+	return map[string]any{
+		"error":     fmt.Sprintf("tool %q timed out after %s", name, timeout),
+		"timed_out": true,
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal_test
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/agent/llmagent"
+	"google.golang.org/adk/v2/internal/testutil"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/functiontool"
+)
+
+// concurrencyProbe records the calls running at once.
+type concurrencyProbe struct {
+	mu      sync.Mutex
+	running int
+	max     int
+	// alone records the calls which ran with no other call running.
+	alone map[string]bool
+	order []string
+}
+
+type probeArgs struct {
+	ID string `json:"id"`
+}
+
+func (p *concurrencyProbe) tool(t *testing.T, name string, opts tool.ExecutionOptions) tool.Tool {
+	t.Helper()
+	tl, err := functiontool.New(functiontool.Config{Name: name, Execution: opts}, func(ctx agent.Context, args probeArgs) (map[string]any, error) {
+		p.mu.Lock()
+		p.running++
+		p.max = max(p.max, p.running)
+		p.order = append(p.order, args.ID)
+		p.mu.Unlock()
+		time.Sleep(30 * time.Millisecond)
+		p.mu.Lock()
+		if p.running == 1 && p.max >= 1 {
+			if p.alone == nil {
+				p.alone = map[string]bool{}
+			}
+			p.alone[args.ID] = !slices.Contains(p.order[:len(p.order)-1], args.ID) || p.alone[args.ID]
+		}
+		p.running--
+		p.mu.Unlock()
+		return map[string]any{"id": args.ID}, nil
+	})
+	if err != nil {
+		t.Fatalf("functiontool.New: %v", err)
+	}
+	return tl
+}
+
+func calls(name string, ids ...string) []*genai.Part {
+	var parts []*genai.Part
+	for _, id := range ids {
+		parts = append(parts, &genai.Part{FunctionCall: &genai.FunctionCall{ID: id, Name: name, Args: map[string]any{"id": id}}})
+	}
+	return parts
+}
+
+// runCalls runs an agent whose model makes the calls, and returns the
+// function responses.
+func runCalls(t *testing.T, cfg llmagent.Config, maxConcurrentToolCalls int, parts ...*genai.Part) []*genai.FunctionResponse {
+	t.Helper()
+	cfg.Name = "agent"
+	cfg.Model = &testutil.MockModel{Responses: []*genai.Content{
+		{Role: genai.RoleModel, Parts: parts},
+		genai.NewContentFromText("done", genai.RoleModel),
+	}}
+	a, err := llmagent.New(cfg)
+	if err != nil {
+		t.Fatalf("llmagent.New: %v", err)
+	}
+	sessions := session.InMemoryService()
+	r, err := runner.New(runner.Config{
+		AppName: "app", Agent: a, SessionService: sessions, AutoCreateSession: true,
+		MaxConcurrentToolCalls: maxConcurrentToolCalls,
+	})
+	if err != nil {
+		t.Fatalf("runner.New: %v", err)
+	}
+	var responses []*genai.FunctionResponse
+	for ev, err := range r.Run(t.Context(), "user", "session", genai.NewContentFromText("go", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		for _, p := range ev.Content.Parts {
+			if p.FunctionResponse != nil {
+				responses = append(responses, p.FunctionResponse)
+			}
+		}
+	}
+	return responses
+}
+
+func TestToolExecution_Concurrency(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     llmagent.ToolExecution
+		opts    tool.ExecutionOptions
+		runner  int
+		wantMax int
+		// wantOrdered is set when the calls run in the order of the response.
+		wantOrdered bool
+	}{
+		{name: "parallel by default", wantMax: 4},
+		{name: "sequential agent", cfg: llmagent.ToolExecution{Sequential: true}, wantMax: 1, wantOrdered: true},
+		{name: "max parallel calls", cfg: llmagent.ToolExecution{MaxParallelCalls: 2}, wantMax: 2},
+		{name: "tool max concurrency", opts: tool.ExecutionOptions{MaxConcurrency: 3}, wantMax: 3},
+		{name: "sequential tool", opts: tool.ExecutionOptions{Sequential: true}, wantMax: 1, wantOrdered: true},
+		{name: "runner max concurrent calls", runner: 1, wantMax: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probe := &concurrencyProbe{}
+			ids := []string{"a", "b", "c", "d"}
+			responses := runCalls(t, llmagent.Config{
+				Tools:         []tool.Tool{probe.tool(t, "probe", tt.opts)},
+				ToolExecution: tt.cfg,
+			}, tt.runner, calls("probe", ids...)...)
+
+			if probe.max != tt.wantMax {
+				t.Errorf("ran up to %d calls at once, want %d", probe.max, tt.wantMax)
+			}
+			if tt.wantOrdered && !slices.Equal(probe.order, ids) {
+				t.Errorf("ran the calls in order %v, want %v", probe.order, ids)
+			}
+			// The responses are in the order of the calls.
+			var got []string
+			for _, r := range responses {
+				got = append(got, fmt.Sprint(r.Response["id"]))
+			}
+			if !slices.Equal(got, ids) {
+				t.Errorf("responses = %v, want %v", got, ids)
+			}
+		})
+	}
+}
+
+func TestToolExecution_SequentialTool(t *testing.T) {
+	probe := &concurrencyProbe{}
+	parts := slices.Concat(calls("parallel", "a", "b"), calls("sequential", "c"), calls("parallel", "d", "e"))
+	runCalls(t, llmagent.Config{Tools: []tool.Tool{
+		probe.tool(t, "parallel", tool.ExecutionOptions{}),
+		probe.tool(t, "sequential", tool.ExecutionOptions{Sequential: true}),
+	}}, 0, parts...)
+
+	if probe.max != 2 {
+		t.Errorf("ran up to %d calls at once, want 2", probe.max)
+	}
+	if !probe.alone["c"] {
+		t.Error("the sequential call ran along other calls")
+	}
+	// The sequential call runs after the calls before it, and before those
+	// after it.
+	i := slices.Index(probe.order, "c")
+	if !slices.ContainsFunc(probe.order[:i], func(s string) bool { return s == "a" }) || slices.Contains(probe.order[:i], "d") {
+		t.Errorf("ran the calls in order %v, want c after a and b and before d and e", probe.order)
+	}
+}
+
+func TestToolExecution_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	// honoring returns when its context is cancelled.
+	honoring, err := functiontool.New(functiontool.Config{Name: "honoring"}, func(ctx agent.Context, args probeArgs) (map[string]any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("functiontool.New: %v", err)
+	}
+	// ignoring ignores the cancellation of its context.
+	ignoring, err := functiontool.New(functiontool.Config{Name: "ignoring", Execution: tool.ExecutionOptions{Timeout: 20 * time.Millisecond}}, func(ctx agent.Context, args probeArgs) (map[string]any, error) {
+		ctx.State().Set("key", "value")
+		<-release
+		ctx.State().Set("key", "late")
+		return map[string]any{}, nil
+	})
+	if err != nil {
+		t.Fatalf("functiontool.New: %v", err)
+	}
+	fast, err := functiontool.New(functiontool.Config{Name: "fast"}, func(ctx agent.Context, args probeArgs) (map[string]any, error) {
+		return map[string]any{"ok": true}, nil
+	})
+	if err != nil {
+		t.Fatalf("functiontool.New: %v", err)
+	}
+
+	parts := slices.Concat(calls("honoring", "a"), calls("ignoring", "b"), calls("fast", "c"))
+	responses := runCalls(t, llmagent.Config{
+		Tools:         []tool.Tool{honoring, ignoring, fast},
+		ToolExecution: llmagent.ToolExecution{Timeout: 50 * time.Millisecond},
+	}, 0, parts...)
+
+	got := map[string]map[string]any{}
+	for _, r := range responses {
+		got[r.Name] = r.Response
+	}
+	want := map[string]map[string]any{
+		"honoring": {"error": `tool "honoring" timed out after 50ms`, "timed_out": true},
+		"ignoring": {"error": `tool "ignoring" timed out after 20ms`, "timed_out": true},
+		"fast":     {"ok": true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("responses mismatch (-want +got):\n%s", diff)
+	}
+}
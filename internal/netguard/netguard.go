@@ -0,0 +1,84 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package netguard keeps HTTP clients from reaching addresses which are
+// not publicly routable, such as loopback, private networks and cloud
+// metadata servers.
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// ErrCustomDialer is returned by PublicTransport for transports with a
+// dialer of their own, which it can't guard.
+var ErrCustomDialer = errors.New("transport has a custom dialer")
+
+// PublicTransport returns a copy of transport, http.DefaultTransport
+// without proxy when it's nil, whose connections to addresses which are
+// not publicly routable fail with an error wrapping blocked. The
+// addresses are checked after DNS resolution, so redirects and DNS
+// rebinding are covered too.
+func PublicTransport(transport http.RoundTripper, blocked error) (*http.Transport, error) {
+	var t *http.Transport
+	switch rt := transport.(type) {
+	case nil:
+		t = http.DefaultTransport.(*http.Transport).Clone()
+		t.Proxy = nil
+	case *http.Transport:
+		if rt.DialContext != nil || rt.Dial != nil || rt.DialTLSContext != nil || rt.DialTLS != nil {
+			return nil, ErrCustomDialer
+		}
+		t = rt.Clone()
+	default:
+		return nil, fmt.Errorf("transport of type %T can't be guarded", transport)
+	}
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: invalid address %q", blocked, address)
+			}
+			if addr := addrPort.Addr().Unmap(); !IsPublic(addr) {
+				return fmt.Errorf("%w: %s is not a public address", blocked, addr)
+			}
+			return nil
+		},
+	}
+	t.DialContext = dialer.DialContext
+	return t, nil
+}
+
+// nonPublicPrefixes are the ranges, besides loopback, link-local and
+// private ones, which are not publicly routable: "this network" and the
+// carrier-grade NAT range of RFC 6598.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// IsPublic reports whether addr is publicly routable.
+func IsPublic(addr netip.Addr) bool {
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() &&
+		!slices.ContainsFunc(nonPublicPrefixes, func(p netip.Prefix) bool { return p.Contains(addr) })
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netguard_test
+
+import (
+	"errors"
+	"net/http"
+	"net/netip"
+	"testing"
+
+	"google.golang.org/adk/v2/internal/netguard"
+)
+
+func TestIsPublic(t *testing.T) {
+	testCases := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "8.8.8.8", want: true},
+		{addr: "2001:4860:4860::8888", want: true},
+		{addr: "127.0.0.1"},
+		{addr: "::1"},
+		{addr: "10.1.2.3"},
+		{addr: "192.168.0.1"},
+		{addr: "169.254.169.254"},
+		{addr: "fe80::1"},
+		{addr: "fd00::1"},
+		{addr: "0.0.0.0"},
+		{addr: "100.64.0.1"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.addr, func(t *testing.T) {
+			if got := netguard.IsPublic(netip.MustParseAddr(tc.addr)); got != tc.want {
+				t.Errorf("IsPublic(%s) = %v, want %v", tc.addr, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPublicTransport(t *testing.T) {
+	custom := &http.Transport{DialContext: http.DefaultTransport.(*http.Transport).DialContext}
+	if _, err := netguard.PublicTransport(custom, errors.New("blocked")); !errors.Is(err, netguard.ErrCustomDialer) {
+		t.Errorf("PublicTransport(custom dialer) error = %v, want %v", err, netguard.ErrCustomDialer)
+	}
+
+	blocked := errors.New("blocked")
+	transport, err := netguard.PublicTransport(nil, blocked)
+	if err != nil {
+		t.Fatalf("PublicTransport(nil) error = %v", err)
+	}
+	_, err = (&http.Client{Transport: transport}).Get("http://127.0.0.1:1/")
+	if !errors.Is(err, blocked) {
+		t.Errorf("Get(loopback) error = %v, want %v", err, blocked)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolinternal
+
+import "context"
+
+// Semaphore bounds the number of tool calls running at once. A nil
+// Semaphore doesn't bound them.
+type Semaphore chan struct{}
+
+// NewSemaphore returns a Semaphore letting n calls run at once, or nil if
+// n isn't positive.
+func NewSemaphore(n int) Semaphore {
+	if n <= 0 {
+		return nil
+	}
+	return make(Semaphore, n)
+}
+
+// Acquire waits for a slot, or for ctx to be done.
+func (s Semaphore) Acquire(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot taken by Acquire.
+func (s Semaphore) Release() {
+	if s != nil {
+		<-s
+	}
+}
+
+type callSemaphoreKey struct{}
+
+// CallSemaphoreToContext returns a copy of ctx carrying the semaphore
+// bounding all the tool calls of a runner.
+func CallSemaphoreToContext(ctx context.Context, s Semaphore) context.Context {
+	return context.WithValue(ctx, callSemaphoreKey{}, s)
+}
+
+// CallSemaphoreFromContext returns the semaphore bounding all the tool
+// calls of the runner, or nil.
+func CallSemaphoreFromContext(ctx context.Context) Semaphore {
+	s, _ := ctx.Value(callSemaphoreKey{}).(Semaphore)
+	return s
+}
@@ -41,6 +41,29 @@ type RequestProcessor interface {
 	ProcessRequest(ctx agent.Context, req *model.LLMRequest) error
 }
 
+// ResponsePartsKey is the key of the result of a FunctionTool holding the
+// []*genai.FunctionResponsePart, e.g. images, to send along with the
+// function response. The flow moves them out of the result into
+// genai.FunctionResponse.Parts.
+const ResponsePartsKey = "__adk_function_response_parts"
+
+// CacheHitKey is the key of the result of a FunctionTool marking it as
+// served from a cache. The flow removes it from the result and records the
+// ID of the function call in the CustomMetadata of the function response
+// event, under CacheHitsMetadataKey.
+const CacheHitKey = "__adk_tool_cache_hit"
+
+// CacheHitsMetadataKey is the key of the CustomMetadata of a function
+// response event holding the IDs of the function calls whose responses
+// were served from a cache.
+const CacheHitsMetadataKey = "tool_cache_hits"
+
+// ConfirmationRequirer is implemented by the tools whose calls may ask for
+// a tool confirmation.
+type ConfirmationRequirer interface {
+	RequiresConfirmation() bool
+}
+
 // ResponseDeferrer allows to skip generation of the FR by the tool.
 // Used in the cases when FR is generated externally (e.g. TaskAgentTool)
 type ResponseDeferrer interface {
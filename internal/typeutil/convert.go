@@ -55,6 +55,23 @@ func ConvertToWithJSONSchema[From, To any](v From, resolvedSchema *jsonschema.Re
 	return typed, nil
 }
 
+// Decode returns the T v holds, either as a *T or in its JSON-decoded
+// form, e.g. event metadata read back from a session service which
+// serializes it. It reports false if v is nil or doesn't decode as T.
+func Decode[T any](v any) (*T, bool) {
+	switch v := v.(type) {
+	case nil:
+		return nil, false
+	case *T:
+		return v, true
+	}
+	t, err := ConvertToWithJSONSchema[any, T](v, nil)
+	if err != nil {
+		return nil, false
+	}
+	return &t, true
+}
+
 // ValidateWithJSONSchema validates a Go value against a resolved schema by
 // first converting it to its JSON-decoded form to avoid struct validation issues.
 func ValidateWithJSONSchema(v any, resolvedSchema *jsonschema.Resolved) error {
@@ -110,3 +110,31 @@ func TestConvertToWithJSONSchema_NoSchemaSkipsValidation(t *testing.T) {
 		t.Errorf("got %v, want nil", got)
 	}
 }
+
+func TestDecode(t *testing.T) {
+	type record struct {
+		Name string `json:"name"`
+	}
+	ptr := &record{Name: "a"}
+	for _, tt := range []struct {
+		name   string
+		v      any
+		want   *record
+		wantOK bool
+	}{
+		{"nil", nil, nil, false},
+		{"pointer", ptr, ptr, true},
+		{"decoded", map[string]any{"name": "b"}, &record{Name: "b"}, true},
+		{"mismatch", "c", nil, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Decode[record](tt.v)
+			if ok != tt.wantOK || (tt.want != nil && (got == nil || *got != *tt.want)) {
+				t.Errorf("Decode(%v) = %+v, %v, want %+v, %v", tt.v, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+	if got, _ := Decode[record](ptr); got != ptr {
+		t.Error("Decode() of a pointer returned a copy")
+	}
+}
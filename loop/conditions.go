@@ -0,0 +1,161 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loop
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/session"
+)
+
+// UntilState stops the loop once pred holds for the session state.
+func UntilState(pred func(state session.ReadonlyState) (bool, error)) Condition {
+	return ConditionFunc(func(_ context.Context, it *Iteration) (*Stop, error) {
+		ok, err := pred(it.State)
+		if err != nil || !ok {
+			return nil, err
+		}
+		return &Stop{Reason: ReasonState}, nil
+	})
+}
+
+// UntilStable stops the loop once n consecutive iterations changed no
+// session state.
+func UntilStable(n int) Condition {
+	return ConditionFunc(func(_ context.Context, it *Iteration) (*Stop, error) {
+		if it.Unchanged < n {
+			return nil, nil
+		}
+		return &Stop{Reason: ReasonStable, Detail: fmt.Sprintf("no state change in %d iterations", it.Unchanged)}, nil
+	})
+}
+
+// MaxIterations stops the loop after n iterations.
+func MaxIterations(n int) Condition {
+	return ConditionFunc(func(_ context.Context, it *Iteration) (*Stop, error) {
+		if it.Number < n {
+			return nil, nil
+		}
+		return &Stop{Reason: ReasonMaxIterations}, nil
+	})
+}
+
+// MaxTokens stops the loop once it used n tokens or more. The budget
+// is checked after each iteration, so the last one may exceed it.
+func MaxTokens(n int) Condition {
+	return ConditionFunc(func(_ context.Context, it *Iteration) (*Stop, error) {
+		if it.Tokens < n {
+			return nil, nil
+		}
+		return &Stop{Reason: ReasonTokenBudget, Detail: fmt.Sprintf("%d tokens used, budget %d", it.Tokens, n)}, nil
+	})
+}
+
+// MaxDuration stops the loop once it ran for d or more. The budget is
+// checked after each iteration, so the last one may exceed it.
+func MaxDuration(d time.Duration) Condition {
+	return ConditionFunc(func(_ context.Context, it *Iteration) (*Stop, error) {
+		if it.Elapsed < d {
+			return nil, nil
+		}
+		return &Stop{Reason: ReasonTimeBudget, Detail: fmt.Sprintf("ran for %v, budget %v", it.Elapsed.Round(time.Millisecond), d)}, nil
+	})
+}
+
+const judgeInstruction = `You judge the output of an iteration of a refinement loop.
+Decide whether the output meets the criterion, and answer with a JSON object
+{"met": <bool>, "reason": <a short explanation>}.`
+
+// UntilJudged stops the loop once llm judges that the text output of an
+// iteration, the text of its last event with any, meets criterion.
+// Iterations without text output are not judged.
+func UntilJudged(llm model.LLM, criterion string) Condition {
+	return ConditionFunc(func(ctx context.Context, it *Iteration) (*Stop, error) {
+		output := lastText(it.Events)
+		if output == "" {
+			return nil, nil
+		}
+		req := &model.LLMRequest{
+			Model: llm.Name(),
+			Contents: []*genai.Content{genai.NewContentFromText(
+				fmt.Sprintf("Criterion:\n%s\n\nOutput:\n%s", criterion, output), genai.RoleUser)},
+			Config: &genai.GenerateContentConfig{
+				SystemInstruction: genai.NewContentFromText(judgeInstruction, genai.RoleUser),
+				ResponseMIMEType:  "application/json",
+				ResponseSchema: &genai.Schema{
+					Type: genai.TypeObject,
+					Properties: map[string]*genai.Schema{
+						"met":    {Type: genai.TypeBoolean},
+						"reason": {Type: genai.TypeString},
+					},
+					Required: []string{"met"},
+				},
+			},
+		}
+		var answer strings.Builder
+		for resp, err := range llm.GenerateContent(ctx, req, false) {
+			if err != nil {
+				return nil, fmt.Errorf("loop: judge: %w", err)
+			}
+			if resp == nil || resp.Partial || resp.Content == nil {
+				continue
+			}
+			for _, p := range resp.Content.Parts {
+				if p != nil && !p.Thought {
+					answer.WriteString(p.Text)
+				}
+			}
+		}
+		var verdict struct {
+			Met    bool   `json:"met"`
+			Reason string `json:"reason"`
+		}
+		if err := json.Unmarshal([]byte(answer.String()), &verdict); err != nil {
+			return nil, fmt.Errorf("loop: judge returned an invalid verdict %q: %w", answer.String(), err)
+		}
+		if !verdict.Met {
+			return nil, nil
+		}
+		return &Stop{Reason: ReasonJudged, Detail: verdict.Reason}, nil
+	})
+}
+
+// lastText returns the text of the last event of events with text
+// content, other than the user's.
+func lastText(events []*session.Event) string {
+	for i := len(events) - 1; i >= 0; i-- {
+		ev := events[i]
+		if ev == nil || ev.Partial || ev.Content == nil || ev.Content.Role == genai.RoleUser {
+			continue
+		}
+		var text strings.Builder
+		for _, p := range ev.Content.Parts {
+			if p != nil && !p.Thought {
+				text.WriteString(p.Text)
+			}
+		}
+		if text.Len() > 0 {
+			return text.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,219 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package loop defines the termination conditions of iterative loops:
+// a predicate on session state, an LLM-judged criterion, no state
+// change in a number of iterations, and token, time and iteration
+// budgets.
+//
+// loopagent.Config.Conditions applies them to a loop agent, and
+// workflow.LoopNode to a cycle of a workflow graph. Both record a
+// Summary of every iteration, and the reason the loop stopped, in the
+// metadata of their events.
+package loop
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/v2/internal/typeutil"
+	"google.golang.org/adk/v2/session"
+)
+
+// Reason is the reason a loop stopped.
+type Reason string
+
+// Reasons of the built-in conditions and of the loops applying them.
+const (
+	ReasonState         Reason = "state"
+	ReasonJudged        Reason = "judged"
+	ReasonStable        Reason = "stable"
+	ReasonTokenBudget   Reason = "token_budget"
+	ReasonTimeBudget    Reason = "time_budget"
+	ReasonMaxIterations Reason = "max_iterations"
+	// ReasonEscalated is recorded by loops stopped by an event which
+	// escalates, e.g. from exitlooptool.
+	ReasonEscalated Reason = "escalated"
+)
+
+// Stop is the verdict of a condition stopping a loop.
+type Stop struct {
+	Reason Reason `json:"reason"`
+	// Detail explains the verdict, e.g. the judge's rationale.
+	Detail string `json:"detail,omitempty"`
+}
+
+// Iteration is a completed iteration of a loop, as seen by the
+// conditions which decide whether to run another one.
+type Iteration struct {
+	// Number is the number of the iteration, starting at 1.
+	Number int
+	// Events are the events of the iteration.
+	Events []*session.Event
+	// State is the session state after the iteration.
+	State session.ReadonlyState
+	// Changed are the state keys whose value the iteration changed,
+	// sorted.
+	Changed []string
+	// Unchanged is the number of consecutive iterations, ending with
+	// this one, which changed no state.
+	Unchanged int
+	// Tokens is the number of tokens used by the loop so far, as
+	// reported in the usage metadata of its events.
+	Tokens int
+	// Elapsed is the time since the loop started.
+	Elapsed time.Duration
+}
+
+// NewIteration returns the iteration following prev, the summary of
+// the previous iteration of the loop or nil for the first one. Its
+// events are events and state is the session state after them; before
+// and after are the Fingerprints of the state before and after them.
+// If before is nil, all the keys of after count as changed. start is
+// the time the loop started.
+func NewIteration(prev *Summary, start time.Time, events []*session.Event, before, after map[string]string, state session.ReadonlyState) *Iteration {
+	it := &Iteration{Number: 1, Events: events, State: state, Elapsed: time.Since(start)}
+	if prev != nil {
+		it.Number = prev.Iteration + 1
+		it.Unchanged = prev.Unchanged
+		it.Tokens = prev.Tokens
+	}
+	for k, v := range after {
+		if before[k] != v {
+			it.Changed = append(it.Changed, k)
+		}
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			it.Changed = append(it.Changed, k)
+		}
+	}
+	slices.Sort(it.Changed)
+	if len(it.Changed) == 0 {
+		it.Unchanged++
+	} else {
+		it.Unchanged = 0
+	}
+	for _, ev := range events {
+		if ev != nil && !ev.Partial && ev.UsageMetadata != nil {
+			it.Tokens += int(ev.UsageMetadata.TotalTokenCount)
+		}
+	}
+	return it
+}
+
+// Fingerprint returns the hashes of the JSON encodings of the values of
+// state, keyed by state key, to tell which keys an iteration changed.
+// Temporary keys and keys with any of the ignored prefixes are left
+// out. Hashing the encodings makes values read back from a session
+// service storing state as JSON compare equal to the values they were
+// written as.
+func Fingerprint(state session.ReadonlyState, ignore ...string) map[string]string {
+	fp := map[string]string{}
+	if state == nil {
+		return fp
+	}
+	for k, v := range state.All() {
+		if strings.HasPrefix(k, session.KeyPrefixTemp) || slices.ContainsFunc(ignore, func(p string) bool { return strings.HasPrefix(k, p) }) {
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			b = fmt.Appendf(nil, "%#v", v)
+		}
+		h := fnv.New64a()
+		h.Write(b)
+		fp[k] = strconv.FormatUint(h.Sum64(), 16)
+	}
+	return fp
+}
+
+// Summary returns the summary of the iteration, stopped by stop if it
+// isn't nil.
+func (it *Iteration) Summary(stop *Stop) *Summary {
+	return &Summary{
+		Iteration: it.Number,
+		Changed:   it.Changed,
+		Unchanged: it.Unchanged,
+		Tokens:    it.Tokens,
+		Elapsed:   it.Elapsed,
+		Stop:      stop,
+	}
+}
+
+// Condition decides, after each iteration of a loop, whether the loop
+// stops.
+type Condition interface {
+	// Check returns the verdict stopping the loop after it, or nil to
+	// run another iteration.
+	Check(ctx context.Context, it *Iteration) (*Stop, error)
+}
+
+// ConditionFunc adapts a function to a Condition.
+type ConditionFunc func(ctx context.Context, it *Iteration) (*Stop, error)
+
+// Check calls f.
+func (f ConditionFunc) Check(ctx context.Context, it *Iteration) (*Stop, error) {
+	return f(ctx, it)
+}
+
+// Check returns the verdict of the first of conditions which stops the
+// loop after it, or nil.
+func Check(ctx context.Context, it *Iteration, conditions []Condition) (*Stop, error) {
+	for _, c := range conditions {
+		stop, err := c.Check(ctx, it)
+		if err != nil || stop != nil {
+			return stop, err
+		}
+	}
+	return nil, nil
+}
+
+// SummaryKey is the session.Event.CustomMetadata key of the Summary of
+// an iteration, on the event which ends it.
+const SummaryKey = "loop_iteration"
+
+// Summary summarizes an iteration of a loop.
+type Summary struct {
+	Iteration int           `json:"iteration"`
+	Changed   []string      `json:"changed,omitempty"`
+	Unchanged int           `json:"unchanged,omitempty"`
+	Tokens    int           `json:"tokens,omitempty"`
+	Elapsed   time.Duration `json:"elapsed"`
+	// Stop is set on the summary of the last iteration of a loop.
+	Stop *Stop `json:"stop,omitempty"`
+}
+
+// SummaryOf returns the Summary recorded on ev, if any.
+func SummaryOf(ev *session.Event) (*Summary, bool) {
+	if ev == nil {
+		return nil, false
+	}
+	return SummaryFrom(ev.CustomMetadata[SummaryKey])
+}
+
+// SummaryFrom returns the Summary v holds, either as a *Summary or in
+// its JSON-decoded form.
+func SummaryFrom(v any) (*Summary, bool) {
+	if s, ok := typeutil.Decode[Summary](v); ok && s.Iteration > 0 {
+		return s, true
+	}
+	return nil, false
+}
@@ -0,0 +1,207 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loop
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/session"
+)
+
+func eventWith(delta map[string]any, tokens int32, text string) *session.Event {
+	ev := &session.Event{Actions: session.EventActions{StateDelta: delta}}
+	if tokens > 0 {
+		ev.UsageMetadata = &genai.GenerateContentResponseUsageMetadata{TotalTokenCount: tokens}
+	}
+	if text != "" {
+		ev.Content = genai.NewContentFromText(text, genai.RoleModel)
+	}
+	return ev
+}
+
+func TestNewIteration(t *testing.T) {
+	start := time.Now().Add(-time.Second)
+	before := Fingerprint(mapState{"score": float64(3)})
+	state := mapState{"draft": "v1", "score": 3, "temp:scratch": 1}
+	events := []*session.Event{eventWith(nil, 10, ""), eventWith(nil, 5, "")}
+	it := NewIteration(nil, start, events, before, Fingerprint(state), state)
+	if it.Number != 1 || it.Tokens != 15 || it.Unchanged != 0 || it.Elapsed < time.Second {
+		t.Errorf("first iteration = %+v, want number 1, 15 tokens, changed", it)
+	}
+	if diff := cmp.Diff([]string{"draft"}, it.Changed); diff != "" {
+		t.Errorf("Changed mismatch (-want +got):\n%s", diff)
+	}
+
+	prev := it.Summary(nil)
+	before = Fingerprint(state)
+	state = mapState{"draft": "v1", "score": float64(3), "temp:scratch": 2}
+	it = NewIteration(prev, start, []*session.Event{eventWith(nil, 7, "")}, before, Fingerprint(state), state)
+	if it.Number != 2 || it.Tokens != 22 || it.Unchanged != 1 || len(it.Changed) != 0 {
+		t.Errorf("second iteration = %+v, want number 2, 22 tokens, unchanged", it)
+	}
+
+	prev = it.Summary(nil)
+	before = Fingerprint(state)
+	state = mapState{"draft": "v2", "score": 3}
+	it = NewIteration(prev, start, nil, before, Fingerprint(state, "score"), state)
+	if diff := cmp.Diff([]string{"draft", "score"}, it.Changed); diff != "" {
+		t.Errorf("Changed with an ignored key mismatch (-want +got):\n%s", diff)
+	}
+
+	// Without the fingerprint of the state before it, every key counts
+	// as changed.
+	it = NewIteration(prev, start, nil, nil, Fingerprint(state), state)
+	if diff := cmp.Diff([]string{"draft", "score"}, it.Changed); diff != "" || it.Unchanged != 0 {
+		t.Errorf("iteration without fingerprint = %+v, want all keys changed", it)
+	}
+}
+
+func TestConditions(t *testing.T) {
+	state := mapState{"done": true}
+	it := &Iteration{Number: 3, Unchanged: 2, Tokens: 100, Elapsed: time.Minute, State: state}
+	tests := []struct {
+		name string
+		cond Condition
+		want *Stop
+	}{
+		{"state holds", UntilState(func(s session.ReadonlyState) (bool, error) {
+			v, _ := s.Get("done")
+			return v == true, nil
+		}), &Stop{Reason: ReasonState}},
+		{"state doesn't hold", UntilState(func(s session.ReadonlyState) (bool, error) { return false, nil }), nil},
+		{"stable", UntilStable(2), &Stop{Reason: ReasonStable, Detail: "no state change in 2 iterations"}},
+		{"not yet stable", UntilStable(3), nil},
+		{"max iterations", MaxIterations(3), &Stop{Reason: ReasonMaxIterations}},
+		{"under max iterations", MaxIterations(4), nil},
+		{"token budget", MaxTokens(100), &Stop{Reason: ReasonTokenBudget, Detail: "100 tokens used, budget 100"}},
+		{"under token budget", MaxTokens(101), nil},
+		{"time budget", MaxDuration(time.Minute), &Stop{Reason: ReasonTimeBudget, Detail: "ran for 1m0s, budget 1m0s"}},
+		{"under time budget", MaxDuration(time.Hour), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cond.Check(t.Context(), it)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Check() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	stop, err := Check(t.Context(), it, []Condition{MaxIterations(10), UntilStable(1), MaxTokens(1)})
+	if err != nil || stop == nil || stop.Reason != ReasonStable {
+		t.Errorf("Check() = %+v, %v, want the verdict of the first condition which holds", stop, err)
+	}
+}
+
+type judgeModel struct {
+	verdict string
+	req     *model.LLMRequest
+}
+
+func (m *judgeModel) Name() string { return "judge" }
+
+func (m *judgeModel) GenerateContent(_ context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	m.req = req
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(&model.LLMResponse{Content: genai.NewContentFromText(m.verdict, genai.RoleModel)}, nil)
+	}
+}
+
+func TestUntilJudged(t *testing.T) {
+	it := &Iteration{Events: []*session.Event{eventWith(nil, 0, "a haiku about loops"), eventWith(nil, 0, "")}}
+
+	m := &judgeModel{verdict: `{"met": true, "reason": "five-seven-five"}`}
+	stop, err := UntilJudged(m, "is a haiku").Check(t.Context(), it)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if diff := cmp.Diff(&Stop{Reason: ReasonJudged, Detail: "five-seven-five"}, stop); diff != "" {
+		t.Errorf("Check() mismatch (-want +got):\n%s", diff)
+	}
+	if got := m.req.Contents[0].Parts[0].Text; got != "Criterion:\nis a haiku\n\nOutput:\na haiku about loops" {
+		t.Errorf("judge prompt = %q", got)
+	}
+
+	m.verdict = `{"met": false}`
+	if stop, err := UntilJudged(m, "is a haiku").Check(t.Context(), it); stop != nil || err != nil {
+		t.Errorf("Check() with unmet criterion = %+v, %v, want nil", stop, err)
+	}
+	m.verdict = "yes"
+	if _, err := UntilJudged(m, "is a haiku").Check(t.Context(), it); err == nil {
+		t.Error("Check() with an invalid verdict succeeded, want error")
+	}
+	m.req = nil
+	if stop, err := UntilJudged(m, "x").Check(t.Context(), &Iteration{}); stop != nil || err != nil || m.req != nil {
+		t.Errorf("Check() without output = %+v, %v, want no judgement", stop, err)
+	}
+}
+
+func TestSummaryOf(t *testing.T) {
+	s := &Summary{Iteration: 2, Tokens: 5, Stop: &Stop{Reason: ReasonEscalated}}
+	b, err := json.Marshal(map[string]any{SummaryKey: s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, ev := range []*session.Event{
+		{LLMResponse: model.LLMResponse{CustomMetadata: map[string]any{SummaryKey: s}}},
+		{LLMResponse: model.LLMResponse{CustomMetadata: decoded}},
+	} {
+		got, ok := SummaryOf(ev)
+		if !ok {
+			t.Fatal("SummaryOf() = false, want true")
+		}
+		if diff := cmp.Diff(s, got); diff != "" {
+			t.Errorf("SummaryOf() mismatch (-want +got):\n%s", diff)
+		}
+	}
+	if _, ok := SummaryOf(&session.Event{}); ok {
+		t.Error("SummaryOf() of an event without summary = true, want false")
+	}
+}
+
+type mapState map[string]any
+
+func (s mapState) Get(key string) (any, error) {
+	v, ok := s[key]
+	if !ok {
+		return nil, session.ErrStateKeyNotExist
+	}
+	return v, nil
+}
+
+func (s mapState) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for k, v := range s {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/agent/workflowagent"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/workflow"
+)
+
+// ErrLeaseHeld is returned when a run of a durable workflow is owned by
+// another runner, e.g. by Runner.RecoverRun when a replica is already
+// recovering the run.
+var ErrLeaseHeld = errors.New("runner: run is owned by another runner")
+
+// defaultLeaseTTL is the default DurabilityConfig.LeaseTTL.
+const defaultLeaseTTL = 30 * time.Second
+
+// DurabilityConfig configures how a [Runner] whose root agent is a
+// durable workflow (see workflowagent.Config.Durable) owns its runs.
+//
+// While a run executes, the runner holds a lease on it, renewed every
+// third of LeaseTTL. A run whose checkpoint is in progress but whose
+// lease expired was abandoned, e.g. because its process crashed; it is
+// reported by Runner.ListAbandonedRuns and continued by
+// Runner.RecoverRun. Replicas sharing a session service must share the
+// LeaseStore, so that two of them don't recover the same run.
+type DurabilityConfig struct {
+	// LeaseStore holds the leases of runs. Defaults to a store local
+	// to the runner, which only suits a single replica.
+	LeaseStore LeaseStore
+	// Owner identifies the runner in the LeaseStore. Defaults to a
+	// random ID.
+	Owner string
+	// LeaseTTL is how long a lease lasts without renewal. Defaults to
+	// 30 seconds.
+	LeaseTTL time.Duration
+}
+
+// LeaseStore grants time-limited ownership of keys to owners.
+// Implementations must be safe for concurrent use.
+type LeaseStore interface {
+	// Acquire takes the lease on key for owner for ttl, or extends it if
+	// owner already holds it. It reports false if another owner holds
+	// an unexpired lease.
+	Acquire(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	// Release gives up the lease on key if owner holds it.
+	Release(ctx context.Context, key, owner string) error
+	// Held reports whether any owner holds an unexpired lease on key.
+	Held(ctx context.Context, key string) (bool, error)
+}
+
+type lease struct {
+	owner   string
+	expires time.Time
+}
+
+type inMemoryLeaseStore struct {
+	mu     sync.Mutex
+	leases map[string]lease
+}
+
+// NewInMemoryLeaseStore returns a LeaseStore which keeps leases in
+// memory. It coordinates runners within one process only.
+func NewInMemoryLeaseStore() LeaseStore {
+	return &inMemoryLeaseStore{leases: map[string]lease{}}
+}
+
+func (s *inMemoryLeaseStore) Acquire(_ context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if l, ok := s.leases[key]; ok && l.owner != owner && now.Before(l.expires) {
+		return false, nil
+	}
+	s.leases[key] = lease{owner: owner, expires: now.Add(ttl)}
+	return true, nil
+}
+
+func (s *inMemoryLeaseStore) Release(_ context.Context, key, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if l, ok := s.leases[key]; ok && l.owner == owner {
+		delete(s.leases, key)
+	}
+	return nil
+}
+
+func (s *inMemoryLeaseStore) Held(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.leases[key]
+	return ok && time.Now().Before(l.expires), nil
+}
+
+// durability is the state of a runner whose root agent runs a durable
+// workflow.
+type durability struct {
+	workflow *workflow.Workflow
+	leases   LeaseStore
+	owner    string
+	ttl      time.Duration
+}
+
+// newDurability returns the durability of a runner with the given root
+// agent, or nil if it doesn't run a durable workflow.
+func newDurability(root agent.Agent, cfg DurabilityConfig) (*durability, error) {
+	w, ok := workflowagent.WorkflowOf(root)
+	if !ok || !w.Durable() {
+		return nil, nil
+	}
+	if cfg.LeaseTTL < 0 {
+		return nil, fmt.Errorf("lease TTL must not be negative, got %v", cfg.LeaseTTL)
+	}
+	d := &durability{
+		workflow: w,
+		leases:   cfg.LeaseStore,
+		owner:    cfg.Owner,
+		ttl:      cfg.LeaseTTL,
+	}
+	if d.leases == nil {
+		d.leases = NewInMemoryLeaseStore()
+	}
+	if d.owner == "" {
+		d.owner = uuid.NewString()
+	}
+	if d.ttl == 0 {
+		d.ttl = defaultLeaseTTL
+	}
+	return d, nil
+}
+
+// runLeaseKey returns the lease key of the run of the given invocation.
+func runLeaseKey(appName, userID, sessionID, invocationID string) string {
+	return strings.Join([]string{appName, userID, sessionID, invocationID}, "/")
+}
+
+// acquire takes the lease on key and keeps renewing it until the
+// returned release function is called. If the lease is lost, lost is
+// called to stop the run. It returns ErrLeaseHeld if another owner
+// holds the lease.
+func (d *durability) acquire(ctx context.Context, key string, lost context.CancelFunc) (release func(), err error) {
+	ok, err := d.leases.Acquire(ctx, key, d.owner, d.ttl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lease %q: %w", key, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrLeaseHeld, key)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(d.ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				ok, err := d.leases.Acquire(ctx, key, d.owner, d.ttl)
+				if err != nil || !ok {
+					log.Printf("runner: lost lease %q, stopping the run (err: %v)", key, err)
+					lost()
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+		if err := d.leases.Release(context.WithoutCancel(ctx), key, d.owner); err != nil {
+			log.Printf("runner: failed to release lease %q: %v", key, err)
+		}
+	}, nil
+}
+
+// AbandonedRun identifies a run of a durable workflow which was
+// interrupted and can be continued with Runner.RecoverRun.
+type AbandonedRun struct {
+	UserID       string
+	SessionID    string
+	InvocationID string
+	// UpdatedAt is the time of the run's last checkpoint.
+	UpdatedAt time.Time
+}
+
+// ListAbandonedRuns returns the runs of the root durable workflow of
+// the given user, or of all users if userID is empty, which are in
+// progress according to their checkpoint but not owned by any runner,
+// oldest first.
+func (r *Runner) ListAbandonedRuns(ctx context.Context, userID string) ([]*AbandonedRun, error) {
+	if r.durable == nil {
+		return nil, fmt.Errorf("root agent %q is not a durable workflow", r.rootAgent.Name())
+	}
+	resp, err := r.sessionService.List(ctx, &session.ListRequest{AppName: r.appName, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	var runs []*AbandonedRun
+	for _, sess := range resp.Sessions {
+		checkpoints, err := workflow.Checkpoints(sess.State())
+		if err != nil {
+			return nil, fmt.Errorf("session %q: %w", sess.ID(), err)
+		}
+		for _, cp := range checkpoints {
+			if cp.Workflow != r.durable.workflow.Name() || cp.Status != workflow.RunInProgress {
+				continue
+			}
+			held, err := r.durable.leases.Held(ctx, runLeaseKey(r.appName, sess.UserID(), sess.ID(), cp.InvocationID))
+			if err != nil {
+				return nil, fmt.Errorf("failed to check lease: %w", err)
+			}
+			if held {
+				continue
+			}
+			runs = append(runs, &AbandonedRun{
+				UserID:       sess.UserID(),
+				SessionID:    sess.ID(),
+				InvocationID: cp.InvocationID,
+				UpdatedAt:    cp.UpdatedAt,
+			})
+		}
+	}
+	slices.SortFunc(runs, func(a, b *AbandonedRun) int {
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	})
+	return runs, nil
+}
+
+// RecoverRun continues an abandoned run of the root durable workflow
+// from its last checkpoint, yielding its events like Run. Nodes
+// completed before the run was interrupted are not re-run; see
+// workflow.Workflow.Recover.
+//
+// It returns ErrLeaseHeld if another runner owns the run, and
+// workflow.ErrNothingToRecover if the run is no longer in progress.
+func (r *Runner) RecoverRun(ctx context.Context, userID, sessionID, invocationID string, cfg agent.RunConfig) iter.Seq2[*session.Event, error] {
+	return r.continueRun(ctx, userID, sessionID, invocationID, cfg, func(ctx context.Context, sess session.Session, cp *workflow.Checkpoint) error {
+		if cp == nil || cp.Status != workflow.RunInProgress {
+			return fmt.Errorf("%w: invocation %q", workflow.ErrNothingToRecover, invocationID)
+		}
+		return nil
+	})
+}
+
+// RetryNode runs the failed node of a failed run of the root durable
+// workflow again from its checkpointed input, and continues the run from
+// there like RecoverRun. See workflow.RetryCheckpoint.
+//
+// It returns ErrLeaseHeld if another runner owns the run,
+// workflow.ErrNothingToRecover if the run did not fail, and
+// workflow.ErrNodeNotFailed if the node did not fail.
+func (r *Runner) RetryNode(ctx context.Context, userID, sessionID, invocationID, node string, cfg agent.RunConfig) iter.Seq2[*session.Event, error] {
+	return r.continueRun(ctx, userID, sessionID, invocationID, cfg, func(ctx context.Context, sess session.Session, cp *workflow.Checkpoint) error {
+		retried, err := workflow.RetryCheckpoint(cp, node)
+		if err != nil {
+			return fmt.Errorf("invocation %q: %w", invocationID, err)
+		}
+		delta, err := retried.StateDelta()
+		if err != nil {
+			return err
+		}
+		ev := session.NewEvent(ctx, invocationID)
+		ev.Author = r.durable.workflow.Name()
+		ev.Actions.StateDelta = delta
+		if err := r.sessionService.AppendEvent(ctx, sess, ev); err != nil {
+			return fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+		return nil
+	})
+}
+
+// continueRun continues the run of the root durable workflow of the
+// given invocation from its checkpoint, once prepare accepted it. The
+// lease on the run is held from before the session is read.
+func (r *Runner) continueRun(ctx context.Context, userID, sessionID, invocationID string, cfg agent.RunConfig, prepare func(context.Context, session.Session, *workflow.Checkpoint) error) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		if r.durable == nil {
+			yield(nil, fmt.Errorf("root agent %q is not a durable workflow", r.rootAgent.Name()))
+			return
+		}
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		release, err := r.durable.acquire(ctx, runLeaseKey(r.appName, userID, sessionID, invocationID), cancel)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer release()
+
+		// The session is read once the lease is held, so that a run
+		// recovered and completed by another runner meanwhile is seen
+		// as such.
+		resp, err := r.sessionService.Get(ctx, &session.GetRequest{
+			AppName:   r.appName,
+			UserID:    userID,
+			SessionID: sessionID,
+		})
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		if err := r.migrateSession(ctx, resp.Session); err != nil {
+			yield(nil, err)
+			return
+		}
+		cp, err := workflow.LoadCheckpoint(resp.Session.State(), r.durable.workflow.Name(), invocationID)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		if err := prepare(ctx, resp.Session, cp); err != nil {
+			yield(nil, err)
+			return
+		}
+		r.runAgent(ctx, resp.Session, nil, cfg, runOptions{}, invocationID, yield)
+	}
+}
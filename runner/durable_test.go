@@ -0,0 +1,296 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/agent/workflowagent"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/workflow"
+)
+
+// durableTestRun is a durable workflow Start -> a -> b -> c whose node
+// b blocks on its first run until the run is stopped.
+type durableTestRun struct {
+	agent    agent.Agent
+	calls    map[string]*atomic.Int32
+	bStarted chan struct{}
+	svc      session.Service
+	leases   runner.LeaseStore
+}
+
+func newDurableTestRun(t *testing.T) *durableTestRun {
+	t.Helper()
+	d := &durableTestRun{
+		calls:    map[string]*atomic.Int32{"a": {}, "b": {}, "c": {}},
+		bStarted: make(chan struct{}),
+		svc:      session.InMemoryService(),
+		leases:   runner.NewInMemoryLeaseStore(),
+	}
+	node := func(name string) workflow.Node {
+		return workflow.NewFunctionNode(name, func(ctx agent.Context, input any) (string, error) {
+			if d.calls[name].Add(1) == 1 && name == "b" {
+				close(d.bStarted)
+				<-ctx.Done()
+				return "", ctx.Err()
+			}
+			return name, nil
+		}, workflow.NodeConfig{})
+	}
+	a, err := workflowagent.New(workflowagent.Config{
+		Name:    workflowAgentName,
+		Edges:   workflow.Chain(workflow.Start, node("a"), node("b"), node("c")),
+		Durable: true,
+	})
+	if err != nil {
+		t.Fatalf("workflowagent.New() error = %v", err)
+	}
+	d.agent = a
+	newNodeTestSession(t, t.Context(), d.svc)
+	return d
+}
+
+// newRunner returns a runner replica sharing the session service and
+// the lease store of d.
+func (d *durableTestRun) newRunner(t *testing.T, owner string) *runner.Runner {
+	t.Helper()
+	r, err := runner.New(runner.Config{
+		AppName:        nodeTestApp,
+		Agent:          d.agent,
+		SessionService: d.svc,
+		DurabilityConfig: runner.DurabilityConfig{
+			LeaseStore: d.leases,
+			Owner:      owner,
+			LeaseTTL:   time.Minute,
+		},
+	})
+	if err != nil {
+		t.Fatalf("runner.New() error = %v", err)
+	}
+	return r
+}
+
+// kill runs the workflow on r and stops consuming its events once node
+// b is running, as if the process running it crashed.
+func (d *durableTestRun) kill(t *testing.T, r *runner.Runner) {
+	t.Helper()
+	aDone := false
+	for ev, err := range r.Run(t.Context(), nodeTestUser, nodeTestSession, userText("go"), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		// b is scheduled once the completion of a is checkpointed.
+		if aDone && len(ev.Actions.StateDelta) > 0 {
+			<-d.bStarted
+			break
+		}
+		aDone = aDone || ev.Output == "a"
+	}
+}
+
+func TestRunner_DurableWorkflow_RecoverAfterCrash(t *testing.T) {
+	ctx := t.Context()
+	d := newDurableTestRun(t)
+	d.kill(t, d.newRunner(t, "replica-1"))
+
+	replica := d.newRunner(t, "replica-2")
+	runs, err := replica.ListAbandonedRuns(ctx, "")
+	if err != nil {
+		t.Fatalf("ListAbandonedRuns() error = %v", err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("ListAbandonedRuns() = %d runs, want 1", len(runs))
+	}
+	run := runs[0]
+	if run.UserID != nodeTestUser || run.SessionID != nodeTestSession || run.InvocationID == "" {
+		t.Errorf("abandoned run = %+v, want one in session %s/%s", run, nodeTestUser, nodeTestSession)
+	}
+
+	var lastOutput any
+	for ev, err := range replica.RecoverRun(ctx, run.UserID, run.SessionID, run.InvocationID, agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("RecoverRun() error = %v", err)
+		}
+		if ev.InvocationID != run.InvocationID {
+			t.Errorf("recovered event in invocation %q, want %q", ev.InvocationID, run.InvocationID)
+		}
+		if ev.Output != nil {
+			lastOutput = ev.Output
+		}
+	}
+
+	if lastOutput != "c" {
+		t.Errorf("recovered run last output = %v, want %q", lastOutput, "c")
+	}
+	for name, want := range map[string]int32{"a": 1, "b": 2, "c": 1} {
+		if got := d.calls[name].Load(); got != want {
+			t.Errorf("node %s ran %d times, want %d", name, got, want)
+		}
+	}
+
+	runs, err = replica.ListAbandonedRuns(ctx, nodeTestUser)
+	if err != nil {
+		t.Fatalf("ListAbandonedRuns() error = %v", err)
+	}
+	if len(runs) != 0 {
+		t.Errorf("ListAbandonedRuns() after recovery = %+v, want none", runs)
+	}
+	for _, err := range replica.RecoverRun(ctx, run.UserID, run.SessionID, run.InvocationID, agent.RunConfig{}) {
+		if !errors.Is(err, workflow.ErrNothingToRecover) {
+			t.Errorf("RecoverRun() of a completed run error = %v, want %v", err, workflow.ErrNothingToRecover)
+		}
+	}
+}
+
+func TestRunner_DurableWorkflow_LeaseHeld(t *testing.T) {
+	ctx := t.Context()
+	d := newDurableTestRun(t)
+	r := d.newRunner(t, "replica-1")
+	d.kill(t, r)
+
+	runs, err := r.ListAbandonedRuns(ctx, nodeTestUser)
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("ListAbandonedRuns() = %v, %v, want 1 run", runs, err)
+	}
+	run := runs[0]
+
+	// Another replica is recovering the run.
+	key := nodeTestApp + "/" + run.UserID + "/" + run.SessionID + "/" + run.InvocationID
+	if ok, err := d.leases.Acquire(ctx, key, "replica-2", time.Minute); err != nil || !ok {
+		t.Fatalf("Acquire() = %v, %v, want true", ok, err)
+	}
+
+	runs, err = r.ListAbandonedRuns(ctx, nodeTestUser)
+	if err != nil {
+		t.Fatalf("ListAbandonedRuns() error = %v", err)
+	}
+	if len(runs) != 0 {
+		t.Errorf("ListAbandonedRuns() = %+v, want none while the run is owned", runs)
+	}
+	var gotErr error
+	for _, err := range r.RecoverRun(ctx, run.UserID, run.SessionID, run.InvocationID, agent.RunConfig{}) {
+		gotErr = err
+	}
+	if !errors.Is(gotErr, runner.ErrLeaseHeld) {
+		t.Errorf("RecoverRun() error = %v, want %v", gotErr, runner.ErrLeaseHeld)
+	}
+	if got := d.calls["c"].Load(); got != 0 {
+		t.Errorf("node c ran %d times, want 0", got)
+	}
+}
+
+func TestRunner_DurableWorkflow_RetryNode(t *testing.T) {
+	ctx := t.Context()
+	boom := errors.New("boom")
+	var bCalls atomic.Int32
+	node := func(name string) workflow.Node {
+		return workflow.NewFunctionNode(name, func(ctx agent.Context, input any) (string, error) {
+			if name == "b" && bCalls.Add(1) == 1 {
+				return "", boom
+			}
+			return name, nil
+		}, workflow.NodeConfig{})
+	}
+	a, err := workflowagent.New(workflowagent.Config{
+		Name:    workflowAgentName,
+		Edges:   workflow.Chain(workflow.Start, node("a"), node("b"), node("c")),
+		Durable: true,
+	})
+	if err != nil {
+		t.Fatalf("workflowagent.New() error = %v", err)
+	}
+	svc := session.InMemoryService()
+	newNodeTestSession(t, ctx, svc)
+	r, err := runner.New(runner.Config{AppName: nodeTestApp, Agent: a, SessionService: svc})
+	if err != nil {
+		t.Fatalf("runner.New() error = %v", err)
+	}
+
+	var invocationID string
+	var runErr error
+	for ev, err := range r.Run(ctx, nodeTestUser, nodeTestSession, userText("go"), agent.RunConfig{}) {
+		if err != nil {
+			runErr = err
+			continue
+		}
+		invocationID = ev.InvocationID
+	}
+	if !errors.Is(runErr, boom) {
+		t.Fatalf("Run() error = %v, want %v", runErr, boom)
+	}
+
+	for _, err := range r.RetryNode(ctx, nodeTestUser, nodeTestSession, invocationID, "a", agent.RunConfig{}) {
+		if !errors.Is(err, workflow.ErrNodeNotFailed) {
+			t.Errorf("RetryNode(a) error = %v, want %v", err, workflow.ErrNodeNotFailed)
+		}
+	}
+
+	var lastOutput any
+	for ev, err := range r.RetryNode(ctx, nodeTestUser, nodeTestSession, invocationID, "b", agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("RetryNode(b) error = %v", err)
+		}
+		if ev.Output != nil {
+			lastOutput = ev.Output
+		}
+	}
+	if lastOutput != "c" {
+		t.Errorf("retried run last output = %v, want %q", lastOutput, "c")
+	}
+	if got := bCalls.Load(); got != 2 {
+		t.Errorf("node b ran %d times, want 2", got)
+	}
+}
+
+func TestInMemoryLeaseStore(t *testing.T) {
+	ctx := t.Context()
+	s := runner.NewInMemoryLeaseStore()
+
+	if ok, _ := s.Acquire(ctx, "k", "one", time.Minute); !ok {
+		t.Fatal("Acquire(one) = false, want true")
+	}
+	if ok, _ := s.Acquire(ctx, "k", "two", time.Minute); ok {
+		t.Error("Acquire(two) of a held lease = true, want false")
+	}
+	if ok, _ := s.Acquire(ctx, "k", "one", time.Minute); !ok {
+		t.Error("renewing Acquire(one) = false, want true")
+	}
+	if err := s.Release(ctx, "k", "two"); err != nil {
+		t.Fatalf("Release(two) error = %v", err)
+	}
+	if held, _ := s.Held(ctx, "k"); !held {
+		t.Error("Held() after release by a non-owner = false, want true")
+	}
+	if err := s.Release(ctx, "k", "one"); err != nil {
+		t.Fatalf("Release(one) error = %v", err)
+	}
+	if held, _ := s.Held(ctx, "k"); held {
+		t.Error("Held() after release = true, want false")
+	}
+
+	if ok, _ := s.Acquire(ctx, "expiring", "one", time.Millisecond); !ok {
+		t.Fatal("Acquire(one) = false, want true")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if ok, _ := s.Acquire(ctx, "expiring", "two", time.Minute); !ok {
+		t.Error("Acquire(two) of an expired lease = false, want true")
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"google.golang.org/adk/v2/internal/llminternal"
 	imemory "google.golang.org/adk/v2/internal/memory"
 	"google.golang.org/adk/v2/internal/plugininternal"
+	"google.golang.org/adk/v2/internal/toolinternal"
 	"google.golang.org/adk/v2/internal/utils"
 	"google.golang.org/adk/v2/model"
 	"google.golang.org/adk/v2/session"
@@ -216,6 +217,7 @@ func (r *Runner) newNodeInvocationContext(
 		StreamingMode: runconfig.StreamingMode(cfg.StreamingMode),
 	})
 	ctx = plugininternal.ToContext(ctx, r.pluginManager)
+	ctx = toolinternal.CallSemaphoreToContext(ctx, r.toolCalls)
 
 	var artifacts agent.Artifacts
 	if r.artifactService != nil {
@@ -33,10 +33,12 @@ import (
 	"google.golang.org/adk/v2/internal/llminternal"
 	imemory "google.golang.org/adk/v2/internal/memory"
 	"google.golang.org/adk/v2/internal/plugininternal"
+	"google.golang.org/adk/v2/internal/toolinternal"
 	"google.golang.org/adk/v2/internal/utils"
 	"google.golang.org/adk/v2/internal/workflowinternal"
 	"google.golang.org/adk/v2/memory"
 	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/platform"
 	"google.golang.org/adk/v2/plugin"
 	"google.golang.org/adk/v2/session"
 )
@@ -56,6 +58,18 @@ type Config struct {
 	PluginConfig PluginConfig
 	// optional
 	AutoCreateSession bool
+	// optional
+	DurabilityConfig DurabilityConfig
+	// StateMigrations, if set, upgrades the session-scoped state of
+	// sessions loaded by the runner from older schema versions. Sessions
+	// it creates start at the current version.
+	// optional
+	StateMigrations *session.StateMigrations
+	// MaxConcurrentToolCalls, if positive, bounds the number of tool calls
+	// running at once across all the invocations of the runner. The other
+	// calls wait for their turn.
+	// optional
+	MaxConcurrentToolCalls int
 }
 
 type PluginConfig struct {
@@ -109,6 +123,11 @@ func New(cfg Config) (*Runner, error) {
 		return nil, fmt.Errorf("failed to create plugin manager: %w", err)
 	}
 
+	durable, err := newDurability(cfg.Agent, cfg.DurabilityConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Runner{
 		appName:           cfg.AppName,
 		rootAgent:         cfg.Agent,
@@ -118,6 +137,9 @@ func New(cfg Config) (*Runner, error) {
 		parents:           parents,
 		pluginManager:     pluginManager,
 		autoCreateSession: cfg.AutoCreateSession,
+		durable:           durable,
+		stateMigrations:   cfg.StateMigrations,
+		toolCalls:         toolinternal.NewSemaphore(cfg.MaxConcurrentToolCalls),
 	}, nil
 }
 
@@ -150,6 +172,16 @@ type Runner struct {
 	parents           parentmap.Map
 	pluginManager     *plugininternal.PluginManager
 	autoCreateSession bool
+	// durable is set when the root agent runs a durable workflow.
+	durable         *durability
+	stateMigrations *session.StateMigrations
+	// toolCalls bounds the tool calls of the invocations.
+	toolCalls toolinternal.Semaphore
+}
+
+// AppName returns the name of the app the runner runs agents for.
+func (r *Runner) AppName() string {
+	return r.appName
 }
 
 func (r *Runner) getOrCreateSession(ctx context.Context, userID, sessionID string) (session.Session, error) {
@@ -159,15 +191,23 @@ func (r *Runner) getOrCreateSession(ctx context.Context, userID, sessionID strin
 		SessionID: sessionID,
 	})
 	if err == nil {
+		if err := r.migrateSession(ctx, getResp.Session); err != nil {
+			return nil, err
+		}
 		return getResp.Session, nil
 	}
 	if !r.autoCreateSession {
 		return nil, err
 	}
+	var state map[string]any
+	if v := r.stateMigrations.Version(); v > 0 {
+		state = map[string]any{session.StateVersionKey: v}
+	}
 	createResp, err := r.sessionService.Create(ctx, &session.CreateRequest{
 		AppName:   r.appName,
 		UserID:    userID,
 		SessionID: sessionID,
+		State:     state,
 	})
 	if err != nil {
 		return nil, err
@@ -175,6 +215,25 @@ func (r *Runner) getOrCreateSession(ctx context.Context, userID, sessionID strin
 	return createResp.Session, nil
 }
 
+// migrateSession upgrades the state of sess with the runner's state
+// migrations, appending an event carrying the migrated state to it.
+func (r *Runner) migrateSession(ctx context.Context, sess session.Session) error {
+	if r.stateMigrations == nil {
+		return nil
+	}
+	delta, err := r.stateMigrations.Migrate(ctx, sess.State())
+	if err != nil || delta == nil {
+		return err
+	}
+	ev := session.NewEvent(ctx, "")
+	ev.Author = r.rootAgent.Name()
+	ev.Actions.StateDelta = delta
+	if err := r.sessionService.AppendEvent(ctx, sess, ev); err != nil {
+		return fmt.Errorf("failed to save migrated session state: %w", err)
+	}
+	return nil
+}
+
 // Run runs the agent for the given user input, yielding events from agents.
 // For each user message it finds the proper agent within an agent tree to
 // continue the conversation within the session.
@@ -249,111 +308,137 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.C
 			return
 		}
 
-		ctx = parentmap.ToContext(ctx, r.parents)
-		ctx = runconfig.ToContext(ctx, &runconfig.RunConfig{
-			StreamingMode: runconfig.StreamingMode(cfg.StreamingMode),
-		})
-		ctx = plugininternal.ToContext(ctx, r.pluginManager)
-
-		var artifacts agent.Artifacts
-		if r.artifactService != nil {
-			artifacts = &artifactinternal.Artifacts{
-				Service:   r.artifactService,
-				SessionID: storedSession.ID(),
-				AppName:   storedSession.AppName(),
-				UserID:    storedSession.UserID(),
+		invocationID := resolveInvocationID(storedSession, msg)
+		if r.durable != nil {
+			// Runs of a durable workflow are owned through a lease
+			// taken before the first checkpoint is saved, so that
+			// ListAbandonedRuns doesn't report them while they run.
+			if invocationID == "" {
+				invocationID = "e-" + platform.NewUUID(ctx)
+			}
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithCancel(ctx)
+			defer cancel()
+			release, err := r.durable.acquire(ctx, runLeaseKey(r.appName, userID, sessionID, invocationID), cancel)
+			if err != nil {
+				yield(nil, err)
+				return
 			}
+			defer release()
 		}
+		r.runAgent(ctx, storedSession, msg, cfg, options, invocationID, yield)
+	}
+}
 
-		var memoryImpl agent.Memory = nil
-		if r.memoryService != nil {
-			memoryImpl = &imemory.Memory{
-				Service:   r.memoryService,
-				SessionID: storedSession.ID(),
-				UserID:    storedSession.UserID(),
-				AppName:   storedSession.AppName(),
-			}
+// runAgent runs the root agent in the given invocation, appending msg,
+// if any, to the session first. Empty invocationID starts a new
+// invocation.
+func (r *Runner) runAgent(parent context.Context, storedSession session.Session, msg *genai.Content, cfg agent.RunConfig, options runOptions, invocationID string, yield func(*session.Event, error) bool) {
+	base := parentmap.ToContext(parent, r.parents)
+	base = runconfig.ToContext(base, &runconfig.RunConfig{
+		StreamingMode: runconfig.StreamingMode(cfg.StreamingMode),
+	})
+	base = plugininternal.ToContext(base, r.pluginManager)
+	base = toolinternal.CallSemaphoreToContext(base, r.toolCalls)
+
+	var artifacts agent.Artifacts
+	if r.artifactService != nil {
+		artifacts = &artifactinternal.Artifacts{
+			Service:   r.artifactService,
+			SessionID: storedSession.ID(),
+			AppName:   storedSession.AppName(),
+			UserID:    storedSession.UserID(),
 		}
+	}
 
-		ic := icontext.NewInvocationContext(ctx, icontext.InvocationContextParams{
-			Artifacts:    artifacts,
-			Memory:       memoryImpl,
-			Session:      storedSession,
-			Agent:        r.rootAgent,
-			UserContent:  msg,
-			RunConfig:    &cfg,
-			InvocationID: resolveInvocationID(storedSession, msg),
-		})
-		ctx := agent.NewContext(ic)
-		ctx, _, err = r.appendMessageToSession(ctx, storedSession, msg, cfg.SaveInputBlobsAsArtifacts, r.pluginManager, options.stateDelta)
-		if err != nil {
-			yield(nil, err)
-			return
+	var memoryImpl agent.Memory = nil
+	if r.memoryService != nil {
+		memoryImpl = &imemory.Memory{
+			Service:   r.memoryService,
+			SessionID: storedSession.ID(),
+			UserID:    storedSession.UserID(),
+			AppName:   storedSession.AppName(),
 		}
+	}
 
-		pluginManager := r.pluginManager
-		if pluginManager != nil {
-			// Defer the after run callbacks to perform global cleanup tasks or finalizing logs and metrics data.
-			// This does NOT emit any event.
-			defer pluginManager.RunAfterRunCallback(ctx)
-
-			earlyExitResult, err := pluginManager.RunBeforeRunCallback(ctx)
-			if earlyExitResult != nil || err != nil {
-				earlyExitEvent := session.NewEvent(ctx, ctx.InvocationID())
-				earlyExitEvent.Author = "user"
-				earlyExitEvent.LLMResponse = model.LLMResponse{
-					Content: msg,
-				}
-				if err := r.sessionService.AppendEvent(ctx, storedSession, earlyExitEvent); err != nil {
-					yield(nil, fmt.Errorf("failed to add event to session: %w", err))
-					return
-				}
-				yield(earlyExitEvent, err)
+	ic := icontext.NewInvocationContext(base, icontext.InvocationContextParams{
+		Artifacts:    artifacts,
+		Memory:       memoryImpl,
+		Session:      storedSession,
+		Agent:        r.rootAgent,
+		UserContent:  msg,
+		RunConfig:    &cfg,
+		InvocationID: invocationID,
+	})
+	ctx := agent.NewContext(ic)
+	ctx, _, err := r.appendMessageToSession(ctx, storedSession, msg, cfg.SaveInputBlobsAsArtifacts, r.pluginManager, options.stateDelta)
+	if err != nil {
+		yield(nil, err)
+		return
+	}
+
+	pluginManager := r.pluginManager
+	if pluginManager != nil {
+		// Defer the after run callbacks to perform global cleanup tasks or finalizing logs and metrics data.
+		// This does NOT emit any event.
+		defer pluginManager.RunAfterRunCallback(ctx)
+
+		earlyExitResult, err := pluginManager.RunBeforeRunCallback(ctx)
+		if earlyExitResult != nil || err != nil {
+			earlyExitEvent := session.NewEvent(ctx, ctx.InvocationID())
+			earlyExitEvent.Author = "user"
+			earlyExitEvent.LLMResponse = model.LLMResponse{
+				Content: msg,
+			}
+			if err := r.sessionService.AppendEvent(ctx, storedSession, earlyExitEvent); err != nil {
+				yield(nil, fmt.Errorf("failed to add event to session: %w", err))
 				return
 			}
+			yield(earlyExitEvent, err)
+			return
 		}
+	}
 
-		for event, err := range r.rootAgent.Run(ctx) {
-			if err != nil {
-				if !yield(event, err) {
-					return
-				}
-				continue
-			}
-
-			if event != nil && !event.LLMResponse.Partial {
-				if event.NodeInfo != nil && event.NodeInfo.MessageAsOutput && event.LLMResponse.Content != nil {
-					clone := *event
-					clone.Output = nil
-					event = &clone
-				}
+	for event, err := range r.rootAgent.Run(ctx) {
+		if err != nil {
+			if !yield(event, err) {
+				return
 			}
+			continue
+		}
 
-			if pluginManager != nil {
-				modifiedEvent, err := pluginManager.RunOnEventCallback(ctx, event)
-				if err != nil {
-					if !yield(nil, err) {
-						return
-					}
-					continue
-				}
-				if modifiedEvent != nil {
-					event = modifiedEvent
-				}
+		if event != nil && !event.LLMResponse.Partial {
+			if event.NodeInfo != nil && event.NodeInfo.MessageAsOutput && event.LLMResponse.Content != nil {
+				clone := *event
+				clone.Output = nil
+				event = &clone
 			}
+		}
 
-			// only commit non-partial event to a session service
-			if !event.LLMResponse.Partial {
-				if err := r.sessionService.AppendEvent(ctx, storedSession, event); err != nil {
-					yield(nil, fmt.Errorf("failed to add event to session: %w", err))
+		if pluginManager != nil {
+			modifiedEvent, err := pluginManager.RunOnEventCallback(ctx, event)
+			if err != nil {
+				if !yield(nil, err) {
 					return
 				}
+				continue
+			}
+			if modifiedEvent != nil {
+				event = modifiedEvent
 			}
+		}
 
-			if !yield(event, nil) {
+		// only commit non-partial event to a session service
+		if !event.LLMResponse.Partial {
+			if err := r.sessionService.AppendEvent(ctx, storedSession, event); err != nil {
+				yield(nil, fmt.Errorf("failed to add event to session: %w", err))
 				return
 			}
 		}
+
+		if !yield(event, nil) {
+			return
+		}
 	}
 }
 
@@ -443,6 +528,7 @@ func (r *Runner) RunLive(ctx context.Context, userID, sessionID string, cfg agen
 		Live:          &cfg,
 	})
 	ctx = plugininternal.ToContext(ctx, r.pluginManager)
+	ctx = toolinternal.CallSemaphoreToContext(ctx, r.toolCalls)
 
 	var artifacts agent.Artifacts
 	if r.artifactService != nil {
@@ -638,3 +638,64 @@ func TestRunner_AutoCreateSession(t *testing.T) {
 		})
 	}
 }
+
+func TestRunner_StateMigrations(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	var migrations session.StateMigrations
+	if err := migrations.Register(0, func(_ context.Context, state map[string]any) error {
+		state["greeting"] = fmt.Sprintf("hello %v", state["name"])
+		delete(state, "name")
+		return nil
+	}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	var seen any
+	testAgent := must(agent.New(agent.Config{
+		Name: "test_agent",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				seen, _ = ctx.Session().State().Get("greeting")
+			}
+		},
+	}))
+
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{
+		AppName: "app", UserID: "user", SessionID: "old",
+		State: map[string]any{"name": "ada"},
+	}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	r, err := New(Config{
+		AppName:           "app",
+		Agent:             testAgent,
+		SessionService:    sessionService,
+		AutoCreateSession: true,
+		StateMigrations:   &migrations,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	msg := genai.NewContentFromText("hi", genai.RoleUser)
+	for sessionID, wantSeen := range map[string]any{"old": "hello ada", "new": nil} {
+		for _, err := range r.Run(ctx, "user", sessionID, msg, agent.RunConfig{}) {
+			if err != nil {
+				t.Fatalf("Run(%s): %v", sessionID, err)
+			}
+		}
+		if seen != wantSeen {
+			t.Errorf("Run(%s): agent saw greeting %v, want %v", sessionID, seen, wantSeen)
+		}
+		resp, err := sessionService.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: sessionID})
+		if err != nil {
+			t.Fatalf("Get(%s): %v", sessionID, err)
+		}
+		if v, err := session.StateVersion(resp.Session.State()); err != nil || v != 1 {
+			t.Errorf("session %s state version = %d, %v, want 1", sessionID, v, err)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adka2a
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/a2aproject/a2a-go/v2/a2a"
+	"github.com/a2aproject/a2a-go/v2/a2asrv"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/llminternal"
+	"google.golang.org/adk/v2/tool/skilltoolset"
+)
+
+const (
+	defaultAgentCardVersion = "1.0.0"
+
+	mimeTypeText = "text/plain"
+	mimeTypeJSON = "application/json"
+)
+
+// skillTagsMetadataKey is a skill frontmatter metadata key holding a comma-separated list of tags
+// which are added to the corresponding [a2a.AgentSkill].
+const skillTagsMetadataKey = "tags"
+
+// SecuritySchemeProvider is implemented by server authentication configurations which can describe
+// the credentials they accept. Schemes and requirements are published in agent cards built with [BuildAgentCard].
+type SecuritySchemeProvider interface {
+	// SecuritySchemes returns the accepted security schemes and the requirements clients must satisfy.
+	SecuritySchemes() (a2a.NamedSecuritySchemes, a2a.SecurityRequirementsOptions)
+}
+
+// AgentCardConfig allows to configure an agent card created with [BuildAgentCard].
+// Zero-value fields are derived from the agent, non-zero fields override derived values.
+type AgentCardConfig struct {
+	// Agent is the agent described by the card.
+	Agent agent.Agent
+
+	// Name defaults to the agent name.
+	Name string
+	// Description defaults to the agent description.
+	Description string
+	// Version defaults to "1.0.0".
+	Version string
+	// SupportedInterfaces lists the endpoints at which the agent is served.
+	SupportedInterfaces []*a2a.AgentInterface
+	// Provider is the optional information about the agent service provider.
+	Provider *a2a.AgentProvider
+	// DocumentationURL is an optional link to the agent documentation.
+	DocumentationURL string
+	// IconURL is an optional link to the agent icon.
+	IconURL string
+	// Capabilities declares optional protocol features supported by the server.
+	Capabilities a2a.AgentCapabilities
+
+	// DefaultInputModes override the MIME types derived from the agent InputSchema.
+	DefaultInputModes []string
+	// DefaultOutputModes override the MIME types derived from the agent OutputSchema and model response modalities.
+	DefaultOutputModes []string
+
+	// Skills replace all the skills derived from the agent, its tools and skill toolsets.
+	Skills []a2a.AgentSkill
+	// SkillOverrides are merged into derived skills with the matching ID. Only non-zero fields are applied.
+	SkillOverrides map[string]a2a.AgentSkill
+	// AdditionalSkills are appended to derived skills.
+	AdditionalSkills []a2a.AgentSkill
+	// ExtendedSkills are only published in the extended card served to authenticated clients.
+	ExtendedSkills []a2a.AgentSkill
+
+	// SecuritySchemeProviders are used to declare the security schemes accepted by the server.
+	SecuritySchemeProviders []SecuritySchemeProvider
+	// SecuritySchemes are declared in addition to the schemes returned by SecuritySchemeProviders.
+	SecuritySchemes a2a.NamedSecuritySchemes
+	// SecurityRequirements are added to the requirements returned by SecuritySchemeProviders.
+	SecurityRequirements a2a.SecurityRequirementsOptions
+
+	// Signer is used to sign the produced cards. Cards are not signed when it is nil.
+	Signer *AgentCardSigner
+}
+
+// BuildAgentCard creates an [a2a.AgentCard] describing the configured agent. Skills are derived from
+// agent descriptions, tools and skill toolsets, input and output modes from agent schemas and model
+// response modalities, and security schemes from SecuritySchemeProviders.
+func BuildAgentCard(ctx context.Context, config AgentCardConfig) (*a2a.AgentCard, error) {
+	if config.Agent == nil {
+		return nil, fmt.Errorf("agent is required")
+	}
+	rootAgent := config.Agent
+
+	card := &a2a.AgentCard{
+		Name:                cmp.Or(config.Name, rootAgent.Name()),
+		Description:         cmp.Or(config.Description, rootAgent.Description()),
+		Version:             cmp.Or(config.Version, defaultAgentCardVersion),
+		SupportedInterfaces: config.SupportedInterfaces,
+		Provider:            config.Provider,
+		DocumentationURL:    config.DocumentationURL,
+		IconURL:             config.IconURL,
+		Capabilities:        config.Capabilities,
+	}
+
+	inputModes, outputModes := getAgentModes(rootAgent)
+	card.DefaultInputModes = cmpOrSlice(config.DefaultInputModes, inputModes)
+	card.DefaultOutputModes = cmpOrSlice(config.DefaultOutputModes, outputModes)
+
+	if len(config.Skills) > 0 {
+		card.Skills = slices.Clone(config.Skills)
+	} else {
+		skills, err := buildDetailedAgentSkills(ctx, rootAgent, card.DefaultInputModes, card.DefaultOutputModes)
+		if err != nil {
+			return nil, err
+		}
+		card.Skills = skills
+	}
+	for i, skill := range card.Skills {
+		if override, ok := config.SkillOverrides[skill.ID]; ok {
+			card.Skills[i] = mergeAgentSkill(skill, override)
+		}
+	}
+	card.Skills = append(card.Skills, config.AdditionalSkills...)
+
+	card.SecuritySchemes, card.SecurityRequirements = buildSecurity(config)
+
+	if config.Signer != nil {
+		if err := SignAgentCard(card, *config.Signer); err != nil {
+			return nil, fmt.Errorf("failed to sign agent card: %w", err)
+		}
+	}
+	return card, nil
+}
+
+// WithExtendedAgentCard returns an [a2asrv.RequestHandlerOption] which serves an extended card to
+// authenticated clients. The card is built with [BuildAgentCard] on first request, includes ExtendedSkills
+// and is signed if Signer is configured. Requests without an authenticated [a2asrv.User] are rejected
+// with [a2a.ErrUnauthenticated].
+// The public card served alongside the handler should declare the capability with [a2a.AgentCapabilities] ExtendedAgentCard.
+func WithExtendedAgentCard(config AgentCardConfig) a2asrv.RequestHandlerOption {
+	config.Capabilities.ExtendedAgentCard = true
+	config.AdditionalSkills = slices.Concat(config.AdditionalSkills, config.ExtendedSkills)
+	build := sync.OnceValues(func() (*a2a.AgentCard, error) {
+		return BuildAgentCard(context.Background(), config)
+	})
+	return a2asrv.WithExtendedAgentCardProducer(a2asrv.ExtendedAgentCardProducerFn(
+		func(ctx context.Context, req *a2a.GetExtendedAgentCardRequest) (*a2a.AgentCard, error) {
+			callCtx, ok := a2asrv.CallContextFrom(ctx)
+			if !ok || callCtx.User == nil || !callCtx.User.Authenticated {
+				return nil, a2a.ErrUnauthenticated
+			}
+			return build()
+		},
+	))
+}
+
+// buildDetailedAgentSkills extends skills created by BuildAgentSkills with skill toolset skills and
+// input and output modes of agents which differ from the card defaults.
+func buildDetailedAgentSkills(ctx context.Context, rootAgent agent.Agent, defaultInputModes, defaultOutputModes []string) ([]a2a.AgentSkill, error) {
+	skills := BuildAgentSkills(rootAgent)
+
+	agents := map[string]agent.Agent{rootAgent.Name(): rootAgent}
+	for _, sub := range rootAgent.SubAgents() {
+		agents[fmt.Sprintf("%s_%s", sub.Name(), sub.Name())] = sub
+	}
+	for i, skill := range skills {
+		agnt, ok := agents[skill.ID]
+		if !ok {
+			continue
+		}
+		inputModes, outputModes := getAgentModes(agnt)
+		if !slices.Equal(inputModes, defaultInputModes) {
+			skills[i].InputModes = inputModes
+		}
+		if !slices.Equal(outputModes, defaultOutputModes) {
+			skills[i].OutputModes = outputModes
+		}
+	}
+
+	toolsetSkills, err := buildToolsetSkills(ctx, rootAgent)
+	if err != nil {
+		return nil, err
+	}
+	skills = append(skills, toolsetSkills...)
+	for _, sub := range rootAgent.SubAgents() {
+		subSkills, err := buildToolsetSkills(ctx, sub)
+		if err != nil {
+			return nil, err
+		}
+		for _, subSkill := range subSkills {
+			subSkill.ID = fmt.Sprintf("%s_%s", sub.Name(), subSkill.ID)
+			subSkill.Name = fmt.Sprintf("%s: %s", sub.Name(), subSkill.Name)
+			subSkill.Tags = slices.Concat([]string{fmt.Sprintf("sub_agent:%s", sub.Name())}, subSkill.Tags)
+			skills = append(skills, subSkill)
+		}
+	}
+	return skills, nil
+}
+
+func buildToolsetSkills(ctx context.Context, agnt agent.Agent) ([]a2a.AgentSkill, error) {
+	llmAgent, ok := agnt.(llminternal.Agent)
+	if !ok {
+		return nil, nil
+	}
+	var result []a2a.AgentSkill
+	for _, toolset := range llminternal.Reveal(llmAgent).Toolsets {
+		skillToolset, ok := toolset.(*skilltoolset.SkillToolset)
+		if !ok {
+			continue
+		}
+		frontmatters, err := skillToolset.Skills(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list skills of %q toolset: %w", toolset.Name(), err)
+		}
+		for _, fm := range frontmatters {
+			tags := []string{"llm", "skills"}
+			for tag := range strings.SplitSeq(fm.Metadata[skillTagsMetadataKey], ",") {
+				if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(tags, tag) {
+					tags = append(tags, tag)
+				}
+			}
+			description := fm.Description
+			if description == "" {
+				description = fmt.Sprintf("Skill: %s", fm.Name)
+			}
+			result = append(result, a2a.AgentSkill{
+				ID:          fmt.Sprintf("%s-skill-%s", agnt.Name(), fm.Name),
+				Name:        fm.Name,
+				Description: description,
+				Tags:        tags,
+			})
+		}
+	}
+	return result, nil
+}
+
+// getAgentModes returns MIME types accepted and produced by the agent. Workflow and custom agents
+// are described by the union of their sub-agent modes.
+func getAgentModes(agnt agent.Agent) (inputModes, outputModes []string) {
+	if llmAgent, ok := agnt.(llminternal.Agent); ok {
+		state := llminternal.Reveal(llmAgent)
+		return getLLMInputModes(state), getLLMOutputModes(state)
+	}
+	for _, sub := range agnt.SubAgents() {
+		subInput, subOutput := getAgentModes(sub)
+		inputModes = appendUnique(inputModes, subInput...)
+		outputModes = appendUnique(outputModes, subOutput...)
+	}
+	if len(inputModes) == 0 {
+		inputModes = []string{mimeTypeText}
+	}
+	if len(outputModes) == 0 {
+		outputModes = []string{mimeTypeText}
+	}
+	return inputModes, outputModes
+}
+
+func getLLMInputModes(state *llminternal.State) []string {
+	if state.InputSchema != nil {
+		return []string{mimeTypeJSON}
+	}
+	return []string{mimeTypeText}
+}
+
+func getLLMOutputModes(state *llminternal.State) []string {
+	if state.OutputSchema != nil {
+		return []string{mimeTypeJSON}
+	}
+	cfg := state.GenerateContentConfig
+	if cfg == nil {
+		return []string{mimeTypeText}
+	}
+	if cfg.ResponseSchema != nil || cfg.ResponseJsonSchema != nil || cfg.ResponseMIMEType == mimeTypeJSON {
+		return []string{mimeTypeJSON}
+	}
+	var modes []string
+	for _, modality := range cfg.ResponseModalities {
+		switch genai.Modality(modality) {
+		case genai.ModalityText:
+			modes = appendUnique(modes, cmp.Or(cfg.ResponseMIMEType, mimeTypeText))
+		case genai.ModalityImage:
+			modes = appendUnique(modes, "image/*")
+		case genai.ModalityAudio:
+			modes = appendUnique(modes, "audio/*")
+		}
+	}
+	if len(modes) == 0 {
+		modes = []string{cmp.Or(cfg.ResponseMIMEType, mimeTypeText)}
+	}
+	return modes
+}
+
+func buildSecurity(config AgentCardConfig) (a2a.NamedSecuritySchemes, a2a.SecurityRequirementsOptions) {
+	var schemes a2a.NamedSecuritySchemes
+	var requirements a2a.SecurityRequirementsOptions
+	addSchemes := func(s a2a.NamedSecuritySchemes) {
+		if len(s) == 0 {
+			return
+		}
+		if schemes == nil {
+			schemes = make(a2a.NamedSecuritySchemes, len(s))
+		}
+		maps.Copy(schemes, s)
+	}
+	for _, provider := range config.SecuritySchemeProviders {
+		s, r := provider.SecuritySchemes()
+		addSchemes(s)
+		requirements = append(requirements, r...)
+	}
+	addSchemes(config.SecuritySchemes)
+	requirements = append(requirements, config.SecurityRequirements...)
+	return schemes, requirements
+}
+
+func mergeAgentSkill(skill, override a2a.AgentSkill) a2a.AgentSkill {
+	skill.Name = cmp.Or(override.Name, skill.Name)
+	skill.Description = cmp.Or(override.Description, skill.Description)
+	skill.Tags = cmpOrSlice(override.Tags, skill.Tags)
+	skill.Examples = cmpOrSlice(override.Examples, skill.Examples)
+	skill.InputModes = cmpOrSlice(override.InputModes, skill.InputModes)
+	skill.OutputModes = cmpOrSlice(override.OutputModes, skill.OutputModes)
+	if len(override.SecurityRequirements) > 0 {
+		skill.SecurityRequirements = override.SecurityRequirements
+	}
+	return skill
+}
+
+func appendUnique(s []string, values ...string) []string {
+	for _, v := range values {
+		if !slices.Contains(s, v) {
+			s = append(s, v)
+		}
+	}
+	return s
+}
+
+func cmpOrSlice(value, fallback []string) []string {
+	if len(value) > 0 {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,257 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adka2a
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/v2/a2a"
+	"github.com/a2aproject/a2a-go/v2/a2asrv"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/agent/llmagent"
+	"google.golang.org/adk/v2/agent/workflowagents/sequentialagent"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/skilltoolset"
+	"google.golang.org/adk/v2/tool/skilltoolset/skill"
+)
+
+type fakeSkillSource struct {
+	skill.Source
+	frontmatters []*skill.Frontmatter
+}
+
+func (s *fakeSkillSource) ListFrontmatters(ctx context.Context) ([]*skill.Frontmatter, error) {
+	return s.frontmatters, nil
+}
+
+type fakeSecuritySchemeProvider struct{}
+
+func (fakeSecuritySchemeProvider) SecuritySchemes() (a2a.NamedSecuritySchemes, a2a.SecurityRequirementsOptions) {
+	return a2a.NamedSecuritySchemes{
+		"apiKey": a2a.APIKeySecurityScheme{Location: a2a.APIKeySecuritySchemeLocationHeader, Name: "X-API-Key"},
+	}, a2a.SecurityRequirementsOptions{
+		{"apiKey": a2a.SecuritySchemeScopes{}},
+	}
+}
+
+func TestBuildAgentCard(t *testing.T) {
+	skills, err := skilltoolset.New(t.Context(), skilltoolset.Config{Source: &fakeSkillSource{
+		frontmatters: []*skill.Frontmatter{
+			{Name: "pdf", Description: "Extract text from PDF files.", Metadata: map[string]string{"tags": "documents, pdf"}},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("skilltoolset.New() error = %v", err)
+	}
+	schema := &genai.Schema{Type: genai.TypeObject, Properties: map[string]*genai.Schema{"city": {Type: genai.TypeString}}}
+
+	testCases := []struct {
+		name   string
+		config AgentCardConfig
+		want   *a2a.AgentCard
+	}{
+		{
+			name: "llm agent with skills",
+			config: AgentCardConfig{
+				Agent: must(llmagent.New(llmagent.Config{
+					Name:        "assistant",
+					Description: "Helps with documents.",
+					Toolsets:    []tool.Toolset{skills},
+				})),
+			},
+			want: &a2a.AgentCard{
+				Name:               "assistant",
+				Description:        "Helps with documents.",
+				Version:            "1.0.0",
+				DefaultInputModes:  []string{"text/plain"},
+				DefaultOutputModes: []string{"text/plain"},
+				Skills: []a2a.AgentSkill{
+					{ID: "assistant", Name: "model", Description: "Helps with documents.", Tags: []string{"llm"}},
+					{ID: "assistant-skill-pdf", Name: "pdf", Description: "Extract text from PDF files.", Tags: []string{"llm", "skills", "documents", "pdf"}},
+				},
+			},
+		},
+		{
+			name: "modes from schemas and modalities",
+			config: AgentCardConfig{
+				Agent: must(sequentialagent.New(sequentialagent.Config{
+					AgentConfig: agent.Config{
+						Name: "pipeline",
+						SubAgents: []agent.Agent{
+							must(llmagent.New(llmagent.Config{Name: "parser", InputSchema: schema, OutputSchema: schema})),
+							must(llmagent.New(llmagent.Config{
+								Name: "painter",
+								GenerateContentConfig: &genai.GenerateContentConfig{
+									ResponseModalities: []string{string(genai.ModalityText), string(genai.ModalityImage)},
+								},
+							})),
+						},
+					},
+				})),
+			},
+			want: &a2a.AgentCard{
+				Name:               "pipeline",
+				Version:            "1.0.0",
+				DefaultInputModes:  []string{"application/json", "text/plain"},
+				DefaultOutputModes: []string{"application/json", "text/plain", "image/*"},
+				Skills: []a2a.AgentSkill{
+					{
+						ID:          "pipeline",
+						Name:        "workflow",
+						Description: "First, this agent will execute the parser agent. Finally, this agent will execute the painter agent.",
+						Tags:        []string{"sequential_workflow"},
+					},
+					{
+						ID:          "pipeline-sub-agents",
+						Name:        "sub-agents",
+						Description: "Orchestrates: No description; No description",
+						Tags:        []string{"sequential_workflow", "orchestration"},
+					},
+					{
+						ID:          "parser_parser",
+						Name:        "parser: model",
+						Description: "An LLM-based agent",
+						Tags:        []string{"sub_agent:parser", "llm"},
+						InputModes:  []string{"application/json"},
+						OutputModes: []string{"application/json"},
+					},
+					{
+						ID:          "painter_painter",
+						Name:        "painter: model",
+						Description: "An LLM-based agent",
+						Tags:        []string{"sub_agent:painter", "llm"},
+						InputModes:  []string{"text/plain"},
+						OutputModes: []string{"text/plain", "image/*"},
+					},
+				},
+			},
+		},
+		{
+			name: "overrides and security",
+			config: AgentCardConfig{
+				Agent:              must(llmagent.New(llmagent.Config{Name: "assistant"})),
+				Name:               "Assistant",
+				Description:        "A helpful assistant.",
+				Version:            "2.1.0",
+				Capabilities:       a2a.AgentCapabilities{Streaming: true},
+				DefaultOutputModes: []string{"text/markdown"},
+				SkillOverrides: map[string]a2a.AgentSkill{
+					"assistant": {Name: "chat", Examples: []string{"What can you do?"}},
+				},
+				AdditionalSkills:        []a2a.AgentSkill{{ID: "extra", Name: "extra", Description: "Extra skill.", Tags: []string{"custom"}}},
+				SecuritySchemeProviders: []SecuritySchemeProvider{fakeSecuritySchemeProvider{}},
+				SecuritySchemes: a2a.NamedSecuritySchemes{
+					"bearer": a2a.HTTPAuthSecurityScheme{Scheme: "Bearer", BearerFormat: "JWT"},
+				},
+				SecurityRequirements: a2a.SecurityRequirementsOptions{{"bearer": a2a.SecuritySchemeScopes{}}},
+			},
+			want: &a2a.AgentCard{
+				Name:               "Assistant",
+				Description:        "A helpful assistant.",
+				Version:            "2.1.0",
+				Capabilities:       a2a.AgentCapabilities{Streaming: true},
+				DefaultInputModes:  []string{"text/plain"},
+				DefaultOutputModes: []string{"text/markdown"},
+				Skills: []a2a.AgentSkill{
+					{ID: "assistant", Name: "chat", Description: "An LLM-based agent", Tags: []string{"llm"}, Examples: []string{"What can you do?"}, OutputModes: []string{"text/plain"}},
+					{ID: "extra", Name: "extra", Description: "Extra skill.", Tags: []string{"custom"}},
+				},
+				SecuritySchemes: a2a.NamedSecuritySchemes{
+					"apiKey": a2a.APIKeySecurityScheme{Location: a2a.APIKeySecuritySchemeLocationHeader, Name: "X-API-Key"},
+					"bearer": a2a.HTTPAuthSecurityScheme{Scheme: "Bearer", BearerFormat: "JWT"},
+				},
+				SecurityRequirements: a2a.SecurityRequirementsOptions{
+					{"apiKey": a2a.SecuritySchemeScopes{}},
+					{"bearer": a2a.SecuritySchemeScopes{}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := BuildAgentCard(t.Context(), tc.config)
+			if err != nil {
+				t.Fatalf("BuildAgentCard() error = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("BuildAgentCard() wrong result (+got,-want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBuildAgentCard_Signed(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() error = %v", err)
+	}
+	card, err := BuildAgentCard(t.Context(), AgentCardConfig{
+		Agent:  must(llmagent.New(llmagent.Config{Name: "assistant"})),
+		Signer: &AgentCardSigner{Signer: privateKey, KeyID: "key-1"},
+	})
+	if err != nil {
+		t.Fatalf("BuildAgentCard() error = %v", err)
+	}
+	if len(card.Signatures) != 1 {
+		t.Fatalf("len(card.Signatures) = %d, want 1", len(card.Signatures))
+	}
+	if err := VerifyAgentCardSignature(card, publicKey); err != nil {
+		t.Errorf("VerifyAgentCardSignature() error = %v", err)
+	}
+}
+
+func TestWithExtendedAgentCard(t *testing.T) {
+	handler := a2asrv.NewHandler(NewExecutor(ExecutorConfig{}), WithExtendedAgentCard(AgentCardConfig{
+		Agent:          must(llmagent.New(llmagent.Config{Name: "assistant"})),
+		ExtendedSkills: []a2a.AgentSkill{{ID: "admin", Name: "admin", Description: "Administrative operations.", Tags: []string{"admin"}}},
+	}))
+
+	testCases := []struct {
+		name    string
+		user    *a2asrv.User
+		wantErr error
+	}{
+		{name: "anonymous", wantErr: a2a.ErrUnauthenticated},
+		{name: "unauthenticated user", user: &a2asrv.User{Name: "guest"}, wantErr: a2a.ErrUnauthenticated},
+		{name: "authenticated user", user: a2asrv.NewAuthenticatedUser("alice", nil)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, callCtx := a2asrv.NewCallContext(t.Context(), nil)
+			callCtx.User = tc.user
+			card, err := handler.GetExtendedAgentCard(ctx, &a2a.GetExtendedAgentCardRequest{})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("GetExtendedAgentCard() error = %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !card.Capabilities.ExtendedAgentCard {
+				t.Errorf("card.Capabilities.ExtendedAgentCard = false, want true")
+			}
+			if last := card.Skills[len(card.Skills)-1]; last.ID != "admin" {
+				t.Errorf("last card skill ID = %q, want %q", last.ID, "admin")
+			}
+		})
+	}
+}
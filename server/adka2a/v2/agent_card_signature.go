@@ -0,0 +1,203 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adka2a
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/a2aproject/a2a-go/v2/a2a"
+)
+
+// ErrInvalidAgentCardSignature is returned by [VerifyAgentCardSignature] if none of the card signatures
+// can be verified with the provided key.
+var ErrInvalidAgentCardSignature = errors.New("invalid agent card signature")
+
+// AgentCardSigner signs agent cards with a JSON Web Signature computed over the canonical JSON
+// representation of the card. Supported keys are ECDSA P-256 (ES256), Ed25519 (EdDSA) and RSA (RS256).
+type AgentCardSigner struct {
+	// Signer is the private key used for signing.
+	Signer crypto.Signer
+	// KeyID is an optional "kid" header value which helps clients select a verification key.
+	KeyID string
+	// JWKSURL is an optional "jku" header value pointing to a JWK Set with the verification key.
+	JWKSURL string
+}
+
+type jwsHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+	JWKSURL   string `json:"jku,omitempty"`
+}
+
+// SignAgentCard appends an [a2a.AgentCardSignature] to the card. The payload is detached: it is the
+// card without signatures serialized with sorted keys, as recommended by the A2A specification.
+func SignAgentCard(card *a2a.AgentCard, signer AgentCardSigner) error {
+	if signer.Signer == nil {
+		return fmt.Errorf("signer is required")
+	}
+	alg, err := getSigningAlgorithm(signer.Signer.Public())
+	if err != nil {
+		return err
+	}
+	header, err := json.Marshal(jwsHeader{Algorithm: alg, Type: "JOSE", KeyID: signer.KeyID, JWKSURL: signer.JWKSURL})
+	if err != nil {
+		return fmt.Errorf("failed to serialize signature header: %w", err)
+	}
+	payload, err := canonicalizeAgentCard(card)
+	if err != nil {
+		return err
+	}
+	protected := base64.RawURLEncoding.EncodeToString(header)
+	signature, err := signJWS(signer.Signer, alg, signingInput(protected, payload))
+	if err != nil {
+		return fmt.Errorf("failed to sign agent card: %w", err)
+	}
+	card.Signatures = append(card.Signatures, a2a.AgentCardSignature{
+		Protected: protected,
+		Signature: base64.RawURLEncoding.EncodeToString(signature),
+	})
+	return nil
+}
+
+// VerifyAgentCardSignature returns nil if at least one of the card signatures was produced by the private
+// key matching the provided public key.
+func VerifyAgentCardSignature(card *a2a.AgentCard, key crypto.PublicKey) error {
+	if len(card.Signatures) == 0 {
+		return fmt.Errorf("%w: card is not signed", ErrInvalidAgentCardSignature)
+	}
+	alg, err := getSigningAlgorithm(key)
+	if err != nil {
+		return err
+	}
+	payload, err := canonicalizeAgentCard(card)
+	if err != nil {
+		return err
+	}
+	for _, sig := range card.Signatures {
+		headerJSON, err := base64.RawURLEncoding.DecodeString(sig.Protected)
+		if err != nil {
+			continue
+		}
+		var header jwsHeader
+		if err := json.Unmarshal(headerJSON, &header); err != nil || header.Algorithm != alg {
+			continue
+		}
+		signature, err := base64.RawURLEncoding.DecodeString(sig.Signature)
+		if err != nil {
+			continue
+		}
+		if verifyJWS(key, signingInput(sig.Protected, payload), signature) {
+			return nil
+		}
+	}
+	return ErrInvalidAgentCardSignature
+}
+
+// canonicalizeAgentCard serializes the card without signatures with lexicographically sorted keys,
+// no insignificant whitespace and no HTML escaping.
+func canonicalizeAgentCard(card *a2a.AgentCard) ([]byte, error) {
+	unsigned := *card
+	unsigned.Signatures = nil
+	raw, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize agent card: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var generic any
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("failed to canonicalize agent card: %w", err)
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	// maps are encoded with sorted keys
+	if err := encoder.Encode(generic); err != nil {
+		return nil, fmt.Errorf("failed to canonicalize agent card: %w", err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func signingInput(protected string, payload []byte) []byte {
+	return []byte(protected + "." + base64.RawURLEncoding.EncodeToString(payload))
+}
+
+func getSigningAlgorithm(key crypto.PublicKey) (string, error) {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return "", fmt.Errorf("unsupported ECDSA curve %s, only P-256 is supported", k.Curve.Params().Name)
+		}
+		return "ES256", nil
+	case ed25519.PublicKey:
+		return "EdDSA", nil
+	case *rsa.PublicKey:
+		return "RS256", nil
+	default:
+		return "", fmt.Errorf("unsupported signing key type %T", key)
+	}
+}
+
+func signJWS(signer crypto.Signer, alg string, input []byte) ([]byte, error) {
+	if alg == "EdDSA" {
+		return signer.Sign(rand.Reader, input, crypto.Hash(0))
+	}
+	digest := sha256.Sum256(input)
+	signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil || alg != "ES256" {
+		return signature, err
+	}
+	// JWS uses fixed-size R || S encoding instead of ASN.1 DER produced by ecdsa signers
+	var parsed struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(signature, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse ECDSA signature: %w", err)
+	}
+	result := make([]byte, 64)
+	parsed.R.FillBytes(result[:32])
+	parsed.S.FillBytes(result[32:])
+	return result, nil
+}
+
+func verifyJWS(key crypto.PublicKey, input, signature []byte) bool {
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, input, signature)
+	case *ecdsa.PublicKey:
+		if len(signature) != 64 {
+			return false
+		}
+		digest := sha256.Sum256(input)
+		r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+		return ecdsa.Verify(k, digest[:], r, s)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(input)
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature) == nil
+	}
+	return false
+}
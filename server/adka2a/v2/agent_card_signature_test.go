@@ -0,0 +1,122 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adka2a
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/v2/a2a"
+)
+
+func TestSignAgentCard(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() error = %v", err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() error = %v", err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+
+	testCases := []struct {
+		name    string
+		signer  crypto.Signer
+		wantAlg string
+	}{
+		{name: "ecdsa", signer: ecKey, wantAlg: "ES256"},
+		{name: "ed25519", signer: edKey, wantAlg: "EdDSA"},
+		{name: "rsa", signer: rsaKey, wantAlg: "RS256"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			card := &a2a.AgentCard{
+				Name:        "agent",
+				Description: "Answers <questions> & more",
+				Version:     "1.0.0",
+				Skills:      []a2a.AgentSkill{{ID: "skill", Name: "skill", Tags: []string{"tag"}}},
+			}
+			if err := SignAgentCard(card, AgentCardSigner{Signer: tc.signer, KeyID: "kid"}); err != nil {
+				t.Fatalf("SignAgentCard() error = %v", err)
+			}
+			if err := VerifyAgentCardSignature(card, tc.signer.Public()); err != nil {
+				t.Fatalf("VerifyAgentCardSignature() error = %v", err)
+			}
+
+			// signatures survive serialization round trip
+			data, err := json.Marshal(card)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			var decoded a2a.AgentCard
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if err := VerifyAgentCardSignature(&decoded, tc.signer.Public()); err != nil {
+				t.Errorf("VerifyAgentCardSignature() after round trip error = %v", err)
+			}
+
+			headerJSON, err := base64.RawURLEncoding.DecodeString(card.Signatures[0].Protected)
+			if err != nil {
+				t.Fatalf("base64 decode of protected header error = %v", err)
+			}
+			var header jwsHeader
+			if err := json.Unmarshal(headerJSON, &header); err != nil {
+				t.Fatalf("json.Unmarshal() header error = %v", err)
+			}
+			if header.Algorithm != tc.wantAlg || header.KeyID != "kid" {
+				t.Errorf("signature header = %+v, want alg %q and kid %q", header, tc.wantAlg, "kid")
+			}
+
+			decoded.Description = "Tampered"
+			if err := VerifyAgentCardSignature(&decoded, tc.signer.Public()); !errors.Is(err, ErrInvalidAgentCardSignature) {
+				t.Errorf("VerifyAgentCardSignature() of tampered card error = %v, want %v", err, ErrInvalidAgentCardSignature)
+			}
+		})
+	}
+}
+
+func TestVerifyAgentCardSignature_WrongKey(t *testing.T) {
+	_, signingKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() error = %v", err)
+	}
+	otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() error = %v", err)
+	}
+	card := &a2a.AgentCard{Name: "agent"}
+	if err := VerifyAgentCardSignature(card, otherKey); !errors.Is(err, ErrInvalidAgentCardSignature) {
+		t.Errorf("VerifyAgentCardSignature() of unsigned card error = %v, want %v", err, ErrInvalidAgentCardSignature)
+	}
+	if err := SignAgentCard(card, AgentCardSigner{Signer: signingKey}); err != nil {
+		t.Fatalf("SignAgentCard() error = %v", err)
+	}
+	if err := VerifyAgentCardSignature(card, otherKey); !errors.Is(err, ErrInvalidAgentCardSignature) {
+		t.Errorf("VerifyAgentCardSignature() error = %v, want %v", err, ErrInvalidAgentCardSignature)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adka2a
+
+import (
+	"context"
+
+	"github.com/a2aproject/a2a-go/v2/a2asrv"
+
+	"google.golang.org/adk/v2/agent"
+)
+
+// WithPrincipalUser returns an [a2asrv.RequestHandlerOption] which exposes an [agent.Principal] attached
+// to the request context, for example by authentication middleware, as an authenticated [a2asrv.User].
+// Agents are then invoked on behalf of the principal UserID instead of a user derived from the A2A context ID.
+func WithPrincipalUser() a2asrv.RequestHandlerOption {
+	return a2asrv.WithCallInterceptors(principalInterceptor{})
+}
+
+type principalInterceptor struct {
+	a2asrv.PassthroughCallInterceptor
+}
+
+// Before implements a2asrv.CallInterceptor.
+func (principalInterceptor) Before(ctx context.Context, callCtx *a2asrv.CallContext, req *a2asrv.Request) (context.Context, any, error) {
+	if principal, ok := agent.PrincipalFromContext(ctx); ok {
+		callCtx.User = a2asrv.NewAuthenticatedUser(principal.UserID, map[string]any{
+			"subject": principal.Subject,
+			"method":  principal.Method,
+		})
+	}
+	return ctx, nil, nil
+}
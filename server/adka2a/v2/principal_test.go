@@ -0,0 +1,42 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adka2a
+
+import (
+	"testing"
+
+	"github.com/a2aproject/a2a-go/v2/a2asrv"
+
+	"google.golang.org/adk/v2/agent"
+)
+
+func TestPrincipalInterceptor(t *testing.T) {
+	ctx, callCtx := a2asrv.NewCallContext(t.Context(), nil)
+	if _, _, err := (principalInterceptor{}).Before(ctx, callCtx, &a2asrv.Request{}); err != nil {
+		t.Fatalf("Before() error = %v", err)
+	}
+	if callCtx.User != nil && callCtx.User.Authenticated {
+		t.Errorf("Before() without principal set authenticated user %+v", callCtx.User)
+	}
+
+	principal := &agent.Principal{UserID: "alice", Subject: "CN=alice", Method: "mtls"}
+	ctx, callCtx = a2asrv.NewCallContext(agent.NewContextWithPrincipal(t.Context(), principal), nil)
+	if _, _, err := (principalInterceptor{}).Before(ctx, callCtx, &a2asrv.Request{}); err != nil {
+		t.Fatalf("Before() error = %v", err)
+	}
+	if callCtx.User == nil || !callCtx.User.Authenticated || callCtx.User.Name != "alice" {
+		t.Errorf("Before() user = %+v, want authenticated user alice", callCtx.User)
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adka2a
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a2aproject/a2a-go/v2/a2a"
+	"github.com/a2aproject/a2a-go/v2/a2asrv"
+	"github.com/a2aproject/a2a-go/v2/a2asrv/push"
+	"github.com/a2aproject/a2a-go/v2/log"
+
+	"google.golang.org/adk/v2/internal/netguard"
+)
+
+const (
+	// PushTokenHeader carries the [a2a.PushConfig] Token registered by the client.
+	PushTokenHeader = "A2A-Notification-Token"
+	// PushTimestampHeader carries the unix time (in seconds) at which a notification was signed.
+	PushTimestampHeader = "X-ADK-Push-Timestamp"
+	// PushSignatureHeader carries the HMAC-SHA256 signature of a notification payload.
+	// The value has the form "v1=<hex digest>", where the digest is computed over "<timestamp>.<body>".
+	PushSignatureHeader = "X-ADK-Push-Signature"
+)
+
+const (
+	defaultPushTimeout        = 30 * time.Second
+	defaultPushMaxAttempts    = 3
+	defaultPushInitialBackoff = 500 * time.Millisecond
+	defaultPushMaxBackoff     = 10 * time.Second
+)
+
+// ErrInvalidPushSignature is returned by [VerifyPushSignature] if a notification signature is missing,
+// malformed, expired or does not match the payload.
+var ErrInvalidPushSignature = errors.New("invalid push notification signature")
+
+// ErrPushURLBlocked is the cause of the delivery failures of notifications to webhooks at addresses which
+// are not publicly routable, see [PushSenderConfig] AllowPrivateNetworks.
+var ErrPushURLBlocked = errors.New("push notification URL is not allowed")
+
+// PushSenderConfig allows to configure a push notification sender created with [NewPushSender].
+type PushSenderConfig struct {
+	// Client is used for webhook delivery, as is. Defaults to an [http.Client] with a 30 second timeout which
+	// doesn't follow redirects and, unless AllowPrivateNetworks is set, refuses to connect to addresses which
+	// are not publicly routable.
+	Client *http.Client
+	// AllowPrivateNetworks allows the default Client to deliver notifications to loopback, link-local and
+	// private addresses. By default, webhook URLs registered by clients can't make the server reach internal
+	// endpoints such as cloud metadata servers.
+	AllowPrivateNetworks bool
+	// SigningKey enables payload signing. When set, every notification carries [PushTimestampHeader] and
+	// [PushSignatureHeader] which receivers can check with [VerifyPushSignature].
+	SigningKey []byte
+	// MaxAttempts is the maximum number of delivery attempts for a single notification. Defaults to 3.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. It is doubled after every failed attempt. Defaults to 500ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts, including delays requested with Retry-After. Defaults to 10s.
+	MaxBackoff time.Duration
+	// FailOnError makes delivery failures stop the execution. By default failures are logged and ignored.
+	FailOnError bool
+}
+
+// PushNotificationConfig is used to enable push notification support in an A2A request handler.
+type PushNotificationConfig struct {
+	// Store keeps push notification configs registered by clients.
+	// Defaults to an in-memory store which is lost on restart.
+	Store push.ConfigStore
+	// Sender delivers notifications to registered webhooks. Defaults to a sender created with [NewPushSender]
+	// using SenderConfig.
+	Sender push.Sender
+	// SenderConfig configures the default Sender. It is ignored when Sender is set.
+	SenderConfig PushSenderConfig
+}
+
+// WithPushNotifications returns an [a2asrv.RequestHandlerOption] which makes the request handler honour
+// push notification configs attached to tasks. Agent cards served alongside the handler should declare
+// the capability with [a2a.AgentCapabilities] PushNotifications.
+func WithPushNotifications(config PushNotificationConfig) a2asrv.RequestHandlerOption {
+	store := config.Store
+	if store == nil {
+		store = push.NewInMemoryStore()
+	}
+	sender := config.Sender
+	if sender == nil {
+		sender = NewPushSender(config.SenderConfig)
+	}
+	return a2asrv.WithPushNotifications(store, sender)
+}
+
+type pushSender struct {
+	client         *http.Client
+	signingKey     []byte
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	failOnError    bool
+	now            func() time.Time
+}
+
+var _ push.Sender = (*pushSender)(nil)
+
+// NewPushSender creates a [push.Sender] which delivers A2A events as JSON webhooks. Transient failures
+// (network errors, 408, 425, 429 and 5xx responses) are retried with exponential backoff.
+func NewPushSender(config PushSenderConfig) push.Sender {
+	client := config.Client
+	if client == nil {
+		client = &http.Client{
+			Timeout: defaultPushTimeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+		if !config.AllowPrivateNetworks {
+			// The default transport can always be guarded.
+			client.Transport, _ = netguard.PublicTransport(nil, ErrPushURLBlocked)
+		}
+	}
+	maxAttempts := config.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultPushMaxAttempts
+	}
+	initialBackoff := config.InitialBackoff
+	if initialBackoff <= 0 {
+		initialBackoff = defaultPushInitialBackoff
+	}
+	maxBackoff := config.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultPushMaxBackoff
+	}
+	return &pushSender{
+		client:         client,
+		signingKey:     config.SigningKey,
+		maxAttempts:    maxAttempts,
+		initialBackoff: initialBackoff,
+		maxBackoff:     maxBackoff,
+		failOnError:    config.FailOnError,
+		now:            time.Now,
+	}
+}
+
+// SendPush implements push.Sender.
+func (s *pushSender) SendPush(ctx context.Context, config *a2a.PushConfig, event a2a.Event) error {
+	body, err := json.Marshal(a2a.StreamResponse{Event: event})
+	if err != nil {
+		return s.handleError(ctx, config, fmt.Errorf("failed to serialize event: %w", err))
+	}
+
+	backoff := s.initialBackoff
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := s.deliver(ctx, config, body)
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentPushError
+		if errors.As(err, &permanent) || attempt >= s.maxAttempts {
+			return s.handleError(ctx, config, fmt.Errorf("push delivery failed after %d attempt(s): %w", attempt, err))
+		}
+
+		delay := min(max(backoff, retryAfter), s.maxBackoff)
+		log.Debug(ctx, "retrying push notification delivery", "url", config.URL, "attempt", attempt, "delay", delay, "cause", err)
+		select {
+		case <-ctx.Done():
+			return s.handleError(ctx, config, fmt.Errorf("push delivery aborted: %w", context.Cause(ctx)))
+		case <-time.After(delay):
+		}
+		backoff *= 2
+	}
+}
+
+// deliver makes a single delivery attempt. It returns the delay requested by the receiver with Retry-After, if any.
+func (s *pushSender) deliver(ctx context.Context, config *a2a.PushConfig, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, &permanentPushError{err: fmt.Errorf("failed to create request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if config.Token != "" {
+		req.Header.Set(PushTokenHeader, config.Token)
+	}
+	if config.Auth != nil && config.Auth.Credentials != "" {
+		switch strings.ToLower(config.Auth.Scheme) {
+		case "bearer":
+			req.Header.Set("Authorization", "Bearer "+config.Auth.Credentials)
+		case "basic":
+			req.Header.Set("Authorization", "Basic "+config.Auth.Credentials)
+		}
+	}
+	if len(s.signingKey) > 0 {
+		timestamp := strconv.FormatInt(s.now().Unix(), 10)
+		req.Header.Set(PushTimestampHeader, timestamp)
+		req.Header.Set(PushSignatureHeader, signPush(s.signingKey, timestamp, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if errors.Is(err, ErrPushURLBlocked) {
+		return 0, &permanentPushError{err: err}
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		if err := resp.Body.Close(); err != nil {
+			log.Warn(ctx, "push response body close failed", "error", err)
+		}
+	}()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return 0, nil
+	}
+	err = fmt.Errorf("endpoint returned non-success status: %s", resp.Status)
+	if !isRetryablePushStatus(resp.StatusCode) {
+		return 0, &permanentPushError{err: err}
+	}
+	return parseRetryAfter(resp.Header.Get("Retry-After")), err
+}
+
+func (s *pushSender) handleError(ctx context.Context, config *a2a.PushConfig, err error) error {
+	if s.failOnError {
+		return err
+	}
+	log.Error(ctx, "push notification sending failed", err, "url", config.URL, "task_id", config.TaskID)
+	return nil
+}
+
+// VerifyPushSignature checks the signature of a push notification produced by a sender created with
+// [NewPushSender] configured with the same key. Signatures older than tolerance are rejected, a zero
+// tolerance disables the check.
+func VerifyPushSignature(key []byte, header http.Header, body []byte, tolerance time.Duration) error {
+	timestamp, signature := header.Get(PushTimestampHeader), header.Get(PushSignatureHeader)
+	if timestamp == "" || signature == "" {
+		return fmt.Errorf("%w: signature headers missing", ErrInvalidPushSignature)
+	}
+	if tolerance > 0 {
+		unix, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: malformed timestamp", ErrInvalidPushSignature)
+		}
+		if age := time.Since(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+			return fmt.Errorf("%w: timestamp outside of tolerance", ErrInvalidPushSignature)
+		}
+	}
+	if !hmac.Equal([]byte(signature), []byte(signPush(key, timestamp, body))) {
+		return fmt.Errorf("%w: signature mismatch", ErrInvalidPushSignature)
+	}
+	return nil
+}
+
+func signPush(key []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func isRetryablePushStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+		return true
+	}
+	return code >= 500
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return 0
+}
+
+type permanentPushError struct {
+	err error
+}
+
+func (e *permanentPushError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentPushError) Unwrap() error {
+	return e.err
+}
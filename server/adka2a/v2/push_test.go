@@ -0,0 +1,220 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adka2a
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/v2/a2a"
+)
+
+func TestPushSender_SendPush(t *testing.T) {
+	signingKey := []byte("secret")
+	task := &a2a.Task{ID: a2a.NewTaskID(), ContextID: a2a.NewContextID()}
+	event := a2a.NewStatusUpdateEvent(task, a2a.TaskStateCompleted, nil)
+
+	testCases := []struct {
+		name         string
+		statuses     []int
+		failOnError  bool
+		wantAttempts int
+		wantErr      bool
+	}{
+		{
+			name:         "delivered on first attempt",
+			statuses:     []int{http.StatusOK},
+			wantAttempts: 1,
+		},
+		{
+			name:         "retried after transient failures",
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusTooEarly, http.StatusAccepted},
+			wantAttempts: 3,
+		},
+		{
+			name:         "permanent failure is not retried",
+			statuses:     []int{http.StatusBadRequest},
+			failOnError:  true,
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "attempts exhausted",
+			statuses:     []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			failOnError:  true,
+			wantAttempts: 3,
+			wantErr:      true,
+		},
+		{
+			name:         "failure ignored by default",
+			statuses:     []int{http.StatusBadRequest},
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := int(attempts.Add(1))
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("io.ReadAll() error = %v", err)
+				}
+				if err := VerifyPushSignature(signingKey, r.Header, body, time.Minute); err != nil {
+					t.Errorf("VerifyPushSignature() error = %v", err)
+				}
+				if got := r.Header.Get(PushTokenHeader); got != "token" {
+					t.Errorf("r.Header[%s] = %q, want %q", PushTokenHeader, got, "token")
+				}
+				var resp a2a.StreamResponse
+				if err := json.Unmarshal(body, &resp); err != nil {
+					t.Errorf("json.Unmarshal() error = %v", err)
+				}
+				if resp.TaskInfo().TaskID != task.ID {
+					t.Errorf("notification task ID = %q, want %q", resp.TaskInfo().TaskID, task.ID)
+				}
+				w.WriteHeader(tc.statuses[min(attempt, len(tc.statuses))-1])
+			}))
+			defer server.Close()
+
+			sender := NewPushSender(PushSenderConfig{
+				SigningKey:           signingKey,
+				InitialBackoff:       time.Millisecond,
+				FailOnError:          tc.failOnError,
+				AllowPrivateNetworks: true,
+			})
+			err := sender.SendPush(t.Context(), &a2a.PushConfig{URL: server.URL, Token: "token"}, event)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("SendPush() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got := int(attempts.Load()); got != tc.wantAttempts {
+				t.Errorf("delivery attempts = %d, want %d", got, tc.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestPushSender_DefaultClient(t *testing.T) {
+	task := &a2a.Task{ID: a2a.NewTaskID(), ContextID: a2a.NewContextID()}
+	event := a2a.NewStatusUpdateEvent(task, a2a.TaskStateCompleted, nil)
+	var hits atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer target.Close()
+	redirect := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	defer redirect.Close()
+
+	testCases := []struct {
+		name    string
+		config  PushSenderConfig
+		url     string
+		wantErr error
+	}{
+		{
+			name:    "private address",
+			url:     target.URL,
+			wantErr: ErrPushURLBlocked,
+		},
+		{
+			name:   "redirect",
+			config: PushSenderConfig{AllowPrivateNetworks: true},
+			url:    redirect.URL,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.config.MaxAttempts, tc.config.FailOnError = 3, true
+			err := NewPushSender(tc.config).SendPush(t.Context(), &a2a.PushConfig{URL: tc.url}, event)
+			if err == nil || (tc.wantErr != nil && !errors.Is(err, tc.wantErr)) {
+				t.Errorf("SendPush() error = %v, want %v", err, tc.wantErr)
+			}
+			if got := hits.Load(); got != 0 {
+				t.Errorf("webhook got %d requests, want none", got)
+			}
+		})
+	}
+}
+
+func TestVerifyPushSignature(t *testing.T) {
+	key := []byte("secret")
+	body := []byte(`{"statusUpdate":{}}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	newHeader := func(timestamp, signature string) http.Header {
+		h := http.Header{}
+		h.Set(PushTimestampHeader, timestamp)
+		h.Set(PushSignatureHeader, signature)
+		return h
+	}
+
+	testCases := []struct {
+		name    string
+		header  http.Header
+		body    []byte
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			header: newHeader(now, signPush(key, now, body)),
+			body:   body,
+		},
+		{
+			name:    "missing headers",
+			header:  http.Header{},
+			body:    body,
+			wantErr: true,
+		},
+		{
+			name:    "tampered body",
+			header:  newHeader(now, signPush(key, now, body)),
+			body:    []byte(`{"task":{}}`),
+			wantErr: true,
+		},
+		{
+			name:    "different key",
+			header:  newHeader(now, signPush([]byte("other"), now, body)),
+			body:    body,
+			wantErr: true,
+		},
+		{
+			name:    "expired",
+			header:  newHeader(stale, signPush(key, stale, body)),
+			body:    body,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := VerifyPushSignature(key, tc.header, tc.body, time.Minute)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("VerifyPushSignature() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidPushSignature) {
+				t.Errorf("VerifyPushSignature() error = %v, want %v", err, ErrInvalidPushSignature)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"sync"
+
+	"google.golang.org/adk/v2/session"
+)
+
+// activeRuns tracks the runs executing in this process, so that they can be cancelled by invocation.
+type activeRuns struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func newActiveRuns() *activeRuns {
+	return &activeRuns{cancels: map[string]context.CancelFunc{}}
+}
+
+func activeRunKey(appName, userID, sessionID, invocationID string) string {
+	return strings.Join([]string{appName, userID, sessionID, invocationID}, "/")
+}
+
+// track runs run under a cancellable context registered under the invocation of its first event until the
+// run ends.
+func (a *activeRuns) track(ctx context.Context, appName, userID, sessionID string, run func(context.Context) iter.Seq2[*session.Event, error]) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		key := ""
+		defer func() {
+			if key != "" {
+				a.mu.Lock()
+				delete(a.cancels, key)
+				a.mu.Unlock()
+			}
+		}()
+		for ev, err := range run(ctx) {
+			if key == "" && ev != nil && ev.InvocationID != "" {
+				key = activeRunKey(appName, userID, sessionID, ev.InvocationID)
+				a.mu.Lock()
+				a.cancels[key] = cancel
+				a.mu.Unlock()
+			}
+			if !yield(ev, err) {
+				return
+			}
+		}
+	}
+}
+
+// cancel cancels the run of the given invocation and reports whether it was executing.
+func (a *activeRuns) cancel(appName, userID, sessionID, invocationID string) bool {
+	a.mu.Lock()
+	cancel, ok := a.cancels[activeRunKey(appName, userID, sessionID, invocationID)]
+	a.mu.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
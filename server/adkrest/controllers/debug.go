@@ -15,6 +15,7 @@
 package controllers
 
 import (
+	"cmp"
 	"fmt"
 	"net/http"
 	"slices"
@@ -24,9 +25,11 @@ import (
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/agent/workflowagent"
 	"google.golang.org/adk/v2/server/adkrest/internal/models"
 	"google.golang.org/adk/v2/server/adkrest/internal/services"
 	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/workflow"
 )
 
 // DebugAPIController is the controller for the Debug API.
@@ -45,7 +48,40 @@ func NewDebugAPIController(sessionService session.Service, agentLoader agent.Loa
 	}
 }
 
-// EventSpanHandler returns the debug span for the event.
+// invocationIDKey is the span attribute holding the invocation ID.
+const invocationIDKey = "gcp.vertex.agent.invocation_id"
+
+// traceSession returns the session addressed by the trace routes scoped by user, or nil for the
+// unscoped /debug/trace routes. Spans don't record their user, so the unscoped routes are refused
+// to authenticated callers. It reports errors to rw and returns false after them.
+func (c *DebugAPIController) traceSession(rw http.ResponseWriter, req *http.Request) (session.Session, bool) {
+	vars := mux.Vars(req)
+	if _, scoped := vars["user_id"]; !scoped {
+		if _, ok := agent.PrincipalFromContext(req.Context()); ok {
+			http.Error(rw, "traces of authenticated users are served under /apps/{app_name}/users/{user_id}/sessions/{session_id}", http.StatusForbidden)
+			return nil, false
+		}
+		return nil, true
+	}
+	sessionID, err := models.SessionIDFromHTTPParameters(vars)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	resp, err := c.sessionService.Get(req.Context(), &session.GetRequest{
+		AppName:   sessionID.AppName,
+		UserID:    sessionID.UserID,
+		SessionID: sessionID.ID,
+	})
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("session not found: %s", sessionID.ID), http.StatusNotFound)
+		return nil, false
+	}
+	return resp.Session, true
+}
+
+// EventSpanHandler returns the debug span for the event. On the route scoped by user, the event must
+// belong to the session.
 func (c *DebugAPIController) EventSpanHandler(rw http.ResponseWriter, req *http.Request) {
 	params := mux.Vars(req)
 	eventID := params["event_id"]
@@ -53,6 +89,14 @@ func (c *DebugAPIController) EventSpanHandler(rw http.ResponseWriter, req *http.
 		http.Error(rw, "event_id parameter is required", http.StatusBadRequest)
 		return
 	}
+	sess, ok := c.traceSession(rw, req)
+	if !ok {
+		return
+	}
+	if sess != nil && !hasEvent(sess, eventID) {
+		http.Error(rw, fmt.Sprintf("event not found: %s", eventID), http.StatusNotFound)
+		return
+	}
 	spans := c.debugTelemetry.GetSpansByEventID(eventID)
 	key := string(semconv.GenAIOperationNameKey)
 	// Return only generate content and execute tool spans.
@@ -69,6 +113,15 @@ func (c *DebugAPIController) EventSpanHandler(rw http.ResponseWriter, req *http.
 	http.Error(rw, fmt.Sprintf("event not found: %s", eventID), http.StatusNotFound)
 }
 
+func hasEvent(sess session.Session, eventID string) bool {
+	for ev := range sess.Events().All() {
+		if ev.ID == eventID {
+			return true
+		}
+	}
+	return false
+}
+
 // ADK web expects different format than in [SessionSpansHandler].
 // The main difference is that span attributes need to be flattened in the response.
 func convertEventSpan(span services.DebugSpan) map[string]any {
@@ -87,7 +140,8 @@ func convertEventSpan(span services.DebugSpan) map[string]any {
 	return flattened
 }
 
-// SessionSpansHandler returns the debug spans for the session.
+// SessionSpansHandler returns the debug spans for the session. On the route scoped by user, only the
+// traces of the invocations of the user's session are returned.
 func (c *DebugAPIController) SessionSpansHandler(rw http.ResponseWriter, req *http.Request) {
 	params := mux.Vars(req)
 	sessionID := params["session_id"]
@@ -95,10 +149,33 @@ func (c *DebugAPIController) SessionSpansHandler(rw http.ResponseWriter, req *ht
 		http.Error(rw, "session_id parameter is required", http.StatusBadRequest)
 		return
 	}
+	sess, ok := c.traceSession(rw, req)
+	if !ok {
+		return
+	}
 	spans := c.debugTelemetry.GetSpansBySessionID(sessionID)
+	if sess != nil {
+		spans = sessionTraces(spans, sess)
+	}
 	EncodeJSONResponse(spans, http.StatusOK, rw)
 }
 
+// sessionTraces returns the spans of the traces of spans which only ran invocations of sess. It
+// leaves out the traces of another user's session with the same ID.
+func sessionTraces(spans []services.DebugSpan, sess session.Session) []services.DebugSpan {
+	invocations := map[string]bool{}
+	for ev := range sess.Events().All() {
+		invocations[ev.InvocationID] = true
+	}
+	foreign := map[string]bool{}
+	for _, span := range spans {
+		if id, ok := span.Attributes[invocationIDKey]; ok && !invocations[id] {
+			foreign[span.TraceID] = true
+		}
+	}
+	return slices.DeleteFunc(spans, func(span services.DebugSpan) bool { return foreign[span.TraceID] })
+}
+
 // EventGraphHandler returns the debug information for the session and session events in form of graph.
 func (c *DebugAPIController) EventGraphHandler(rw http.ResponseWriter, req *http.Request) {
 	vars := mux.Vars(req)
@@ -160,6 +237,16 @@ func (c *DebugAPIController) EventGraphHandler(rw http.ResponseWriter, req *http
 		http.Error(rw, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	// Workflow agents are drawn as their graph, with node statuses as of the event's invocation.
+	if wf, ok := workflowagent.WorkflowOf(agent); ok {
+		statuses, err := wf.NodeStatuses(resp.Session, event.InvocationID)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		EncodeJSONResponse(map[string]string{"dotSrc": wf.DOT(statuses)}, http.StatusOK, rw)
+		return
+	}
 	graph, err := services.GetAgentGraph(req.Context(), agent, highlightedPairs)
 	if err != nil {
 		http.Error(rw, err.Error(), http.StatusInternalServerError)
@@ -168,6 +255,67 @@ func (c *DebugAPIController) EventGraphHandler(rw http.ResponseWriter, req *http
 	EncodeJSONResponse(map[string]string{"dotSrc": graph}, http.StatusOK, rw)
 }
 
+// WorkflowGraphHandler returns the graph of a workflow agent with the status of its nodes in an invocation
+// of the session. The "format" query parameter selects "dot" (default) or "mermaid" output, and
+// "invocation_id" selects the invocation, defaulting to the latest one.
+func (c *DebugAPIController) WorkflowGraphHandler(rw http.ResponseWriter, req *http.Request) {
+	sessionID, err := models.SessionIDFromHTTPParameters(mux.Vars(req))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	format := cmp.Or(req.URL.Query().Get("format"), "dot")
+	if format != "dot" && format != "mermaid" {
+		http.Error(rw, fmt.Sprintf("unsupported format %q, want dot or mermaid", format), http.StatusBadRequest)
+		return
+	}
+	agent, err := c.agentloader.LoadAgent(sessionID.AppName)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	wf, ok := workflowagent.WorkflowOf(agent)
+	if !ok {
+		http.Error(rw, fmt.Sprintf("agent %q is not a workflow agent", agent.Name()), http.StatusBadRequest)
+		return
+	}
+	resp, err := c.sessionService.Get(req.Context(), &session.GetRequest{
+		AppName:   sessionID.AppName,
+		UserID:    sessionID.UserID,
+		SessionID: sessionID.ID,
+	})
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	invocationID := req.URL.Query().Get("invocation_id")
+	if invocationID == "" {
+		events := resp.Session.Events()
+		for i := events.Len() - 1; i >= 0 && invocationID == ""; i-- {
+			invocationID = events.At(i).InvocationID
+		}
+	}
+	result := models.WorkflowGraphResponse{InvocationID: invocationID, NodeStatuses: map[string]string{}}
+	var statuses map[string]workflow.NodeStatus
+	if invocationID != "" {
+		statuses, err = wf.NodeStatuses(resp.Session, invocationID)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	for name, status := range statuses {
+		result.NodeStatuses[name] = status.String()
+	}
+	if format == "mermaid" {
+		result.MermaidSrc = wf.Mermaid(statuses)
+	} else {
+		result.DotSrc = wf.DOT(statuses)
+	}
+	EncodeJSONResponse(result, http.StatusOK, rw)
+}
+
 func functionalCalls(event *session.Event) []*genai.FunctionCall {
 	if event.LLMResponse.Content == nil || event.LLMResponse.Content.Parts == nil {
 		return nil
@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -32,8 +33,13 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.36.0"
 	"go.opentelemetry.io/otel/trace"
 
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/agent/workflowagent"
 	"google.golang.org/adk/v2/server/adkrest/controllers"
+	"google.golang.org/adk/v2/server/adkrest/internal/fakes"
+	"google.golang.org/adk/v2/server/adkrest/internal/models"
 	"google.golang.org/adk/v2/server/adkrest/internal/services"
+	"google.golang.org/adk/v2/workflow"
 )
 
 func TestSessionSpansHandler(t *testing.T) {
@@ -127,6 +133,61 @@ func TestSessionSpansHandler(t *testing.T) {
 	}
 }
 
+func TestSessionSpansHandler_UserScoped(t *testing.T) {
+	testTelemetry := setupTestTelemetry(t)
+	tracer := testTelemetry.tp.Tracer("test-tracer")
+	for _, invocationID := range []string{"inv1", "other-user-inv"} {
+		_, span := tracer.Start(context.Background(), "span-"+invocationID, trace.WithAttributes(
+			attribute.String(string(semconv.GenAIConversationIDKey), "testSession"),
+			attribute.String("gcp.vertex.agent.invocation_id", invocationID),
+		))
+		span.End()
+	}
+	_ = testTelemetry.tp.ForceFlush(context.Background())
+
+	id := fakes.SessionKey{AppName: "testApp", UserID: "testUser", SessionID: "testSession"}
+	sessionService := fakes.FakeSessionService{Sessions: map[fakes.SessionKey]fakes.TestSession{
+		id: {Id: id, SessionState: fakes.TestState{}, UpdatedAt: time.Now(), SessionEvents: fakes.TestEvents{
+			{ID: "e1", Author: "a", InvocationID: "inv1"},
+		}},
+	}}
+	apiController := controllers.NewDebugAPIController(&sessionService, nil, testTelemetry.dt)
+
+	for _, tt := range []struct {
+		name       string
+		userID     string
+		wantStatus int
+		wantSpans  []string
+	}{
+		{name: "own_session", userID: "testUser", wantStatus: http.StatusOK, wantSpans: []string{"span-inv1"}},
+		{name: "missing_session", userID: "otherUser", wantStatus: http.StatusNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/apps/testApp/users/"+tt.userID+"/sessions/testSession/trace", nil)
+			req = mux.SetURLVars(req, map[string]string{"app_name": "testApp", "user_id": tt.userID, "session_id": "testSession"})
+			rr := httptest.NewRecorder()
+			apiController.SessionSpansHandler(rr, req)
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var spans []services.DebugSpan
+			if err := json.NewDecoder(rr.Body).Decode(&spans); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			var names []string
+			for _, span := range spans {
+				names = append(names, span.Name)
+			}
+			if diff := cmp.Diff(tt.wantSpans, names); diff != "" {
+				t.Errorf("handler returned unexpected spans (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestEventSpanHandler(t *testing.T) {
 	tc := []struct {
 		name       string
@@ -239,6 +300,147 @@ func TestEventSpanHandler(t *testing.T) {
 	}
 }
 
+func newWorkflowGraphTest(t *testing.T) (*controllers.DebugAPIController, fakes.SessionKey) {
+	t.Helper()
+	echo := func(ctx agent.Context, input any) (any, error) { return input, nil }
+	first := workflow.NewFunctionNode("first", echo, workflow.NodeConfig{})
+	second := workflow.NewFunctionNode("second", echo, workflow.NodeConfig{})
+	wfAgent, err := workflowagent.New(workflowagent.Config{
+		Name:  "testApp",
+		Edges: workflow.Chain(workflow.Start, first, second),
+	})
+	if err != nil {
+		t.Fatalf("workflowagent.New() error = %v", err)
+	}
+	id := fakes.SessionKey{AppName: "testApp", UserID: "testUser", SessionID: "testSession"}
+	sessionService := fakes.FakeSessionService{
+		Sessions: map[fakes.SessionKey]fakes.TestSession{
+			id: {Id: id, SessionState: fakes.TestState{}, UpdatedAt: time.Now(), SessionEvents: fakes.TestEvents{
+				{ID: "e1", Author: "first", InvocationID: "inv1", Output: "a"},
+				{ID: "e2", Author: "second", InvocationID: "inv1", Output: "a"},
+				{ID: "e3", Author: "first", InvocationID: "inv2", Output: "b"},
+			}},
+		},
+	}
+	return controllers.NewDebugAPIController(&sessionService, agent.NewSingleLoader(wfAgent), nil), id
+}
+
+func TestWorkflowGraphHandler(t *testing.T) {
+	tc := []struct {
+		name       string
+		query      string
+		wantStatus int
+		want       models.WorkflowGraphResponse
+	}{
+		{
+			name:       "latest_invocation_dot",
+			wantStatus: http.StatusOK,
+			want: models.WorkflowGraphResponse{
+				InvocationID: "inv2",
+				NodeStatuses: map[string]string{"START": "completed", "first": "completed"},
+				DotSrc: `digraph "testApp" {
+  rankdir=LR;
+  node [shape=box, style="rounded,filled", fillcolor="#ffffff"];
+  "START" [label="START\n(completed)", shape=circle, fillcolor="#69CB87", tooltip="Start node"];
+  "first" [label="first\n(completed)", fillcolor="#69CB87"];
+  "second" [label="second"];
+  "START" -> "first";
+  "first" -> "second";
+}
+`,
+			},
+		},
+		{
+			name:       "invocation_mermaid",
+			query:      "?format=mermaid&invocation_id=inv1",
+			wantStatus: http.StatusOK,
+			want: models.WorkflowGraphResponse{
+				InvocationID: "inv1",
+				NodeStatuses: map[string]string{"START": "completed", "first": "completed", "second": "completed"},
+				MermaidSrc: `flowchart LR
+  n0(("START<br/>(completed)"))
+  n1("first<br/>(completed)")
+  n2("second<br/>(completed)")
+  n0 --> n1
+  n1 --> n2
+  classDef completed fill:#69CB87
+  class n0,n1,n2 completed
+`,
+			},
+		},
+		{
+			name:       "unsupported_format",
+			query:      "?format=svg",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			apiController, id := newWorkflowGraphTest(t)
+			req := httptest.NewRequest(http.MethodGet, "/apps/testApp/users/testUser/sessions/testSession/graph"+tt.query, nil)
+			req = mux.SetURLVars(req, sessionVars(id))
+			rr := httptest.NewRecorder()
+
+			apiController.WorkflowGraphHandler(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v, body: %s", rr.Code, tt.wantStatus, rr.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got models.WorkflowGraphResponse
+			if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("handler returned unexpected body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWorkflowGraphHandler_NotWorkflowAgent(t *testing.T) {
+	plain, err := agent.New(agent.Config{Name: "testApp"})
+	if err != nil {
+		t.Fatalf("agent.New() error = %v", err)
+	}
+	apiController := controllers.NewDebugAPIController(&fakes.FakeSessionService{}, agent.NewSingleLoader(plain), nil)
+	req := httptest.NewRequest(http.MethodGet, "/apps/testApp/users/testUser/sessions/testSession/graph", nil)
+	req = mux.SetURLVars(req, sessionVars(fakes.SessionKey{AppName: "testApp", UserID: "testUser", SessionID: "testSession"}))
+	rr := httptest.NewRecorder()
+
+	apiController.WorkflowGraphHandler(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestEventGraphHandler_Workflow(t *testing.T) {
+	apiController, id := newWorkflowGraphTest(t)
+	vars := sessionVars(id)
+	vars["event_id"] = "e1"
+	req := httptest.NewRequest(http.MethodGet, "/apps/testApp/users/testUser/sessions/testSession/events/e1/graph", nil)
+	req = mux.SetURLVars(req, vars)
+	rr := httptest.NewRecorder()
+
+	apiController.EventGraphHandler(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v, body: %s", rr.Code, http.StatusOK, rr.Body)
+	}
+	var got map[string]string
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	// e1 belongs to inv1, in which both nodes ran.
+	if !strings.Contains(got["dotSrc"], `"second" [label="second\n(completed)"`) {
+		t.Errorf("dotSrc = %s, want completed node second", got["dotSrc"])
+	}
+}
+
 func ignoreDynamicFields() cmp.Option {
 	return cmpopts.IgnoreMapEntries(func(k string, v any) bool {
 		switch k {
@@ -17,7 +17,10 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"google.golang.org/adk/v2/server/ratelimit"
 )
 
 // TODO: Move to an internal package, controllers doesn't have to be public API.
@@ -46,6 +49,8 @@ func NewErrorHandler(fn errorHandler) http.HandlerFunc {
 		if err != nil {
 			if statusErr, ok := err.(statusError); ok {
 				http.Error(w, statusErr.Error(), statusErr.Status())
+			} else if errors.Is(err, ratelimit.ErrLimitExceeded) {
+				ratelimit.WriteError(w, err)
 			} else {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 			}
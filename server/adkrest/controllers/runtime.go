@@ -17,7 +17,9 @@ package controllers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
 	"log"
 	"net/http"
 	"time"
@@ -30,6 +32,8 @@ import (
 	"google.golang.org/adk/v2/memory"
 	"google.golang.org/adk/v2/runner"
 	"google.golang.org/adk/v2/server/adkrest/internal/models"
+	"google.golang.org/adk/v2/server/authn"
+	"google.golang.org/adk/v2/server/ratelimit"
 	"google.golang.org/adk/v2/session"
 )
 
@@ -42,11 +46,28 @@ type RuntimeAPIController struct {
 	agentLoader       agent.Loader
 	pluginConfig      runner.PluginConfig
 	autoCreateSession bool
+	rateLimiter       *ratelimit.Limiter
+	runs              *activeRuns
+}
+
+// RuntimeAPIOption configures optional behavior of the [RuntimeAPIController].
+type RuntimeAPIOption func(*RuntimeAPIController)
+
+// WithRateLimiter limits the rate and concurrency of runs started by the controller.
+// Rejected requests get 429 Too Many Requests responses with a Retry-After header.
+func WithRateLimiter(limiter *ratelimit.Limiter) RuntimeAPIOption {
+	return func(c *RuntimeAPIController) {
+		c.rateLimiter = limiter
+	}
 }
 
 // NewRuntimeAPIController creates the controller for the Runtime API.
-func NewRuntimeAPIController(sessionService session.Service, memoryService memory.Service, agentLoader agent.Loader, artifactService artifact.Service, sseTimeout time.Duration, pluginConfig runner.PluginConfig, autoCreateSession bool) *RuntimeAPIController {
-	return &RuntimeAPIController{sessionService: sessionService, memoryService: memoryService, agentLoader: agentLoader, artifactService: artifactService, sseTimeout: sseTimeout, pluginConfig: pluginConfig, autoCreateSession: autoCreateSession}
+func NewRuntimeAPIController(sessionService session.Service, memoryService memory.Service, agentLoader agent.Loader, artifactService artifact.Service, sseTimeout time.Duration, pluginConfig runner.PluginConfig, autoCreateSession bool, opts ...RuntimeAPIOption) *RuntimeAPIController {
+	c := &RuntimeAPIController{sessionService: sessionService, memoryService: memoryService, agentLoader: agentLoader, artifactService: artifactService, sseTimeout: sseTimeout, pluginConfig: pluginConfig, autoCreateSession: autoCreateSession, runs: newActiveRuns()}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // RunAgent executes a non-streaming agent run for a given session and message.
@@ -69,11 +90,20 @@ func (c *RuntimeAPIController) RunHandler(rw http.ResponseWriter, req *http.Requ
 
 // RunAgent executes a non-streaming agent run for a given session and message.
 func (c *RuntimeAPIController) runAgent(ctx context.Context, runAgentRequest models.RunAgentRequest) ([]*session.Event, error) {
+	if err := authn.AuthorizeUser(ctx, runAgentRequest.UserId); err != nil {
+		return nil, newStatusError(err, http.StatusForbidden)
+	}
 	err := c.validateSessionExists(ctx, runAgentRequest.AppName, runAgentRequest.UserId, runAgentRequest.SessionId)
 	if err != nil {
 		return nil, err
 	}
 
+	release, err := c.rateLimiter.Acquire(ctx, ratelimit.Key{AppName: runAgentRequest.AppName, UserID: runAgentRequest.UserId, SessionID: runAgentRequest.SessionId})
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	r, rCfg, err := c.getRunner(runAgentRequest)
 	if err != nil {
 		return nil, err
@@ -83,7 +113,9 @@ func (c *RuntimeAPIController) runAgent(ctx context.Context, runAgentRequest mod
 	if runAgentRequest.StateDelta != nil {
 		opts = append(opts, runner.WithStateDelta(*runAgentRequest.StateDelta))
 	}
-	resp := r.Run(ctx, runAgentRequest.UserId, runAgentRequest.SessionId, &runAgentRequest.NewMessage, *rCfg, opts...)
+	resp := c.runs.track(ctx, runAgentRequest.AppName, runAgentRequest.UserId, runAgentRequest.SessionId, func(ctx context.Context) iter.Seq2[*session.Event, error] {
+		return r.Run(ctx, runAgentRequest.UserId, runAgentRequest.SessionId, &runAgentRequest.NewMessage, *rCfg, opts...)
+	})
 
 	var events []*session.Event
 	for event, err := range resp {
@@ -112,12 +144,28 @@ func (c *RuntimeAPIController) RunSSEHandler(rw http.ResponseWriter, req *http.R
 		return
 	}
 
+	if err := authn.AuthorizeUser(req.Context(), runAgentRequest.UserId); err != nil {
+		http.Error(rw, err.Error(), http.StatusForbidden)
+		return
+	}
+
 	err = c.validateSessionExists(req.Context(), runAgentRequest.AppName, runAgentRequest.UserId, runAgentRequest.SessionId)
 	if err != nil {
 		http.Error(rw, "failed to find the session: "+err.Error(), http.StatusNotFound)
 		return
 	}
 
+	release, err := c.rateLimiter.Acquire(req.Context(), ratelimit.Key{AppName: runAgentRequest.AppName, UserID: runAgentRequest.UserId, SessionID: runAgentRequest.SessionId})
+	if err != nil {
+		if errors.Is(err, ratelimit.ErrLimitExceeded) {
+			ratelimit.WriteError(rw, err)
+		} else {
+			http.Error(rw, "failed to acquire rate limit: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	defer release()
+
 	r, rCfg, err := c.getRunner(runAgentRequest)
 	if err != nil {
 		http.Error(rw, "failed to get runner: "+err.Error(), http.StatusInternalServerError)
@@ -138,7 +186,9 @@ func (c *RuntimeAPIController) RunSSEHandler(rw http.ResponseWriter, req *http.R
 	if runAgentRequest.StateDelta != nil {
 		opts = append(opts, runner.WithStateDelta(*runAgentRequest.StateDelta))
 	}
-	resp := r.Run(req.Context(), runAgentRequest.UserId, runAgentRequest.SessionId, &runAgentRequest.NewMessage, *rCfg, opts...)
+	resp := c.runs.track(req.Context(), runAgentRequest.AppName, runAgentRequest.UserId, runAgentRequest.SessionId, func(ctx context.Context) iter.Seq2[*session.Event, error] {
+		return r.Run(ctx, runAgentRequest.UserId, runAgentRequest.SessionId, &runAgentRequest.NewMessage, *rCfg, opts...)
+	})
 
 	for event, err := range resp {
 		if err != nil {
@@ -267,6 +317,14 @@ func (c *RuntimeAPIController) RunLiveHandler(rw http.ResponseWriter, req *http.
 	if appName == "" || userID == "" || sessionID == "" {
 		return fmt.Errorf("appName, userId, and sessionId are required")
 	}
+	if err := authn.AuthorizeUser(req.Context(), userID); err != nil {
+		return newStatusError(err, http.StatusForbidden)
+	}
+	release, err := c.rateLimiter.Acquire(req.Context(), ratelimit.Key{AppName: appName, UserID: userID, SessionID: sessionID})
+	if err != nil {
+		return err
+	}
+	defer release()
 
 	ws, err := upgrader.Upgrade(rw, req, nil)
 	if err != nil {
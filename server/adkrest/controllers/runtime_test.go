@@ -33,6 +33,7 @@ import (
 	"google.golang.org/adk/v2/runner"
 	"google.golang.org/adk/v2/server/adkrest/internal/fakes"
 	"google.golang.org/adk/v2/server/adkrest/internal/models"
+	"google.golang.org/adk/v2/server/ratelimit"
 	"google.golang.org/adk/v2/session"
 )
 
@@ -273,3 +274,47 @@ func TestDecodeRequestBody_RejectsUnknownFields(t *testing.T) {
 		t.Errorf("decodeRequestBody: expected error for unknown field, got nil")
 	}
 }
+
+func TestRunHandler_RateLimited(t *testing.T) {
+	fakeAgent, err := agent.New(agent.Config{
+		Name: "testApp",
+		Run:  testAgent([]testAgentResult{{event: makeEvent("invocation-1", "testApp", "Hello from agent")}}),
+	})
+	if err != nil {
+		t.Fatalf("agent.New failed: %v", err)
+	}
+	id := fakes.SessionKey{AppName: "testApp", UserID: "testUser", SessionID: "testSession"}
+	sessionService := fakes.FakeSessionService{
+		Sessions: map[fakes.SessionKey]fakes.TestSession{
+			id: {Id: id, SessionState: fakes.TestState{}, SessionEvents: fakes.TestEvents{}, UpdatedAt: time.Now()},
+		},
+	}
+	limiter, err := ratelimit.New(ratelimit.Config{PerUser: ratelimit.PerMinute(1)})
+	if err != nil {
+		t.Fatalf("ratelimit.New() error = %v", err)
+	}
+	controller := NewRuntimeAPIController(&sessionService, nil, agent.NewSingleLoader(fakeAgent), nil, 10*time.Second, runner.PluginConfig{}, false, WithRateLimiter(limiter))
+	handler := NewErrorHandler(controller.RunHandler)
+
+	reqBytes, _ := json.Marshal(models.RunAgentRequest{
+		AppName:    "testApp",
+		UserId:     "testUser",
+		SessionId:  "testSession",
+		NewMessage: genai.Content{Parts: []*genai.Part{{Text: "Hello"}}},
+	})
+
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodPost, "/run", bytes.NewReader(reqBytes)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("first run status = %d, want %d, body: %s", rr.Code, http.StatusOK, rr.Body)
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodPost, "/run", bytes.NewReader(reqBytes)))
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("second run status = %d, want %d", rr.Code, http.StatusTooManyRequests)
+	}
+	if rr.Header().Get("Retry-After") == "" {
+		t.Error("second run has no Retry-After header")
+	}
+}
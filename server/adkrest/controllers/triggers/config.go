@@ -14,7 +14,11 @@
 
 package triggers
 
-import "time"
+import (
+	"time"
+
+	"google.golang.org/adk/v2/server/ratelimit"
+)
 
 // TriggerConfig contains configuration options for triggers.
 type TriggerConfig struct {
@@ -26,4 +30,7 @@ type TriggerConfig struct {
 	MaxDelay time.Duration
 	// MaxConcurrentRuns is the maximum number of concurrent runs.
 	MaxConcurrentRuns int
+	// RateLimiter limits trigger runs per app and per user. Triggers create a new session for each
+	// run, so session limits don't apply. Requests are not limited when it's nil.
+	RateLimiter *ratelimit.Limiter
 }
@@ -0,0 +1,120 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	// Thursday
+	from := time.Date(2026, 1, 1, 10, 30, 15, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want []time.Time
+	}{
+		{
+			spec: "* * * * *",
+			want: []time.Time{time.Date(2026, 1, 1, 10, 31, 0, 0, time.UTC), time.Date(2026, 1, 1, 10, 32, 0, 0, time.UTC)},
+		},
+		{
+			spec: "*/15 * * * *",
+			want: []time.Time{time.Date(2026, 1, 1, 10, 45, 0, 0, time.UTC), time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC)},
+		},
+		{
+			spec: "0 9 * * MON-FRI",
+			want: []time.Time{time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC), time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)},
+		},
+		{
+			spec: "0 0 1,15 * *",
+			want: []time.Time{time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			// day of month and day of week are alternatives when both are restricted
+			spec: "0 12 13 * 5",
+			want: []time.Time{time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC), time.Date(2026, 1, 9, 12, 0, 0, 0, time.UTC), time.Date(2026, 1, 13, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			spec: "0 0 * * 7",
+			want: []time.Time{time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			spec: "@monthly",
+			want: []time.Time{time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			spec: "@every 90m",
+			want: []time.Time{from.Add(90 * time.Minute), from.Add(180 * time.Minute)},
+		},
+		{
+			spec: "0 0 30 2 *",
+			want: []time.Time{{}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.spec, func(t *testing.T) {
+			s, err := parseSchedule(tc.spec, time.UTC)
+			if err != nil {
+				t.Fatalf("parseSchedule() error = %v", err)
+			}
+			got := from
+			for i, want := range tc.want {
+				got = s.next(got)
+				if !got.Equal(want) {
+					t.Fatalf("next #%d = %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSchedule_Location(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	s, err := parseSchedule("0 9 * * *", warsaw)
+	if err != nil {
+		t.Fatalf("parseSchedule() error = %v", err)
+	}
+	got := s.next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	if want := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("next = %v, want %v", got, want)
+	}
+}
+
+func TestParseSchedule_Errors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * FOO *",
+		"@every",
+		"@every -1m",
+		"@every soon",
+		"@fortnightly",
+	} {
+		if _, err := parseSchedule(spec, time.UTC); err == nil {
+			t.Errorf("parseSchedule(%q) error = nil, want error", spec)
+		}
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"google.golang.org/adk/v2/memory"
 	"google.golang.org/adk/v2/runner"
 	"google.golang.org/adk/v2/server/adkrest/internal/models"
+	"google.golang.org/adk/v2/server/ratelimit"
 	"google.golang.org/adk/v2/session"
 )
 
@@ -122,6 +123,13 @@ func (c *EventarcController) EventarcTriggerHandler(w http.ResponseWriter, r *ht
 		userID = eventArcDefaultUserID
 	}
 
+	release, err := c.runner.triggerConfig.RateLimiter.Acquire(r.Context(), ratelimit.Key{AppName: appName, UserID: userID})
+	if err != nil {
+		respondLimitError(w, err)
+		return
+	}
+	defer release()
+
 	// Semaphore limits concurrent agent calls based on the TriggerConfig.
 	if c.semaphore != nil {
 		c.semaphore <- struct{}{}
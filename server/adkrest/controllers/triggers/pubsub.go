@@ -24,6 +24,7 @@ import (
 	"google.golang.org/adk/v2/memory"
 	"google.golang.org/adk/v2/runner"
 	"google.golang.org/adk/v2/server/adkrest/internal/models"
+	"google.golang.org/adk/v2/server/ratelimit"
 	"google.golang.org/adk/v2/session"
 )
 
@@ -76,6 +77,13 @@ func (c *PubSubController) PubSubTriggerHandler(w http.ResponseWriter, r *http.R
 		userID = pubSubDefaultUserID
 	}
 
+	release, err := c.runner.triggerConfig.RateLimiter.Acquire(r.Context(), ratelimit.Key{AppName: appName, UserID: userID})
+	if err != nil {
+		respondLimitError(w, err)
+		return
+	}
+	defer release()
+
 	// Semaphore limits concurrent agent calls based on the TriggerConfig.
 	if c.semaphore != nil {
 		c.semaphore <- struct{}{}
@@ -0,0 +1,158 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggers
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/artifact"
+	"google.golang.org/adk/v2/memory"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/server/ratelimit"
+	"google.golang.org/adk/v2/session"
+)
+
+const schedulerDefaultUserID = "scheduler"
+
+// Schedule describes agent runs started periodically by a [Scheduler].
+type Schedule struct {
+	// Name identifies the schedule in logs. Defaults to the Spec.
+	Name string
+	// AppName is the name of the agent to run.
+	AppName string
+	// Spec is a standard 5-field cron expression ("minute hour day-of-month month day-of-week"),
+	// a descriptor such as "@hourly" or "@daily", or a fixed interval such as "@every 15m".
+	Spec string
+	// Location is the time zone cron expressions are evaluated in. Defaults to the local time zone.
+	Location *time.Location
+	// Message is the user message sent to the agent on each run.
+	Message string
+	// UserID is the ID of the user the agent runs for. Defaults to "scheduler".
+	UserID string
+	// SessionID is the ID of a session reused by all runs, which is created if it doesn't exist.
+	// If empty, each run creates a new session.
+	SessionID string
+}
+
+type scheduledRun struct {
+	Schedule
+	schedule schedule
+}
+
+// Scheduler runs agents on schedules within the server process.
+// A run is skipped if the previous run of the same schedule is still in progress.
+type Scheduler struct {
+	runner    *RetriableRunner
+	semaphore chan struct{}
+	runs      []scheduledRun
+	now       func() time.Time
+}
+
+// NewScheduler creates a new Scheduler. Schedules are started with [Scheduler.Run].
+func NewScheduler(sessionService session.Service, agentLoader agent.Loader, memoryService memory.Service, artifactService artifact.Service, pluginConfig runner.PluginConfig, triggerConfig TriggerConfig, schedules []Schedule) (*Scheduler, error) {
+	runs := make([]scheduledRun, 0, len(schedules))
+	for _, s := range schedules {
+		if s.AppName == "" {
+			return nil, fmt.Errorf("schedule %q: app name is required", cmp.Or(s.Name, s.Spec))
+		}
+		if s.Message == "" {
+			return nil, fmt.Errorf("schedule %q: message is required", cmp.Or(s.Name, s.Spec))
+		}
+		parsed, err := parseSchedule(s.Spec, s.Location)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: %w", cmp.Or(s.Name, s.Spec), err)
+		}
+		s.Name = cmp.Or(s.Name, s.Spec)
+		s.UserID = cmp.Or(s.UserID, schedulerDefaultUserID)
+		runs = append(runs, scheduledRun{Schedule: s, schedule: parsed})
+	}
+	return &Scheduler{
+		runner: &RetriableRunner{
+			sessionService:  sessionService,
+			agentLoader:     agentLoader,
+			memoryService:   memoryService,
+			artifactService: artifactService,
+			pluginConfig:    pluginConfig,
+			triggerConfig:   triggerConfig,
+		},
+		semaphore: make(chan struct{}, triggerConfig.MaxConcurrentRuns),
+		runs:      runs,
+		now:       time.Now,
+	}, nil
+}
+
+// Run starts agents on their schedules until ctx is done. It waits for runs in progress to complete
+// before returning.
+func (s *Scheduler) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, run := range s.runs {
+		wg.Go(func() {
+			s.loop(ctx, run)
+		})
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+func (s *Scheduler) loop(ctx context.Context, run scheduledRun) {
+	next := run.schedule.next(s.now())
+	for !next.IsZero() {
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.runOnce(ctx, run)
+
+		// Activations missed while the agent was running are skipped.
+		now := s.now()
+		for next = run.schedule.next(next); !next.IsZero() && !next.After(now); next = run.schedule.next(next) {
+			log.Printf("Schedule %q: skipping run at %v as the previous run is still in progress", run.Name, next)
+		}
+	}
+	log.Printf("Schedule %q has no further activations", run.Name)
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, run scheduledRun) {
+	release, err := s.runner.triggerConfig.RateLimiter.Acquire(ctx, ratelimit.Key{AppName: run.AppName, UserID: run.UserID, SessionID: run.SessionID})
+	if err != nil {
+		log.Printf("Schedule %q: skipping run: %v", run.Name, err)
+		return
+	}
+	defer release()
+
+	// Semaphore limits concurrent agent calls based on the TriggerConfig.
+	if s.semaphore != nil {
+		select {
+		case s.semaphore <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		defer func() { <-s.semaphore }()
+	}
+
+	if _, err := s.runner.RunAgentInSession(ctx, run.AppName, run.UserID, run.SessionID, run.Message); err != nil {
+		log.Printf("Schedule %q: failed to run agent: %v", run.Name, err)
+	}
+}
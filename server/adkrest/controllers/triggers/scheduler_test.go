@@ -0,0 +1,87 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/server/adkrest/controllers/triggers"
+	"google.golang.org/adk/v2/session"
+)
+
+func TestScheduler_Run(t *testing.T) {
+	tests := []struct {
+		name            string
+		sessionID       string
+		wantSameSession bool
+	}{
+		{name: "session per run"},
+		{name: "fixed session", sessionID: "daily", wantSameSession: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			testAgent, runs := newRecordingAgent(t)
+			scheduler, err := triggers.NewScheduler(session.InMemoryService(), agent.NewSingleLoader(testAgent), nil, nil, runner.PluginConfig{}, defaultTriggerConfig, []triggers.Schedule{{
+				AppName:   "test-agent",
+				Spec:      "@every 20ms",
+				Message:   "tick",
+				SessionID: tc.sessionID,
+			}})
+			if err != nil {
+				t.Fatalf("NewScheduler() error = %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(t.Context(), 150*time.Millisecond)
+			defer cancel()
+			if err := scheduler.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("Run() error = %v, want %v", err, context.DeadlineExceeded)
+			}
+
+			got := runs()
+			if len(got) < 2 {
+				t.Fatalf("expected at least 2 runs, got %d", len(got))
+			}
+			for _, run := range got {
+				if run.message != "tick" || run.userID != "scheduler" {
+					t.Errorf("run = %+v, want message %q for user %q", run, "tick", "scheduler")
+				}
+			}
+			if sameSession := got[0].sessionID == got[1].sessionID; sameSession != tc.wantSameSession {
+				t.Errorf("runs in sessions %q and %q, want same session = %v", got[0].sessionID, got[1].sessionID, tc.wantSameSession)
+			}
+			if tc.sessionID != "" && got[0].sessionID != tc.sessionID {
+				t.Errorf("run session = %q, want %q", got[0].sessionID, tc.sessionID)
+			}
+		})
+	}
+}
+
+func TestNewScheduler_Errors(t *testing.T) {
+	testAgent, _ := newRecordingAgent(t)
+	for _, s := range []triggers.Schedule{
+		{Spec: "@hourly", Message: "tick"},
+		{AppName: "test-agent", Spec: "@hourly"},
+		{AppName: "test-agent", Spec: "every hour", Message: "tick"},
+	} {
+		if _, err := triggers.NewScheduler(session.InMemoryService(), agent.NewSingleLoader(testAgent), nil, nil, runner.PluginConfig{}, defaultTriggerConfig, []triggers.Schedule{s}); err == nil {
+			t.Errorf("NewScheduler(%+v) error = nil, want error", s)
+		}
+	}
+}
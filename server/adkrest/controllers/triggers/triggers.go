@@ -16,6 +16,7 @@ package triggers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
@@ -32,6 +33,7 @@ import (
 	"google.golang.org/adk/v2/runner"
 	"google.golang.org/adk/v2/server/adkrest/controllers"
 	"google.golang.org/adk/v2/server/adkrest/internal/models"
+	"google.golang.org/adk/v2/server/ratelimit"
 	"google.golang.org/adk/v2/session"
 )
 
@@ -45,14 +47,22 @@ type RetriableRunner struct {
 }
 
 func (r *RetriableRunner) RunAgent(ctx context.Context, appName, userID, messageContent string) ([]*session.Event, error) {
-	// Each retry = new session
-	sessReq := &session.CreateRequest{
-		AppName: appName,
-		UserID:  userID,
-	}
-	sessResp, err := r.sessionService.Create(ctx, sessReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create session: %v", err)
+	return r.RunAgentInSession(ctx, appName, userID, "", messageContent)
+}
+
+// RunAgentInSession runs the agent in the session with the given ID, which is created if it doesn't exist.
+// A new session is created for the run if sessionID is empty.
+func (r *RetriableRunner) RunAgentInSession(ctx context.Context, appName, userID, sessionID, messageContent string) ([]*session.Event, error) {
+	if sessionID == "" {
+		sessReq := &session.CreateRequest{
+			AppName: appName,
+			UserID:  userID,
+		}
+		sessResp, err := r.sessionService.Create(ctx, sessReq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create session: %v", err)
+		}
+		sessionID = sessResp.Session.ID()
 	}
 
 	userMessage := genai.Content{
@@ -68,18 +78,19 @@ func (r *RetriableRunner) RunAgent(ctx context.Context, appName, userID, message
 	}
 
 	runR, err := runner.New(runner.Config{
-		AppName:         appName,
-		Agent:           curAgent,
-		SessionService:  r.sessionService,
-		MemoryService:   r.memoryService,
-		ArtifactService: r.artifactService,
-		PluginConfig:    r.pluginConfig,
+		AppName:           appName,
+		Agent:             curAgent,
+		SessionService:    r.sessionService,
+		MemoryService:     r.memoryService,
+		ArtifactService:   r.artifactService,
+		PluginConfig:      r.pluginConfig,
+		AutoCreateSession: true,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create runner: %v", err)
 	}
 
-	return r.runAgentWithRetry(ctx, runR, sessResp.Session.UserID(), sessResp.Session.ID(), &userMessage)
+	return r.runAgentWithRetry(ctx, runR, userID, sessionID, &userMessage)
 }
 
 // runAgentWithRetry uses exponential backoff with jitter to handle 429 rate-limit errors.
@@ -122,6 +133,17 @@ func respondError(w http.ResponseWriter, code int, msg string) {
 	controllers.EncodeJSONResponse(resp, code, w)
 }
 
+// respondLimitError reports a request rejected by the rate limiter.
+// Pub/Sub and Eventarc redeliver messages rejected with 429 Too Many Requests.
+func respondLimitError(w http.ResponseWriter, err error) {
+	if !errors.Is(err, ratelimit.ErrLimitExceeded) {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("failed to acquire rate limit: %v", err))
+		return
+	}
+	ratelimit.SetRetryAfter(w.Header(), err)
+	respondError(w, http.StatusTooManyRequests, err.Error())
+}
+
 func respondSuccess(w http.ResponseWriter) {
 	resp := models.TriggerResponse{Status: "success"}
 	controllers.EncodeJSONResponse(resp, http.StatusOK, w)
@@ -0,0 +1,332 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggers
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/artifact"
+	"google.golang.org/adk/v2/memory"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/server/ratelimit"
+	"google.golang.org/adk/v2/session"
+)
+
+const (
+	webhookDefaultUserID            = "webhook-caller"
+	webhookDefaultSignatureHeader   = "X-Signature-256"
+	webhookDefaultIdempotencyHeader = "Idempotency-Key"
+	webhookDefaultIdempotencyTTL    = 24 * time.Hour
+	webhookDefaultMaxBodyBytes      = 1 << 20
+	webhookSignaturePrefix          = "sha256="
+)
+
+// WebhookConfig contains configuration options for the generic webhook trigger.
+type WebhookConfig struct {
+	// MessageTemplate is a text/template rendering the agent message from the request. Templates can use
+	// .AppName, .Body (the JSON-decoded payload, nil if the payload is not JSON), .RawBody, .Headers and .Query,
+	// and the "json" function which encodes a value as JSON, e.g. `{{.Body.issue.title}}` or `{{.Headers.Get "X-Sender"}}`.
+	// Referencing a field missing from the payload is an error. Defaults to the raw request body.
+	MessageTemplate string
+	// UserIDTemplate is a text/template rendering the ID of the user the agent runs for, with the same data
+	// as MessageTemplate. Defaults to "webhook-caller".
+	UserIDTemplate string
+	// Secret enables verification of HMAC-SHA256 request signatures. Requests must carry the hex-encoded
+	// HMAC of the raw body, optionally prefixed with "sha256=", in the SignatureHeader. Required unless
+	// Insecure is set.
+	Secret []byte
+	// Insecure accepts unsigned requests when no Secret is set, letting anyone who can reach the endpoint
+	// run the agent. Only use it behind another layer authenticating the callers.
+	Insecure bool
+	// SignatureHeader is the header carrying request signatures. Defaults to "X-Signature-256".
+	SignatureHeader string
+	// IdempotencyHeader is the header carrying the idempotency key of a request. Requests repeating the key of
+	// a successful or in-progress run don't start another run. Defaults to "Idempotency-Key".
+	IdempotencyHeader string
+	// IdempotencyTTL is how long idempotency keys of successful runs are remembered. Defaults to 24 hours.
+	IdempotencyTTL time.Duration
+	// MaxBodyBytes limits the size of request bodies; larger requests are rejected. Defaults to 1 MiB.
+	MaxBodyBytes int64
+}
+
+// webhookTemplateData is available to webhook templates.
+type webhookTemplateData struct {
+	AppName string
+	Body    any
+	RawBody string
+	Headers http.Header
+	Query   url.Values
+}
+
+// WebhookController handles the generic webhook trigger endpoint.
+type WebhookController struct {
+	runner            *RetriableRunner
+	semaphore         chan struct{}
+	messageTemplate   *template.Template
+	userIDTemplate    *template.Template
+	secret            []byte
+	signatureHeader   string
+	idempotencyHeader string
+	idempotency       *idempotencyKeys
+	maxBodyBytes      int64
+}
+
+// NewWebhookController creates a new WebhookController.
+func NewWebhookController(sessionService session.Service, agentLoader agent.Loader, memoryService memory.Service, artifactService artifact.Service, pluginConfig runner.PluginConfig, triggerConfig TriggerConfig, webhookConfig WebhookConfig) (*WebhookController, error) {
+	if len(webhookConfig.Secret) == 0 && !webhookConfig.Insecure {
+		return nil, fmt.Errorf("webhook secret is required unless insecure requests are enabled")
+	}
+	funcs := template.FuncMap{"json": templateJSON}
+	var messageTemplate, userIDTemplate *template.Template
+	var err error
+	if webhookConfig.MessageTemplate != "" {
+		messageTemplate, err = template.New("message").Funcs(funcs).Option("missingkey=error").Parse(webhookConfig.MessageTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse message template: %w", err)
+		}
+	}
+	if webhookConfig.UserIDTemplate != "" {
+		userIDTemplate, err = template.New("user_id").Funcs(funcs).Option("missingkey=error").Parse(webhookConfig.UserIDTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse user ID template: %w", err)
+		}
+	}
+	ttl := webhookConfig.IdempotencyTTL
+	if ttl <= 0 {
+		ttl = webhookDefaultIdempotencyTTL
+	}
+	maxBodyBytes := webhookConfig.MaxBodyBytes
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = webhookDefaultMaxBodyBytes
+	}
+
+	return &WebhookController{
+		runner: &RetriableRunner{
+			sessionService:  sessionService,
+			agentLoader:     agentLoader,
+			memoryService:   memoryService,
+			artifactService: artifactService,
+			pluginConfig:    pluginConfig,
+			triggerConfig:   triggerConfig,
+		},
+		semaphore:         make(chan struct{}, triggerConfig.MaxConcurrentRuns),
+		messageTemplate:   messageTemplate,
+		userIDTemplate:    userIDTemplate,
+		secret:            webhookConfig.Secret,
+		signatureHeader:   cmp.Or(webhookConfig.SignatureHeader, webhookDefaultSignatureHeader),
+		idempotencyHeader: cmp.Or(webhookConfig.IdempotencyHeader, webhookDefaultIdempotencyHeader),
+		idempotency:       newIdempotencyKeys(ttl),
+		maxBodyBytes:      maxBodyBytes,
+	}, nil
+}
+
+// WebhookTriggerHandler handles the webhook trigger endpoint.
+func (c *WebhookController) WebhookTriggerHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, c.maxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("body exceeds %d bytes", c.maxBodyBytes))
+			return
+		}
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("failed to read body: %v", err))
+		return
+	}
+	if err := c.verifySignature(r.Header.Get(c.signatureHeader), body); err != nil {
+		respondError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	appName, err := appName(r)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("failed to retrieve app name: %v", err))
+		return
+	}
+
+	data := webhookTemplateData{AppName: appName, RawBody: string(body), Headers: r.Header, Query: r.URL.Query()}
+	if len(body) > 0 && json.Valid(body) {
+		_ = json.Unmarshal(body, &data.Body)
+	}
+	message, err := render(c.messageTemplate, data, string(body))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("failed to render message: %v", err))
+		return
+	}
+	if strings.TrimSpace(message) == "" {
+		respondError(w, http.StatusBadRequest, "empty message")
+		return
+	}
+	userID, err := render(c.userIDTemplate, data, webhookDefaultUserID)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("failed to render user ID: %v", err))
+		return
+	}
+	userID = cmp.Or(strings.TrimSpace(userID), webhookDefaultUserID)
+
+	// Keys are scoped to the app, so that apps served by the same endpoint don't share them.
+	idempotencyKey := r.Header.Get(c.idempotencyHeader)
+	if idempotencyKey != "" {
+		idempotencyKey = appName + "/" + idempotencyKey
+		switch c.idempotency.begin(idempotencyKey) {
+		case idempotencyInProgress:
+			respondError(w, http.StatusConflict, "a run with the same idempotency key is in progress")
+			return
+		case idempotencyDone:
+			respondSuccess(w)
+			return
+		}
+	}
+	succeeded := false
+	defer func() {
+		if idempotencyKey != "" {
+			c.idempotency.end(idempotencyKey, succeeded)
+		}
+	}()
+
+	release, err := c.runner.triggerConfig.RateLimiter.Acquire(r.Context(), ratelimit.Key{AppName: appName, UserID: userID})
+	if err != nil {
+		respondLimitError(w, err)
+		return
+	}
+	defer release()
+
+	// Semaphore limits concurrent agent calls based on the TriggerConfig.
+	if c.semaphore != nil {
+		c.semaphore <- struct{}{}
+		defer func() { <-c.semaphore }()
+	}
+
+	if _, err := c.runner.RunAgent(r.Context(), appName, userID, message); err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("failed to run agent: %v", err))
+		return
+	}
+
+	succeeded = true
+	respondSuccess(w)
+}
+
+// verifySignature checks the HMAC-SHA256 signature of the body if a secret is configured. Without one,
+// the controller was created with Insecure set.
+func (c *WebhookController) verifySignature(signature string, body []byte) error {
+	if len(c.secret) == 0 {
+		return nil
+	}
+	if signature == "" {
+		return fmt.Errorf("missing %s header", c.signatureHeader)
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, webhookSignaturePrefix))
+	if err != nil {
+		return fmt.Errorf("malformed signature: %v", err)
+	}
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// render executes tmpl with data, or returns fallback if tmpl is nil.
+func render(tmpl *template.Template, data webhookTemplateData, fallback string) (string, error) {
+	if tmpl == nil {
+		return fallback, nil
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func templateJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+type idempotencyState int
+
+const (
+	idempotencyNew idempotencyState = iota
+	idempotencyInProgress
+	idempotencyDone
+)
+
+type idempotencyEntry struct {
+	state   idempotencyState
+	expires time.Time
+}
+
+// idempotencyKeys remembers keys of in-progress and successful runs. Keys of failed runs are
+// forgotten so that the sender can retry them.
+type idempotencyKeys struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	keys      map[string]idempotencyEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func newIdempotencyKeys(ttl time.Duration) *idempotencyKeys {
+	return &idempotencyKeys{ttl: ttl, keys: make(map[string]idempotencyEntry), now: time.Now}
+}
+
+// begin returns the state of the key and marks new keys as in progress.
+func (k *idempotencyKeys) begin(key string) idempotencyState {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	now := k.now()
+	if now.Sub(k.lastSweep) > time.Minute {
+		for existing, entry := range k.keys {
+			if entry.state == idempotencyDone && now.After(entry.expires) {
+				delete(k.keys, existing)
+			}
+		}
+		k.lastSweep = now
+	}
+	if entry, ok := k.keys[key]; ok && (entry.state == idempotencyInProgress || now.Before(entry.expires)) {
+		return entry.state
+	}
+	k.keys[key] = idempotencyEntry{state: idempotencyInProgress}
+	return idempotencyNew
+}
+
+// end records the outcome of the run started for the key.
+func (k *idempotencyKeys) end(key string, succeeded bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if !succeeded {
+		delete(k.keys, key)
+		return
+	}
+	k.keys[key] = idempotencyEntry{state: idempotencyDone, expires: k.now().Add(k.ttl)}
+}
@@ -0,0 +1,236 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggers_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/server/adkrest/controllers/triggers"
+	"google.golang.org/adk/v2/server/adkrest/internal/fakes"
+	"google.golang.org/adk/v2/session"
+)
+
+// recordedRun is a run observed by a recordingAgent.
+type recordedRun struct {
+	userID    string
+	sessionID string
+	message   string
+}
+
+// newRecordingAgent returns an agent which records its runs and fails those with messages containing "fail".
+func newRecordingAgent(t *testing.T) (agent.Agent, func() []recordedRun) {
+	t.Helper()
+	var mu sync.Mutex
+	var runs []recordedRun
+	a, err := agent.New(agent.Config{
+		Name: "test-agent",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				var message string
+				if c := ctx.UserContent(); c != nil && len(c.Parts) > 0 {
+					message = c.Parts[0].Text
+				}
+				mu.Lock()
+				runs = append(runs, recordedRun{userID: ctx.Session().UserID(), sessionID: ctx.Session().ID(), message: message})
+				mu.Unlock()
+				if strings.Contains(message, "fail") {
+					yield(nil, fmt.Errorf("agent failed"))
+					return
+				}
+				yield(&session.Event{ID: "success-event"}, nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("agent.New failed: %v", err)
+	}
+	return a, func() []recordedRun {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]recordedRun(nil), runs...)
+	}
+}
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhookTriggerHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      triggers.WebhookConfig
+		body        string
+		headers     map[string]string
+		wantCode    int
+		wantUserID  string
+		wantMessage string
+	}{
+		{
+			name:        "raw body",
+			config:      triggers.WebhookConfig{Insecure: true},
+			body:        "Hello agent",
+			wantCode:    http.StatusOK,
+			wantUserID:  "webhook-caller",
+			wantMessage: "Hello agent",
+		},
+		{
+			name: "templates",
+			config: triggers.WebhookConfig{
+				Insecure:        true,
+				MessageTemplate: `New issue: {{.Body.issue.title}} {{json .Body.labels}}`,
+				UserIDTemplate:  `{{.Headers.Get "X-Sender"}}`,
+			},
+			body:        `{"issue": {"title": "Crash on start"}, "labels": ["bug"]}`,
+			headers:     map[string]string{"X-Sender": "octocat"},
+			wantCode:    http.StatusOK,
+			wantUserID:  "octocat",
+			wantMessage: `New issue: Crash on start ["bug"]`,
+		},
+		{
+			name:     "empty message",
+			config:   triggers.WebhookConfig{Insecure: true},
+			body:     "",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "template error",
+			config:   triggers.WebhookConfig{Insecure: true, MessageTemplate: `{{.Body.missing.field}}`},
+			body:     `{}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:        "valid signature",
+			config:      triggers.WebhookConfig{Secret: []byte("s3cret")},
+			body:        "signed",
+			headers:     map[string]string{"X-Signature-256": sign("s3cret", "signed")},
+			wantCode:    http.StatusOK,
+			wantUserID:  "webhook-caller",
+			wantMessage: "signed",
+		},
+		{
+			name:     "invalid signature",
+			config:   triggers.WebhookConfig{Secret: []byte("s3cret")},
+			body:     "signed",
+			headers:  map[string]string{"X-Signature-256": sign("other", "signed")},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "missing signature",
+			config:   triggers.WebhookConfig{Secret: []byte("s3cret")},
+			body:     "signed",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "body too large",
+			config:   triggers.WebhookConfig{Insecure: true, MaxBodyBytes: 4},
+			body:     "Hello agent",
+			wantCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			testAgent, runs := newRecordingAgent(t)
+			controller := setupWebhookTest(t, testAgent, tc.config)
+
+			rr := postWebhook(t, controller, tc.body, tc.headers)
+			if rr.Code != tc.wantCode {
+				t.Fatalf("expected status %d, got %d. Body: %s", tc.wantCode, rr.Code, rr.Body.String())
+			}
+			if tc.wantCode != http.StatusOK {
+				if got := runs(); len(got) != 0 {
+					t.Errorf("expected no runs, got %v", got)
+				}
+				return
+			}
+			got := runs()
+			if len(got) != 1 {
+				t.Fatalf("expected 1 run, got %d", len(got))
+			}
+			if got[0].userID != tc.wantUserID || got[0].message != tc.wantMessage {
+				t.Errorf("run = %+v, want user %q and message %q", got[0], tc.wantUserID, tc.wantMessage)
+			}
+		})
+	}
+}
+
+func TestWebhookTriggerHandler_Idempotency(t *testing.T) {
+	testAgent, runs := newRecordingAgent(t)
+	controller := setupWebhookTest(t, testAgent, triggers.WebhookConfig{Insecure: true})
+
+	for range 2 {
+		if rr := postWebhook(t, controller, "once", map[string]string{"Idempotency-Key": "k1"}); rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+		}
+	}
+	if got := len(runs()); got != 1 {
+		t.Errorf("expected 1 run for a repeated key, got %d", got)
+	}
+
+	// Failed runs can be retried with the same key.
+	for range 2 {
+		if rr := postWebhook(t, controller, "fail", map[string]string{"Idempotency-Key": "k2"}); rr.Code != http.StatusInternalServerError {
+			t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rr.Code)
+		}
+	}
+	if got := len(runs()); got != 3 {
+		t.Errorf("expected failed runs to be retried, got %d runs", got)
+	}
+}
+
+func TestNewWebhookController_RequiresSecret(t *testing.T) {
+	testAgent, _ := newRecordingAgent(t)
+	sessionService := &fakes.FakeSessionService{Sessions: make(map[fakes.SessionKey]fakes.TestSession)}
+	if _, err := triggers.NewWebhookController(sessionService, agent.NewSingleLoader(testAgent), nil, nil, runner.PluginConfig{}, defaultTriggerConfig, triggers.WebhookConfig{}); err == nil {
+		t.Error("NewWebhookController() error = nil, want error without a secret or Insecure")
+	}
+}
+
+func setupWebhookTest(t *testing.T, a agent.Agent, config triggers.WebhookConfig) *triggers.WebhookController {
+	t.Helper()
+	sessionService := &fakes.FakeSessionService{Sessions: make(map[fakes.SessionKey]fakes.TestSession)}
+	controller, err := triggers.NewWebhookController(sessionService, agent.NewSingleLoader(a), nil, nil, runner.PluginConfig{}, defaultTriggerConfig, config)
+	if err != nil {
+		t.Fatalf("NewWebhookController() error = %v", err)
+	}
+	return controller
+}
+
+func postWebhook(t *testing.T, controller *triggers.WebhookController, body string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/apps/test-agent/trigger/webhook", strings.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req = mux.SetURLVars(req, map[string]string{"app_name": "test-agent"})
+	rr := httptest.NewRecorder()
+	controller.WebhookTriggerHandler(rr, req)
+	return rr
+}
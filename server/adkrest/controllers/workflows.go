@@ -0,0 +1,222 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/agent/workflowagent"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/server/adkrest/internal/models"
+	"google.golang.org/adk/v2/server/ratelimit"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/workflow"
+)
+
+// WorkflowsAPIController is the controller for the Workflows API, which inspects and controls the runs of
+// workflow agents.
+type WorkflowsAPIController struct {
+	sessionService session.Service
+	agentLoader    agent.Loader
+	runtime        *RuntimeAPIController
+}
+
+// NewWorkflowsAPIController creates the controller for the Workflows API. Runs are continued, and cancelled
+// while executing, through the runtime controller.
+func NewWorkflowsAPIController(sessionService session.Service, agentLoader agent.Loader, runtime *RuntimeAPIController) *WorkflowsAPIController {
+	return &WorkflowsAPIController{sessionService: sessionService, agentLoader: agentLoader, runtime: runtime}
+}
+
+// workflowRun is a run addressed by a request, with its session as of the request.
+type workflowRun struct {
+	sessionID    models.SessionID
+	invocationID string
+	workflow     *workflow.Workflow
+	session      session.Session
+	snapshot     *workflow.RunSnapshot
+}
+
+// loadRun loads the run addressed by the request. It fails with 404 Not Found if the session has no such run.
+func (c *WorkflowsAPIController) loadRun(req *http.Request) (*workflowRun, error) {
+	vars := mux.Vars(req)
+	sessionID, err := models.SessionIDFromHTTPParameters(vars)
+	if err != nil {
+		return nil, newStatusError(err, http.StatusBadRequest)
+	}
+	invocationID := vars["invocation_id"]
+	if invocationID == "" {
+		return nil, newStatusError(errors.New("invocation_id parameter is required"), http.StatusBadRequest)
+	}
+	a, err := c.agentLoader.LoadAgent(sessionID.AppName)
+	if err != nil {
+		return nil, newStatusError(fmt.Errorf("failed to load agent: %w", err), http.StatusInternalServerError)
+	}
+	wf, ok := workflowagent.WorkflowOf(a)
+	if !ok {
+		return nil, newStatusError(fmt.Errorf("agent of app %q is not a workflow", sessionID.AppName), http.StatusBadRequest)
+	}
+	resp, err := c.sessionService.Get(req.Context(), &session.GetRequest{
+		AppName:   sessionID.AppName,
+		UserID:    sessionID.UserID,
+		SessionID: sessionID.ID,
+	})
+	if err != nil {
+		return nil, newStatusError(fmt.Errorf("failed to get session: %w", err), http.StatusNotFound)
+	}
+	snap, err := wf.InspectRun(resp.Session, invocationID)
+	if err != nil {
+		return nil, newStatusError(fmt.Errorf("failed to inspect run: %w", err), http.StatusInternalServerError)
+	}
+	if len(snap.Nodes) == 0 && snap.Status == "" {
+		return nil, newStatusError(fmt.Errorf("run %q not found", invocationID), http.StatusNotFound)
+	}
+	return &workflowRun{sessionID: sessionID, invocationID: invocationID, workflow: wf, session: resp.Session, snapshot: snap}, nil
+}
+
+// GetRunHandler returns the state of a workflow run: the status, attempts and outputs of its nodes and the
+// interrupts it waits on.
+func (c *WorkflowsAPIController) GetRunHandler(rw http.ResponseWriter, req *http.Request) error {
+	run, err := c.loadRun(req)
+	if err != nil {
+		return err
+	}
+	EncodeJSONResponse(models.FromRunSnapshot(run.snapshot), http.StatusOK, rw)
+	return nil
+}
+
+// AnswerInterruptHandler answers an open interrupt of a workflow run with a payload validated against the
+// response schema of the interrupt, and returns the events of the resumed run.
+func (c *WorkflowsAPIController) AnswerInterruptHandler(rw http.ResponseWriter, req *http.Request) error {
+	run, err := c.loadRun(req)
+	if err != nil {
+		return err
+	}
+	var answer models.AnswerInterruptRequest
+	d := json.NewDecoder(req.Body)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&answer); err != nil {
+		return newStatusError(fmt.Errorf("failed to decode request: %w", err), http.StatusBadRequest)
+	}
+	interruptID := mux.Vars(req)["interrupt_id"]
+	interrupt := run.snapshot.Interrupt(interruptID)
+	if interrupt == nil {
+		return newStatusError(fmt.Errorf("run %q is not waiting on interrupt %q", run.invocationID, interruptID), http.StatusConflict)
+	}
+	payload, err := interrupt.Validate(answer.Payload)
+	if err != nil {
+		return newStatusError(err, http.StatusBadRequest)
+	}
+
+	sessionEvents, err := c.runtime.runAgent(req.Context(), models.RunAgentRequest{
+		AppName:   run.sessionID.AppName,
+		UserId:    run.sessionID.UserID,
+		SessionId: run.sessionID.ID,
+		NewMessage: genai.Content{Role: genai.RoleUser, Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{
+			ID:       interruptID,
+			Name:     workflow.WorkflowInputFunctionCallName,
+			Response: map[string]any{"payload": payload},
+		}}}},
+	})
+	if err != nil {
+		return err
+	}
+	encodeEvents(rw, sessionEvents)
+	return nil
+}
+
+// CancelRunHandler cancels a workflow run: the run is stopped if it's executing in this server, and its
+// interrupts can no longer be answered. It returns the state of the cancelled run.
+func (c *WorkflowsAPIController) CancelRunHandler(rw http.ResponseWriter, req *http.Request) error {
+	run, err := c.loadRun(req)
+	if err != nil {
+		return err
+	}
+	switch run.snapshot.Status {
+	case workflow.RunCompleted, workflow.RunFailed, workflow.RunCancelled:
+		return newStatusError(fmt.Errorf("run %q is already %s", run.invocationID, run.snapshot.Status), http.StatusConflict)
+	}
+	c.runtime.runs.cancel(run.sessionID.AppName, run.sessionID.UserID, run.sessionID.ID, run.invocationID)
+
+	ev, err := run.workflow.CancelEvent(req.Context(), run.session, run.invocationID)
+	if err != nil {
+		return newStatusError(fmt.Errorf("failed to cancel run: %w", err), http.StatusInternalServerError)
+	}
+	if err := c.sessionService.AppendEvent(req.Context(), run.session, ev); err != nil {
+		return newStatusError(fmt.Errorf("failed to cancel run: %w", err), http.StatusInternalServerError)
+	}
+	cancelled, err := c.loadRun(req)
+	if err != nil {
+		return err
+	}
+	EncodeJSONResponse(models.FromRunSnapshot(cancelled.snapshot), http.StatusOK, rw)
+	return nil
+}
+
+// RetryNodeHandler runs the failed node of a failed run of a durable workflow again from its checkpointed
+// input, continues the run from there, and returns the events of the run. Runs of workflows which aren't
+// durable have no checkpointed input and can't be retried: the run's "retryable" field tells which runs can.
+func (c *WorkflowsAPIController) RetryNodeHandler(rw http.ResponseWriter, req *http.Request) error {
+	run, err := c.loadRun(req)
+	if err != nil {
+		return err
+	}
+	if !run.workflow.Durable() {
+		return newStatusError(fmt.Errorf("workflow %q is not durable, the nodes of its runs can't be retried", run.workflow.Name()), http.StatusConflict)
+	}
+	node := mux.Vars(req)["node_name"]
+
+	release, err := c.runtime.rateLimiter.Acquire(req.Context(), ratelimit.Key{AppName: run.sessionID.AppName, UserID: run.sessionID.UserID, SessionID: run.sessionID.ID})
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	r, rCfg, err := c.runtime.getRunner(models.RunAgentRequest{AppName: run.sessionID.AppName})
+	if err != nil {
+		return err
+	}
+	resp := c.runtime.runs.track(req.Context(), run.sessionID.AppName, run.sessionID.UserID, run.sessionID.ID, func(ctx context.Context) iter.Seq2[*session.Event, error] {
+		return r.RetryNode(ctx, run.sessionID.UserID, run.sessionID.ID, run.invocationID, node, *rCfg)
+	})
+	var sessionEvents []*session.Event
+	for event, err := range resp {
+		if err != nil {
+			if errors.Is(err, workflow.ErrNothingToRecover) || errors.Is(err, workflow.ErrNodeNotFailed) || errors.Is(err, runner.ErrLeaseHeld) {
+				return newStatusError(err, http.StatusConflict)
+			}
+			return newStatusError(fmt.Errorf("failed to retry node: %w", err), http.StatusInternalServerError)
+		}
+		sessionEvents = append(sessionEvents, event)
+	}
+	encodeEvents(rw, sessionEvents)
+	return nil
+}
+
+func encodeEvents(rw http.ResponseWriter, sessionEvents []*session.Event) {
+	events := []models.Event{}
+	for _, event := range sessionEvents {
+		events = append(events, models.FromSessionEvent(*event))
+	}
+	EncodeJSONResponse(events, http.StatusOK, rw)
+}
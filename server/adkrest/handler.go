@@ -30,6 +30,8 @@ import (
 	"google.golang.org/adk/v2/server/adkrest/controllers"
 	"google.golang.org/adk/v2/server/adkrest/internal/routers"
 	"google.golang.org/adk/v2/server/adkrest/internal/services"
+	"google.golang.org/adk/v2/server/authn"
+	"google.golang.org/adk/v2/server/ratelimit"
 	"google.golang.org/adk/v2/session"
 )
 
@@ -43,11 +45,16 @@ func NewServer(cfg ServerConfig) (*Server, error) {
 	}
 
 	router := mux.NewRouter().StrictSlash(true)
+	if cfg.Authentication.Authenticator != nil {
+		router.Use(authn.Middleware(cfg.Authentication), authorizePathUser)
+	}
 	// TODO: Allow taking a prefix to allow customizing the path
 	// where the ADK REST API will be served.
+	runtime := controllers.NewRuntimeAPIController(cfg.SessionService, cfg.MemoryService, cfg.AgentLoader, cfg.ArtifactService, cfg.SSEWriteTimeout, cfg.PluginConfig, false, controllers.WithRateLimiter(cfg.RateLimiter))
 	setupRouter(router,
 		routers.NewSessionsAPIRouter(controllers.NewSessionsAPIController(cfg.SessionService)),
-		routers.NewRuntimeAPIRouter(controllers.NewRuntimeAPIController(cfg.SessionService, cfg.MemoryService, cfg.AgentLoader, cfg.ArtifactService, cfg.SSEWriteTimeout, cfg.PluginConfig, false)),
+		routers.NewRuntimeAPIRouter(runtime),
+		routers.NewWorkflowsAPIRouter(controllers.NewWorkflowsAPIController(cfg.SessionService, cfg.AgentLoader, runtime)),
 		routers.NewAppsAPIRouter(controllers.NewAppsAPIController(cfg.AgentLoader)),
 		routers.NewDebugAPIRouter(controllers.NewDebugAPIController(cfg.SessionService, cfg.AgentLoader, debugTelemetry)),
 		routers.NewArtifactsAPIRouter(controllers.NewArtifactsAPIController(cfg.ArtifactService)),
@@ -68,6 +75,13 @@ type ServerConfig struct {
 	SSEWriteTimeout time.Duration
 	PluginConfig    runner.PluginConfig
 	DebugConfig     DebugTelemetryConfig
+	// Authentication configures inbound request authentication. Requests are not authenticated
+	// when Authenticator is nil. Authenticated principals can only access their own sessions.
+	Authentication authn.MiddlewareConfig
+	// RateLimiter limits the rate and concurrency of runs started with /run, /run_sse and /run_live, and of
+	// workflow runs continued through the workflow_runs endpoints.
+	// Requests are not limited when it's nil.
+	RateLimiter *ratelimit.Limiter
 }
 
 // DebugTelemetryConfig contains parameters for the debug telemetry.
@@ -100,6 +114,19 @@ func (s *Server) LogProcessor() sdklog.Processor {
 	return s.telemetryStore.LogProcessor()
 }
 
+// authorizePathUser rejects requests addressing a {user_id} other than the authenticated one.
+func authorizePathUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if userID, ok := mux.Vars(r)["user_id"]; ok {
+			if err := authn.AuthorizeUser(r.Context(), userID); err != nil {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func setupRouter(router *mux.Router, subrouters ...routers.Router) *mux.Router {
 	routers.SetupSubRouters(router, subrouters...)
 	return router
@@ -0,0 +1,136 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adkrest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/server/adkrest"
+	"google.golang.org/adk/v2/server/authn"
+	"google.golang.org/adk/v2/session"
+)
+
+func TestServer_Authentication(t *testing.T) {
+	const appName = "auth_app"
+
+	var gotPrincipal *agent.Principal
+	a, err := agent.New(agent.Config{
+		Name: appName,
+		Run: func(ic agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				gotPrincipal, _ = agent.PrincipalFromContext(ic)
+				event := session.NewEvent(ic, ic.InvocationID())
+				event.Content = genai.NewContentFromText("ok", genai.RoleModel)
+				yield(event, nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("agent.New() error = %v", err)
+	}
+	authenticator, err := authn.NewAPIKeyAuthenticator(authn.APIKeyConfig{Keys: map[string]string{"alice-key": "alice"}})
+	if err != nil {
+		t.Fatalf("authn.NewAPIKeyAuthenticator() error = %v", err)
+	}
+	handler, err := adkrest.NewServer(adkrest.ServerConfig{
+		SessionService: session.InMemoryService(),
+		AgentLoader:    agent.NewSingleLoader(a),
+		Authentication: authn.MiddlewareConfig{Authenticator: authenticator},
+	})
+	if err != nil {
+		t.Fatalf("adkrest.NewServer() error = %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	do := func(method, path, key string, body any) int {
+		t.Helper()
+		raw, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		req, err := http.NewRequest(method, srv.URL+path, bytes.NewReader(raw))
+		if err != nil {
+			t.Fatalf("http.NewRequest() error = %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	sessionsPath := "/apps/" + appName + "/users/alice/sessions/s1"
+	if got := do(http.MethodPost, sessionsPath, "", map[string]any{}); got != http.StatusUnauthorized {
+		t.Errorf("create session without key: status = %d, want %d", got, http.StatusUnauthorized)
+	}
+	if got := do(http.MethodPost, sessionsPath, "wrong-key", map[string]any{}); got != http.StatusUnauthorized {
+		t.Errorf("create session with unknown key: status = %d, want %d", got, http.StatusUnauthorized)
+	}
+	if got := do(http.MethodPost, "/apps/"+appName+"/users/bob/sessions/s1", "alice-key", map[string]any{}); got != http.StatusForbidden {
+		t.Errorf("create session for another user: status = %d, want %d", got, http.StatusForbidden)
+	}
+	if got := do(http.MethodPost, sessionsPath, "alice-key", map[string]any{}); got != http.StatusOK {
+		t.Fatalf("create session: status = %d, want %d", got, http.StatusOK)
+	}
+
+	runBody := func(userID string) map[string]any {
+		return map[string]any{
+			"appName":    appName,
+			"userId":     userID,
+			"sessionId":  "s1",
+			"newMessage": genai.NewContentFromText("hi", genai.RoleUser),
+		}
+	}
+	if got := do(http.MethodPost, "/run", "alice-key", runBody("bob")); got != http.StatusForbidden {
+		t.Errorf("run for another user: status = %d, want %d", got, http.StatusForbidden)
+	}
+	if got := do(http.MethodPost, "/run", "alice-key", runBody("alice")); got != http.StatusOK {
+		t.Fatalf("run: status = %d, want %d", got, http.StatusOK)
+	}
+	if gotPrincipal == nil || gotPrincipal.UserID != "alice" || gotPrincipal.Method != authn.MethodAPIKey {
+		t.Errorf("agent principal = %+v, want user alice authenticated with %q", gotPrincipal, authn.MethodAPIKey)
+	}
+
+	// Traces are only served scoped by user.
+	for _, tt := range []struct {
+		path string
+		want int
+	}{
+		{"/debug/trace/session/s1", http.StatusForbidden},
+		{"/debug/trace/some-event", http.StatusForbidden},
+		{"/apps/" + appName + "/users/bob/sessions/s1/trace", http.StatusForbidden},
+		{"/apps/" + appName + "/users/bob/sessions/s1/events/some-event/trace", http.StatusForbidden},
+		{sessionsPath + "/trace", http.StatusOK},
+		{sessionsPath + "/events/some-event/trace", http.StatusNotFound},
+	} {
+		if got := do(http.MethodGet, tt.path, "alice-key", nil); got != tt.want {
+			t.Errorf("GET %s: status = %d, want %d", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// WorkflowGraphResponse represents the graph of a workflow agent with the status of its nodes in an invocation.
+type WorkflowGraphResponse struct {
+	// DotSrc is the graph in the Graphviz DOT language, set for the "dot" format.
+	DotSrc string `json:"dotSrc,omitempty"`
+	// MermaidSrc is the graph as a Mermaid flowchart, set for the "mermaid" format.
+	MermaidSrc string `json:"mermaidSrc,omitempty"`
+	// InvocationID is the invocation the node statuses are reported for.
+	InvocationID string `json:"invocationId,omitempty"`
+	// NodeStatuses maps node paths to their status, e.g. "completed" or "waiting". Nodes which didn't run are omitted.
+	NodeStatuses map[string]string `json:"nodeStatuses"`
+}
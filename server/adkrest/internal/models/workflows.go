@@ -0,0 +1,92 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"google.golang.org/adk/v2/workflow"
+)
+
+// WorkflowRun represents the state of a run of a workflow agent.
+type WorkflowRun struct {
+	InvocationID string `json:"invocationId"`
+	// Status is the status of the run, e.g. "paused" or "failed". Empty when unknown, e.g. for a finished
+	// run of a workflow which isn't durable.
+	Status string `json:"status,omitempty"`
+	// Retryable reports whether the failed nodes of the run can be retried. Only failed runs of durable
+	// workflows can be.
+	Retryable bool `json:"retryable"`
+	// Nodes maps node names to their state. Nodes which didn't run are omitted.
+	Nodes map[string]WorkflowNodeState `json:"nodes"`
+	// Interrupts are the requests for input the run waits on.
+	Interrupts []WorkflowInterrupt `json:"interrupts"`
+}
+
+// WorkflowNodeState represents the state of a node in a workflow run.
+type WorkflowNodeState struct {
+	// Status is the status of the node, e.g. "completed" or "waiting".
+	Status   string `json:"status"`
+	Attempts int    `json:"attempts,omitempty"`
+	Input    any    `json:"input,omitempty"`
+	Output   any    `json:"output,omitempty"`
+	// Interrupts are the IDs of the interrupts the node waits on.
+	Interrupts []string `json:"interrupts,omitempty"`
+}
+
+// WorkflowInterrupt represents a request for input a workflow run waits on.
+type WorkflowInterrupt struct {
+	InterruptID    string             `json:"interruptId"`
+	Node           string             `json:"node"`
+	Message        string             `json:"message,omitempty"`
+	Payload        any                `json:"payload,omitempty"`
+	ResponseSchema *jsonschema.Schema `json:"responseSchema,omitempty"`
+}
+
+// AnswerInterruptRequest is the answer to a request for input of a workflow run.
+type AnswerInterruptRequest struct {
+	// Payload is the answer. It must conform to the response schema of the request, if any.
+	Payload any `json:"payload"`
+}
+
+// FromRunSnapshot converts a workflow.RunSnapshot to its API representation.
+func FromRunSnapshot(snap *workflow.RunSnapshot) WorkflowRun {
+	run := WorkflowRun{
+		InvocationID: snap.InvocationID,
+		Status:       string(snap.Status),
+		Retryable:    snap.Retryable,
+		Nodes:        map[string]WorkflowNodeState{},
+		Interrupts:   []WorkflowInterrupt{},
+	}
+	for name, ns := range snap.Nodes {
+		run.Nodes[name] = WorkflowNodeState{
+			Status:     ns.Status.String(),
+			Attempts:   ns.Attempt,
+			Input:      ns.Input,
+			Output:     ns.Output,
+			Interrupts: ns.Interrupts,
+		}
+	}
+	for _, in := range snap.Interrupts {
+		run.Interrupts = append(run.Interrupts, WorkflowInterrupt{
+			InterruptID:    in.Request.InterruptID,
+			Node:           in.Node,
+			Message:        in.Request.Message,
+			Payload:        in.Request.Payload,
+			ResponseSchema: in.Request.ResponseSchema,
+		})
+	}
+	return run
+}
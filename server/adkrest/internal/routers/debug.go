@@ -39,12 +39,24 @@ func (r *DebugAPIRouter) Routes() Routes {
 			Pattern:     "/debug/trace/{event_id}",
 			HandlerFunc: r.runtimeController.EventSpanHandler,
 		},
+		Route{
+			Name:        "GetUserEventTrace",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/events/{event_id}/trace",
+			HandlerFunc: r.runtimeController.EventSpanHandler,
+		},
 		Route{
 			Name:        "GetEventGraph",
 			Methods:     []string{http.MethodGet},
 			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/events/{event_id}/graph",
 			HandlerFunc: r.runtimeController.EventGraphHandler,
 		},
+		Route{
+			Name:        "GetWorkflowGraph",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/graph",
+			HandlerFunc: r.runtimeController.WorkflowGraphHandler,
+		},
 
 		Route{
 			Name:        "GetSessionTrace",
@@ -52,5 +64,11 @@ func (r *DebugAPIRouter) Routes() Routes {
 			Pattern:     "/debug/trace/session/{session_id}",
 			HandlerFunc: r.runtimeController.SessionSpansHandler,
 		},
+		Route{
+			Name:        "GetUserSessionTrace",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/trace",
+			HandlerFunc: r.runtimeController.SessionSpansHandler,
+		},
 	}
 }
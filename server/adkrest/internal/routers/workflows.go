@@ -0,0 +1,62 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routers
+
+import (
+	"net/http"
+
+	"google.golang.org/adk/v2/server/adkrest/controllers"
+)
+
+// WorkflowsAPIRouter defines the routes for the Workflows API.
+type WorkflowsAPIRouter struct {
+	workflowsController *controllers.WorkflowsAPIController
+}
+
+// NewWorkflowsAPIRouter creates a new WorkflowsAPIRouter.
+func NewWorkflowsAPIRouter(controller *controllers.WorkflowsAPIController) *WorkflowsAPIRouter {
+	return &WorkflowsAPIRouter{workflowsController: controller}
+}
+
+// Routes returns the routes for the Workflows API.
+func (r *WorkflowsAPIRouter) Routes() Routes {
+	const run = "/apps/{app_name}/users/{user_id}/sessions/{session_id}/workflow_runs/{invocation_id}"
+	return Routes{
+		Route{
+			Name:        "GetWorkflowRun",
+			Methods:     []string{http.MethodGet},
+			Pattern:     run,
+			HandlerFunc: controllers.NewErrorHandler(r.workflowsController.GetRunHandler),
+		},
+		Route{
+			Name:        "AnswerWorkflowInterrupt",
+			Methods:     []string{http.MethodPost},
+			Pattern:     run + "/interrupts/{interrupt_id}",
+			HandlerFunc: controllers.NewErrorHandler(r.workflowsController.AnswerInterruptHandler),
+		},
+		Route{
+			Name:        "CancelWorkflowRun",
+			Methods:     []string{http.MethodPost},
+			Pattern:     run + "/cancel",
+			HandlerFunc: controllers.NewErrorHandler(r.workflowsController.CancelRunHandler),
+		},
+		Route{
+			Name:        "RetryWorkflowNode",
+			Methods:     []string{http.MethodPost},
+			Pattern:     run + "/nodes/{node_name}/retry",
+			HandlerFunc: controllers.NewErrorHandler(r.workflowsController.RetryNodeHandler),
+		},
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adkrest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/agent/workflowagent"
+	"google.golang.org/adk/v2/server/adkrest"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/workflow"
+)
+
+// newApprovalServer builds the REST handler around a workflow agent in
+// which ask pauses on a request for a boolean and done reports it.
+func newApprovalServer(t *testing.T) *adkrest.Server {
+	t.Helper()
+	ask := workflow.NewEmittingFunctionNode[any, any]("ask",
+		func(ic agent.Context, _ any, emit func(*session.Event) error) (any, error) {
+			if err := emit(workflow.NewRequestInputEvent(ic, session.RequestInput{
+				InterruptID:    "approve",
+				Message:        "Approve?",
+				ResponseSchema: &jsonschema.Schema{Type: "boolean"},
+			})); err != nil {
+				return nil, err
+			}
+			return nil, workflow.ErrNodeInterrupted
+		},
+		workflow.NodeConfig{},
+	)
+	done := workflow.NewFunctionNode("done",
+		func(_ agent.Context, approved bool) (string, error) {
+			return fmt.Sprintf("approved: %t", approved), nil
+		},
+		workflow.NodeConfig{},
+	)
+	a, err := workflowagent.New(workflowagent.Config{
+		Name:  hitlApp,
+		Edges: workflow.Chain(workflow.Start, ask, done),
+	})
+	if err != nil {
+		t.Fatalf("workflowagent.New() error = %v", err)
+	}
+	srv, err := adkrest.NewServer(adkrest.ServerConfig{
+		SessionService: session.InMemoryService(),
+		AgentLoader:    agent.NewSingleLoader(a),
+	})
+	if err != nil {
+		t.Fatalf("adkrest.NewServer() error = %v", err)
+	}
+	return srv
+}
+
+// workflowRun is the subset of the REST WorkflowRun JSON the tests inspect.
+type workflowRun struct {
+	Status    string `json:"status"`
+	Retryable bool   `json:"retryable"`
+	Nodes     map[string]struct {
+		Status string `json:"status"`
+		Output any    `json:"output"`
+	} `json:"nodes"`
+	Interrupts []struct {
+		InterruptID    string             `json:"interruptId"`
+		Node           string             `json:"node"`
+		Message        string             `json:"message"`
+		ResponseSchema *jsonschema.Schema `json:"responseSchema"`
+	} `json:"interrupts"`
+}
+
+// startApprovalRun runs the first turn of the approval workflow and
+// returns the URL of its run.
+func startApprovalRun(t *testing.T, baseURL string) string {
+	t.Helper()
+	sid := createSession(t, baseURL)
+	var events []struct {
+		InvocationID string `json:"invocationId"`
+	}
+	postJSON(t, baseURL+"/run", map[string]any{
+		"appName":    hitlApp,
+		"userId":     hitlUser,
+		"sessionId":  sid,
+		"newMessage": genai.NewContentFromText("go", genai.RoleUser),
+	}, &events)
+	if len(events) == 0 || events[0].InvocationID == "" {
+		t.Fatalf("run returned no invocation: %+v", events)
+	}
+	return fmt.Sprintf("%s/apps/%s/users/%s/sessions/%s/workflow_runs/%s", baseURL, hitlApp, hitlUser, sid, events[0].InvocationID)
+}
+
+func getRun(t *testing.T, url string) workflowRun {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: status %d, body %s", url, resp.StatusCode, data)
+	}
+	var run workflowRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		t.Fatalf("decode run: %v\nbody: %s", err, data)
+	}
+	return run
+}
+
+func postStatus(t *testing.T, url string, body any) int {
+	t.Helper()
+	raw, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("marshal request: %v", err)
+	}
+	resp, err := http.Post(url, "application/json", bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("POST %s: %v", url, err)
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestWorkflowRuns_InspectAndAnswer(t *testing.T) {
+	srv := httptest.NewServer(newApprovalServer(t))
+	defer srv.Close()
+	runURL := startApprovalRun(t, srv.URL)
+
+	run := getRun(t, runURL)
+	if run.Status != "paused" || run.Nodes["ask"].Status != "waiting" {
+		t.Fatalf("run = %+v, want paused with ask waiting", run)
+	}
+	if len(run.Interrupts) != 1 {
+		t.Fatalf("interrupts = %+v, want one", run.Interrupts)
+	}
+	in := run.Interrupts[0]
+	if in.InterruptID != "approve" || in.Node != "ask" || in.Message != "Approve?" || in.ResponseSchema == nil || in.ResponseSchema.Type != "boolean" {
+		t.Errorf("interrupt = %+v, want the request of ask", in)
+	}
+
+	if got := postStatus(t, runURL+"/interrupts/approve", map[string]any{"payload": "yes"}); got != http.StatusBadRequest {
+		t.Errorf("answer with invalid payload: status %d, want %d", got, http.StatusBadRequest)
+	}
+	if got := postStatus(t, runURL+"/interrupts/other", map[string]any{"payload": true}); got != http.StatusConflict {
+		t.Errorf("answer to unknown interrupt: status %d, want %d", got, http.StatusConflict)
+	}
+
+	var events []restEvent
+	postJSON(t, runURL+"/interrupts/approve", map[string]any{"payload": true}, &events)
+	if got := greetingOutput(events); got != "approved: true" {
+		t.Errorf("resumed run output = %q, want %q", got, "approved: true")
+	}
+	run = getRun(t, runURL)
+	if len(run.Interrupts) != 0 || run.Nodes["done"].Output != "approved: true" {
+		t.Errorf("run after answer = %+v, want done without interrupts", run)
+	}
+}
+
+func TestWorkflowRuns_Cancel(t *testing.T) {
+	srv := httptest.NewServer(newApprovalServer(t))
+	defer srv.Close()
+	runURL := startApprovalRun(t, srv.URL)
+
+	var run workflowRun
+	postJSON(t, runURL+"/cancel", map[string]any{}, &run)
+	if run.Status != "cancelled" || len(run.Interrupts) != 0 {
+		t.Errorf("cancelled run = %+v, want cancelled without interrupts", run)
+	}
+	if got := postStatus(t, runURL+"/interrupts/approve", map[string]any{"payload": true}); got != http.StatusConflict {
+		t.Errorf("answer after cancel: status %d, want %d", got, http.StatusConflict)
+	}
+	if got := postStatus(t, runURL+"/cancel", map[string]any{}); got != http.StatusConflict {
+		t.Errorf("second cancel: status %d, want %d", got, http.StatusConflict)
+	}
+	if got := postStatus(t, runURL+"/nodes/ask/retry", map[string]any{}); got != http.StatusConflict {
+		t.Errorf("retry in a workflow which isn't durable: status %d, want %d", got, http.StatusConflict)
+	}
+}
+
+func TestWorkflowRuns_FailedRunWithoutCheckpoint(t *testing.T) {
+	fail := workflow.NewFunctionNode("fail",
+		func(_ agent.Context, _ any) (string, error) {
+			return "", errors.New("boom")
+		},
+		workflow.NodeConfig{},
+	)
+	a, err := workflowagent.New(workflowagent.Config{
+		Name:  hitlApp,
+		Edges: workflow.Chain(workflow.Start, fail),
+	})
+	if err != nil {
+		t.Fatalf("workflowagent.New() error = %v", err)
+	}
+	handler, err := adkrest.NewServer(adkrest.ServerConfig{
+		SessionService: session.InMemoryService(),
+		AgentLoader:    agent.NewSingleLoader(a),
+	})
+	if err != nil {
+		t.Fatalf("adkrest.NewServer() error = %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	sid := createSession(t, srv.URL)
+	postStatus(t, srv.URL+"/run", map[string]any{
+		"appName":    hitlApp,
+		"userId":     hitlUser,
+		"sessionId":  sid,
+		"newMessage": genai.NewContentFromText("go", genai.RoleUser),
+	})
+	sessionURL := fmt.Sprintf("%s/apps/%s/users/%s/sessions/%s", srv.URL, hitlApp, hitlUser, sid)
+	resp, err := http.Get(sessionURL)
+	if err != nil {
+		t.Fatalf("GET %s: %v", sessionURL, err)
+	}
+	var sess struct {
+		Events []struct {
+			InvocationID string `json:"invocationId"`
+		} `json:"events"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&sess)
+	_ = resp.Body.Close()
+	if err != nil || len(sess.Events) == 0 {
+		t.Fatalf("session events = %+v, %v, want the events of the failed run", sess.Events, err)
+	}
+	runURL := sessionURL + "/workflow_runs/" + sess.Events[0].InvocationID
+
+	run := getRun(t, runURL)
+	if run.Status != "failed" || run.Retryable {
+		t.Errorf("run = %+v, want failed and not retryable", run)
+	}
+	if got := run.Nodes["fail"].Status; got != "failed" {
+		t.Errorf("node fail status = %q, want %q", got, "failed")
+	}
+	if got := postStatus(t, runURL+"/nodes/fail/retry", map[string]any{}); got != http.StatusConflict {
+		t.Errorf("retry in a workflow which isn't durable: status %d, want %d", got, http.StatusConflict)
+	}
+}
+
+func TestWorkflowRuns_UnknownRun(t *testing.T) {
+	srv := httptest.NewServer(newApprovalServer(t))
+	defer srv.Close()
+	sid := createSession(t, srv.URL)
+
+	resp, err := http.Get(fmt.Sprintf("%s/apps/%s/users/%s/sessions/%s/workflow_runs/missing", srv.URL, hitlApp, hitlUser, sid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
@@ -25,6 +25,7 @@ import (
 
 	"google.golang.org/adk/v2/server/agentengine/controllers/method"
 	"google.golang.org/adk/v2/server/agentengine/internal/models"
+	"google.golang.org/adk/v2/server/authn"
 	"google.golang.org/adk/v2/session"
 )
 
@@ -77,6 +78,11 @@ func (c *AgentEngineAPIController) Query(rw http.ResponseWriter, req *http.Reque
 		}
 	}
 
+	if err := authn.AuthorizeUser(req.Context(), queryUserID(query)); err != nil {
+		http.Error(rw, err.Error(), http.StatusForbidden)
+		return
+	}
+
 	err = c.handleQuery(req.Context(), rw, payload, query.ClassMethod)
 	if err != nil {
 		log.Printf("handleQuery failed: %v", err)
@@ -92,3 +98,24 @@ func (c *AgentEngineAPIController) handleQuery(context context.Context, rw http.
 	}
 	return handler.Handle(context, rw, payload)
 }
+
+// queryUserID extracts user_id from the query input. Methods either pass it directly
+// or as a part of JSON-encoded request_json.
+func queryUserID(query models.Query) string {
+	input, ok := query.Input.(map[string]any)
+	if !ok {
+		return ""
+	}
+	if userID, ok := input["user_id"].(string); ok {
+		return userID
+	}
+	if requestJSON, ok := input["request_json"].(string); ok {
+		var request struct {
+			UserID string `json:"user_id"`
+		}
+		if err := json.Unmarshal([]byte(requestJSON), &request); err == nil {
+			return request.UserID
+		}
+	}
+	return ""
+}
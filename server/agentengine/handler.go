@@ -32,12 +32,16 @@ import (
 	"google.golang.org/adk/v2/server/agentengine/controllers"
 	"google.golang.org/adk/v2/server/agentengine/controllers/method"
 	"google.golang.org/adk/v2/server/agentengine/internal/routers"
+	"google.golang.org/adk/v2/server/authn"
 )
 
 // NewHandler creates and returns an http.Handler for the AgentEngine API.
 // Handles both streaming and non-streaming versions
 func NewHandler(config *launcher.Config, sseWriteTimeout time.Duration, maxPayloadSize int64, agentEngineID string) (http.Handler, error) {
 	router := mux.NewRouter().StrictSlash(true)
+	if config.Authentication.Authenticator != nil {
+		router.Use(authn.Middleware(config.Authentication))
+	}
 
 	nonStreamAgentEngineController, err := controllers.NewAgentEngineAPIController(config.SessionService, sseWriteTimeout, maxPayloadSize,
 		listNonStreamHandlers(config, agentEngineID))
@@ -0,0 +1,84 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authn
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+
+	"github.com/a2aproject/a2a-go/v2/a2a"
+
+	"google.golang.org/adk/v2/agent"
+)
+
+const (
+	// MethodAPIKey is the [agent.Principal] Method of principals authenticated with an API key.
+	MethodAPIKey = "api_key"
+
+	defaultAPIKeyHeader = "X-API-Key"
+)
+
+// APIKeyConfig allows to configure an authenticator created with [NewAPIKeyAuthenticator].
+type APIKeyConfig struct {
+	// Header is the request header carrying the key. Defaults to "X-API-Key".
+	Header string
+	// Keys maps accepted API keys to the IDs of users they belong to.
+	Keys map[string]string
+}
+
+type apiKeyAuthenticator struct {
+	header string
+	// users is keyed by key digests to avoid comparing secrets byte by byte
+	users map[[sha256.Size]byte]string
+}
+
+// NewAPIKeyAuthenticator creates an [Authenticator] which accepts requests carrying one of the configured keys.
+func NewAPIKeyAuthenticator(config APIKeyConfig) (Authenticator, error) {
+	if len(config.Keys) == 0 {
+		return nil, fmt.Errorf("at least one API key is required")
+	}
+	users := make(map[[sha256.Size]byte]string, len(config.Keys))
+	for key, userID := range config.Keys {
+		if key == "" || userID == "" {
+			return nil, fmt.Errorf("API keys and user IDs must not be empty")
+		}
+		users[sha256.Sum256([]byte(key))] = userID
+	}
+	return &apiKeyAuthenticator{header: cmp.Or(config.Header, defaultAPIKeyHeader), users: users}, nil
+}
+
+// Authenticate implements Authenticator.
+func (a *apiKeyAuthenticator) Authenticate(req *http.Request) (*agent.Principal, error) {
+	key := req.Header.Get(a.header)
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+	userID, ok := a.users[sha256.Sum256([]byte(key))]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown API key", ErrInvalidCredentials)
+	}
+	return &agent.Principal{UserID: userID, Subject: userID, Method: MethodAPIKey}, nil
+}
+
+// SecuritySchemes describes the accepted credentials in A2A agent cards.
+func (a *apiKeyAuthenticator) SecuritySchemes() (a2a.NamedSecuritySchemes, a2a.SecurityRequirementsOptions) {
+	return a2a.NamedSecuritySchemes{
+		MethodAPIKey: a2a.APIKeySecurityScheme{Location: a2a.APIKeySecuritySchemeLocationHeader, Name: a.header},
+	}, a2a.SecurityRequirementsOptions{
+		{MethodAPIKey: a2a.SecuritySchemeScopes{}},
+	}
+}
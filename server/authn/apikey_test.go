@@ -0,0 +1,67 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authn
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	authenticator, err := NewAPIKeyAuthenticator(APIKeyConfig{Keys: map[string]string{"secret-1": "alice", "secret-2": "bob"}})
+	if err != nil {
+		t.Fatalf("NewAPIKeyAuthenticator() error = %v", err)
+	}
+
+	testCases := []struct {
+		name       string
+		key        string
+		wantUserID string
+		wantErr    error
+	}{
+		{name: "first key", key: "secret-1", wantUserID: "alice"},
+		{name: "second key", key: "secret-2", wantUserID: "bob"},
+		{name: "unknown key", key: "secret-3", wantErr: ErrInvalidCredentials},
+		{name: "no key", wantErr: ErrNoCredentials},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/run", nil)
+			if tc.key != "" {
+				req.Header.Set("X-API-Key", tc.key)
+			}
+			principal, err := authenticator.Authenticate(req)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tc.wantErr)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+			if principal.UserID != tc.wantUserID || principal.Method != MethodAPIKey {
+				t.Errorf("Authenticate() = %+v, want user ID %q, method %q", principal, tc.wantUserID, MethodAPIKey)
+			}
+		})
+	}
+}
+
+func TestNewAPIKeyAuthenticator_Errors(t *testing.T) {
+	for _, keys := range []map[string]string{nil, {"": "alice"}, {"secret": ""}} {
+		if _, err := NewAPIKeyAuthenticator(APIKeyConfig{Keys: keys}); err == nil {
+			t.Errorf("NewAPIKeyAuthenticator(%v) error = nil, want error", keys)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package authn provides inbound authentication for ADK servers.
+//
+// An [Authenticator] resolves the [agent.Principal] of an HTTP request. Built-in
+// authenticators cover API keys ([NewAPIKeyAuthenticator]), JWT / OIDC bearer
+// tokens verified against a local JWKS ([NewJWTAuthenticator]) and mTLS client
+// certificates ([NewMTLSAuthenticator]), and can be combined with [Any].
+//
+// [Middleware] attaches the principal to the request context, from where it
+// reaches agents, tools and plugins through [agent.ReadonlyContext] Principal.
+// Servers use [AuthorizeUser] to reject requests which address a user other
+// than the authenticated one.
+package authn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+
+	"github.com/a2aproject/a2a-go/v2/a2a"
+
+	"google.golang.org/adk/v2/agent"
+)
+
+var (
+	// ErrNoCredentials is returned by an [Authenticator] when the request doesn't carry
+	// the kind of credentials it handles. [Any] moves on to the next authenticator.
+	ErrNoCredentials = errors.New("no credentials")
+	// ErrInvalidCredentials is returned by an [Authenticator] when the request credentials
+	// are malformed, expired or unknown.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrForbidden is returned by [AuthorizeUser] when a request addresses a user other than the authenticated one.
+	ErrForbidden = errors.New("principal is not allowed to act on behalf of the user")
+)
+
+// Authenticator resolves the principal who made a request.
+type Authenticator interface {
+	// Authenticate returns the request principal. It returns an error wrapping [ErrNoCredentials] if
+	// the request doesn't carry supported credentials and [ErrInvalidCredentials] if they are rejected.
+	Authenticate(req *http.Request) (*agent.Principal, error)
+}
+
+// AuthenticatorFunc is a function type which implements [Authenticator].
+type AuthenticatorFunc func(req *http.Request) (*agent.Principal, error)
+
+// Authenticate implements Authenticator.
+func (fn AuthenticatorFunc) Authenticate(req *http.Request) (*agent.Principal, error) {
+	return fn(req)
+}
+
+type anyAuthenticator []Authenticator
+
+// Any returns an [Authenticator] which tries the provided authenticators in order and returns the
+// first principal found. Authentication fails without trying the rest if credentials are rejected.
+func Any(authenticators ...Authenticator) Authenticator {
+	return anyAuthenticator(authenticators)
+}
+
+// Authenticate implements Authenticator.
+func (a anyAuthenticator) Authenticate(req *http.Request) (*agent.Principal, error) {
+	for _, authenticator := range a {
+		principal, err := authenticator.Authenticate(req)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		return principal, err
+	}
+	return nil, ErrNoCredentials
+}
+
+// SecuritySchemes returns the union of schemes accepted by the combined authenticators, any of which
+// can be used to satisfy the requirements.
+func (a anyAuthenticator) SecuritySchemes() (a2a.NamedSecuritySchemes, a2a.SecurityRequirementsOptions) {
+	schemes := a2a.NamedSecuritySchemes{}
+	var requirements a2a.SecurityRequirementsOptions
+	for _, authenticator := range a {
+		provider, ok := authenticator.(interface {
+			SecuritySchemes() (a2a.NamedSecuritySchemes, a2a.SecurityRequirementsOptions)
+		})
+		if !ok {
+			continue
+		}
+		s, r := provider.SecuritySchemes()
+		maps.Copy(schemes, s)
+		requirements = append(requirements, r...)
+	}
+	return schemes, requirements
+}
+
+// MiddlewareConfig allows to configure the authentication middleware created with [Middleware].
+type MiddlewareConfig struct {
+	// Authenticator resolves request principals.
+	Authenticator Authenticator
+	// UserID maps an authenticated principal to an ADK user ID. By default the principal
+	// UserID set by the authenticator is used.
+	UserID func(principal *agent.Principal) (string, error)
+	// AllowAnonymous lets requests without credentials through. Such requests carry no principal
+	// and are not restricted by [AuthorizeUser].
+	AllowAnonymous bool
+}
+
+// Middleware returns an HTTP middleware which authenticates requests and attaches the principal to the
+// request context. Requests without valid credentials are rejected with 401 Unauthorized.
+// CORS preflight requests are passed through unauthenticated.
+func Middleware(config MiddlewareConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Method == http.MethodOptions {
+				next.ServeHTTP(w, req)
+				return
+			}
+			principal, err := authenticate(req, config)
+			if errors.Is(err, ErrNoCredentials) && config.AllowAnonymous {
+				next.ServeHTTP(w, req)
+				return
+			}
+			if err != nil {
+				http.Error(w, fmt.Sprintf("authentication failed: %v", err), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, req.WithContext(agent.NewContextWithPrincipal(req.Context(), principal)))
+		})
+	}
+}
+
+func authenticate(req *http.Request, config MiddlewareConfig) (*agent.Principal, error) {
+	principal, err := config.Authenticator.Authenticate(req)
+	if err != nil {
+		return nil, err
+	}
+	if principal == nil {
+		return nil, ErrNoCredentials
+	}
+	if config.UserID != nil {
+		userID, err := config.UserID(principal)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+		}
+		mapped := *principal
+		mapped.UserID = userID
+		principal = &mapped
+	}
+	if principal.UserID == "" {
+		return nil, fmt.Errorf("%w: principal is not mapped to a user", ErrInvalidCredentials)
+	}
+	return principal, nil
+}
+
+// AuthorizeUser returns an error wrapping [ErrForbidden] if ctx carries a principal mapped to a user
+// other than userID.
+//
+// Contexts without a principal are not restricted: AuthorizeUser is only a check when [Middleware],
+// which rejects unauthenticated requests, is mounted in front of the handler calling it. Servers
+// which accept unauthenticated requests serve every user.
+func AuthorizeUser(ctx context.Context, userID string) error {
+	principal, ok := agent.PrincipalFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if principal.UserID != userID {
+		return fmt.Errorf("%w: %q", ErrForbidden, userID)
+	}
+	return nil
+}
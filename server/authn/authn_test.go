@@ -0,0 +1,182 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authn
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/v2/agent"
+)
+
+func headerAuthenticator(header, method string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) (*agent.Principal, error) {
+		value := req.Header.Get(header)
+		switch value {
+		case "":
+			return nil, ErrNoCredentials
+		case "bad":
+			return nil, fmt.Errorf("%w: rejected", ErrInvalidCredentials)
+		}
+		return &agent.Principal{UserID: value, Subject: value, Method: method}, nil
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	testCases := []struct {
+		name          string
+		config        MiddlewareConfig
+		method        string
+		header        string
+		wantStatus    int
+		wantPrincipal *agent.Principal
+	}{
+		{
+			name:          "authenticated",
+			config:        MiddlewareConfig{Authenticator: headerAuthenticator("X-User", "test")},
+			header:        "alice",
+			wantStatus:    http.StatusOK,
+			wantPrincipal: &agent.Principal{UserID: "alice", Subject: "alice", Method: "test"},
+		},
+		{
+			name:       "no credentials",
+			config:     MiddlewareConfig{Authenticator: headerAuthenticator("X-User", "test")},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "invalid credentials",
+			config:     MiddlewareConfig{Authenticator: headerAuthenticator("X-User", "test")},
+			header:     "bad",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "anonymous allowed",
+			config:     MiddlewareConfig{Authenticator: headerAuthenticator("X-User", "test"), AllowAnonymous: true},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "anonymous allowed does not accept invalid credentials",
+			config:     MiddlewareConfig{Authenticator: headerAuthenticator("X-User", "test"), AllowAnonymous: true},
+			header:     "bad",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "preflight",
+			config:     MiddlewareConfig{Authenticator: headerAuthenticator("X-User", "test")},
+			method:     http.MethodOptions,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "user ID mapping",
+			config: MiddlewareConfig{
+				Authenticator: headerAuthenticator("X-User", "test"),
+				UserID: func(p *agent.Principal) (string, error) {
+					return "tenant/" + p.Subject, nil
+				},
+			},
+			header:        "alice",
+			wantStatus:    http.StatusOK,
+			wantPrincipal: &agent.Principal{UserID: "tenant/alice", Subject: "alice", Method: "test"},
+		},
+		{
+			name: "user ID mapping failure",
+			config: MiddlewareConfig{
+				Authenticator: headerAuthenticator("X-User", "test"),
+				UserID: func(p *agent.Principal) (string, error) {
+					return "", errors.New("unknown subject")
+				},
+			},
+			header:     "alice",
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotPrincipal *agent.Principal
+			handler := Middleware(tc.config)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				gotPrincipal, _ = agent.PrincipalFromContext(req.Context())
+			}))
+
+			method := tc.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, "/run", nil)
+			if tc.header != "" {
+				req.Header.Set("X-User", tc.header)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d", rr.Code, tc.wantStatus)
+			}
+			if diff := cmp.Diff(tc.wantPrincipal, gotPrincipal); diff != "" {
+				t.Errorf("principal mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAny(t *testing.T) {
+	authenticator := Any(headerAuthenticator("X-First", "first"), headerAuthenticator("X-Second", "second"))
+
+	testCases := []struct {
+		name       string
+		headers    map[string]string
+		wantMethod string
+		wantErr    error
+	}{
+		{name: "first", headers: map[string]string{"X-First": "alice", "X-Second": "bob"}, wantMethod: "first"},
+		{name: "falls through", headers: map[string]string{"X-Second": "bob"}, wantMethod: "second"},
+		{name: "rejected credentials stop", headers: map[string]string{"X-First": "bad", "X-Second": "bob"}, wantErr: ErrInvalidCredentials},
+		{name: "none", wantErr: ErrNoCredentials},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			principal, err := authenticator.Authenticate(req)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tc.wantErr)
+			}
+			if tc.wantErr == nil && principal.Method != tc.wantMethod {
+				t.Errorf("Authenticate() method = %q, want %q", principal.Method, tc.wantMethod)
+			}
+		})
+	}
+}
+
+func TestAuthorizeUser(t *testing.T) {
+	ctx := t.Context()
+	if err := AuthorizeUser(ctx, "anyone"); err != nil {
+		t.Errorf("AuthorizeUser() on unauthenticated context error = %v, want nil", err)
+	}
+
+	ctx = agent.NewContextWithPrincipal(ctx, &agent.Principal{UserID: "alice"})
+	if err := AuthorizeUser(ctx, "alice"); err != nil {
+		t.Errorf("AuthorizeUser(alice) error = %v, want nil", err)
+	}
+	if err := AuthorizeUser(ctx, "bob"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("AuthorizeUser(bob) error = %v, want %v", err, ErrForbidden)
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authn
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/a2aproject/a2a-go/v2/a2a"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+
+	"google.golang.org/adk/v2/agent"
+)
+
+const (
+	// MethodJWT is the [agent.Principal] Method of principals authenticated with a bearer JWT.
+	MethodJWT = "jwt"
+
+	defaultUserIDClaim = "sub"
+	defaultJWTLeeway   = time.Minute
+)
+
+var defaultJWTAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// JWTConfig allows to configure an authenticator created with [NewJWTAuthenticator].
+type JWTConfig struct {
+	// JWKS is a JSON Web Key Set document with the keys trusted for token verification,
+	// for example a local copy of the keys published by an OIDC provider.
+	JWKS []byte
+	// Issuer is the required "iss" claim value.
+	Issuer string
+	// Audience is the value required to be present in the "aud" claim.
+	Audience string
+	// UserIDClaim is the name of a string claim holding the user ID. Defaults to "sub".
+	UserIDClaim string
+	// Algorithms restricts the accepted signature algorithms. Defaults to asymmetric RSA, ECDSA and EdDSA algorithms.
+	Algorithms []jose.SignatureAlgorithm
+	// Leeway is the clock skew tolerated when validating time-based claims. Defaults to 1 minute.
+	Leeway time.Duration
+	// OpenIDConfigurationURL is the OIDC discovery document URL of the token issuer.
+	// When set, A2A agent cards declare an OpenID Connect security scheme instead of a generic bearer scheme.
+	OpenIDConfigurationURL string
+}
+
+type jwtAuthenticator struct {
+	keys        jose.JSONWebKeySet
+	issuer      string
+	audience    string
+	userIDClaim string
+	algorithms  []jose.SignatureAlgorithm
+	leeway      time.Duration
+	oidcURL     string
+	now         func() time.Time
+}
+
+// NewJWTAuthenticator creates an [Authenticator] which accepts "Authorization: Bearer" JWTs
+// signed with one of the keys from the configured JWKS. Tokens must not be expired and must
+// carry the configured issuer and audience.
+func NewJWTAuthenticator(config JWTConfig) (Authenticator, error) {
+	var keys jose.JSONWebKeySet
+	if err := json.Unmarshal(config.JWKS, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+	if len(keys.Keys) == 0 {
+		return nil, fmt.Errorf("JWKS contains no keys")
+	}
+	if config.Issuer == "" || config.Audience == "" {
+		return nil, fmt.Errorf("issuer and audience are required")
+	}
+	algorithms := config.Algorithms
+	if len(algorithms) == 0 {
+		algorithms = defaultJWTAlgorithms
+	}
+	leeway := config.Leeway
+	if leeway <= 0 {
+		leeway = defaultJWTLeeway
+	}
+	return &jwtAuthenticator{
+		keys:        keys,
+		issuer:      config.Issuer,
+		audience:    config.Audience,
+		userIDClaim: cmp.Or(config.UserIDClaim, defaultUserIDClaim),
+		algorithms:  algorithms,
+		leeway:      leeway,
+		oidcURL:     config.OpenIDConfigurationURL,
+		now:         time.Now,
+	}, nil
+}
+
+// Authenticate implements Authenticator.
+func (a *jwtAuthenticator) Authenticate(req *http.Request) (*agent.Principal, error) {
+	scheme, raw, ok := strings.Cut(req.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, ErrNoCredentials
+	}
+	token, err := jwt.ParseSigned(strings.TrimSpace(raw), a.algorithms)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed token: %w", ErrInvalidCredentials, err)
+	}
+
+	var claims jwt.Claims
+	var allClaims map[string]any
+	if err := a.verify(token, &claims, &allClaims); err != nil {
+		return nil, err
+	}
+	if claims.Expiry == nil {
+		return nil, fmt.Errorf("%w: token has no expiry", ErrInvalidCredentials)
+	}
+	expected := jwt.Expected{Issuer: a.issuer, AnyAudience: jwt.Audience{a.audience}, Time: a.now()}
+	if err := claims.ValidateWithLeeway(expected, a.leeway); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+
+	userID, _ := allClaims[a.userIDClaim].(string)
+	if userID == "" {
+		return nil, fmt.Errorf("%w: token has no %q claim", ErrInvalidCredentials, a.userIDClaim)
+	}
+	return &agent.Principal{UserID: userID, Subject: claims.Subject, Method: MethodJWT, Claims: allClaims}, nil
+}
+
+// verify checks the token signature with keys matching its key ID and decodes claims into dest.
+func (a *jwtAuthenticator) verify(token *jwt.JSONWebToken, dest ...any) error {
+	keys := a.keys.Keys
+	if len(token.Headers) > 0 && token.Headers[0].KeyID != "" {
+		keys = a.keys.Key(token.Headers[0].KeyID)
+	}
+	for _, key := range keys {
+		if err := token.Claims(key.Public(), dest...); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: token signature verification failed", ErrInvalidCredentials)
+}
+
+// SecuritySchemes describes the accepted credentials in A2A agent cards.
+func (a *jwtAuthenticator) SecuritySchemes() (a2a.NamedSecuritySchemes, a2a.SecurityRequirementsOptions) {
+	var scheme a2a.SecurityScheme = a2a.HTTPAuthSecurityScheme{Scheme: "Bearer", BearerFormat: "JWT"}
+	if a.oidcURL != "" {
+		scheme = a2a.OpenIDConnectSecurityScheme{OpenIDConnectURL: a.oidcURL}
+	}
+	return a2a.NamedSecuritySchemes{MethodJWT: scheme}, a2a.SecurityRequirementsOptions{
+		{MethodJWT: a2a.SecuritySchemeScopes{}},
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authn
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/v2/a2a"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+var testNow = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+type testSigningKey struct {
+	kid string
+	alg jose.SignatureAlgorithm
+	key any
+}
+
+func newTestSigningKeys(t *testing.T) (ec, ed testSigningKey, jwks []byte) {
+	t.Helper()
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() error = %v", err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() error = %v", err)
+	}
+	ec = testSigningKey{kid: "ec", alg: jose.ES256, key: ecKey}
+	ed = testSigningKey{kid: "ed", alg: jose.EdDSA, key: edKey}
+	jwks, err = json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{KeyID: ec.kid, Algorithm: string(ec.alg), Key: ecKey.Public()},
+		{KeyID: ed.kid, Algorithm: string(ed.alg), Key: edKey.Public()},
+	}})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	return ec, ed, jwks
+}
+
+func signTestToken(t *testing.T, key testSigningKey, claims ...any) string {
+	t.Helper()
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: key.alg, Key: key.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader(jose.HeaderKey("kid"), key.kid),
+	)
+	if err != nil {
+		t.Fatalf("jose.NewSigner() error = %v", err)
+	}
+	builder := jwt.Signed(signer)
+	for _, c := range claims {
+		builder = builder.Claims(c)
+	}
+	token, err := builder.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize() error = %v", err)
+	}
+	return token
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	ecKey, edKey, jwks := newTestSigningKeys(t)
+	_, otherKey, _ := newTestSigningKeys(t)
+
+	validClaims := jwt.Claims{
+		Issuer:   "https://issuer.example.com",
+		Subject:  "user-123",
+		Audience: jwt.Audience{"adk"},
+		Expiry:   jwt.NewNumericDate(testNow.Add(time.Hour)),
+		IssuedAt: jwt.NewNumericDate(testNow),
+	}
+	withClaims := func(modify func(c *jwt.Claims)) jwt.Claims {
+		c := validClaims
+		modify(&c)
+		return c
+	}
+
+	testCases := []struct {
+		name         string
+		userIDClaim  string
+		header       string
+		wantUserID   string
+		wantClaimKey string
+		wantErr      error
+	}{
+		{
+			name:       "ecdsa",
+			header:     "Bearer " + signTestToken(t, ecKey, validClaims),
+			wantUserID: "user-123",
+		},
+		{
+			name:       "ed25519",
+			header:     "bearer " + signTestToken(t, edKey, validClaims),
+			wantUserID: "user-123",
+		},
+		{
+			name:         "custom user ID claim",
+			userIDClaim:  "email",
+			header:       "Bearer " + signTestToken(t, ecKey, validClaims, map[string]any{"email": "user@example.com"}),
+			wantUserID:   "user@example.com",
+			wantClaimKey: "email",
+		},
+		{
+			name:    "no header",
+			wantErr: ErrNoCredentials,
+		},
+		{
+			name:    "other scheme",
+			header:  "Basic dXNlcjpwYXNz",
+			wantErr: ErrNoCredentials,
+		},
+		{
+			name:    "malformed",
+			header:  "Bearer not-a-token",
+			wantErr: ErrInvalidCredentials,
+		},
+		{
+			name:    "unknown key",
+			header:  "Bearer " + signTestToken(t, otherKey, validClaims),
+			wantErr: ErrInvalidCredentials,
+		},
+		{
+			name:    "expired",
+			header:  "Bearer " + signTestToken(t, ecKey, withClaims(func(c *jwt.Claims) { c.Expiry = jwt.NewNumericDate(testNow.Add(-time.Hour)) })),
+			wantErr: ErrInvalidCredentials,
+		},
+		{
+			name:    "no expiry",
+			header:  "Bearer " + signTestToken(t, ecKey, withClaims(func(c *jwt.Claims) { c.Expiry = nil })),
+			wantErr: ErrInvalidCredentials,
+		},
+		{
+			name:    "wrong issuer",
+			header:  "Bearer " + signTestToken(t, ecKey, withClaims(func(c *jwt.Claims) { c.Issuer = "https://evil.example.com" })),
+			wantErr: ErrInvalidCredentials,
+		},
+		{
+			name:    "wrong audience",
+			header:  "Bearer " + signTestToken(t, ecKey, withClaims(func(c *jwt.Claims) { c.Audience = jwt.Audience{"other"} })),
+			wantErr: ErrInvalidCredentials,
+		},
+		{
+			name:        "missing user ID claim",
+			userIDClaim: "email",
+			header:      "Bearer " + signTestToken(t, ecKey, validClaims),
+			wantErr:     ErrInvalidCredentials,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			authenticator, err := NewJWTAuthenticator(JWTConfig{
+				JWKS:        jwks,
+				Issuer:      "https://issuer.example.com",
+				Audience:    "adk",
+				UserIDClaim: tc.userIDClaim,
+			})
+			if err != nil {
+				t.Fatalf("NewJWTAuthenticator() error = %v", err)
+			}
+			authenticator.(*jwtAuthenticator).now = func() time.Time { return testNow }
+
+			req := httptest.NewRequest(http.MethodPost, "/run", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			principal, err := authenticator.Authenticate(req)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("Authenticate() error = %v, want %v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if principal.UserID != tc.wantUserID || principal.Subject != "user-123" || principal.Method != MethodJWT {
+				t.Errorf("Authenticate() = %+v, want user ID %q, subject %q, method %q", principal, tc.wantUserID, "user-123", MethodJWT)
+			}
+			if tc.wantClaimKey != "" {
+				if _, ok := principal.Claims[tc.wantClaimKey]; !ok {
+					t.Errorf("Authenticate() claims = %v, want key %q", principal.Claims, tc.wantClaimKey)
+				}
+			}
+		})
+	}
+}
+
+func TestNewJWTAuthenticator_Errors(t *testing.T) {
+	_, _, jwks := newTestSigningKeys(t)
+	testCases := []struct {
+		name   string
+		config JWTConfig
+	}{
+		{name: "invalid JWKS", config: JWTConfig{JWKS: []byte("{"), Issuer: "iss", Audience: "aud"}},
+		{name: "empty JWKS", config: JWTConfig{JWKS: []byte(`{"keys":[]}`), Issuer: "iss", Audience: "aud"}},
+		{name: "no issuer", config: JWTConfig{JWKS: jwks, Audience: "aud"}},
+		{name: "no audience", config: JWTConfig{JWKS: jwks, Issuer: "iss"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewJWTAuthenticator(tc.config); err == nil {
+				t.Error("NewJWTAuthenticator() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestJWTAuthenticator_SecuritySchemes(t *testing.T) {
+	_, _, jwks := newTestSigningKeys(t)
+	authenticator, err := NewJWTAuthenticator(JWTConfig{
+		JWKS:                   jwks,
+		Issuer:                 "iss",
+		Audience:               "aud",
+		OpenIDConfigurationURL: "https://issuer.example.com/.well-known/openid-configuration",
+	})
+	if err != nil {
+		t.Fatalf("NewJWTAuthenticator() error = %v", err)
+	}
+	schemes, requirements := authenticator.(*jwtAuthenticator).SecuritySchemes()
+	if _, ok := schemes[MethodJWT].(a2a.OpenIDConnectSecurityScheme); !ok {
+		t.Errorf("SecuritySchemes() = %v, want OpenID Connect scheme", schemes)
+	}
+	if len(requirements) != 1 {
+		t.Errorf("SecuritySchemes() requirements = %v, want 1", requirements)
+	}
+}
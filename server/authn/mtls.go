@@ -0,0 +1,77 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authn
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/http"
+
+	"github.com/a2aproject/a2a-go/v2/a2a"
+
+	"google.golang.org/adk/v2/agent"
+)
+
+// MethodMTLS is the [agent.Principal] Method of principals authenticated with a TLS client certificate.
+const MethodMTLS = "mtls"
+
+// MTLSConfig allows to configure an authenticator created with [NewMTLSAuthenticator].
+type MTLSConfig struct {
+	// UserID maps a verified client certificate to a user ID. Defaults to the certificate subject common name.
+	UserID func(cert *x509.Certificate) (string, error)
+}
+
+type mtlsAuthenticator struct {
+	userID func(cert *x509.Certificate) (string, error)
+}
+
+// NewMTLSAuthenticator creates an [Authenticator] which identifies clients by certificates presented during
+// the TLS handshake. Only certificates verified by the server are accepted: the server [tls.Config] must set
+// ClientCAs and a ClientAuth mode which verifies presented certificates.
+func NewMTLSAuthenticator(config MTLSConfig) Authenticator {
+	userID := config.UserID
+	if userID == nil {
+		userID = func(cert *x509.Certificate) (string, error) {
+			if cert.Subject.CommonName == "" {
+				return "", fmt.Errorf("certificate subject has no common name")
+			}
+			return cert.Subject.CommonName, nil
+		}
+	}
+	return &mtlsAuthenticator{userID: userID}
+}
+
+// Authenticate implements Authenticator.
+func (a *mtlsAuthenticator) Authenticate(req *http.Request) (*agent.Principal, error) {
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return nil, ErrNoCredentials
+	}
+	if len(req.TLS.VerifiedChains) == 0 {
+		return nil, fmt.Errorf("%w: client certificate was not verified", ErrInvalidCredentials)
+	}
+	cert := req.TLS.VerifiedChains[0][0]
+	userID, err := a.userID(cert)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+	return &agent.Principal{UserID: userID, Subject: cert.Subject.String(), Method: MethodMTLS}, nil
+}
+
+// SecuritySchemes describes the accepted credentials in A2A agent cards.
+func (a *mtlsAuthenticator) SecuritySchemes() (a2a.NamedSecuritySchemes, a2a.SecurityRequirementsOptions) {
+	return a2a.NamedSecuritySchemes{MethodMTLS: a2a.MutualTLSSecurityScheme{}}, a2a.SecurityRequirementsOptions{
+		{MethodMTLS: a2a.SecuritySchemeScopes{}},
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/v2/agent"
+)
+
+func newTestCertificate(t *testing.T, cn string, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate() error = %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("x509.ParseCertificate() error = %v", err)
+	}
+	return cert, key
+}
+
+func TestMTLSAuthenticator(t *testing.T) {
+	ca, caKey := newTestCertificate(t, "test-ca", true, nil, nil)
+	clientCert, clientKey := newTestCertificate(t, "alice", false, ca, caKey)
+
+	server := httptest.NewUnstartedServer(Middleware(MiddlewareConfig{
+		Authenticator: NewMTLSAuthenticator(MTLSConfig{}),
+	})(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		principal, _ := agent.PrincipalFromContext(req.Context())
+		_, _ = io.WriteString(w, principal.UserID+" "+principal.Method)
+	})))
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	server.TLS = &tls.Config{ClientCAs: pool, ClientAuth: tls.VerifyClientCertIfGiven}
+	server.StartTLS()
+	defer server.Close()
+
+	testCases := []struct {
+		name       string
+		clientCert *tls.Certificate
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "client certificate",
+			clientCert: &tls.Certificate{Certificate: [][]byte{clientCert.Raw}, PrivateKey: clientKey, Leaf: clientCert},
+			wantStatus: http.StatusOK,
+			wantBody:   "alice mtls",
+		},
+		{
+			name:       "no client certificate",
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transport := server.Client().Transport.(*http.Transport).Clone()
+			if tc.clientCert != nil {
+				transport.TLSClientConfig.Certificates = []tls.Certificate{*tc.clientCert}
+			}
+			client := &http.Client{Transport: transport}
+
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("client.Get() error = %v", err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tc.wantStatus)
+			}
+			if tc.wantBody != "" && string(body) != tc.wantBody {
+				t.Errorf("body = %q, want %q", body, tc.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/utils"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/session"
+)
+
+// AgentInput is the input of the MCP tool of an agent.
+type AgentInput struct {
+	// Message is the user message sent to the agent.
+	Message string `json:"message" jsonschema:"the message to send to the agent"`
+	// SessionID is the ID of the session to continue. By default, the
+	// calls of an MCP client session continue the same session.
+	SessionID string `json:"session_id,omitempty" jsonschema:"the ID of the session to continue; by default the session of the previous call"`
+}
+
+// AgentOutput is the structured output of the MCP tool of an agent.
+type AgentOutput struct {
+	// SessionID is the ID of the session the call ran in.
+	SessionID string `json:"session_id"`
+	// Output is the final response of the agent.
+	Output string `json:"output"`
+}
+
+// addAgent adds the MCP tool of a.
+func (s *server) addAgent(srv *mcp.Server, a agent.Agent) error {
+	r, err := runner.New(runner.Config{
+		AppName:         a.Name(),
+		Agent:           a,
+		SessionService:  s.cfg.SessionService,
+		ArtifactService: s.cfg.ArtifactService,
+		MemoryService:   s.cfg.MemoryService,
+		PluginConfig:    s.cfg.PluginConfig,
+	})
+	if err != nil {
+		return fmt.Errorf("mcpserver: failed to create runner for agent %q: %w", a.Name(), err)
+	}
+	inputSchema, err := jsonschema.For[AgentInput](nil)
+	if err != nil {
+		return fmt.Errorf("mcpserver: %w", err)
+	}
+	outputSchema, err := jsonschema.For[AgentOutput](nil)
+	if err != nil {
+		return fmt.Errorf("mcpserver: %w", err)
+	}
+	description := a.Description()
+	if description == "" {
+		description = fmt.Sprintf("Sends a message to the %s agent.", a.Name())
+	}
+	srv.AddTool(&mcp.Tool{
+		Name:         a.Name(),
+		Description:  description,
+		InputSchema:  inputSchema,
+		OutputSchema: outputSchema,
+	}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var in AgentInput
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &in); err != nil {
+				return errorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+		if in.Message == "" {
+			return errorResult(fmt.Errorf("missing argument message")), nil
+		}
+		return s.runAgent(ctx, r, a.Name(), req, in), nil
+	})
+	return nil
+}
+
+// runAgent runs the agent of app for a call of its tool.
+func (s *server) runAgent(ctx context.Context, r *runner.Runner, app string, req *mcp.CallToolRequest, in AgentInput) *mcp.CallToolResult {
+	userID := s.userID(ctx, req.Extra)
+	sess, err := s.session(ctx, app, userID, in.SessionID, req.Session)
+	if err != nil {
+		return errorResult(err)
+	}
+
+	progressToken := req.Params.GetProgressToken()
+	cfg := agent.RunConfig{StreamingMode: agent.StreamingModeNone}
+	if progressToken != nil {
+		cfg.StreamingMode = agent.StreamingModeSSE
+	}
+	var (
+		output    string
+		result    any
+		artifacts = map[string]int64{}
+		progress  float64
+	)
+	for ev, err := range r.Run(ctx, userID, sess.ID(), genai.NewContentFromText(in.Message, genai.RoleUser), cfg) {
+		if err != nil {
+			return errorResult(err)
+		}
+		maps.Copy(artifacts, ev.Actions.ArtifactDelta)
+		if ev.Output != nil {
+			result = ev.Output
+		}
+		text := eventText(ev)
+		if ev.Partial || !ev.IsFinalResponse() {
+			if msg := progressMessage(ev, text); progressToken != nil && msg != "" {
+				progress++
+				if err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+					ProgressToken: progressToken,
+					Progress:      progress,
+					Message:       msg,
+				}); err != nil {
+					log.Printf("mcpserver: failed to notify progress: %v", err)
+				}
+			}
+			continue
+		}
+		if text != "" {
+			output = text
+		}
+	}
+	if output == "" && result != nil {
+		output = renderOutput(result)
+	}
+
+	res := &mcp.CallToolResult{
+		Content:           []mcp.Content{&mcp.TextContent{Text: output}},
+		StructuredContent: AgentOutput{SessionID: sess.ID(), Output: output},
+	}
+	for _, name := range slices.Sorted(maps.Keys(artifacts)) {
+		res.Content = append(res.Content, &mcp.ResourceLink{
+			URI:  artifactURI(app, sess.ID(), name),
+			Name: name,
+		})
+	}
+	return res
+}
+
+// eventText returns the text of the content of ev, leaving out thoughts.
+func eventText(ev *session.Event) string {
+	if ev.Content == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, p := range ev.Content.Parts {
+		if p != nil && !p.Thought {
+			b.WriteString(p.Text)
+		}
+	}
+	return b.String()
+}
+
+// progressMessage returns the message of the progress notification of
+// ev, an event preceding the final response: its text, or the tools it
+// calls.
+func progressMessage(ev *session.Event, text string) string {
+	if text != "" {
+		return text
+	}
+	var calls []string
+	for _, fc := range utils.FunctionCalls(ev.Content) {
+		calls = append(calls, fc.Name)
+	}
+	if len(calls) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s is calling %s", ev.Author, strings.Join(calls, ", "))
+}
+
+// renderOutput formats the Output of a node: strings as is, anything
+// else as JSON.
+func renderOutput(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	if b, err := json.Marshal(v); err == nil {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
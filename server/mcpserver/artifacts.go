@@ -0,0 +1,88 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcpserver
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/v2/artifact"
+)
+
+// artifactScheme is the URI scheme of the MCP resources of artifacts,
+// "adk-artifact://<app>/<session ID>/<artifact name>".
+const artifactScheme = "adk-artifact"
+
+func artifactURI(app, sessionID, name string) string {
+	return artifactScheme + "://" + url.PathEscape(app) + "/" + url.PathEscape(sessionID) + "/" + url.PathEscape(name)
+}
+
+// parseArtifactURI returns the app, session ID and name of the artifact
+// of uri.
+func parseArtifactURI(uri string) (app, sessionID, name string, ok bool) {
+	rest, ok := strings.CutPrefix(uri, artifactScheme+"://")
+	if !ok {
+		return "", "", "", false
+	}
+	segments := strings.Split(rest, "/")
+	if len(segments) != 3 {
+		return "", "", "", false
+	}
+	for i, s := range segments {
+		unescaped, err := url.PathUnescape(s)
+		if err != nil || unescaped == "" {
+			return "", "", "", false
+		}
+		segments[i] = unescaped
+	}
+	return segments[0], segments[1], segments[2], true
+}
+
+// addArtifacts exposes the artifacts of the sessions of the caller as
+// MCP resources, at the latest version.
+func (s *server) addArtifacts(srv *mcp.Server) {
+	srv.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "artifact",
+		Description: "An artifact of an ADK session.",
+		URITemplate: artifactScheme + "://{app}/{session_id}/{name}",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		uri := req.Params.URI
+		app, sessionID, name, ok := parseArtifactURI(uri)
+		if !ok {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+		resp, err := s.cfg.ArtifactService.Load(ctx, &artifact.LoadRequest{
+			AppName:   app,
+			UserID:    s.userID(ctx, req.Extra),
+			SessionID: sessionID,
+			FileName:  name,
+		})
+		if err != nil || resp.Part == nil {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+		contents := &mcp.ResourceContents{URI: uri}
+		if blob := resp.Part.InlineData; blob != nil {
+			contents.MIMEType = blob.MIMEType
+			contents.Blob = blob.Data
+		} else {
+			contents.MIMEType = "text/plain"
+			contents.Text = resp.Part.Text
+		}
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{contents}}, nil
+	})
+}
@@ -0,0 +1,255 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mcpserver serves ADK agents and tools over the Model Context
+// Protocol (MCP), to MCP clients such as IDEs and desktop assistants.
+//
+// Every agent of the loader becomes an MCP tool taking a message and,
+// optionally, the ID of the session to continue. Calls run the agent
+// with a runner.Runner; without a session ID, the calls of an MCP
+// client session continue the same ADK session. Clients asking for
+// progress receive the partial responses of the agent as progress
+// notifications. Artifacts are exposed as MCP resources, and the
+// results of agent calls link to the artifacts they saved.
+//
+// Tools listed in Config.Tools are served as MCP tools of their own,
+// with the schemas of their declarations.
+//
+// New returns the MCP server, to run on any MCP transport, e.g.
+// mcp.StdioTransport, and NewHTTPHandler serves it over streamable HTTP.
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/artifact"
+	"google.golang.org/adk/v2/internal/version"
+	"google.golang.org/adk/v2/memory"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
+)
+
+// DefaultUserID is the default Config.DefaultUserID.
+const DefaultUserID = "mcp_user"
+
+// ToolsAppName is the app name of the sessions in which the tools of
+// Config.Tools run.
+const ToolsAppName = "mcp_tools"
+
+// Config contains parameters for the MCP server.
+type Config struct {
+	// Name is the name of the server implementation advertised to
+	// clients. Defaults to "adk".
+	Name string
+	// Version is the version advertised to clients. Defaults to the ADK
+	// version.
+	Version string
+
+	AgentLoader     agent.Loader
+	SessionService  session.Service
+	ArtifactService artifact.Service
+	MemoryService   memory.Service
+	PluginConfig    runner.PluginConfig
+
+	// Tools are served as MCP tools of their own, with the schemas of
+	// their declarations. They must be function tools, e.g. created
+	// with functiontool.New. They run in sessions of ToolsAppName.
+	Tools []tool.Tool
+
+	// DefaultUserID is the ADK user of requests without an authenticated
+	// principal (see agent.PrincipalFromContext) or token info carrying
+	// a user ID. Defaults to DefaultUserID.
+	DefaultUserID string
+}
+
+// New returns an MCP server serving the agents of cfg.AgentLoader and
+// cfg.Tools. The session service defaults to an in-memory one.
+func New(cfg Config) (*mcp.Server, error) {
+	if cfg.AgentLoader == nil && len(cfg.Tools) == 0 {
+		return nil, fmt.Errorf("mcpserver: Config.AgentLoader or Config.Tools must be set")
+	}
+	if cfg.Name == "" {
+		cfg.Name = "adk"
+	}
+	if cfg.Version == "" {
+		cfg.Version = version.Version
+	}
+	if cfg.SessionService == nil {
+		cfg.SessionService = session.InMemoryService()
+	}
+	if cfg.DefaultUserID == "" {
+		cfg.DefaultUserID = DefaultUserID
+	}
+
+	s := &server{cfg: cfg, sessions: map[sessionKey]string{}}
+	srv := mcp.NewServer(&mcp.Implementation{Name: cfg.Name, Version: cfg.Version}, nil)
+	if cfg.AgentLoader != nil {
+		for _, name := range cfg.AgentLoader.ListAgents() {
+			a, err := cfg.AgentLoader.LoadAgent(name)
+			if err != nil {
+				return nil, fmt.Errorf("mcpserver: failed to load agent %q: %w", name, err)
+			}
+			if err := s.addAgent(srv, a); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, t := range cfg.Tools {
+		if err := s.addTool(srv, t); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.ArtifactService != nil {
+		s.addArtifacts(srv)
+	}
+	return srv, nil
+}
+
+// NewHTTPHandler returns a handler serving the MCP server of cfg, as
+// returned by New, over streamable HTTP; see HTTPHandler.
+func NewHTTPHandler(cfg Config) (http.Handler, error) {
+	srv, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return HTTPHandler(srv), nil
+}
+
+// HTTPHandler returns a handler serving srv, as returned by New, over
+// streamable HTTP.
+//
+// The calls of an MCP client session run as the principal of the
+// request which initialized it, when the handler is mounted behind
+// authentication middleware such as authn.Middleware, so every MCP
+// client session is bound to that principal: requests of other
+// principals for it are rejected.
+func HTTPHandler(srv *mcp.Server) http.Handler {
+	return &principalSessions{
+		next:   mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return srv }, nil),
+		owners: map[string]string{},
+	}
+}
+
+// principalSessions binds MCP client sessions to the principal which
+// initialized them.
+type principalSessions struct {
+	next http.Handler
+
+	mu sync.Mutex
+	// owners maps the IDs of MCP client sessions to the user ID of
+	// their principal, empty without one.
+	owners map[string]string
+}
+
+func (h *principalSessions) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var user string
+	if p, ok := agent.PrincipalFromContext(r.Context()); ok {
+		user = p.UserID
+	}
+	id := r.Header.Get(mcpSessionIDHeader)
+	if id != "" {
+		h.mu.Lock()
+		owner, ok := h.owners[id]
+		h.mu.Unlock()
+		if ok && owner != user {
+			http.Error(w, "session user mismatch", http.StatusForbidden)
+			return
+		}
+	}
+	h.next.ServeHTTP(w, r)
+	if id == "" {
+		id = w.Header().Get(mcpSessionIDHeader)
+	}
+	if id == "" {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if r.Method == http.MethodDelete {
+		delete(h.owners, id)
+	} else if _, ok := h.owners[id]; !ok {
+		h.owners[id] = user
+	}
+}
+
+const mcpSessionIDHeader = "Mcp-Session-Id"
+
+// server holds the state shared by the handlers of an MCP server.
+type server struct {
+	cfg Config
+
+	mu sync.Mutex
+	// sessions maps MCP client sessions to the ADK sessions their
+	// calls continue by default.
+	sessions map[sessionKey]string
+}
+
+type sessionKey struct {
+	appName, userID, mcpSessionID string
+}
+
+// userID returns the ADK user of a request: the user of the
+// authenticated principal, if any, or else of the token info.
+func (s *server) userID(ctx context.Context, extra *mcp.RequestExtra) string {
+	if p, ok := agent.PrincipalFromContext(ctx); ok && p.UserID != "" {
+		return p.UserID
+	}
+	if extra != nil && extra.TokenInfo != nil && extra.TokenInfo.UserID != "" {
+		return extra.TokenInfo.UserID
+	}
+	return s.cfg.DefaultUserID
+}
+
+// session returns the ADK session of app a call continues: sessionID,
+// created if it doesn't exist, or else the session of the MCP client
+// session mcpSession, created on its first call.
+func (s *server) session(ctx context.Context, appName, userID, sessionID string, mcpSession *mcp.ServerSession) (session.Session, error) {
+	key := sessionKey{appName: appName, userID: userID}
+	if mcpSession != nil {
+		key.mcpSessionID = mcpSession.ID()
+	}
+	if sessionID == "" {
+		s.mu.Lock()
+		sessionID = s.sessions[key]
+		s.mu.Unlock()
+	}
+	if sessionID != "" {
+		resp, err := s.cfg.SessionService.Get(ctx, &session.GetRequest{AppName: appName, UserID: userID, SessionID: sessionID})
+		if err == nil {
+			return resp.Session, nil
+		}
+	}
+	resp, err := s.cfg.SessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID, SessionID: sessionID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	s.mu.Lock()
+	if _, ok := s.sessions[key]; !ok {
+		s.sessions[key] = resp.Session.ID()
+	}
+	s.mu.Unlock()
+	return resp.Session, nil
+}
+
+// errorResult returns the result of a tool call which failed with err.
+func errorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}
+}
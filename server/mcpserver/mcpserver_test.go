@@ -0,0 +1,332 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcpserver_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/agent/llmagent"
+	"google.golang.org/adk/v2/artifact"
+	"google.golang.org/adk/v2/internal/testutil"
+	"google.golang.org/adk/v2/server/mcpserver"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/functiontool"
+)
+
+type chartArgs struct {
+	Title string `json:"title"`
+}
+
+func saveChart(ctx agent.Context, args chartArgs) (map[string]any, error) {
+	if _, err := ctx.Artifacts().Save(ctx, "chart.png", genai.NewPartFromBytes([]byte("png"), "image/png")); err != nil {
+		return nil, err
+	}
+	return map[string]any{"saved": args.Title}, nil
+}
+
+type addArgs struct {
+	A int `json:"a"`
+	B int `json:"b"`
+}
+
+type addResult struct {
+	Sum int `json:"sum"`
+}
+
+func add(ctx agent.Context, args addArgs) (addResult, error) {
+	if err := ctx.State().Set("last_sum", args.A+args.B); err != nil {
+		return addResult{}, err
+	}
+	return addResult{Sum: args.A + args.B}, nil
+}
+
+// connect serves cfg on an in-memory transport and returns a client
+// session, recording the messages of the progress notifications it
+// receives in progress.
+func connect(t *testing.T, cfg mcpserver.Config, progress *[]string) *mcp.ClientSession {
+	t.Helper()
+	server, err := mcpserver.New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatalf("server.Connect: %v", err)
+	}
+	var mu sync.Mutex
+	client := mcp.NewClient(&mcp.Implementation{Name: "client"}, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, req *mcp.ProgressNotificationClientRequest) {
+			mu.Lock()
+			defer mu.Unlock()
+			*progress = append(*progress, req.Params.Message)
+		},
+	})
+	cs, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client.Connect: %v", err)
+	}
+	t.Cleanup(func() { cs.Close() })
+	return cs
+}
+
+func TestServer(t *testing.T) {
+	chartTool, err := functiontool.New(functiontool.Config{Name: "save_chart", Description: "saves a chart"}, saveChart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addTool, err := functiontool.New(functiontool.Config{Name: "add", Description: "adds two numbers"}, add)
+	if err != nil {
+		t.Fatal(err)
+	}
+	llm := &testutil.MockModel{Responses: []*genai.Content{
+		{Role: genai.RoleModel, Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{Name: "save_chart", Args: map[string]any{"title": "sales"}}}}},
+		genai.NewContentFromText("Here is the chart.", genai.RoleModel),
+		genai.NewContentFromText("Anything else?", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{Name: "charts", Description: "Draws charts.", Model: llm, Tools: []tool.Tool{chartTool}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := session.InMemoryService()
+	var progress []string
+	cs := connect(t, mcpserver.Config{
+		AgentLoader:     agent.NewSingleLoader(a),
+		SessionService:  sessions,
+		ArtifactService: artifact.InMemoryService(),
+		Tools:           []tool.Tool{addTool},
+	}, &progress)
+
+	tools, err := cs.ListTools(t.Context(), nil)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	var names []string
+	for _, tl := range tools.Tools {
+		names = append(names, tl.Name)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"add", "charts"}) {
+		t.Errorf("ListTools() = %v, want [add charts]", names)
+	}
+
+	t.Run("agent", func(t *testing.T) {
+		params := &mcp.CallToolParams{Name: "charts", Arguments: map[string]any{"message": "chart the sales"}}
+		params.SetProgressToken("p1")
+		res, err := cs.CallTool(t.Context(), params)
+		if err != nil {
+			t.Fatalf("CallTool: %v", err)
+		}
+		if res.IsError {
+			t.Fatalf("CallTool() = %+v, want success", res.Content[0])
+		}
+		if text := res.Content[0].(*mcp.TextContent).Text; text != "Here is the chart." {
+			t.Errorf("CallTool() text = %q, want the final response", text)
+		}
+		out, _ := res.StructuredContent.(map[string]any)
+		sessionID, _ := out["session_id"].(string)
+		if sessionID == "" {
+			t.Fatalf("CallTool() structured content = %v, want the session ID", res.StructuredContent)
+		}
+		if !slices.Contains(progress, "charts is calling save_chart") {
+			t.Errorf("progress notifications = %q, want the tool call", progress)
+		}
+
+		if len(res.Content) != 2 {
+			t.Fatalf("CallTool() content = %v, want the text and the artifact link", res.Content)
+		}
+		link, ok := res.Content[1].(*mcp.ResourceLink)
+		if !ok || link.Name != "chart.png" {
+			t.Fatalf("CallTool() content[1] = %#v, want a link to chart.png", res.Content[1])
+		}
+		read, err := cs.ReadResource(t.Context(), &mcp.ReadResourceParams{URI: link.URI})
+		if err != nil {
+			t.Fatalf("ReadResource(%q): %v", link.URI, err)
+		}
+		if c := read.Contents[0]; string(c.Blob) != "png" || c.MIMEType != "image/png" {
+			t.Errorf("ReadResource() = %+v, want the artifact", c)
+		}
+
+		// Calls of the same client session continue its session.
+		res, err = cs.CallTool(t.Context(), &mcp.CallToolParams{Name: "charts", Arguments: map[string]any{"message": "thanks"}})
+		if err != nil {
+			t.Fatalf("CallTool: %v", err)
+		}
+		if out, _ := res.StructuredContent.(map[string]any); out["session_id"] != sessionID {
+			t.Errorf("second CallTool() session = %v, want %q", out["session_id"], sessionID)
+		}
+		last := llm.Requests[len(llm.Requests)-1]
+		if len(last.Contents) < 4 {
+			t.Errorf("second call sent %d contents to the model, want the history of the session", len(last.Contents))
+		}
+	})
+
+	t.Run("tool", func(t *testing.T) {
+		res, err := cs.CallTool(t.Context(), &mcp.CallToolParams{Name: "add", Arguments: map[string]any{"a": 1, "b": 2}})
+		if err != nil {
+			t.Fatalf("CallTool: %v", err)
+		}
+		if res.IsError {
+			t.Fatalf("CallTool() = %+v, want success", res.Content[0])
+		}
+		if out, _ := res.StructuredContent.(map[string]any); out["sum"] != float64(3) {
+			t.Errorf("CallTool() structured content = %v, want sum 3", res.StructuredContent)
+		}
+		list, err := sessions.List(t.Context(), &session.ListRequest{AppName: mcpserver.ToolsAppName, UserID: mcpserver.DefaultUserID})
+		if err != nil || len(list.Sessions) != 1 {
+			t.Fatalf("List() = %v, %v, want the session of the tools", list, err)
+		}
+		got, err := sessions.Get(t.Context(), &session.GetRequest{AppName: mcpserver.ToolsAppName, UserID: mcpserver.DefaultUserID, SessionID: list.Sessions[0].ID()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v, err := got.Session.State().Get("last_sum"); err != nil || v != 3 {
+			t.Errorf("state last_sum = %v, %v, want 3", v, err)
+		}
+	})
+
+	t.Run("missing message", func(t *testing.T) {
+		res, err := cs.CallTool(t.Context(), &mcp.CallToolParams{Name: "charts", Arguments: map[string]any{}})
+		if err != nil {
+			t.Fatalf("CallTool: %v", err)
+		}
+		if !res.IsError {
+			t.Errorf("CallTool() without a message succeeded, want an error result")
+		}
+	})
+}
+
+func TestNew_NothingToServe(t *testing.T) {
+	if _, err := mcpserver.New(mcpserver.Config{}); err == nil {
+		t.Error("New() without agents and tools succeeded, want error")
+	}
+}
+
+// headerTransport sets the principal header of the requests it sends.
+type headerTransport struct{ user string }
+
+func (t headerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("X-User", t.user)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestHTTPHandler_Principals(t *testing.T) {
+	addTool, err := functiontool.New(functiontool.Config{Name: "add", Description: "adds two numbers"}, add)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := llmagent.New(llmagent.Config{Name: "echo", Model: &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromText("hi alice", genai.RoleModel),
+		genai.NewContentFromText("hi bob", genai.RoleModel),
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := session.InMemoryService()
+	handler, err := mcpserver.NewHTTPHandler(mcpserver.Config{
+		AgentLoader:    agent.NewSingleLoader(a),
+		SessionService: sessions,
+		Tools:          []tool.Tool{addTool},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Stands in for authentication middleware.
+	authenticate := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := r.Header.Get("X-User")
+		handler.ServeHTTP(w, r.WithContext(agent.NewContextWithPrincipal(r.Context(), &agent.Principal{UserID: user, Subject: user})))
+	})
+	srv := httptest.NewServer(authenticate)
+	t.Cleanup(srv.Close)
+
+	dial := func(user string) *mcp.ClientSession {
+		t.Helper()
+		client := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil)
+		cs, err := client.Connect(t.Context(), &mcp.StreamableClientTransport{
+			Endpoint:   srv.URL,
+			HTTPClient: &http.Client{Transport: headerTransport{user: user}},
+		}, nil)
+		if err != nil {
+			t.Fatalf("Connect(%s): %v", user, err)
+		}
+		t.Cleanup(func() { cs.Close() })
+		return cs
+	}
+	alice, bob := dial("alice"), dial("bob")
+
+	res, err := alice.CallTool(t.Context(), &mcp.CallToolParams{Name: "echo", Arguments: map[string]any{"message": "hello"}})
+	if err != nil || res.IsError {
+		t.Fatalf("CallTool(alice) = %+v, %v", res, err)
+	}
+	aliceSession, _ := res.StructuredContent.(map[string]any)["session_id"].(string)
+	if _, err := sessions.Get(t.Context(), &session.GetRequest{AppName: "echo", UserID: "alice", SessionID: aliceSession}); err != nil {
+		t.Fatalf("session of alice: %v", err)
+	}
+
+	// Bob can't continue the session of alice: the ID names a session of
+	// his own.
+	res, err = bob.CallTool(t.Context(), &mcp.CallToolParams{Name: "echo", Arguments: map[string]any{"message": "hello", "session_id": aliceSession}})
+	if err != nil || res.IsError {
+		t.Fatalf("CallTool(bob) = %+v, %v", res, err)
+	}
+	got, err := sessions.Get(t.Context(), &session.GetRequest{AppName: "echo", UserID: "alice", SessionID: aliceSession})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := got.Session.Events().Len(); n != 2 {
+		t.Errorf("session of alice has %d events, want the 2 of her call", n)
+	}
+	if _, err := sessions.Get(t.Context(), &session.GetRequest{AppName: "echo", UserID: "bob", SessionID: aliceSession}); err != nil {
+		t.Errorf("session of bob: %v", err)
+	}
+
+	for _, user := range []string{"alice", "bob"} {
+		list, err := sessions.List(t.Context(), &session.ListRequest{AppName: mcpserver.ToolsAppName, UserID: user})
+		if err != nil || len(list.Sessions) != 0 {
+			t.Fatalf("List(%s) = %v, %v, want no tool sessions yet", user, list, err)
+		}
+	}
+	if _, err := bob.CallTool(t.Context(), &mcp.CallToolParams{Name: "add", Arguments: map[string]any{"a": 1, "b": 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if list, err := sessions.List(t.Context(), &session.ListRequest{AppName: mcpserver.ToolsAppName, UserID: "bob"}); err != nil || len(list.Sessions) != 1 {
+		t.Errorf("List(bob) = %v, %v, want the session of the tools", list, err)
+	}
+
+	// The MCP session of alice can't be used by bob.
+	req, err := http.NewRequest(http.MethodDelete, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Mcp-Session-Id", alice.ID())
+	resp, err := (&http.Client{Transport: headerTransport{user: "bob"}}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("DELETE of the session of alice by bob = %d, want %d", resp.StatusCode, http.StatusForbidden)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	artifactinternal "google.golang.org/adk/v2/internal/artifact"
+	icontext "google.golang.org/adk/v2/internal/context"
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
+)
+
+// addTool adds the MCP tool re-exporting t.
+func (s *server) addTool(srv *mcp.Server, t tool.Tool) error {
+	ft, ok := t.(toolinternal.FunctionTool)
+	if !ok {
+		return fmt.Errorf("mcpserver: tool %q is not a function tool", t.Name())
+	}
+	decl := ft.Declaration()
+	if decl == nil {
+		return fmt.Errorf("mcpserver: tool %q has no declaration", t.Name())
+	}
+	inputSchema, err := declarationSchema(decl.ParametersJsonSchema, decl.Parameters)
+	if err != nil {
+		return fmt.Errorf("mcpserver: tool %q: %w", t.Name(), err)
+	}
+	if inputSchema == nil {
+		inputSchema = map[string]any{"type": "object"}
+	}
+	mcpTool := &mcp.Tool{Name: decl.Name, Description: decl.Description, InputSchema: inputSchema}
+	if mcpTool.Description == "" {
+		mcpTool.Description = t.Description()
+	}
+	srv.AddTool(mcpTool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := map[string]any{}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return errorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+		return s.runTool(ctx, ft, req, args), nil
+	})
+	return nil
+}
+
+// runTool runs t for a call of its MCP tool, in the session of
+// ToolsAppName of the caller. The state and artifacts the tool changes
+// are recorded in an event appended to the session.
+func (s *server) runTool(ctx context.Context, t toolinternal.FunctionTool, req *mcp.CallToolRequest, args map[string]any) *mcp.CallToolResult {
+	userID := s.userID(ctx, req.Extra)
+	sess, err := s.session(ctx, ToolsAppName, userID, "", req.Session)
+	if err != nil {
+		return errorResult(err)
+	}
+	params := icontext.InvocationContextParams{Session: sess}
+	if s.cfg.ArtifactService != nil {
+		params.Artifacts = &artifactinternal.Artifacts{
+			Service:   s.cfg.ArtifactService,
+			AppName:   ToolsAppName,
+			UserID:    userID,
+			SessionID: sess.ID(),
+		}
+	}
+	invCtx := icontext.NewInvocationContext(ctx, params)
+	actions := &session.EventActions{}
+	toolCtx := agent.NewToolContext(invCtx, "", actions, nil)
+
+	result, err := t.Run(toolCtx, args)
+	if len(actions.StateDelta) > 0 || len(actions.ArtifactDelta) > 0 {
+		ev := session.NewEvent(ctx, invCtx.InvocationID())
+		ev.Author = t.Name()
+		ev.Actions = *actions
+		if err := s.cfg.SessionService.AppendEvent(ctx, sess, ev); err != nil {
+			return errorResult(fmt.Errorf("failed to record the tool's changes: %w", err))
+		}
+	}
+	if err != nil {
+		return errorResult(err)
+	}
+
+	parts, _ := result[toolinternal.ResponsePartsKey].([]*genai.FunctionResponsePart)
+	delete(result, toolinternal.ResponsePartsKey)
+	text, err := json.Marshal(result)
+	if err != nil {
+		return errorResult(fmt.Errorf("failed to encode the result: %w", err))
+	}
+	res := &mcp.CallToolResult{
+		Content:           []mcp.Content{&mcp.TextContent{Text: string(text)}},
+		StructuredContent: result,
+	}
+	for _, p := range parts {
+		if p == nil || p.InlineData == nil {
+			continue
+		}
+		res.Content = append(res.Content, blobContent(p.InlineData.Data, p.InlineData.MIMEType))
+	}
+	return res
+}
+
+// blobContent returns the MCP content of binary data.
+func blobContent(data []byte, mimeType string) mcp.Content {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return &mcp.ImageContent{Data: data, MIMEType: mimeType}
+	case strings.HasPrefix(mimeType, "audio/"):
+		return &mcp.AudioContent{Data: data, MIMEType: mimeType}
+	default:
+		return &mcp.EmbeddedResource{Resource: &mcp.ResourceContents{URI: "data:" + mimeType, MIMEType: mimeType, Blob: data}}
+	}
+}
+
+// declarationSchema returns the JSON schema of a declaration: jsonSchema
+// if set, and otherwise schema converted to JSON schema.
+func declarationSchema(jsonSchema any, schema *genai.Schema) (any, error) {
+	if jsonSchema != nil {
+		return jsonSchema, nil
+	}
+	if schema == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("marshal schema: %w", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("unmarshal schema: %w", err)
+	}
+	lowercaseSchemaTypes(m)
+	return m, nil
+}
+
+// lowercaseSchemaTypes converts the types of a genai.Schema encoding,
+// e.g. "OBJECT", to JSON schema types.
+func lowercaseSchemaTypes(val any) {
+	switch v := val.(type) {
+	case map[string]any:
+		if t, ok := v["type"].(string); ok {
+			v["type"] = strings.ToLower(t)
+		}
+		for _, child := range v {
+			lowercaseSchemaTypes(child)
+		}
+	case []any:
+		for _, child := range v {
+			lowercaseSchemaTypes(child)
+		}
+	}
+}
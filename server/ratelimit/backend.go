@@ -0,0 +1,141 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Backend stores the state of a [Limiter]. Implementations must be safe for concurrent use.
+type Backend interface {
+	// TakeToken takes a token from the bucket identified by key, refilled at the given rate.
+	// If the bucket is empty it returns false and the time until the next token is available.
+	TakeToken(ctx context.Context, key string, rate Rate) (ok bool, retryAfter time.Duration, err error)
+	// ReturnToken puts back a token taken with TakeToken, e.g. because another limit rejected the request.
+	ReturnToken(ctx context.Context, key string, rate Rate) error
+	// AcquireSlot reserves one of limit slots identified by key. It returns false if all slots are taken.
+	AcquireSlot(ctx context.Context, key string, limit int) (bool, error)
+	// ReleaseSlot frees a slot reserved with AcquireSlot.
+	ReleaseSlot(ctx context.Context, key string) error
+}
+
+// sweepInterval is the number of TakeToken calls between removals of idle buckets.
+const sweepInterval = 1024
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	rate    Rate
+}
+
+// refill adds tokens accumulated since the last update.
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updated).Seconds()
+	b.tokens = min(float64(b.rate.burst()), b.tokens+elapsed*b.rate.Limit)
+	b.updated = now
+}
+
+type inMemoryBackend struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	slots   map[string]int
+	calls   int
+	now     func() time.Time
+}
+
+// NewInMemoryBackend returns a [Backend] which keeps limiter state in the process memory.
+// Limits are not shared between server replicas.
+func NewInMemoryBackend() Backend {
+	return &inMemoryBackend{
+		buckets: make(map[string]*bucket),
+		slots:   make(map[string]int),
+		now:     time.Now,
+	}
+}
+
+// TakeToken implements Backend.
+func (b *inMemoryBackend) TakeToken(ctx context.Context, key string, rate Rate) (bool, time.Duration, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.calls++
+	if b.calls%sweepInterval == 0 {
+		b.sweep(now)
+	}
+
+	bk, ok := b.buckets[key]
+	if !ok {
+		bk = &bucket{tokens: float64(rate.burst()), updated: now}
+		b.buckets[key] = bk
+	}
+	bk.rate = rate
+	bk.refill(now)
+	if bk.tokens >= 1 {
+		bk.tokens--
+		return true, 0, nil
+	}
+	retryAfter := time.Duration((1 - bk.tokens) / rate.Limit * float64(time.Second))
+	return false, retryAfter, nil
+}
+
+// ReturnToken implements Backend.
+func (b *inMemoryBackend) ReturnToken(ctx context.Context, key string, rate Rate) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	bk, ok := b.buckets[key]
+	if !ok {
+		// swept, so already full
+		return nil
+	}
+	bk.refill(b.now())
+	bk.tokens = min(float64(rate.burst()), bk.tokens+1)
+	return nil
+}
+
+// sweep removes buckets which have refilled completely, as they are equivalent to new ones.
+func (b *inMemoryBackend) sweep(now time.Time) {
+	for key, bk := range b.buckets {
+		bk.refill(now)
+		if bk.tokens >= float64(bk.rate.burst()) {
+			delete(b.buckets, key)
+		}
+	}
+}
+
+// AcquireSlot implements Backend.
+func (b *inMemoryBackend) AcquireSlot(ctx context.Context, key string, limit int) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.slots[key] >= limit {
+		return false, nil
+	}
+	b.slots[key]++
+	return true, nil
+}
+
+// ReleaseSlot implements Backend.
+func (b *inMemoryBackend) ReleaseSlot(ctx context.Context, key string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.slots[key] <= 1 {
+		delete(b.slots, key)
+		return nil
+	}
+	b.slots[key]--
+	return nil
+}
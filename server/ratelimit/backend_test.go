@@ -0,0 +1,117 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInMemoryBackend_TakeToken(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	backend := NewInMemoryBackend().(*inMemoryBackend)
+	backend.now = func() time.Time { return now }
+	rate := Rate{Limit: 2, Burst: 3}
+
+	take := func() (bool, time.Duration) {
+		t.Helper()
+		ok, retryAfter, err := backend.TakeToken(t.Context(), "k", rate)
+		if err != nil {
+			t.Fatalf("TakeToken() error = %v", err)
+		}
+		return ok, retryAfter
+	}
+
+	for i := range 3 {
+		if ok, _ := take(); !ok {
+			t.Fatalf("TakeToken() #%d = false, want burst of 3 allowed", i)
+		}
+	}
+	ok, retryAfter := take()
+	if ok {
+		t.Fatal("TakeToken() after burst = true, want false")
+	}
+	if retryAfter != 500*time.Millisecond {
+		t.Errorf("TakeToken() retryAfter = %v, want %v", retryAfter, 500*time.Millisecond)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := take(); !ok {
+		t.Error("TakeToken() after refill = false, want true")
+	}
+	if ok, _ := take(); ok {
+		t.Error("TakeToken() = true, want false as only one token was refilled")
+	}
+
+	if ok, _, _ := backend.TakeToken(t.Context(), "other", rate); !ok {
+		t.Error("TakeToken() for other key = false, want independent bucket")
+	}
+}
+
+func TestInMemoryBackend_ReturnToken(t *testing.T) {
+	backend := NewInMemoryBackend()
+	rate := Rate{Limit: 0.001, Burst: 1}
+
+	if ok, _, _ := backend.TakeToken(t.Context(), "k", rate); !ok {
+		t.Fatal("TakeToken() = false, want true")
+	}
+	for range 2 {
+		if err := backend.ReturnToken(t.Context(), "k", rate); err != nil {
+			t.Fatalf("ReturnToken() error = %v", err)
+		}
+	}
+	if ok, _, _ := backend.TakeToken(t.Context(), "k", rate); !ok {
+		t.Error("TakeToken() after ReturnToken() = false, want true")
+	}
+	if ok, _, _ := backend.TakeToken(t.Context(), "k", rate); ok {
+		t.Error("TakeToken() = true, want ReturnToken() not to exceed the burst")
+	}
+}
+
+func TestInMemoryBackend_Sweep(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	backend := NewInMemoryBackend().(*inMemoryBackend)
+	backend.now = func() time.Time { return now }
+	rate := Rate{Limit: 1}
+
+	if _, _, err := backend.TakeToken(t.Context(), "idle", rate); err != nil {
+		t.Fatalf("TakeToken() error = %v", err)
+	}
+	now = now.Add(time.Minute)
+	backend.sweep(now)
+	if _, ok := backend.buckets["idle"]; ok {
+		t.Error("sweep() kept a full bucket")
+	}
+}
+
+func TestInMemoryBackend_Slots(t *testing.T) {
+	backend := NewInMemoryBackend()
+	ctx := t.Context()
+
+	for i := range 2 {
+		if ok, err := backend.AcquireSlot(ctx, "s", 2); err != nil || !ok {
+			t.Fatalf("AcquireSlot() #%d = %v, %v, want true", i, ok, err)
+		}
+	}
+	if ok, _ := backend.AcquireSlot(ctx, "s", 2); ok {
+		t.Fatal("AcquireSlot() over limit = true, want false")
+	}
+	if err := backend.ReleaseSlot(ctx, "s"); err != nil {
+		t.Fatalf("ReleaseSlot() error = %v", err)
+	}
+	if ok, _ := backend.AcquireSlot(ctx, "s", 2); !ok {
+		t.Error("AcquireSlot() after release = false, want true")
+	}
+}
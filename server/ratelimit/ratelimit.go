@@ -0,0 +1,310 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ratelimit limits agent invocations served by ADK servers.
+//
+// A [Limiter] applies token bucket request rates per app and per user, and
+// caps the number of concurrent invocations of a session so that overlapping
+// runs on the same session are rejected. Requests over a limit can wait for
+// capacity up to [Config] MaxWait, or are rejected with a [*LimitError] which
+// servers report as 429 Too Many Requests with a Retry-After header.
+//
+// Limiter state is kept in a [Backend]. [NewInMemoryBackend] is used by
+// default; servers running multiple replicas can provide a shared one.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"google.golang.org/adk/v2/internal/version"
+)
+
+// Scope identifies the limit which rejected a request.
+type Scope string
+
+const (
+	// ScopeApp is the request rate limit shared by all users of an app.
+	ScopeApp Scope = "app"
+	// ScopeUser is the request rate limit of a single user of an app.
+	ScopeUser Scope = "user"
+	// ScopeSession is the limit of concurrent invocations of a single session.
+	ScopeSession Scope = "session"
+)
+
+const (
+	meterName = "google.golang.org/adk/v2/server/ratelimit"
+
+	// sessionRetryAfter is suggested to clients whose run overlaps with another run of the same session,
+	// as the time the other run takes to complete is unknown.
+	sessionRetryAfter = time.Second
+	// slotPollInterval is the interval of checking for a free session slot while waiting for capacity.
+	slotPollInterval = 50 * time.Millisecond
+)
+
+// ErrLimitExceeded is matched by errors returned when a request is rejected by a [Limiter].
+var ErrLimitExceeded = errors.New("rate limit exceeded")
+
+// LimitError is returned by [Limiter.Acquire] when a request exceeds a limit.
+type LimitError struct {
+	// Scope of the exceeded limit.
+	Scope Scope
+	// RetryAfter is the suggested time to wait before retrying the request.
+	RetryAfter time.Duration
+}
+
+// Error implements error.
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s %s limit, retry after %v", ErrLimitExceeded, e.Scope, e.RetryAfter)
+}
+
+// Is allows to match the error with [ErrLimitExceeded].
+func (e *LimitError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// Rate configures a token bucket. The zero value disables the limit.
+type Rate struct {
+	// Limit is the number of requests allowed per second on average.
+	Limit float64
+	// Burst is the maximum number of requests allowed at once. Defaults to Limit rounded up, but at least 1.
+	Burst int
+}
+
+// PerMinute returns a Rate which allows n requests per minute, all of which can be made at once.
+func PerMinute(n int) Rate {
+	return Rate{Limit: float64(n) / 60, Burst: n}
+}
+
+func (r Rate) enabled() bool {
+	return r.Limit > 0
+}
+
+func (r Rate) burst() int {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+	return max(1, int(math.Ceil(r.Limit)))
+}
+
+// Key identifies the invocation a request starts.
+type Key struct {
+	AppName string
+	UserID  string
+	// SessionID is empty for requests which create a new session, which are not subject to session limits.
+	SessionID string
+}
+
+// Config allows to configure a [Limiter].
+type Config struct {
+	// Backend stores the limiter state. Defaults to [NewInMemoryBackend].
+	Backend Backend
+	// PerApp limits the rate of requests to each app.
+	PerApp Rate
+	// PerUser limits the rate of requests of each user of an app.
+	PerUser Rate
+	// MaxConcurrentRunsPerSession limits the number of concurrent invocations of each session.
+	// Use 1 to reject overlapping runs on the same session. Zero means no limit.
+	MaxConcurrentRunsPerSession int
+	// MaxWait is how long a request waits for capacity before it's rejected. Zero rejects requests immediately.
+	MaxWait time.Duration
+	// MeterProvider is used to export limiter metrics. Defaults to the global OpenTelemetry MeterProvider.
+	MeterProvider metric.MeterProvider
+}
+
+// Limiter limits the rate and concurrency of agent invocations.
+// A nil *Limiter allows all requests.
+type Limiter struct {
+	backend       Backend
+	perApp        Rate
+	perUser       Rate
+	maxPerSession int
+	maxWait       time.Duration
+
+	decisions  metric.Int64Counter
+	activeRuns metric.Int64UpDownCounter
+	waitTime   metric.Float64Histogram
+}
+
+// New creates a [Limiter].
+func New(config Config) (*Limiter, error) {
+	if config.PerApp.Limit < 0 || config.PerUser.Limit < 0 || config.MaxConcurrentRunsPerSession < 0 || config.MaxWait < 0 {
+		return nil, fmt.Errorf("limits must not be negative")
+	}
+	backend := config.Backend
+	if backend == nil {
+		backend = NewInMemoryBackend()
+	}
+	meterProvider := config.MeterProvider
+	if meterProvider == nil {
+		meterProvider = otel.GetMeterProvider()
+	}
+	meter := meterProvider.Meter(meterName, metric.WithInstrumentationVersion(version.Version))
+
+	decisions, err := meter.Int64Counter("adk.ratelimit.decisions",
+		metric.WithDescription("Number of requests checked by the rate limiter."),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create decisions counter: %w", err)
+	}
+	activeRuns, err := meter.Int64UpDownCounter("adk.ratelimit.active_runs",
+		metric.WithDescription("Number of agent invocations admitted by the rate limiter and still running."),
+		metric.WithUnit("{run}"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create active runs counter: %w", err)
+	}
+	waitTime, err := meter.Float64Histogram("adk.ratelimit.wait_duration",
+		metric.WithDescription("Time requests spent waiting for capacity."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wait duration histogram: %w", err)
+	}
+
+	return &Limiter{
+		backend:       backend,
+		perApp:        config.PerApp,
+		perUser:       config.PerUser,
+		maxPerSession: config.MaxConcurrentRunsPerSession,
+		maxWait:       config.MaxWait,
+		decisions:     decisions,
+		activeRuns:    activeRuns,
+		waitTime:      waitTime,
+	}, nil
+}
+
+// Acquire admits an invocation identified by key, waiting for capacity up to the configured MaxWait.
+// It returns an error wrapping [*LimitError] if the invocation is over a limit. The returned release
+// function must be called when the invocation completes.
+func (l *Limiter) Acquire(ctx context.Context, key Key) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	appAttr := attribute.String("app_name", key.AppName)
+	start := time.Now()
+	deadline := start.Add(l.maxWait)
+	for {
+		release, err := l.tryAcquire(ctx, key)
+		if err == nil {
+			if waited := time.Since(start); waited > 0 && l.maxWait > 0 {
+				l.waitTime.Record(ctx, waited.Seconds(), metric.WithAttributes(appAttr))
+			}
+			l.decisions.Add(ctx, 1, metric.WithAttributes(appAttr, attribute.String("decision", "allowed")))
+			l.activeRuns.Add(ctx, 1, metric.WithAttributes(appAttr))
+			return func() {
+				release()
+				l.activeRuns.Add(context.WithoutCancel(ctx), -1, metric.WithAttributes(appAttr))
+			}, nil
+		}
+
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) {
+			return nil, err
+		}
+		wait := limitErr.RetryAfter
+		if limitErr.Scope == ScopeSession {
+			wait = slotPollInterval
+		}
+		if time.Now().Add(wait).After(deadline) {
+			l.decisions.Add(ctx, 1, metric.WithAttributes(appAttr,
+				attribute.String("decision", "rejected"), attribute.String("scope", string(limitErr.Scope))))
+			return nil, err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// tryAcquire checks all limits once. The session slot is reserved first so that rejected overlapping
+// runs don't consume request tokens.
+func (l *Limiter) tryAcquire(ctx context.Context, key Key) (func(), error) {
+	release := func() {}
+	if l.maxPerSession > 0 && key.SessionID != "" {
+		slotKey := "session/" + key.AppName + "/" + key.UserID + "/" + key.SessionID
+		ok, err := l.backend.AcquireSlot(ctx, slotKey, l.maxPerSession)
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire session slot: %w", err)
+		}
+		if !ok {
+			return nil, &LimitError{Scope: ScopeSession, RetryAfter: sessionRetryAfter}
+		}
+		release = func() {
+			// the invocation is complete even if its context was cancelled
+			_ = l.backend.ReleaseSlot(context.WithoutCancel(ctx), slotKey)
+		}
+	}
+
+	buckets := []struct {
+		scope Scope
+		key   string
+		rate  Rate
+	}{
+		{scope: ScopeUser, key: "user/" + key.AppName + "/" + key.UserID, rate: l.perUser},
+		{scope: ScopeApp, key: "app/" + key.AppName, rate: l.perApp},
+	}
+	var taken []string
+	for _, b := range buckets {
+		if !b.rate.enabled() || (b.scope == ScopeUser && key.UserID == "") {
+			continue
+		}
+		ok, retryAfter, err := l.backend.TakeToken(ctx, b.key, b.rate)
+		if err == nil && ok {
+			taken = append(taken, b.key)
+			continue
+		}
+		// Return the tokens taken so far, or a user waiting for the app limit would drain its own
+		// bucket on every retry.
+		for _, tb := range buckets {
+			if slices.Contains(taken, tb.key) {
+				_ = l.backend.ReturnToken(context.WithoutCancel(ctx), tb.key, tb.rate)
+			}
+		}
+		release()
+		if err != nil {
+			return nil, fmt.Errorf("failed to take %s token: %w", b.scope, err)
+		}
+		return nil, &LimitError{Scope: b.scope, RetryAfter: retryAfter}
+	}
+	return release, nil
+}
+
+// SetRetryAfter sets the Retry-After header if err wraps a [*LimitError].
+func SetRetryAfter(header http.Header, err error) {
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) {
+		return
+	}
+	seconds := max(1, int(math.Ceil(limitErr.RetryAfter.Seconds())))
+	header.Set("Retry-After", strconv.Itoa(seconds))
+}
+
+// WriteError responds with 429 Too Many Requests and a Retry-After header.
+func WriteError(w http.ResponseWriter, err error) {
+	SetRetryAfter(w.Header(), err)
+	http.Error(w, err.Error(), http.StatusTooManyRequests)
+}
@@ -0,0 +1,212 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func newTestLimiter(t *testing.T, config Config) *Limiter {
+	t.Helper()
+	limiter, err := New(config)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return limiter
+}
+
+func TestLimiter_Acquire(t *testing.T) {
+	testCases := []struct {
+		name      string
+		config    Config
+		keys      []Key
+		wantScope Scope
+	}{
+		{
+			name:   "no limits",
+			config: Config{},
+			keys:   []Key{{AppName: "app", UserID: "u", SessionID: "s"}, {AppName: "app", UserID: "u", SessionID: "s"}},
+		},
+		{
+			name:      "per user",
+			config:    Config{PerUser: Rate{Limit: 0.001, Burst: 1}},
+			keys:      []Key{{AppName: "app", UserID: "u1"}, {AppName: "app", UserID: "u2"}, {AppName: "app", UserID: "u1"}},
+			wantScope: ScopeUser,
+		},
+		{
+			name:      "per app",
+			config:    Config{PerApp: Rate{Limit: 0.001, Burst: 2}},
+			keys:      []Key{{AppName: "app", UserID: "u1"}, {AppName: "other", UserID: "u1"}, {AppName: "app", UserID: "u2"}, {AppName: "app", UserID: "u3"}},
+			wantScope: ScopeApp,
+		},
+		{
+			name:      "overlapping session runs",
+			config:    Config{MaxConcurrentRunsPerSession: 1},
+			keys:      []Key{{AppName: "app", UserID: "u", SessionID: "s1"}, {AppName: "app", UserID: "u", SessionID: "s2"}, {AppName: "app", UserID: "u", SessionID: "s1"}},
+			wantScope: ScopeSession,
+		},
+		{
+			name:   "new sessions are not limited",
+			config: Config{MaxConcurrentRunsPerSession: 1},
+			keys:   []Key{{AppName: "app", UserID: "u"}, {AppName: "app", UserID: "u"}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			limiter := newTestLimiter(t, tc.config)
+			var err error
+			for i, key := range tc.keys {
+				// runs are kept active until the end of the test
+				_, err = limiter.Acquire(t.Context(), key)
+				if err != nil && i != len(tc.keys)-1 {
+					t.Fatalf("Acquire(%+v) #%d error = %v, want nil", key, i, err)
+				}
+			}
+			if tc.wantScope == "" {
+				if err != nil {
+					t.Fatalf("Acquire() error = %v, want nil", err)
+				}
+				return
+			}
+			var limitErr *LimitError
+			if !errors.As(err, &limitErr) || !errors.Is(err, ErrLimitExceeded) {
+				t.Fatalf("Acquire() error = %v, want LimitError", err)
+			}
+			if limitErr.Scope != tc.wantScope || limitErr.RetryAfter <= 0 {
+				t.Errorf("Acquire() error = %+v, want scope %q and positive RetryAfter", limitErr, tc.wantScope)
+			}
+		})
+	}
+}
+
+func TestLimiter_Release(t *testing.T) {
+	limiter := newTestLimiter(t, Config{MaxConcurrentRunsPerSession: 1})
+	key := Key{AppName: "app", UserID: "u", SessionID: "s"}
+
+	release, err := limiter.Acquire(t.Context(), key)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	release()
+	if _, err := limiter.Acquire(t.Context(), key); err != nil {
+		t.Errorf("Acquire() after release error = %v, want nil", err)
+	}
+}
+
+func TestLimiter_MaxWait(t *testing.T) {
+	limiter := newTestLimiter(t, Config{MaxConcurrentRunsPerSession: 1, MaxWait: 5 * time.Second})
+	key := Key{AppName: "app", UserID: "u", SessionID: "s"}
+
+	release, err := limiter.Acquire(t.Context(), key)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		release()
+	}()
+	if _, err := limiter.Acquire(t.Context(), key); err != nil {
+		t.Errorf("Acquire() queued for a running session error = %v, want nil", err)
+	}
+}
+
+func TestLimiter_AppLimitKeepsUserTokens(t *testing.T) {
+	backend := NewInMemoryBackend()
+	perUser := Rate{Limit: 0.001, Burst: 2}
+	limiter := newTestLimiter(t, Config{Backend: backend, PerApp: Rate{Limit: 0.001, Burst: 1}, PerUser: perUser})
+
+	if _, err := limiter.Acquire(t.Context(), Key{AppName: "app", UserID: "u1"}); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	for range 3 {
+		if _, err := limiter.Acquire(t.Context(), Key{AppName: "app", UserID: "u2"}); !errors.Is(err, ErrLimitExceeded) {
+			t.Fatalf("Acquire() over the app limit error = %v, want ErrLimitExceeded", err)
+		}
+	}
+	for i := range perUser.Burst {
+		if ok, _, err := backend.TakeToken(t.Context(), "user/app/u2", perUser); err != nil || !ok {
+			t.Fatalf("TakeToken() #%d of the rejected user = %v, %v, want its bucket full", i, ok, err)
+		}
+	}
+}
+
+func TestLimiter_Nil(t *testing.T) {
+	var limiter *Limiter
+	release, err := limiter.Acquire(t.Context(), Key{AppName: "app"})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	release()
+}
+
+func TestLimiter_Metrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	limiter := newTestLimiter(t, Config{
+		PerUser:       Rate{Limit: 0.001, Burst: 1},
+		MeterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	})
+	key := Key{AppName: "app", UserID: "u"}
+	release, err := limiter.Acquire(t.Context(), key)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	if _, err := limiter.Acquire(t.Context(), key); err == nil {
+		t.Fatal("Acquire() error = nil, want limit error")
+	}
+	release()
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(t.Context(), &data); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	got := map[string]int64{}
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, dp := range sum.DataPoints {
+					decision, _ := dp.Attributes.Value("decision")
+					got[m.Name+"/"+decision.AsString()] += dp.Value
+				}
+			}
+		}
+	}
+	want := map[string]int64{
+		"adk.ratelimit.decisions/allowed":  1,
+		"adk.ratelimit.decisions/rejected": 1,
+		"adk.ratelimit.active_runs/":       0,
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("metric %s = %d, want %d (all: %v)", name, got[name], value, got)
+		}
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	rr := httptest.NewRecorder()
+	WriteError(rr, &LimitError{Scope: ScopeUser, RetryAfter: 1500 * time.Millisecond})
+	if rr.Code != 429 {
+		t.Errorf("status = %d, want 429", rr.Code)
+	}
+	if got := rr.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want %q", got, "2")
+	}
+}
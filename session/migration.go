@@ -0,0 +1,162 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+)
+
+// StateVersionKey is the session state key holding the version of the
+// schema of the session's state, as maintained by StateMigrations. A
+// session without it is at version 0.
+const StateVersionKey = "state_schema_version"
+
+// ErrStateVersion is returned by StateMigrations.Migrate for a session
+// whose state version is unknown to the registry.
+var ErrStateVersion = errors.New("session: unsupported state schema version")
+
+// StateMigrationFunc upgrades state, the session-scoped state of a
+// session, from one schema version to the next by modifying it in
+// place. Keys deleted from state are set to nil in the session.
+type StateMigrationFunc func(ctx context.Context, state map[string]any) error
+
+// StateMigrations is a registry of the migrations upgrading session
+// state from older schema versions to the current one. Its zero value
+// is an empty registry, whose current version is 0.
+type StateMigrations struct {
+	steps []StateMigrationFunc
+}
+
+// Register registers fn as the migration upgrading state from version
+// from to from+1, which becomes the current version. Migrations must be
+// registered in order, starting at version 0.
+func (m *StateMigrations) Register(from int, fn StateMigrationFunc) error {
+	if fn == nil {
+		return fmt.Errorf("session: nil migration from state version %d", from)
+	}
+	if from != len(m.steps) {
+		return fmt.Errorf("session: migration from state version %d registered when the current version is %d", from, len(m.steps))
+	}
+	m.steps = append(m.steps, fn)
+	return nil
+}
+
+// Version returns the current state schema version: the number of
+// registered migrations.
+func (m *StateMigrations) Version() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.steps)
+}
+
+// Migrate applies to state the migrations from its version to the
+// current one, and returns the state delta recording the result,
+// including the new StateVersionKey, for an event appended to the
+// session. It returns a nil delta if state is up to date, and an error
+// wrapping ErrStateVersion if its version is newer than the current one
+// or isn't a number.
+//
+// Only session-scoped keys are migrated: app and user state is shared
+// by many sessions, which would each migrate it again, so it's neither
+// passed to the migrations nor may be set by them. Temporary keys are
+// not migrated either.
+func (m *StateMigrations) Migrate(ctx context.Context, state ReadonlyState) (map[string]any, error) {
+	from, err := StateVersion(state)
+	if err != nil {
+		return nil, err
+	}
+	to := m.Version()
+	switch {
+	case from == to:
+		return nil, nil
+	case from > to:
+		return nil, fmt.Errorf("%w: state is at version %d, newer than the current version %d", ErrStateVersion, from, to)
+	}
+
+	old := map[string]any{}
+	for k, v := range state.All() {
+		if !unscoped(k) {
+			old[k] = v
+		}
+	}
+	migrated := maps.Clone(old)
+	for v := from; v < to; v++ {
+		if err := m.steps[v](ctx, migrated); err != nil {
+			return nil, fmt.Errorf("session: migrating state from version %d: %w", v, err)
+		}
+		for k := range migrated {
+			if unscoped(k) {
+				return nil, fmt.Errorf("session: migrating state from version %d: key %q is not session-scoped", v, k)
+			}
+		}
+	}
+
+	delta := map[string]any{}
+	for k, v := range migrated {
+		if prev, ok := old[k]; !ok || !reflect.DeepEqual(prev, v) {
+			delta[k] = v
+		}
+	}
+	for k := range old {
+		if _, ok := migrated[k]; !ok {
+			delta[k] = nil
+		}
+	}
+	delta[StateVersionKey] = to
+	return delta, nil
+}
+
+// unscoped reports whether key is not session-scoped state which
+// migrations apply to.
+func unscoped(key string) bool {
+	return strings.HasPrefix(key, KeyPrefixApp) || strings.HasPrefix(key, KeyPrefixUser) || strings.HasPrefix(key, KeyPrefixTemp)
+}
+
+// StateVersion returns the state schema version of state, as recorded
+// under StateVersionKey. It returns 0 if the key isn't set.
+func StateVersion(state ReadonlyState) (int, error) {
+	if state == nil {
+		return 0, nil
+	}
+	v, err := state.Get(StateVersionKey)
+	if errors.Is(err, ErrStateKeyNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	switch v := v.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return int(n), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %v", ErrStateVersion, v)
+}
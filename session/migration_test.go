@@ -0,0 +1,117 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func testMigrations(t *testing.T) *StateMigrations {
+	t.Helper()
+	var m StateMigrations
+	// v0 -> v1: rename "name" to "user_name".
+	if err := m.Register(0, func(_ context.Context, state map[string]any) error {
+		if v, ok := state["name"]; ok {
+			state["user_name"] = v
+			delete(state, "name")
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("Register(0): %v", err)
+	}
+	// v1 -> v2: default "tier".
+	if err := m.Register(1, func(_ context.Context, state map[string]any) error {
+		if _, ok := state["tier"]; !ok {
+			state["tier"] = "free"
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("Register(1): %v", err)
+	}
+	return &m
+}
+
+func TestStateMigrations_Migrate(t *testing.T) {
+	m := testMigrations(t)
+	if got := m.Version(); got != 2 {
+		t.Fatalf("Version() = %d, want 2", got)
+	}
+
+	tests := []struct {
+		name  string
+		state map[string]any
+		want  map[string]any
+	}{
+		{
+			name:  "from version 0",
+			state: map[string]any{"name": "ada", "other": 1, "temp:x": 1, "app:name": "x", "user:name": "y"},
+			want:  map[string]any{"name": nil, "user_name": "ada", "tier": "free", StateVersionKey: 2},
+		},
+		{
+			name:  "from version 1 decoded from JSON",
+			state: map[string]any{"user_name": "ada", StateVersionKey: float64(1)},
+			want:  map[string]any{"tier": "free", StateVersionKey: 2},
+		},
+		{
+			name:  "up to date",
+			state: map[string]any{"user_name": "ada", StateVersionKey: 2},
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.Migrate(t.Context(), &state{state: tt.state, mu: new(sync.RWMutex)})
+			if err != nil {
+				t.Fatalf("Migrate: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Migrate() delta mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStateMigrations_Errors(t *testing.T) {
+	m := testMigrations(t)
+	if err := m.Register(1, func(context.Context, map[string]any) error { return nil }); err == nil {
+		t.Error("Register() of an out of order migration succeeded, want error")
+	}
+
+	newer := &state{state: map[string]any{StateVersionKey: 3}, mu: new(sync.RWMutex)}
+	if _, err := m.Migrate(t.Context(), newer); !errors.Is(err, ErrStateVersion) {
+		t.Errorf("Migrate() of newer state error = %v, want %v", err, ErrStateVersion)
+	}
+
+	shared := &StateMigrations{}
+	_ = shared.Register(0, func(_ context.Context, state map[string]any) error {
+		state["user:tier"] = "free"
+		return nil
+	})
+	if _, err := shared.Migrate(t.Context(), &state{state: map[string]any{}, mu: new(sync.RWMutex)}); err == nil {
+		t.Error("Migrate() of a migration setting user state succeeded, want error")
+	}
+
+	failing := &StateMigrations{}
+	errBoom := errors.New("boom")
+	_ = failing.Register(0, func(context.Context, map[string]any) error { return errBoom })
+	if _, err := failing.Migrate(t.Context(), &state{state: map[string]any{}, mu: new(sync.RWMutex)}); !errors.Is(err, errBoom) {
+		t.Errorf("Migrate() error = %v, want %v", err, errBoom)
+	}
+}
@@ -167,6 +167,12 @@ type NodeInfo struct {
 	// stands in for a whole delegation chain rather than each level
 	// re-emitting a duplicate. Mirrors adk-python's node_info.output_for.
 	OutputFor []string `json:"outputFor,omitempty"`
+
+	// WorkflowVersion is the version of the workflow whose graph
+	// contains the emitting node, as set by workflow.WithVersion.
+	// Empty for unversioned workflows. Lets a resume tell whether the
+	// graph changed since the run paused.
+	WorkflowVersion string `json:"workflowVersion,omitempty"`
 }
 
 // RequestInput describes a single human-in-the-loop prompt emitted
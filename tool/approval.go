@@ -0,0 +1,160 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/approval"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/tool/toolutils"
+)
+
+// WithApproval wraps a toolset so that each call of its tools needs to be
+// approved according to policy. The call asks for a tool confirmation,
+// whose hint is the rendered message of the policy and whose payload is
+// the approval.Audit of the request, until the decisions reach the
+// quorum of the policy; each confirmation is a decision, with the
+// approver, comment and payload of the decision taken from the payload of
+// the confirmation. Rejected calls fail with ErrConfirmationRejected.
+//
+// The approval.Audit of a call, with all its decisions, is kept in the
+// session state under the key "tool_approval:" followed by the ID of the
+// function call. Expired requests are settled when the next confirmation
+// arrives. Like WithConfirmation, only tools which provide a
+// FunctionDeclaration and a Run method are wrapped.
+//
+// EXPERIMENTAL: WithApproval is experimental and not currently in scope for the v1.0 API.
+func WithApproval(ts Toolset, policy approval.Policy) (Toolset, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &approvalToolset{toolset: ts, policy: policy}, nil
+}
+
+type approvalToolset struct {
+	toolset Toolset
+	policy  approval.Policy
+}
+
+func (s *approvalToolset) Name() string { return s.toolset.Name() }
+
+func (s *approvalToolset) Tools(ctx agent.ReadonlyContext) ([]Tool, error) {
+	tools, err := s.toolset.Tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := make([]Tool, 0, len(tools))
+	for _, t := range tools {
+		if rt, ok := t.(runnableTool); ok {
+			wrapped = append(wrapped, &approvalTool{runnableTool: rt, policy: &s.policy})
+		} else {
+			wrapped = append(wrapped, t)
+		}
+	}
+	return wrapped, nil
+}
+
+// approvalTool is a wrapper around a tool that adds approval logic.
+type approvalTool struct {
+	runnableTool
+	policy *approval.Policy
+}
+
+func approvalStateKey(functionCallID string) string {
+	return "tool_approval:" + functionCallID
+}
+
+func (t *approvalTool) Declaration() *genai.FunctionDeclaration {
+	return t.runnableTool.Declaration()
+}
+
+func (t *approvalTool) ProcessRequest(ctx agent.Context, req *model.LLMRequest) error {
+	return toolutils.PackWrappedTool(ctx, req, t.runnableTool, t)
+}
+
+func (t *approvalTool) ExecutionOptions() ExecutionOptions {
+	return ExecutionOptionsOf(t.runnableTool)
+}
+
+func (t *approvalTool) RequiresConfirmation() bool {
+	return true
+}
+
+func (t *approvalTool) Run(ctx agent.Context, args any) (map[string]any, error) {
+	now := time.Now()
+	key := approvalStateKey(ctx.FunctionCallID())
+	var audit *approval.Audit
+	if v, err := ctx.State().Get(key); err == nil {
+		audit, _ = approval.AuditFrom(v)
+	}
+	fresh := audit == nil
+	if fresh {
+		req, err := t.policy.NewRequest(ctx.FunctionCallID(), t.Name(), args, now)
+		if err != nil {
+			return nil, err
+		}
+		audit = &approval.Audit{Request: req}
+	}
+
+	decisions := audit.Tally.Decisions
+	audit.Decision = nil
+	if confirmation := ctx.ToolConfirmation(); confirmation != nil {
+		answer := map[string]any{}
+		if payload, ok := confirmation.Payload.(map[string]any); ok {
+			maps.Copy(answer, payload)
+		}
+		answer["approved"] = confirmation.Confirmed
+		d, err := t.policy.Decide(ctx, audit.Request, decisions, answer, approval.IdentityOf(ctx), now)
+		if err != nil {
+			return nil, err
+		}
+		decisions = append(slices.Clip(decisions), d)
+		audit.Decision = &d
+	}
+	audit.Tally = t.policy.Tally(decisions)
+
+	kind := approval.Decided
+	if audit.Tally.Outcome == approval.Pending {
+		kind = approval.Requested
+	}
+	audit.NotifyError = ""
+	if fresh || kind == approval.Decided {
+		if err := t.policy.Notify(ctx, approval.Notification{Kind: kind, Request: audit.Request, Tally: audit.Tally}); err != nil {
+			audit.NotifyError = err.Error()
+		}
+	}
+	if err := ctx.State().Set(key, audit); err != nil {
+		return nil, err
+	}
+
+	switch audit.Tally.Outcome {
+	case approval.Approved:
+		return t.runnableTool.Run(ctx, args)
+	case approval.Rejected:
+		return nil, fmt.Errorf("error tool %q %w", t.Name(), ErrConfirmationRejected)
+	}
+	if err := ctx.RequestConfirmation(audit.Request.Message, audit); err != nil {
+		return nil, err
+	}
+	ctx.Actions().SkipSummarization = true
+	return nil, fmt.Errorf("error tool %q %w", t.Name(), ErrConfirmationRequired)
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool_test
+
+import (
+	"errors"
+	"iter"
+	"maps"
+	"testing"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/approval"
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/functiontool"
+	"google.golang.org/adk/v2/tool/toolconfirmation"
+)
+
+// mapState is a session.State backed by a map.
+type mapState map[string]any
+
+func (s mapState) Get(key string) (any, error) {
+	v, ok := s[key]
+	if !ok {
+		return nil, session.ErrStateKeyNotExist
+	}
+	return v, nil
+}
+
+func (s mapState) Set(key string, v any) error {
+	s[key] = v
+	return nil
+}
+
+func (s mapState) All() iter.Seq2[string, any] { return maps.All(s) }
+
+func TestWithApproval(t *testing.T) {
+	ran := 0
+	transfer, err := functiontool.New(functiontool.Config{Name: "transfer"}, func(ctx agent.Context, input struct{ Amount int }) (struct{}, error) {
+		ran++
+		return struct{}{}, nil
+	})
+	if err != nil {
+		t.Fatalf("functiontool.New() failed: %v", err)
+	}
+	ts, err := tool.WithApproval(&testToolset{tools: []tool.Tool{transfer}}, approval.Policy{
+		Users:   []string{"alice", "bob"},
+		Quorum:  approval.Quorum{Approvals: 2},
+		Message: "Transfer {{.Amount}}?",
+	})
+	if err != nil {
+		t.Fatalf("WithApproval() failed: %v", err)
+	}
+	tools, err := ts.Tools(nil)
+	if err != nil {
+		t.Fatalf("Tools() failed: %v", err)
+	}
+	approved := tools[0].(toolinternal.FunctionTool)
+
+	state := mapState{}
+	call := func(confirmation *toolconfirmation.ToolConfirmation) (*testContext, error) {
+		ctx := &testContext{Context: t.Context(), toolConfirmationResult: confirmation, state: state}
+		if confirmation != nil {
+			approver := confirmation.Payload.(map[string]any)["approver"].(string)
+			ctx.Context = agent.NewContextWithPrincipal(ctx.Context, &agent.Principal{Subject: approver})
+		}
+		_, err := approved.Run(ctx, map[string]any{"Amount": 10})
+		return ctx, err
+	}
+
+	ctx, err := call(nil)
+	if !errors.Is(err, tool.ErrConfirmationRequired) || !ctx.requestConfirmationCalled {
+		t.Fatalf("first call error = %v, confirmation requested = %v, want a confirmation request", err, ctx.requestConfirmationCalled)
+	}
+	_, err = call(&toolconfirmation.ToolConfirmation{Confirmed: true, Payload: map[string]any{"approver": "alice"}})
+	if !errors.Is(err, tool.ErrConfirmationRequired) {
+		t.Fatalf("call approved by alice error = %v, want another confirmation request", err)
+	}
+	if _, err := call(&toolconfirmation.ToolConfirmation{Confirmed: true, Payload: map[string]any{"approver": "bob"}}); err != nil {
+		t.Fatalf("call approved by alice and bob error = %v", err)
+	}
+	if ran != 1 {
+		t.Errorf("tool ran %d times, want 1", ran)
+	}
+
+	audit, ok := approval.AuditFrom(state["tool_approval:test-function-call-id"])
+	if !ok {
+		t.Fatal("no audit in state")
+	}
+	if audit.Request.Message != "Transfer 10?" || audit.Tally.Outcome != approval.Approved || len(audit.Tally.Decisions) != 2 {
+		t.Errorf("audit = %+v, want the rendered request approved by two decisions", audit)
+	}
+}
+
+func TestWithApproval_Rejected(t *testing.T) {
+	noop, err := functiontool.New(functiontool.Config{Name: "noop"}, func(ctx agent.Context, input struct{}) (struct{}, error) {
+		t.Error("rejected tool ran")
+		return struct{}{}, nil
+	})
+	if err != nil {
+		t.Fatalf("functiontool.New() failed: %v", err)
+	}
+	ts, err := tool.WithApproval(&testToolset{tools: []tool.Tool{noop}}, approval.Policy{})
+	if err != nil {
+		t.Fatalf("WithApproval() failed: %v", err)
+	}
+	tools, _ := ts.Tools(nil)
+	ctx := &testContext{Context: t.Context(), toolConfirmationResult: &toolconfirmation.ToolConfirmation{Confirmed: false}, state: mapState{}}
+	if _, err := tools[0].(toolinternal.FunctionTool).Run(ctx, map[string]any{}); !errors.Is(err, tool.ErrConfirmationRejected) {
+		t.Errorf("Run() error = %v, want %v", err, tool.ErrConfirmationRejected)
+	}
+
+	if _, err := tool.WithApproval(&testToolset{}, approval.Policy{Roles: []string{"admin"}}); !errors.Is(err, approval.ErrInvalidPolicy) {
+		t.Errorf("WithApproval() with roles and no resolver error = %v, want %v", err, approval.ErrInvalidPolicy)
+	}
+}
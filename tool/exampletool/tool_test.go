@@ -61,6 +61,10 @@ func (m *mockToolContext) UserContent() *genai.Content {
 	return m.userContent
 }
 
+func (m *mockToolContext) Principal() *agent.Principal {
+	return nil
+}
+
 // Implement other interface methods with panic or nil as needed for this specific test
 func (m *mockToolContext) FunctionCallID() string { return "" }
 
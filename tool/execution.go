@@ -0,0 +1,106 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/tool/toolutils"
+)
+
+// ExecutionOptions control how the calls of a tool are executed by the
+// agents.
+type ExecutionOptions struct {
+	// MaxConcurrency, if positive, bounds the number of calls of the tool
+	// running at once in an agent, across its invocations. The other calls
+	// wait for their turn.
+	MaxConcurrency int
+	// Sequential runs the calls of the tool alone: never in parallel with
+	// the other function calls of the same model response.
+	Sequential bool
+	// Timeout, if positive, bounds the duration of the calls of the tool.
+	// A call timing out gets an error function response, and its context
+	// is cancelled.
+	Timeout time.Duration
+}
+
+// ExecutionOptionsProvider is implemented by the tools with execution
+// options.
+type ExecutionOptionsProvider interface {
+	ExecutionOptions() ExecutionOptions
+}
+
+// ExecutionOptionsOf returns the execution options of t, if it has some.
+func ExecutionOptionsOf(t Tool) ExecutionOptions {
+	if p, ok := t.(ExecutionOptionsProvider); ok {
+		return p.ExecutionOptions()
+	}
+	return ExecutionOptions{}
+}
+
+// WithExecutionOptions wraps a toolset to set the execution options of its
+// tools. Only the tools implementing the `runnableTool` interface are
+// wrapped; the others are returned unmodified.
+func WithExecutionOptions(ts Toolset, opts ExecutionOptions) Toolset {
+	if ts == nil {
+		panic("toolset must not be nil")
+	}
+	return &executionToolset{toolset: ts, opts: opts}
+}
+
+type executionToolset struct {
+	toolset Toolset
+	opts    ExecutionOptions
+}
+
+func (s *executionToolset) Name() string { return s.toolset.Name() }
+
+func (s *executionToolset) Tools(ctx agent.ReadonlyContext) ([]Tool, error) {
+	tools, err := s.toolset.Tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := make([]Tool, 0, len(tools))
+	for _, t := range tools {
+		if rt, ok := t.(runnableTool); ok {
+			wrapped = append(wrapped, &executionTool{runnableTool: rt, opts: s.opts})
+		} else {
+			wrapped = append(wrapped, t)
+		}
+	}
+	return wrapped, nil
+}
+
+// executionTool is a wrapper around a tool setting its execution options.
+type executionTool struct {
+	runnableTool
+	opts ExecutionOptions
+}
+
+func (t *executionTool) Declaration() *genai.FunctionDeclaration {
+	return t.runnableTool.Declaration()
+}
+
+func (t *executionTool) ProcessRequest(ctx agent.Context, req *model.LLMRequest) error {
+	return toolutils.PackWrappedTool(ctx, req, t.runnableTool, t)
+}
+
+func (t *executionTool) ExecutionOptions() ExecutionOptions {
+	return t.opts
+}
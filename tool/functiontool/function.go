@@ -65,6 +65,10 @@ type Config struct {
 	// where ToolArgs is the input type of your go function
 	// Returning true means confirmation is required.
 	RequireConfirmationProvider any
+
+	// Execution controls how the calls of the tool are executed, e.g. their
+	// timeout.
+	Execution tool.ExecutionOptions
 }
 
 // Func represents a Go function that can be wrapped in a tool.
@@ -151,6 +155,17 @@ func (f *functionTool[TArgs, TResults]) IsLongRunning() bool {
 	return f.cfg.IsLongRunning
 }
 
+// ExecutionOptions implements tool.ExecutionOptionsProvider.
+func (f *functionTool[TArgs, TResults]) ExecutionOptions() tool.ExecutionOptions {
+	return f.cfg.Execution
+}
+
+// RequiresConfirmation reports whether the calls of the tool may ask for a
+// tool confirmation.
+func (f *functionTool[TArgs, TResults]) RequiresConfirmation() bool {
+	return f.requireConfirmation || f.requireConfirmationProvider != nil
+}
+
 // ProcessRequest packs the function tool's declaration into the LLM request.
 func (f *functionTool[TArgs, TResults]) ProcessRequest(ctx agent.Context, req *model.LLMRequest) error {
 	return toolutils.PackTool(req, f)
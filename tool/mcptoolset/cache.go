@@ -0,0 +1,95 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// toolListCache caches the tool lists of the server, by connection
+// scope.
+type toolListCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]toolListEntry
+	// gen is incremented by invalidate, so that lists fetched before
+	// are not cached.
+	gen int
+}
+
+type toolListEntry struct {
+	tools   []*mcp.Tool
+	expires time.Time
+}
+
+// newToolListCache returns a cache keeping tool lists for ttl; a ttl
+// <= 0 disables it.
+func newToolListCache(ttl time.Duration) *toolListCache {
+	return &toolListCache{ttl: ttl, entries: map[string]toolListEntry{}}
+}
+
+// get returns the cached tool list of scope, if any.
+func (c *toolListCache) get(scope string) ([]*mcp.Tool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[scope]
+	if !ok || time.Now().After(e.expires) {
+		return nil, false
+	}
+	return e.tools, true
+}
+
+// generation returns the generation of the cache, to pass to put the
+// list fetched next.
+func (c *toolListCache) generation() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gen
+}
+
+// put caches the tool list of scope fetched at generation gen, unless
+// the cache was invalidated since.
+func (c *toolListCache) put(scope string, gen int, tools []*mcp.Tool) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen {
+		return
+	}
+	c.entries[scope] = toolListEntry{tools: tools, expires: time.Now().Add(c.ttl)}
+}
+
+// reset drops the cached tool list of scope, e.g. when reconnecting to
+// the server, which may have changed its tools meanwhile. Lists fetched
+// since are still cached.
+func (c *toolListCache) reset(scope string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, scope)
+}
+
+// invalidate drops the cached tool lists, and those being fetched.
+func (c *toolListCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	clear(c.entries)
+}
@@ -39,6 +39,9 @@ type MCPClient interface {
 type connectionRefresher struct {
 	client    *mcp.Client
 	transport mcp.Transport
+	// onConnect, if set, is called with every new session, e.g. to
+	// renew resource subscriptions after a reconnection.
+	onConnect func(context.Context, *mcp.ClientSession)
 
 	mu      sync.Mutex
 	session *mcp.ClientSession
@@ -109,6 +112,58 @@ func (c *connectionRefresher) ListTools(ctx context.Context) ([]*mcp.Tool, error
 	return tools, nil
 }
 
+// ListResources lists all resources of the MCP server, handling
+// pagination and automatically reconnecting if needed.
+func (c *connectionRefresher) ListResources(ctx context.Context) ([]*mcp.Resource, error) {
+	resources, _, err := withRetry(ctx, c, func(session *mcp.ClientSession) ([]*mcp.Resource, error) {
+		var resources []*mcp.Resource
+		for r, err := range session.Resources(ctx, nil) {
+			if err != nil {
+				return nil, err
+			}
+			resources = append(resources, r)
+		}
+		return resources, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MCP resources: %w", err)
+	}
+	return resources, nil
+}
+
+// ReadResource reads a resource of the MCP server, automatically
+// reconnecting if needed.
+func (c *connectionRefresher) ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	result, _, err := withRetry(ctx, c, func(session *mcp.ClientSession) (*mcp.ReadResourceResult, error) {
+		return session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MCP resource %q: %w", uri, err)
+	}
+	return result, nil
+}
+
+// Subscribe subscribes to the updates of a resource of the MCP server,
+// automatically reconnecting if needed.
+func (c *connectionRefresher) Subscribe(ctx context.Context, uri string) error {
+	_, _, err := withRetry(ctx, c, func(session *mcp.ClientSession) (struct{}, error) {
+		return struct{}{}, session.Subscribe(ctx, &mcp.SubscribeParams{URI: uri})
+	})
+	return err
+}
+
+// GetPrompt renders a prompt of the MCP server with args, automatically
+// reconnecting if needed.
+func (c *connectionRefresher) GetPrompt(ctx context.Context, name string, args map[string]string) (*mcp.GetPromptResult, error) {
+	result, _, err := withRetry(ctx, c, func(session *mcp.ClientSession) (*mcp.GetPromptResult, error) {
+		return session.GetPrompt(ctx, &mcp.GetPromptParams{Name: name, Arguments: args})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP prompt %q: %w", name, err)
+	}
+	return result, nil
+}
+
 // withRetry executes fn with the current session, and if it fails, attempts to refresh
 // the connection and retry once. Returns the result, whether a reconnection occurred, and any error.
 func withRetry[T any](ctx context.Context, c *connectionRefresher, fn func(*mcp.ClientSession) (T, error)) (T, bool, error) {
@@ -159,6 +214,9 @@ func (c *connectionRefresher) getSession(ctx context.Context) (*mcp.ClientSessio
 	}
 
 	c.session = session
+	if c.onConnect != nil {
+		c.onConnect(ctx, session)
+	}
 	return c.session, nil
 }
 
@@ -184,7 +242,41 @@ func (c *connectionRefresher) refreshConnection(ctx context.Context) (*mcp.Clien
 	}
 
 	c.session = session
+	if c.onConnect != nil {
+		c.onConnect(ctx, session)
+	}
 	return c.session, nil
 }
 
+// ping checks the health of the session, if connected.
+func (c *connectionRefresher) ping(ctx context.Context) error {
+	c.mu.Lock()
+	session := c.session
+	c.mu.Unlock()
+	if session == nil {
+		return nil
+	}
+	return session.Ping(ctx, &mcp.PingParams{})
+}
+
+// hasSession reports whether session is the session of the connection.
+func (c *connectionRefresher) hasSession(session *mcp.ClientSession) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.session != nil && c.session == session
+}
+
+// close closes the session, if connected.
+func (c *connectionRefresher) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.session == nil {
+		return
+	}
+	if err := c.session.Close(); err != nil {
+		log.Printf("failed to close MCP session: %v", err)
+	}
+	c.session = nil
+}
+
 var _ MCPClient = (*connectionRefresher)(nil)
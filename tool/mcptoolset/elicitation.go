@@ -0,0 +1,191 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool/toolconfirmation"
+)
+
+// ElicitationRequest is the payload of the tool confirmation requested
+// when the MCP server asks the user for input during a tool call (an
+// MCP elicitation), with Config.Elicitation set. The hint of the
+// confirmation is the server's message.
+//
+// Confirming it accepts the elicitation, with the payload of the
+// confirmation, an object conforming to RequestedSchema, as the user's
+// input; rejecting it declines the elicitation. The tool call is then
+// made again, and the server's elicitations answered in order, so the
+// server must tolerate a call being repeated.
+type ElicitationRequest struct {
+	Message string `json:"message"`
+	// RequestedSchema is the JSON schema of the input, for form
+	// elicitations.
+	RequestedSchema any `json:"requested_schema,omitempty"`
+	// URL is the URL the user is asked to visit, for URL elicitations.
+	URL string `json:"url,omitempty"`
+}
+
+// elicitationStateKeyPrefix prefixes the session state key recording
+// the elicitations of a tool call, by function call ID.
+const elicitationStateKeyPrefix = "mcp_elicitation:"
+
+// elicitationRecord is the state of the elicitations of a tool call
+// across its runs.
+type elicitationRecord struct {
+	Answers []elicitationAnswer `json:"answers,omitempty"`
+	Pending *ElicitationRequest `json:"pending,omitempty"`
+}
+
+type elicitationAnswer struct {
+	Action  string         `json:"action"`
+	Content map[string]any `json:"content,omitempty"`
+}
+
+func loadElicitationRecord(ctx agent.Context) (elicitationRecord, error) {
+	var rec elicitationRecord
+	v, err := ctx.State().Get(elicitationStateKeyPrefix + ctx.FunctionCallID())
+	if errors.Is(err, session.ErrStateKeyNotExist) || v == nil {
+		return rec, nil
+	}
+	if err != nil {
+		return rec, err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return rec, err
+	}
+	return rec, json.Unmarshal(b, &rec)
+}
+
+func (rec elicitationRecord) save(ctx agent.Context) error {
+	return ctx.State().Set(elicitationStateKeyPrefix+ctx.FunctionCallID(), rec)
+}
+
+// answer records the user's answer to the pending elicitation, as given
+// in confirmation.
+func (rec *elicitationRecord) answer(confirmation *toolconfirmation.ToolConfirmation) {
+	a := elicitationAnswer{Action: "decline"}
+	if confirmation.Confirmed {
+		a.Action = "accept"
+		a.Content, _ = confirmation.Payload.(map[string]any)
+	}
+	rec.Answers = append(rec.Answers, a)
+	rec.Pending = nil
+}
+
+// elicitations routes the elicitations of the server to the tool calls
+// in flight on the connection. MCP doesn't say which request an
+// elicitation relates to, so it is attributed to the call in flight in
+// the scope of the connection only if there is one; the tools run
+// sequentially to make that the usual case.
+type elicitations struct {
+	// scope returns the connection scope of a call, and sessionScope
+	// that of the session of an elicitation. If unset, all calls are in
+	// the same scope.
+	scope        func(context.Context) string
+	sessionScope func(*mcp.ClientSession) (string, bool)
+
+	mu    sync.Mutex
+	calls []*elicitationCall
+}
+
+// elicitationCall is a tool call in flight, answering the server's
+// elicitations with answers, in order, and recording the first one it
+// can't answer.
+type elicitationCall struct {
+	scope   string
+	answers []elicitationAnswer
+	pending *ElicitationRequest
+}
+
+// begin registers a call answering elicitations with answers, until
+// the returned function is called.
+func (e *elicitations) begin(ctx context.Context, answers []elicitationAnswer) (*elicitationCall, func()) {
+	call := &elicitationCall{answers: answers}
+	if e.scope != nil {
+		call.scope = e.scope(ctx)
+	}
+	e.mu.Lock()
+	e.calls = append(e.calls, call)
+	e.mu.Unlock()
+	return call, func() {
+		e.mu.Lock()
+		e.calls = slices.DeleteFunc(e.calls, func(c *elicitationCall) bool { return c == call })
+		e.mu.Unlock()
+	}
+}
+
+// pending returns the elicitation of call which the user has yet to
+// answer, if any.
+func (e *elicitations) pending(call *elicitationCall) *ElicitationRequest {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return call.pending
+}
+
+// handle answers an elicitation of the server: with the next answer of
+// the call in flight if it has one, and otherwise by cancelling it and
+// recording it as the call's pending request. Elicitations outside of
+// tool calls of the scope of the connection are declined, and those
+// made while several calls of the scope are in flight are cancelled,
+// as they may relate to any of them.
+func (e *elicitations) handle(_ context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	scope := ""
+	if e.sessionScope != nil {
+		var ok bool
+		if scope, ok = e.sessionScope(req.Session); !ok {
+			return &mcp.ElicitResult{Action: "decline"}, nil
+		}
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var call *elicitationCall
+	for _, c := range e.calls {
+		if c.scope != scope {
+			continue
+		}
+		if call != nil {
+			return &mcp.ElicitResult{Action: "cancel"}, nil
+		}
+		call = c
+	}
+	if call == nil {
+		return &mcp.ElicitResult{Action: "decline"}, nil
+	}
+	if call.pending != nil {
+		return &mcp.ElicitResult{Action: "cancel"}, nil
+	}
+	if len(call.answers) > 0 {
+		a := call.answers[0]
+		call.answers = call.answers[1:]
+		return &mcp.ElicitResult{Action: a.Action, Content: a.Content}, nil
+	}
+	call.pending = &ElicitationRequest{
+		Message:         req.Params.Message,
+		RequestedSchema: req.Params.RequestedSchema,
+		URL:             req.Params.URL,
+	}
+	return &mcp.ElicitResult{Action: "cancel"}, nil
+}
@@ -0,0 +1,399 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/auth"
+	icontext "google.golang.org/adk/v2/internal/context"
+	"google.golang.org/adk/v2/internal/testutil"
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/mcptoolset"
+	"google.golang.org/adk/v2/tool/toolconfirmation"
+)
+
+// connect connects server to a toolset created with cfg.
+func connect(t *testing.T, server *mcp.Server, cfg mcptoolset.Config) tool.Toolset {
+	t.Helper()
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatalf("server.Connect: %v", err)
+	}
+	cfg.Transport = clientTransport
+	ts, err := mcptoolset.New(cfg)
+	if err != nil {
+		t.Fatalf("mcptoolset.New: %v", err)
+	}
+	return ts
+}
+
+// newToolContext returns a tool context for call "call-1" in a session
+// whose state persists across the contexts it returns.
+func newToolContext(t *testing.T, sess session.Session, confirmation *toolconfirmation.ToolConfirmation) (agent.Context, *session.EventActions) {
+	t.Helper()
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Session: sess})
+	actions := &session.EventActions{StateDelta: map[string]any{}}
+	return agent.NewToolContext(invCtx, "call-1", actions, confirmation), actions
+}
+
+func newSession(t *testing.T) session.Session {
+	t.Helper()
+	resp, err := session.InMemoryService().Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return resp.Session
+}
+
+func findTool(t *testing.T, ts tool.Toolset, ctx agent.ReadonlyContext, name string) tool.Tool {
+	t.Helper()
+	tools, err := ts.Tools(ctx)
+	if err != nil {
+		t.Fatalf("Tools: %v", err)
+	}
+	for _, tl := range tools {
+		if tl.Name() == name {
+			return tl
+		}
+	}
+	t.Fatalf("tool %q not found", name)
+	return nil
+}
+
+func TestResources(t *testing.T) {
+	var notes atomic.Value
+	notes.Store("buy milk")
+	var reads atomic.Int32
+	server := mcp.NewServer(&mcp.Implementation{Name: "notes", Version: "v1.0.0"}, &mcp.ServerOptions{
+		SubscribeHandler:   func(context.Context, *mcp.SubscribeRequest) error { return nil },
+		UnsubscribeHandler: func(context.Context, *mcp.UnsubscribeRequest) error { return nil },
+	})
+	server.AddResource(&mcp.Resource{URI: "notes://today", Name: "today", Description: "Today's notes", MIMEType: "text/plain"},
+		func(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			reads.Add(1)
+			return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{
+				{URI: req.Params.URI, MIMEType: "text/plain", Text: notes.Load().(string)},
+			}}, nil
+		})
+
+	updated := make(chan string, 1)
+	ts := connect(t, server, mcptoolset.Config{
+		LoadResourceTool:  true,
+		ContextResources:  []string{"notes://today"},
+		OnResourceUpdated: func(_ context.Context, uri string) { updated <- uri },
+	})
+	sess := newSession(t)
+	toolCtx, _ := newToolContext(t, sess, nil)
+
+	load := findTool(t, ts, toolCtx, mcptoolset.LoadResourceToolName)
+	if !strings.Contains(load.Description(), "notes://today (today): Today's notes") {
+		t.Errorf("load tool description = %q, want the resources listed", load.Description())
+	}
+	got, err := load.(toolinternal.FunctionTool).Run(toolCtx, map[string]any{"uri": "notes://today"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	contents, _ := got["contents"].([]map[string]any)
+	if len(contents) != 1 || contents[0]["text"] != "buy milk" || contents[0]["mime_type"] != "text/plain" {
+		t.Errorf("Run() = %v, want the contents of the resource", got)
+	}
+
+	inject := findTool(t, ts, toolCtx, "mcp_resource_context").(toolinternal.RequestProcessor)
+	instruction := func() string {
+		t.Helper()
+		req := &model.LLMRequest{}
+		if err := inject.ProcessRequest(toolCtx, req); err != nil {
+			t.Fatalf("ProcessRequest: %v", err)
+		}
+		if req.Config == nil || req.Config.SystemInstruction == nil {
+			return ""
+		}
+		return req.Config.SystemInstruction.Parts[0].Text
+	}
+	if got := instruction(); !strings.Contains(got, "buy milk") {
+		t.Errorf("instruction = %q, want the resource contents", got)
+	}
+	before := reads.Load()
+	if got := instruction(); !strings.Contains(got, "buy milk") || reads.Load() != before {
+		t.Errorf("instruction = %q after %d reads, want the cached contents", got, reads.Load()-before)
+	}
+
+	notes.Store("buy bread")
+	if err := server.ResourceUpdated(t.Context(), &mcp.ResourceUpdatedNotificationParams{URI: "notes://today"}); err != nil {
+		t.Fatalf("ResourceUpdated: %v", err)
+	}
+	select {
+	case uri := <-updated:
+		if uri != "notes://today" {
+			t.Errorf("OnResourceUpdated(%q), want notes://today", uri)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnResourceUpdated not called")
+	}
+	if got := instruction(); !strings.Contains(got, "buy bread") {
+		t.Errorf("instruction after update = %q, want the new contents", got)
+	}
+}
+
+func TestResources_ScopeAuth(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "notes", Version: "v1.0.0"}, &mcp.ServerOptions{
+		SubscribeHandler:   func(context.Context, *mcp.SubscribeRequest) error { return nil },
+		UnsubscribeHandler: func(context.Context, *mcp.UnsubscribeRequest) error { return nil },
+	})
+	// Every user reads their own notes, named by their token.
+	server.AddResource(&mcp.Resource{URI: "notes://today", Name: "today", MIMEType: "text/plain"},
+		func(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			token := strings.TrimPrefix(req.Extra.Header.Get("Authorization"), "Bearer ")
+			return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{
+				{URI: req.Params.URI, MIMEType: "text/plain", Text: "notes of " + token},
+			}}, nil
+		})
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+	httpServer := httptest.NewServer(handler)
+	defer httpServer.Close()
+	// The toolset keeps its connections open.
+	defer httpServer.CloseClientConnections()
+
+	ts, err := mcptoolset.New(mcptoolset.Config{
+		Transport:        &mcp.StreamableClientTransport{Endpoint: httpServer.URL},
+		ConnectionScope:  mcptoolset.ScopeByUser,
+		ScopeAuth:        func(scope string) auth.CredentialProvider { return auth.StaticToken(scope) },
+		ContextResources: []string{"notes://today"},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	instruction := func(userID string) string {
+		t.Helper()
+		resp, err := session.InMemoryService().Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: userID})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		ctx, _ := newToolContext(t, resp.Session, nil)
+		inject := findTool(t, ts, ctx, "mcp_resource_context").(toolinternal.RequestProcessor)
+		req := &model.LLMRequest{}
+		if err := inject.ProcessRequest(ctx, req); err != nil {
+			t.Fatalf("ProcessRequest: %v", err)
+		}
+		if req.Config == nil || req.Config.SystemInstruction == nil {
+			return ""
+		}
+		return req.Config.SystemInstruction.Parts[0].Text
+	}
+	// Each user's cached contents are only served to them.
+	for range 2 {
+		for _, user := range []string{"alice", "bob"} {
+			got := instruction(user)
+			if !strings.Contains(got, "notes of "+user) {
+				t.Errorf("instruction of %s = %q, want their own notes", user, got)
+			}
+		}
+	}
+}
+
+func TestInstructionProvider(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "prompts", Version: "v1.0.0"}, nil)
+	server.AddPrompt(&mcp.Prompt{Name: "review", Arguments: []*mcp.PromptArgument{{Name: "lang"}}},
+		func(_ context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return &mcp.GetPromptResult{Messages: []*mcp.PromptMessage{
+				{Role: "user", Content: &mcp.TextContent{Text: "You review " + req.Params.Arguments["lang"] + " code."}},
+				{Role: "user", Content: &mcp.EmbeddedResource{Resource: &mcp.ResourceContents{URI: "style://guide", Text: "Prefer short names."}}},
+			}}, nil
+		})
+	ts := connect(t, server, mcptoolset.Config{})
+
+	provider, err := mcptoolset.InstructionProvider(ts, "review", map[string]string{"lang": "Go"})
+	if err != nil {
+		t.Fatalf("InstructionProvider: %v", err)
+	}
+	ctx := icontext.NewReadonlyContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{}))
+	got, err := provider(ctx)
+	if err != nil {
+		t.Fatalf("provider: %v", err)
+	}
+	if want := "You review Go code.\n\nPrefer short names."; got != want {
+		t.Errorf("provider() = %q, want %q", got, want)
+	}
+
+	if _, err := mcptoolset.InstructionProvider(nil, "review", nil); err == nil {
+		t.Error("InstructionProvider() of a non-MCP toolset succeeded, want error")
+	}
+}
+
+func TestSampling(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "sampler", Version: "v1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "summarize"}, func(ctx context.Context, req *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
+		res, err := req.Session.CreateMessage(ctx, &mcp.CreateMessageParams{
+			SystemPrompt: "Be brief.",
+			MaxTokens:    10,
+			Messages:     []*mcp.SamplingMessage{{Role: "user", Content: &mcp.TextContent{Text: "Summarize MCP."}}},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{res.Content}}, nil, nil
+	})
+	llm := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("A protocol.", genai.RoleModel)}}
+	ts := connect(t, server, mcptoolset.Config{SamplingModel: llm})
+
+	toolCtx, _ := newToolContext(t, newSession(t), nil)
+	got, err := findTool(t, ts, toolCtx, "summarize").(toolinternal.FunctionTool).Run(toolCtx, map[string]any{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got["output"] != "A protocol." {
+		t.Errorf("Run() = %v, want the sampled message", got)
+	}
+	if len(llm.Requests) != 1 {
+		t.Fatalf("model got %d requests, want 1", len(llm.Requests))
+	}
+	req := llm.Requests[0]
+	if req.Config.SystemInstruction.Parts[0].Text != "Be brief." || req.Config.MaxOutputTokens != 10 || req.Contents[0].Parts[0].Text != "Summarize MCP." {
+		t.Errorf("model request = %+v, want the sampling request", req)
+	}
+}
+
+func TestElicitation(t *testing.T) {
+	var calls atomic.Int32
+	server := mcp.NewServer(&mcp.Implementation{Name: "greeter", Version: "v1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "greet"}, func(ctx context.Context, req *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
+		calls.Add(1)
+		res, err := req.Session.Elicit(ctx, &mcp.ElicitParams{
+			Message: "What is your name?",
+			RequestedSchema: &jsonschemaObject{Type: "object", Properties: map[string]any{
+				"name": map[string]any{"type": "string"},
+			}},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		text := "no name given"
+		if res.Action == "accept" {
+			text = "hello " + res.Content["name"].(string)
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil, nil
+	})
+
+	for _, tt := range []struct {
+		name         string
+		confirmation *toolconfirmation.ToolConfirmation
+		want         string
+	}{
+		{"accepted", &toolconfirmation.ToolConfirmation{Confirmed: true, Payload: map[string]any{"name": "ada"}}, "hello ada"},
+		{"declined", &toolconfirmation.ToolConfirmation{Confirmed: false}, "no name given"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := connect(t, server, mcptoolset.Config{Elicitation: true})
+			sess := newSession(t)
+
+			toolCtx, actions := newToolContext(t, sess, nil)
+			greet := findTool(t, ts, toolCtx, "greet").(toolinternal.FunctionTool)
+			if _, err := greet.Run(toolCtx, map[string]any{}); !errors.Is(err, tool.ErrConfirmationRequired) {
+				t.Fatalf("first Run() error = %v, want %v", err, tool.ErrConfirmationRequired)
+			}
+			requested := actions.RequestedToolConfirmations["call-1"]
+			if requested.Hint != "What is your name?" {
+				t.Errorf("requested confirmation hint = %q, want the server's message", requested.Hint)
+			}
+			if req, ok := requested.Payload.(*mcptoolset.ElicitationRequest); !ok || req.RequestedSchema == nil {
+				t.Errorf("requested confirmation payload = %#v, want an ElicitationRequest with a schema", requested.Payload)
+			}
+
+			toolCtx, _ = newToolContext(t, sess, tt.confirmation)
+			got, err := greet.Run(toolCtx, map[string]any{})
+			if err != nil {
+				t.Fatalf("second Run: %v", err)
+			}
+			if got["output"] != tt.want {
+				t.Errorf("second Run() = %v, want %q", got, tt.want)
+			}
+		})
+	}
+	if calls.Load() != 4 {
+		t.Errorf("server got %d calls, want each call made again once answered", calls.Load())
+	}
+}
+
+func TestElicitation_ParallelCalls(t *testing.T) {
+	var started, elicited sync.WaitGroup
+	started.Add(2)
+	elicited.Add(2)
+	server := mcp.NewServer(&mcp.Implementation{Name: "greeter", Version: "v1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "greet"}, func(ctx context.Context, req *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
+		// elicit while both calls are in flight
+		started.Done()
+		started.Wait()
+		res, err := req.Session.Elicit(ctx, &mcp.ElicitParams{
+			Message:         "What is your name?",
+			RequestedSchema: &jsonschemaObject{Type: "object"},
+		})
+		elicited.Done()
+		elicited.Wait()
+		if err != nil {
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: res.Action}}}, nil, nil
+	})
+
+	ts := connect(t, server, mcptoolset.Config{Elicitation: true})
+	sess := newSession(t)
+	toolCtx, _ := newToolContext(t, sess, nil)
+	greet := findTool(t, ts, toolCtx, "greet")
+	if !tool.ExecutionOptionsOf(greet).Sequential {
+		t.Error("tool answering elicitations isn't sequential")
+	}
+
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Go(func() {
+			toolCtx, actions := newToolContext(t, sess, nil)
+			got, err := greet.(toolinternal.FunctionTool).Run(toolCtx, map[string]any{})
+			if err != nil || len(actions.RequestedToolConfirmations) > 0 {
+				t.Errorf("Run() = %v, %v, confirmations %v, want the ambiguous elicitation cancelled", got, err, actions.RequestedToolConfirmations)
+			} else if got["output"] != "cancel" {
+				t.Errorf("Run() = %v, want the ambiguous elicitation cancelled", got)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+type jsonschemaObject struct {
+	Type       string         `json:"type"`
+	Properties map[string]any `json:"properties"`
+}
+
+func TestNew_HandlersRequireOwnClient(t *testing.T) {
+	client := mcp.NewClient(&mcp.Implementation{Name: "custom"}, nil)
+	if _, err := mcptoolset.New(mcptoolset.Config{Client: client, Elicitation: true}); err == nil {
+		t.Error("New() with a custom client and elicitation succeeded, want error")
+	}
+}
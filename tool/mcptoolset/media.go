@@ -0,0 +1,211 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+)
+
+// DefaultInlineLimit is the default MediaConfig.InlineLimit.
+const DefaultInlineLimit = 1 << 20
+
+// MediaConfig configures the conversion of the non-text content of the
+// results of MCP tools: images, audio and binary embedded resources.
+//
+// Content up to InlineLimit bytes is sent to the model as parts of the
+// function response, and larger content is saved as an artifact. The
+// "media" field of the function response describes every item, as a
+// MediaRef.
+type MediaConfig struct {
+	// InlineLimit is the size in bytes up to which content is inlined
+	// in the function response. Zero means DefaultInlineLimit; a
+	// negative limit saves all content as artifacts.
+	InlineLimit int
+	// AllowedMIMETypes are the MIME types of the content kept, either
+	// exact ("image/png") or with a wildcard subtype ("image/*"). If
+	// empty, all content is kept. Other content is omitted.
+	AllowedMIMETypes []string
+}
+
+// MediaRef describes an item of non-text content of an MCP tool result.
+type MediaRef struct {
+	MIMEType string `json:"mime_type"`
+	// URI is the URI of embedded resources.
+	URI string `json:"uri,omitempty"`
+	// Inline is set for content sent as a part of the function response.
+	Inline bool `json:"inline,omitempty"`
+	// Artifact and Version identify the artifact the content is saved
+	// as.
+	Artifact string `json:"artifact,omitempty"`
+	Version  int64  `json:"version,omitempty"`
+	// Omitted is the reason the content was dropped, if it was.
+	Omitted string `json:"omitted,omitempty"`
+}
+
+func (r MediaRef) String() string {
+	switch {
+	case r.Artifact != "":
+		return fmt.Sprintf("%s (artifact %s, version %d)", r.MIMEType, r.Artifact, r.Version)
+	case r.Omitted != "":
+		return fmt.Sprintf("%s (omitted: %s)", r.MIMEType, r.Omitted)
+	default:
+		return r.MIMEType
+	}
+}
+
+// ToolError is the error of an MCP tool call whose result is an error.
+// Function responses of failed calls carry no parts, so its non-text
+// content is saved as artifacts regardless of the inline limit.
+type ToolError struct {
+	// Details is the text content of the result.
+	Details string
+	// Media describes the non-text content of the result.
+	Media []MediaRef
+}
+
+func (e *ToolError) Error() string {
+	msg := "Tool execution failed."
+	if e.Details != "" {
+		msg += " Details: " + e.Details
+	}
+	if len(e.Media) > 0 {
+		refs := make([]string, len(e.Media))
+		for i, r := range e.Media {
+			refs[i] = r.String()
+		}
+		msg += " Attachments: " + strings.Join(refs, ", ")
+	}
+	return msg
+}
+
+// convertedContent is the content of a tool result converted for the
+// function response.
+type convertedContent struct {
+	text      strings.Builder
+	media     []MediaRef
+	resources []map[string]any
+	parts     []*genai.FunctionResponsePart
+}
+
+// convertContent converts content, the content of the result of tool
+// name. Non-text content is inlined, unless inline is false, or saved
+// as artifacts.
+func (cfg MediaConfig) convertContent(ctx agent.Context, name string, content []mcp.Content, inline bool) *convertedContent {
+	out := &convertedContent{}
+	for _, c := range content {
+		var (
+			data     []byte
+			mimeType string
+			uri      string
+		)
+		switch c := c.(type) {
+		case *mcp.TextContent:
+			out.text.WriteString(c.Text)
+			continue
+		case *mcp.ImageContent:
+			data, mimeType = c.Data, c.MIMEType
+		case *mcp.AudioContent:
+			data, mimeType = c.Data, c.MIMEType
+		case *mcp.ResourceLink:
+			out.resources = append(out.resources, resourceEntry(c.URI, c.Name, c.MIMEType, ""))
+			continue
+		case *mcp.EmbeddedResource:
+			if c.Resource == nil {
+				continue
+			}
+			if c.Resource.Blob == nil {
+				out.resources = append(out.resources, resourceEntry(c.Resource.URI, "", c.Resource.MIMEType, c.Resource.Text))
+				continue
+			}
+			data, mimeType, uri = c.Resource.Blob, c.Resource.MIMEType, c.Resource.URI
+		default:
+			continue
+		}
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+		ref := MediaRef{MIMEType: mimeType, URI: uri}
+		limit := cfg.InlineLimit
+		if limit == 0 {
+			limit = DefaultInlineLimit
+		}
+		switch {
+		case !cfg.allowed(mimeType):
+			ref.Omitted = "MIME type not allowed"
+		case inline && len(data) <= limit:
+			ref.Inline = true
+			out.parts = append(out.parts, genai.NewFunctionResponsePartFromBytes(data, mimeType))
+		default:
+			ref.Artifact = artifactName(ctx, name, len(out.media), mimeType)
+			resp, err := ctx.Artifacts().Save(ctx, ref.Artifact, genai.NewPartFromBytes(data, mimeType))
+			if err != nil {
+				ref.Artifact = ""
+				ref.Omitted = err.Error()
+				break
+			}
+			ref.Version = resp.Version
+		}
+		out.media = append(out.media, ref)
+	}
+	return out
+}
+
+// allowed reports whether content of mimeType is kept.
+func (cfg MediaConfig) allowed(mimeType string) bool {
+	if len(cfg.AllowedMIMETypes) == 0 {
+		return true
+	}
+	if mt, _, err := mime.ParseMediaType(mimeType); err == nil {
+		mimeType = mt
+	}
+	for _, pattern := range cfg.AllowedMIMETypes {
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(mimeType)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// artifactName names the artifact of the i-th item of non-text content
+// of the result of tool name, e.g. "screenshot_<call id>_0.png".
+func artifactName(ctx agent.Context, name string, i int, mimeType string) string {
+	var ext string
+	if exts, _ := mime.ExtensionsByType(mimeType); len(exts) > 0 {
+		ext = exts[0]
+	}
+	return fmt.Sprintf("%s_%s_%d%s", name, ctx.FunctionCallID(), i, ext)
+}
+
+func resourceEntry(uri, name, mimeType, text string) map[string]any {
+	entry := map[string]any{"uri": uri}
+	if name != "" {
+		entry["name"] = name
+	}
+	if mimeType != "" {
+		entry["mime_type"] = mimeType
+	}
+	if text != "" {
+		entry["text"] = text
+	}
+	return entry
+}
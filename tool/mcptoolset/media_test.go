@@ -0,0 +1,189 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/agent/llmagent"
+	"google.golang.org/adk/v2/artifact"
+	artifactinternal "google.golang.org/adk/v2/internal/artifact"
+	icontext "google.golang.org/adk/v2/internal/context"
+	"google.golang.org/adk/v2/internal/testutil"
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/mcptoolset"
+)
+
+var (
+	smallImage = []byte("png-data")
+	largeAudio = bytes.Repeat([]byte("a"), 64)
+)
+
+func mediaServer() *mcp.Server {
+	server := mcp.NewServer(&mcp.Implementation{Name: "media", Version: "v1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "capture"}, func(context.Context, *mcp.CallToolRequest, struct{}) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{
+			&mcp.TextContent{Text: "captured"},
+			&mcp.ImageContent{Data: smallImage, MIMEType: "image/png"},
+			&mcp.AudioContent{Data: largeAudio, MIMEType: "audio/wav"},
+			&mcp.EmbeddedResource{Resource: &mcp.ResourceContents{URI: "file:///clip.mp4", MIMEType: "video/mp4", Blob: []byte("mp4")}},
+			&mcp.EmbeddedResource{Resource: &mcp.ResourceContents{URI: "file:///log.txt", MIMEType: "text/plain", Text: "log"}},
+		}}, nil, nil
+	})
+	mcp.AddTool(server, &mcp.Tool{Name: "broken"}, func(context.Context, *mcp.CallToolRequest, struct{}) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{
+			&mcp.TextContent{Text: "render failed"},
+			&mcp.ImageContent{Data: smallImage, MIMEType: "image/png"},
+		}}, nil, nil
+	})
+	return server
+}
+
+var mediaConfig = mcptoolset.MediaConfig{InlineLimit: 16, AllowedMIMETypes: []string{"image/*", "audio/wav"}}
+
+// newArtifactToolContext returns a tool context for call "call-1" whose
+// artifacts are saved in artifacts.
+func newArtifactToolContext(t *testing.T, artifacts artifact.Service) (agent.Context, *session.EventActions) {
+	t.Helper()
+	sess := newSession(t)
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+		Session: sess,
+		Artifacts: &artifactinternal.Artifacts{
+			Service: artifacts, AppName: sess.AppName(), UserID: sess.UserID(), SessionID: sess.ID(),
+		},
+	})
+	actions := &session.EventActions{}
+	return agent.NewToolContext(invCtx, "call-1", actions, nil), actions
+}
+
+func TestMedia(t *testing.T) {
+	ts := connect(t, mediaServer(), mcptoolset.Config{Media: mediaConfig})
+	toolCtx, actions := newArtifactToolContext(t, artifact.InMemoryService())
+
+	got, err := findTool(t, ts, toolCtx, "capture").(toolinternal.FunctionTool).Run(toolCtx, map[string]any{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	parts := got[toolinternal.ResponsePartsKey]
+	delete(got, toolinternal.ResponsePartsKey)
+	want := map[string]any{
+		"output": "captured",
+		"media": []mcptoolset.MediaRef{
+			{MIMEType: "image/png", Inline: true},
+			{MIMEType: "audio/wav", Artifact: "capture_call-1_1.wav", Version: 1},
+			{MIMEType: "video/mp4", URI: "file:///clip.mp4", Omitted: "MIME type not allowed"},
+		},
+		"resources": []map[string]any{{"uri": "file:///log.txt", "mime_type": "text/plain", "text": "log"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	wantParts := []*genai.FunctionResponsePart{genai.NewFunctionResponsePartFromBytes(smallImage, "image/png")}
+	if diff := cmp.Diff(wantParts, parts); diff != "" {
+		t.Errorf("response parts mismatch (-want +got):\n%s", diff)
+	}
+	if _, ok := actions.ArtifactDelta["capture_call-1_1.wav"]; !ok {
+		t.Errorf("ArtifactDelta = %v, want the saved audio", actions.ArtifactDelta)
+	}
+	loaded, err := toolCtx.Artifacts().Load(toolCtx, "capture_call-1_1.wav")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !bytes.Equal(loaded.Part.InlineData.Data, largeAudio) {
+		t.Errorf("saved artifact = %q, want the audio", loaded.Part.InlineData.Data)
+	}
+}
+
+func TestMedia_ToolError(t *testing.T) {
+	ts := connect(t, mediaServer(), mcptoolset.Config{Media: mediaConfig})
+	toolCtx, actions := newArtifactToolContext(t, artifact.InMemoryService())
+
+	_, err := findTool(t, ts, toolCtx, "broken").(toolinternal.FunctionTool).Run(toolCtx, map[string]any{})
+	var toolErr *mcptoolset.ToolError
+	if !errors.As(err, &toolErr) {
+		t.Fatalf("Run() error = %v, want a *ToolError", err)
+	}
+	want := &mcptoolset.ToolError{
+		Details: "render failed",
+		Media:   []mcptoolset.MediaRef{{MIMEType: "image/png", Artifact: "broken_call-1_0.png", Version: 1}},
+	}
+	if diff := cmp.Diff(want, toolErr); diff != "" {
+		t.Errorf("Run() error mismatch (-want +got):\n%s", diff)
+	}
+	if !strings.Contains(err.Error(), "Details: render failed") || !strings.Contains(err.Error(), "broken_call-1_0.png") {
+		t.Errorf("Run() error = %q, want the details and the attachment", err)
+	}
+	if _, ok := actions.ArtifactDelta["broken_call-1_0.png"]; !ok {
+		t.Errorf("ArtifactDelta = %v, want the saved image", actions.ArtifactDelta)
+	}
+}
+
+func TestMedia_FunctionResponseParts(t *testing.T) {
+	ts := connect(t, mediaServer(), mcptoolset.Config{Media: mediaConfig})
+	llm := &testutil.MockModel{Responses: []*genai.Content{
+		{Role: genai.RoleModel, Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{ID: "call-1", Name: "capture", Args: map[string]any{}}}}},
+		genai.NewContentFromText("A screenshot.", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{Name: "agent", Model: llm, Toolsets: []tool.Toolset{ts}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := session.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: sessions, ArtifactService: artifact.InMemoryService()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessions.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "s"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var response *genai.FunctionResponse
+	for ev, err := range r.Run(t.Context(), "user", "s", genai.NewContentFromText("capture", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resps := ev.Content.Parts; len(resps) > 0 && resps[0].FunctionResponse != nil {
+			response = resps[0].FunctionResponse
+		}
+	}
+	if response == nil {
+		t.Fatal("no function response")
+	}
+	if _, ok := response.Response[toolinternal.ResponsePartsKey]; ok {
+		t.Errorf("function response %v holds the parts key", response.Response)
+	}
+	if len(response.Parts) != 1 || !bytes.Equal(response.Parts[0].InlineData.Data, smallImage) {
+		t.Errorf("function response parts = %v, want the image", response.Parts)
+	}
+	if len(llm.Requests) != 2 {
+		t.Fatalf("model got %d requests, want 2", len(llm.Requests))
+	}
+	last := llm.Requests[1].Contents[len(llm.Requests[1].Contents)-1]
+	if fr := last.Parts[0].FunctionResponse; fr == nil || len(fr.Parts) != 1 {
+		t.Errorf("model request ends with %v, want the function response with its parts", last.Parts[0])
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/v2/agent"
+)
+
+// ScopeByUser is a Config.ConnectionScope giving every user connections
+// of their own.
+func ScopeByUser(ctx agent.ReadonlyContext) string {
+	return ctx.UserID()
+}
+
+// connectionPool holds the connections to the MCP server, by scope:
+// calls whose contexts map to different scopes never share a
+// connection. Each scope has up to maxPerScope connections; calls use
+// the least busy one, and a new one is opened while all are busy.
+//
+// Connections unused for idleTimeout are closed, and idle connections
+// are pinged every healthCheckInterval and closed if the ping fails.
+// Either is disabled when zero.
+type connectionPool struct {
+	client *mcp.Client
+	// transport returns the transport of the connections of a scope.
+	transport func(scope string) (mcp.Transport, error)
+	scope     func(agent.ReadonlyContext) string
+	// onConnect is called with every new session of a scope; see
+	// connectionRefresher.
+	onConnect func(ctx context.Context, scope string, session *mcp.ClientSession)
+
+	maxPerScope         int
+	idleTimeout         time.Duration
+	healthCheckInterval time.Duration
+
+	mu       sync.Mutex
+	conns    map[string][]*pooledConn
+	sweeping bool
+}
+
+type pooledConn struct {
+	*connectionRefresher
+	inFlight    int
+	lastUsed    time.Time
+	lastChecked time.Time
+}
+
+func newConnectionPool(client *mcp.Client, transport func(string) (mcp.Transport, error)) *connectionPool {
+	return &connectionPool{
+		client:      client,
+		transport:   transport,
+		maxPerScope: 1,
+		conns:       map[string][]*pooledConn{},
+	}
+}
+
+// scopeOf returns the scope of the calls made with ctx. Contexts other
+// than ADK contexts are in the default scope, "".
+func (p *connectionPool) scopeOf(ctx context.Context) string {
+	if p.scope == nil {
+		return ""
+	}
+	rc, ok := ctx.(agent.ReadonlyContext)
+	if !ok {
+		return ""
+	}
+	return p.scope(rc)
+}
+
+// sessionScope returns the scope of the connection of session.
+func (p *connectionPool) sessionScope(session *mcp.ClientSession) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for scope, conns := range p.conns {
+		for _, c := range conns {
+			if c.hasSession(session) {
+				return scope, true
+			}
+		}
+	}
+	return "", false
+}
+
+// acquire returns a connection for a call made with ctx, to release
+// once the call is done.
+func (p *connectionPool) acquire(ctx context.Context) (*pooledConn, error) {
+	scope := p.scopeOf(ctx)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	conns := p.conns[scope]
+	var conn *pooledConn
+	for _, c := range conns {
+		if conn == nil || c.inFlight < conn.inFlight {
+			conn = c
+		}
+	}
+	if conn == nil || (conn.inFlight > 0 && len(conns) < p.maxPerScope) {
+		transport, err := p.transport(scope)
+		if err != nil {
+			return nil, err
+		}
+		refresher := newConnectionRefresher(p.client, transport)
+		if p.onConnect != nil {
+			refresher.onConnect = func(ctx context.Context, session *mcp.ClientSession) {
+				p.onConnect(ctx, scope, session)
+			}
+		}
+		conn = &pooledConn{connectionRefresher: refresher, lastChecked: time.Now()}
+		p.conns[scope] = append(conns, conn)
+		p.startSweeping()
+	}
+	conn.inFlight++
+	conn.lastUsed = time.Now()
+	return conn, nil
+}
+
+func (p *connectionPool) release(conn *pooledConn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	conn.inFlight--
+	conn.lastUsed = time.Now()
+}
+
+// startSweeping starts evicting idle and unhealthy connections, if
+// enabled, until the pool is empty. p.mu must be held.
+func (p *connectionPool) startSweeping() {
+	interval := p.idleTimeout
+	if p.healthCheckInterval > 0 && (interval <= 0 || p.healthCheckInterval < interval) {
+		interval = p.healthCheckInterval
+	}
+	if p.sweeping || interval <= 0 {
+		return
+	}
+	p.sweeping = true
+	go func() {
+		ticker := time.NewTicker(max(interval/2, time.Millisecond))
+		defer ticker.Stop()
+		for range ticker.C {
+			if !p.sweep() {
+				return
+			}
+		}
+	}()
+}
+
+// sweep closes the idle connections which expired or fail their health
+// check. It reports whether the pool still has connections, and stops
+// the sweeping otherwise.
+func (p *connectionPool) sweep() bool {
+	now := time.Now()
+	var idle, check, unhealthy []*pooledConn
+	p.mu.Lock()
+	for _, conns := range p.conns {
+		for _, c := range conns {
+			if c.inFlight > 0 {
+				continue
+			}
+			switch {
+			case p.idleTimeout > 0 && now.Sub(c.lastUsed) >= p.idleTimeout:
+				idle = append(idle, c)
+			case p.healthCheckInterval > 0 && now.Sub(c.lastChecked) >= p.healthCheckInterval:
+				// Keep the connection from being used or evicted
+				// meanwhile.
+				c.inFlight++
+				c.lastChecked = now
+				check = append(check, c)
+			}
+		}
+	}
+	p.mu.Unlock()
+
+	for _, c := range check {
+		ctx, cancel := context.WithTimeout(context.Background(), max(p.healthCheckInterval, time.Second))
+		err := c.ping(ctx)
+		cancel()
+		p.mu.Lock()
+		c.inFlight--
+		p.mu.Unlock()
+		if err != nil {
+			log.Printf("MCP connection failed its health check: %v", err)
+			unhealthy = append(unhealthy, c)
+		}
+	}
+
+	// Connections in use, or used since they were found idle, are kept.
+	var closed []*pooledConn
+	p.mu.Lock()
+	for scope, conns := range p.conns {
+		conns = slices.DeleteFunc(conns, func(c *pooledConn) bool {
+			expired := slices.Contains(idle, c) && time.Since(c.lastUsed) >= p.idleTimeout
+			if c.inFlight == 0 && (expired || slices.Contains(unhealthy, c)) {
+				closed = append(closed, c)
+				return true
+			}
+			return false
+		})
+		if len(conns) == 0 {
+			delete(p.conns, scope)
+		} else {
+			p.conns[scope] = conns
+		}
+	}
+	remaining := len(p.conns) > 0
+	if !remaining {
+		p.sweeping = false
+	}
+	p.mu.Unlock()
+
+	for _, c := range closed {
+		c.close()
+	}
+	return remaining
+}
+
+// withConn runs fn with a connection for a call made with ctx.
+func withConn[T any](ctx context.Context, p *connectionPool, fn func(*connectionRefresher) (T, error)) (T, error) {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	defer p.release(conn)
+	return fn(conn.connectionRefresher)
+}
+
+// CallTool implements MCPClient.
+func (p *connectionPool) CallTool(ctx context.Context, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	return withConn(ctx, p, func(c *connectionRefresher) (*mcp.CallToolResult, error) {
+		return c.CallTool(ctx, params)
+	})
+}
+
+// ListTools implements MCPClient.
+func (p *connectionPool) ListTools(ctx context.Context) ([]*mcp.Tool, error) {
+	return withConn(ctx, p, func(c *connectionRefresher) ([]*mcp.Tool, error) {
+		return c.ListTools(ctx)
+	})
+}
+
+func (p *connectionPool) ListResources(ctx context.Context) ([]*mcp.Resource, error) {
+	return withConn(ctx, p, func(c *connectionRefresher) ([]*mcp.Resource, error) {
+		return c.ListResources(ctx)
+	})
+}
+
+func (p *connectionPool) ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	return withConn(ctx, p, func(c *connectionRefresher) (*mcp.ReadResourceResult, error) {
+		return c.ReadResource(ctx, uri)
+	})
+}
+
+func (p *connectionPool) Subscribe(ctx context.Context, uri string) error {
+	_, err := withConn(ctx, p, func(c *connectionRefresher) (struct{}, error) {
+		return struct{}{}, c.Subscribe(ctx, uri)
+	})
+	return err
+}
+
+func (p *connectionPool) GetPrompt(ctx context.Context, name string, args map[string]string) (*mcp.GetPromptResult, error) {
+	return withConn(ctx, p, func(c *connectionRefresher) (*mcp.GetPromptResult, error) {
+		return c.GetPrompt(ctx, name, args)
+	})
+}
+
+var _ MCPClient = (*connectionPool)(nil)
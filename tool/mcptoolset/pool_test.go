@@ -0,0 +1,279 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/v2/agent"
+	icontext "google.golang.org/adk/v2/internal/context"
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/mcptoolset"
+)
+
+// countingServer returns a server with the get_weather tool, and the
+// number of tools/list requests it received.
+func countingServer() (*mcp.Server, *atomic.Int32) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test_server", Version: "v1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "get_weather", Description: "returns weather in the given city"}, weatherFunc)
+	var lists atomic.Int32
+	server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method == "tools/list" {
+				lists.Add(1)
+			}
+			return next(ctx, method, req)
+		}
+	})
+	return server, &lists
+}
+
+func userContext(t *testing.T, userID string) agent.ReadonlyContext {
+	t.Helper()
+	resp, err := session.InMemoryService().Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: userID})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return icontext.NewReadonlyContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Session: resp.Session}))
+}
+
+func toolNames(t *testing.T, ts tool.Toolset, ctx agent.ReadonlyContext) []string {
+	t.Helper()
+	tools, err := ts.Tools(ctx)
+	if err != nil {
+		t.Fatalf("Tools: %v", err)
+	}
+	var names []string
+	for _, tl := range tools {
+		names = append(names, tl.Name())
+	}
+	return names
+}
+
+func TestToolListCache(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		ttl       time.Duration
+		wantLists int32
+	}{
+		{name: "disabled by default", wantLists: 3},
+		{name: "cached", ttl: time.Minute, wantLists: 1},
+		{name: "expired", ttl: time.Nanosecond, wantLists: 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server, lists := countingServer()
+			ts, err := mcptoolset.New(mcptoolset.Config{
+				Transport:   &reconnectableTransport{server: server},
+				ToolListTTL: tc.ttl,
+			})
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			ctx := userContext(t, "user")
+			for range 3 {
+				toolNames(t, ts, ctx)
+			}
+			if got := lists.Load(); got != tc.wantLists {
+				t.Errorf("tools/list requests = %d, want %d", got, tc.wantLists)
+			}
+		})
+	}
+}
+
+func TestToolListCache_ListChanged(t *testing.T) {
+	server, _ := countingServer()
+	ts, err := mcptoolset.New(mcptoolset.Config{Transport: &reconnectableTransport{server: server}, ToolListTTL: time.Minute})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx := userContext(t, "user")
+	if got := toolNames(t, ts, ctx); !slices.Equal(got, []string{"get_weather"}) {
+		t.Fatalf("Tools = %v, want [get_weather]", got)
+	}
+
+	// The server notifies its tool list changed; the cache must be
+	// dropped.
+	mcp.AddTool(server, &mcp.Tool{Name: "get_time", Description: "returns the time"}, weatherFunc)
+	deadline := time.Now().Add(5 * time.Second)
+	for !slices.Contains(toolNames(t, ts, ctx), "get_time") {
+		if time.Now().After(deadline) {
+			t.Fatal("the tool list wasn't refreshed after the server's list_changed notification")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestToolListCache_Reconnect(t *testing.T) {
+	server, lists := countingServer()
+	spy := &spyTransport{Transport: &reconnectableTransport{server: server}}
+	ts, err := mcptoolset.New(mcptoolset.Config{Transport: spy, ToolListTTL: time.Minute})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{})
+	ctx := icontext.NewReadonlyContext(invCtx)
+	tools, err := ts.Tools(ctx)
+	if err != nil {
+		t.Fatalf("Tools: %v", err)
+	}
+
+	// A call reconnecting to the server drops the cached list.
+	if err := spy.lastConn.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := tools[0].(toolinternal.FunctionTool).Run(agent.NewToolContext(invCtx, "", nil, nil), map[string]any{"city": "Paris"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	toolNames(t, ts, ctx)
+	if got := lists.Load(); got != 2 {
+		t.Errorf("tools/list requests = %d, want 2", got)
+	}
+}
+
+func TestToolListCache_CustomClient(t *testing.T) {
+	server, _ := countingServer()
+	_, err := mcptoolset.New(mcptoolset.Config{
+		Transport:   &reconnectableTransport{server: server},
+		Client:      mcp.NewClient(&mcp.Implementation{Name: "client"}, nil),
+		ToolListTTL: time.Minute,
+	})
+	if err == nil {
+		t.Error("New() with Client and ToolListTTL succeeded, want error")
+	}
+}
+
+func TestConnectionScope(t *testing.T) {
+	server, lists := countingServer()
+	spy := &spyTransport{Transport: &reconnectableTransport{server: server}}
+	ts, err := mcptoolset.New(mcptoolset.Config{
+		Transport:       spy,
+		ConnectionScope: mcptoolset.ScopeByUser,
+		ToolListTTL:     time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	alice, bob := userContext(t, "alice"), userContext(t, "bob")
+	toolNames(t, ts, alice)
+	toolNames(t, ts, bob)
+	toolNames(t, ts, alice)
+	toolNames(t, ts, bob)
+
+	if spy.connectCount != 2 {
+		t.Errorf("connections = %d, want one per user, 2", spy.connectCount)
+	}
+	// The tool lists are cached by user.
+	if got := lists.Load(); got != 2 {
+		t.Errorf("tools/list requests = %d, want 2", got)
+	}
+}
+
+func TestIdleTimeout(t *testing.T) {
+	server, _ := countingServer()
+	spy := &spyTransport{Transport: &reconnectableTransport{server: server}}
+	ts, err := mcptoolset.New(mcptoolset.Config{
+		Transport:   spy,
+		IdleTimeout: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx := userContext(t, "user")
+	toolNames(t, ts, ctx)
+	toolNames(t, ts, ctx)
+	if spy.connectCount != 1 {
+		t.Fatalf("connections = %d, want 1", spy.connectCount)
+	}
+
+	// The idle connection is closed and reopened on demand.
+	deadline := time.Now().Add(5 * time.Second)
+	for countSessions(server) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the idle connection wasn't closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	toolNames(t, ts, ctx)
+	if spy.connectCount != 2 {
+		t.Errorf("connections = %d, want 2", spy.connectCount)
+	}
+}
+
+func countSessions(server *mcp.Server) int {
+	n := 0
+	for range server.Sessions() {
+		n++
+	}
+	return n
+}
+
+func TestScopeHeaders(t *testing.T) {
+	server, _ := countingServer()
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+	var (
+		mu    sync.Mutex
+		users = map[string]bool{}
+	)
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		users[r.Header.Get("X-User")] = true
+		mu.Unlock()
+		handler.ServeHTTP(w, r)
+	}))
+	defer httpServer.Close()
+	// The toolset keeps its connections open.
+	defer httpServer.CloseClientConnections()
+
+	ts, err := mcptoolset.New(mcptoolset.Config{
+		Transport:       &mcp.StreamableClientTransport{Endpoint: httpServer.URL},
+		ConnectionScope: mcptoolset.ScopeByUser,
+		ScopeHeaders: func(scope string) http.Header {
+			return http.Header{"X-User": {scope}}
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	toolNames(t, ts, userContext(t, "alice"))
+	toolNames(t, ts, userContext(t, "bob"))
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !users["alice"] || !users["bob"] || users[""] {
+		t.Errorf("X-User headers received = %v, want alice and bob only", users)
+	}
+}
+
+func TestScopeHeaders_RequiresStreamableTransport(t *testing.T) {
+	server, _ := countingServer()
+	_, err := mcptoolset.New(mcptoolset.Config{
+		Transport:    &reconnectableTransport{server: server},
+		ScopeHeaders: func(string) http.Header { return nil },
+	})
+	if err == nil {
+		t.Fatal("New succeeded, want an error for a non-HTTP transport")
+	}
+}
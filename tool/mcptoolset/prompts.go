@@ -0,0 +1,58 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/tool"
+)
+
+// InstructionProvider returns an instruction provider, for
+// llmagent.Config.InstructionProvider, rendering the prompt name of the
+// MCP server of ts, a toolset returned by New, with args. The
+// instruction is the text of the prompt's messages, including the text
+// of embedded resources, separated by blank lines.
+func InstructionProvider(ts tool.Toolset, name string, args map[string]string) (func(agent.ReadonlyContext) (string, error), error) {
+	s, ok := ts.(*set)
+	if !ok {
+		return nil, fmt.Errorf("mcptoolset: %T is not an MCP toolset", ts)
+	}
+	return func(ctx agent.ReadonlyContext) (string, error) {
+		result, err := s.conn.GetPrompt(ctx, name, args)
+		if err != nil {
+			return "", err
+		}
+		var texts []string
+		for _, m := range result.Messages {
+			if m == nil {
+				continue
+			}
+			switch c := m.Content.(type) {
+			case *mcp.TextContent:
+				texts = append(texts, c.Text)
+			case *mcp.EmbeddedResource:
+				if c.Resource != nil && c.Resource.Text != "" {
+					texts = append(texts, c.Resource.Text)
+				}
+			}
+		}
+		return strings.Join(texts, "\n\n"), nil
+	}, nil
+}
@@ -0,0 +1,301 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/internal/utils"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/tool/toolutils"
+)
+
+// LoadResourceToolName is the name of the tool loading MCP resources,
+// added by Config.LoadResourceTool.
+const LoadResourceToolName = "load_mcp_resource"
+
+// resources reads MCP resources, caching the contents of the resources
+// it is subscribed to until the server notifies that they changed. The
+// subscriptions and the cache are kept by connection scope, as the
+// contents of a resource may differ between the users of the scopes.
+type resources struct {
+	conn *connectionPool
+	// onUpdated is Config.OnResourceUpdated.
+	onUpdated func(ctx context.Context, uri string)
+
+	mu         sync.Mutex
+	subscribed map[resourceKey]bool
+	cache      map[resourceKey]*mcp.ReadResourceResult
+}
+
+// resourceKey identifies a resource read in a connection scope.
+type resourceKey struct {
+	scope string
+	uri   string
+}
+
+func newResources(conn *connectionPool, onUpdated func(context.Context, string)) *resources {
+	return &resources{
+		conn:       conn,
+		onUpdated:  onUpdated,
+		subscribed: map[resourceKey]bool{},
+		cache:      map[resourceKey]*mcp.ReadResourceResult{},
+	}
+}
+
+// read returns the contents of the resource uri, from the cache of the
+// scope of ctx if the scope is subscribed to it.
+func (r *resources) read(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	key := resourceKey{scope: r.conn.scopeOf(ctx), uri: uri}
+	r.mu.Lock()
+	cached, ok := r.cache[key]
+	r.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+	result, err := r.conn.ReadResource(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	if r.subscribed[key] {
+		r.cache[key] = result
+	}
+	r.mu.Unlock()
+	return result, nil
+}
+
+// subscribe subscribes the scope of ctx to the updates of the resource
+// uri, unless it already is. Resources of servers which don't support
+// subscriptions are read again on every use.
+func (r *resources) subscribe(ctx context.Context, uri string) {
+	key := resourceKey{scope: r.conn.scopeOf(ctx), uri: uri}
+	r.mu.Lock()
+	_, tried := r.subscribed[key]
+	r.mu.Unlock()
+	if tried {
+		return
+	}
+	err := r.conn.Subscribe(ctx, uri)
+	r.mu.Lock()
+	r.subscribed[key] = err == nil
+	r.mu.Unlock()
+}
+
+// updated handles the notification that the resource uri changed. The
+// cached contents of the scope of the notifying session are dropped, or
+// those of every scope if the session is no longer in the pool.
+func (r *resources) updated(ctx context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+	uri := req.Params.URI
+	scope, ok := r.conn.sessionScope(req.Session)
+	r.mu.Lock()
+	for key := range r.cache {
+		if key.uri == uri && (!ok || key.scope == scope) {
+			delete(r.cache, key)
+		}
+	}
+	r.mu.Unlock()
+	if r.onUpdated != nil {
+		r.onUpdated(ctx, uri)
+	}
+}
+
+// resubscribe renews the subscriptions of scope on session, a new
+// session of the scope, and drops the cache of the scope since updates
+// may have been missed.
+func (r *resources) resubscribe(ctx context.Context, scope string, session *mcp.ClientSession) {
+	r.mu.Lock()
+	var uris []string
+	for key, ok := range r.subscribed {
+		if key.scope != scope {
+			continue
+		}
+		if ok {
+			uris = append(uris, key.uri)
+		}
+		delete(r.cache, key)
+	}
+	r.mu.Unlock()
+	for _, uri := range uris {
+		if err := session.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}); err != nil {
+			log.Printf("failed to renew the subscription to MCP resource %q: %v", uri, err)
+			r.mu.Lock()
+			r.subscribed[resourceKey{scope: scope, uri: uri}] = false
+			r.mu.Unlock()
+		}
+	}
+}
+
+// resourceContents converts the contents of a resource to the response
+// of a tool: text as is, and binary contents base64-encoded.
+func resourceContents(result *mcp.ReadResourceResult) []map[string]any {
+	var contents []map[string]any
+	for _, c := range result.Contents {
+		if c == nil {
+			continue
+		}
+		content := map[string]any{"uri": c.URI}
+		if c.MIMEType != "" {
+			content["mime_type"] = c.MIMEType
+		}
+		if c.Blob != nil {
+			content["blob"] = base64.StdEncoding.EncodeToString(c.Blob)
+		} else {
+			content["text"] = c.Text
+		}
+		contents = append(contents, content)
+	}
+	return contents
+}
+
+// loadResourceTool lets the model load the resources of the server,
+// which its description lists.
+type loadResourceTool struct {
+	resources   *resources
+	description string
+}
+
+func newLoadResourceTool(ctx context.Context, r *resources) (*loadResourceTool, error) {
+	list, err := r.conn.ListResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	b.WriteString("Loads the contents of a resource of the MCP server by URI.")
+	if len(list) > 0 {
+		b.WriteString(" Available resources:")
+		for _, res := range list {
+			fmt.Fprintf(&b, "\n- %s (%s)", res.URI, res.Name)
+			if res.Description != "" {
+				fmt.Fprintf(&b, ": %s", res.Description)
+			}
+		}
+	}
+	return &loadResourceTool{resources: r, description: b.String()}, nil
+}
+
+// Name implements tool.Tool.
+func (t *loadResourceTool) Name() string {
+	return LoadResourceToolName
+}
+
+// Description implements tool.Tool.
+func (t *loadResourceTool) Description() string {
+	return t.description
+}
+
+// IsLongRunning implements tool.Tool.
+func (t *loadResourceTool) IsLongRunning() bool {
+	return false
+}
+
+func (t *loadResourceTool) ProcessRequest(ctx agent.Context, req *model.LLMRequest) error {
+	return toolutils.PackTool(req, t)
+}
+
+func (t *loadResourceTool) Declaration() *genai.FunctionDeclaration {
+	return &genai.FunctionDeclaration{
+		Name:        LoadResourceToolName,
+		Description: t.description,
+		Parameters: &genai.Schema{
+			Type: genai.TypeObject,
+			Properties: map[string]*genai.Schema{
+				"uri": {Type: genai.TypeString, Description: "The URI of the resource."},
+			},
+			Required: []string{"uri"},
+		},
+	}
+}
+
+func (t *loadResourceTool) Run(ctx agent.Context, args any) (map[string]any, error) {
+	m, _ := args.(map[string]any)
+	uri, _ := m["uri"].(string)
+	if uri == "" {
+		return nil, fmt.Errorf("tool %q: missing argument uri", LoadResourceToolName)
+	}
+	result, err := t.resources.read(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"contents": resourceContents(result)}, nil
+}
+
+const resourceContextInstructions = `The following are the contents of resources of an MCP server.
+<MCP_RESOURCES>
+%s
+</MCP_RESOURCES>`
+
+// resourceContextTool injects the contents of resources into the
+// system instructions of every LLM request. It is not called by the
+// model.
+type resourceContextTool struct {
+	resources *resources
+	uris      []string
+}
+
+// Name implements tool.Tool.
+func (t *resourceContextTool) Name() string {
+	return "mcp_resource_context"
+}
+
+// Description implements tool.Tool.
+func (t *resourceContextTool) Description() string {
+	return "Injects the contents of MCP resources into the instructions."
+}
+
+// IsLongRunning implements tool.Tool.
+func (t *resourceContextTool) IsLongRunning() bool {
+	return false
+}
+
+// ProcessRequest reads, or takes from the cache, the contents of the
+// resources and appends their text to the system instructions. Binary
+// contents are left out.
+func (t *resourceContextTool) ProcessRequest(ctx agent.Context, req *model.LLMRequest) error {
+	var b strings.Builder
+	for _, uri := range t.uris {
+		t.resources.subscribe(ctx, uri)
+		result, err := t.resources.read(ctx, uri)
+		if err != nil {
+			return err
+		}
+		for _, c := range result.Contents {
+			if c == nil || c.Blob != nil || c.Text == "" {
+				continue
+			}
+			fmt.Fprintf(&b, "<resource uri=%q>\n%s\n</resource>\n", c.URI, c.Text)
+		}
+	}
+	if b.Len() > 0 {
+		utils.AppendInstructions(req, fmt.Sprintf(resourceContextInstructions, strings.TrimSuffix(b.String(), "\n")))
+	}
+	return nil
+}
+
+var (
+	_ toolinternal.FunctionTool     = (*loadResourceTool)(nil)
+	_ toolinternal.RequestProcessor = (*loadResourceTool)(nil)
+	_ toolinternal.RequestProcessor = (*resourceContextTool)(nil)
+)
@@ -0,0 +1,102 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/model"
+)
+
+// samplingHandler returns the handler of the sampling/createMessage
+// requests of the server, generating the messages with llm. The
+// server's model preferences are ignored.
+func samplingHandler(llm model.LLM) func(context.Context, *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	return func(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+		params := req.Params
+		llmReq := &model.LLMRequest{Model: llm.Name(), Config: &genai.GenerateContentConfig{}}
+		for _, m := range params.Messages {
+			if m == nil {
+				continue
+			}
+			part, err := samplingPart(m.Content)
+			if err != nil {
+				return nil, err
+			}
+			role := genai.RoleUser
+			if m.Role == "assistant" {
+				role = genai.RoleModel
+			}
+			llmReq.Contents = append(llmReq.Contents, &genai.Content{Role: role, Parts: []*genai.Part{part}})
+		}
+		if params.SystemPrompt != "" {
+			llmReq.Config.SystemInstruction = genai.NewContentFromText(params.SystemPrompt, genai.RoleUser)
+		}
+		if params.MaxTokens > 0 {
+			llmReq.Config.MaxOutputTokens = int32(params.MaxTokens)
+		}
+		if params.Temperature != 0 {
+			llmReq.Config.Temperature = genai.Ptr(float32(params.Temperature))
+		}
+		llmReq.Config.StopSequences = params.StopSequences
+
+		var text strings.Builder
+		stopReason := "endTurn"
+		for resp, err := range llm.GenerateContent(ctx, llmReq, false) {
+			if err != nil {
+				return nil, fmt.Errorf("mcptoolset: sampling: %w", err)
+			}
+			if resp == nil || resp.Partial {
+				continue
+			}
+			if resp.FinishReason == genai.FinishReasonMaxTokens {
+				stopReason = "maxTokens"
+			}
+			if resp.Content == nil {
+				continue
+			}
+			for _, p := range resp.Content.Parts {
+				if p != nil && !p.Thought {
+					text.WriteString(p.Text)
+				}
+			}
+		}
+		return &mcp.CreateMessageResult{
+			Content:    &mcp.TextContent{Text: text.String()},
+			Model:      llm.Name(),
+			Role:       "assistant",
+			StopReason: stopReason,
+		}, nil
+	}
+}
+
+// samplingPart converts the content of a sampling message to a part.
+func samplingPart(c mcp.Content) (*genai.Part, error) {
+	switch c := c.(type) {
+	case *mcp.TextContent:
+		return genai.NewPartFromText(c.Text), nil
+	case *mcp.ImageContent:
+		return genai.NewPartFromBytes(c.Data, c.MIMEType), nil
+	case *mcp.AudioContent:
+		return genai.NewPartFromBytes(c.Data, c.MIMEType), nil
+	default:
+		return nil, fmt.Errorf("mcptoolset: sampling: unsupported message content %T", c)
+	}
+}
@@ -16,13 +16,17 @@
 package mcptoolset
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"google.golang.org/adk/v2/agent"
 	"google.golang.org/adk/v2/auth"
+	"google.golang.org/adk/v2/internal/version"
+	"google.golang.org/adk/v2/model"
 	"google.golang.org/adk/v2/tool"
 )
 
@@ -53,12 +57,67 @@ func New(cfg Config) (tool.Toolset, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &set{
-		mcpClient:                   newConnectionRefresher(cfg.Client, transport),
+	if cfg.ScopeAuth != nil || cfg.ScopeHeaders != nil {
+		if _, ok := transport.(*mcp.StreamableClientTransport); !ok {
+			return nil, fmt.Errorf("mcptoolset: Config.ScopeAuth and ScopeHeaders require a streamable HTTP transport; "+
+				"set Config.Endpoint or pass a *mcp.StreamableClientTransport (got %T)", transport)
+		}
+	}
+	needsHandlers := cfg.SamplingModel != nil || cfg.Elicitation || len(cfg.ContextResources) > 0 || cfg.OnResourceUpdated != nil || cfg.ToolListTTL > 0
+	if cfg.Client != nil && needsHandlers {
+		return nil, fmt.Errorf("mcptoolset: Config.SamplingModel, Elicitation, ContextResources, OnResourceUpdated and ToolListTTL " +
+			"require the MCP client created by the toolset; don't set Config.Client")
+	}
+
+	s := &set{
 		toolFilter:                  cfg.ToolFilter,
 		requireConfirmation:         cfg.RequireConfirmation,
 		requireConfirmationProvider: cfg.RequireConfirmationProvider,
-	}, nil
+		loadResourceTool:            cfg.LoadResourceTool,
+		contextResources:            cfg.ContextResources,
+		media:                       cfg.Media,
+		toolCache:                   newToolListCache(cfg.ToolListTTL),
+	}
+	client := cfg.Client
+	if client == nil {
+		opts := &mcp.ClientOptions{
+			ToolListChangedHandler: func(context.Context, *mcp.ToolListChangedRequest) {
+				s.toolCache.invalidate()
+			},
+			// The resources are created below, once the connections are.
+			ResourceUpdatedHandler: func(ctx context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+				s.resources.updated(ctx, req)
+			},
+		}
+		if cfg.SamplingModel != nil {
+			opts.CreateMessageHandler = samplingHandler(cfg.SamplingModel)
+		}
+		if cfg.Elicitation {
+			s.elicitations = &elicitations{}
+			opts.ElicitationHandler = s.elicitations.handle
+		}
+		client = mcp.NewClient(&mcp.Implementation{Name: "adk-mcp-client", Version: version.Version}, opts)
+	}
+	s.conn = newConnectionPool(client, func(scope string) (mcp.Transport, error) {
+		return scopeTransport(cfg, transport, scope), nil
+	})
+	s.conn.scope = cfg.ConnectionScope
+	if cfg.MaxConnectionsPerScope > 0 {
+		s.conn.maxPerScope = cfg.MaxConnectionsPerScope
+	}
+	s.conn.idleTimeout = cfg.IdleTimeout
+	s.conn.healthCheckInterval = cfg.HealthCheckInterval
+	s.mcpClient = s.conn
+	s.resources = newResources(s.conn, cfg.OnResourceUpdated)
+	s.conn.onConnect = func(ctx context.Context, scope string, session *mcp.ClientSession) {
+		s.toolCache.reset(scope)
+		s.resources.resubscribe(ctx, scope, session)
+	}
+	if s.elicitations != nil {
+		s.elicitations.scope = s.conn.scopeOf
+		s.elicitations.sessionScope = s.conn.sessionScope
+	}
+	return s, nil
 }
 
 // buildTransport resolves the MCP transport from cfg. When Transport is nil and
@@ -83,6 +142,51 @@ func buildTransport(cfg Config) (mcp.Transport, error) {
 	return &stCopy, nil
 }
 
+// scopeTransport returns the transport of the connections of scope:
+// base, with the credentials and headers of the scope, if any, applied
+// to its HTTP requests. New checks that base is a streamable HTTP
+// transport if they are set.
+func scopeTransport(cfg Config, base mcp.Transport, scope string) mcp.Transport {
+	if cfg.ScopeAuth == nil && cfg.ScopeHeaders == nil {
+		return base
+	}
+	st := *base.(*mcp.StreamableClientTransport)
+	if cfg.ScopeHeaders != nil {
+		if header := cfg.ScopeHeaders(scope); len(header) > 0 {
+			c := &http.Client{}
+			if st.HTTPClient != nil {
+				*c = *st.HTTPClient
+			}
+			c.Transport = &headerTransport{header: header, base: c.Transport}
+			st.HTTPClient = c
+		}
+	}
+	if cfg.ScopeAuth != nil {
+		if provider := cfg.ScopeAuth(scope); provider != nil {
+			st.HTTPClient = authHTTPClient(st.HTTPClient, provider)
+		}
+	}
+	return &st
+}
+
+// headerTransport sets headers on the requests it sends.
+type headerTransport struct {
+	header http.Header
+	base   http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range t.header {
+		req.Header[k] = v
+	}
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
 // authHTTPClient returns a shallow copy of base whose Transport applies provider
 // to every request. base may be nil.
 func authHTTPClient(base *http.Client, provider auth.CredentialProvider) *http.Client {
@@ -139,6 +243,76 @@ type Config struct {
 	// func(name string, toolInput any) bool
 	// Returning true means confirmation is required.
 	RequireConfirmationProvider tool.ConfirmationProvider
+
+	// LoadResourceTool adds to the tools of the set a tool, named
+	// LoadResourceToolName, with which the model loads the resources of
+	// the server by URI. Its description lists the resources.
+	LoadResourceTool bool
+
+	// ContextResources are the URIs of resources of the server whose
+	// text contents are injected into the system instructions of every
+	// LLM request. The set subscribes to their updates and caches them
+	// meanwhile; resources of servers without subscriptions are read
+	// for every request.
+	ContextResources []string
+
+	// OnResourceUpdated, if set, is called when the server notifies that
+	// a resource the set is subscribed to changed.
+	OnResourceUpdated func(ctx context.Context, uri string)
+
+	// SamplingModel, if set, handles the sampling requests of the
+	// server (sampling/createMessage): their messages are generated by
+	// the model.
+	SamplingModel model.LLM
+
+	// Elicitation maps the requests of the server for user input during
+	// tool calls (elicitation/create) onto tool confirmations; see
+	// ElicitationRequest. The tools then run sequentially, and the
+	// elicitations made while several calls are in flight on the
+	// connection are cancelled. Without it, the client doesn't support
+	// elicitation.
+	Elicitation bool
+
+	// ToolListTTL, if positive, is how long the tool list of the server
+	// is cached; by default it's fetched on every call of Tools. The
+	// cache is also dropped when the server notifies that its tool list
+	// changed, and on reconnections. It can't be set with Client.
+	ToolListTTL time.Duration
+
+	// ConnectionScope, if set, partitions the connections to the
+	// server: calls whose contexts map to different scopes never share
+	// a connection, and tool lists are cached by scope. ScopeByUser
+	// gives every user connections of their own. By default, all calls
+	// share the same connections.
+	ConnectionScope func(ctx agent.ReadonlyContext) string
+
+	// ScopeAuth, if set, returns the credential provider of the
+	// connections of a scope, e.g. the credentials of the user for
+	// ScopeByUser, so that one toolset serves many tenants. It is
+	// applied after Auth, and requires a streamable HTTP transport.
+	ScopeAuth func(scope string) auth.CredentialProvider
+
+	// ScopeHeaders, if set, returns headers set on the HTTP requests of
+	// the connections of a scope. It requires a streamable HTTP
+	// transport.
+	ScopeHeaders func(scope string) http.Header
+
+	// MaxConnectionsPerScope is the number of connections of a scope
+	// opened while all of them are busy with calls. Defaults to 1.
+	MaxConnectionsPerScope int
+
+	// IdleTimeout, if set, closes the connections which haven't been
+	// used for that long. They are reopened on demand.
+	IdleTimeout time.Duration
+
+	// HealthCheckInterval, if set, pings the idle connections at that
+	// interval, and closes those which don't respond.
+	HealthCheckInterval time.Duration
+
+	// Media configures the conversion of the images, audio and binary
+	// embedded resources in the results of the tools: inlined into the
+	// function response or saved as artifacts.
+	Media MediaConfig
 }
 
 type set struct {
@@ -146,6 +320,14 @@ type set struct {
 	toolFilter                  tool.Predicate
 	requireConfirmation         bool
 	requireConfirmationProvider tool.ConfirmationProvider
+
+	conn             *connectionPool
+	resources        *resources
+	elicitations     *elicitations
+	loadResourceTool bool
+	contextResources []string
+	media            MediaConfig
+	toolCache        *toolListCache
 }
 
 func (*set) Name() string {
@@ -162,14 +344,20 @@ func (*set) IsLongRunning() bool {
 
 // Tools fetch MCP tools from the server, convert to adk tool.Tool and filter by name.
 func (s *set) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
-	mcpTools, err := s.mcpClient.ListTools(ctx)
-	if err != nil {
-		return nil, err
+	scope := s.conn.scopeOf(ctx)
+	mcpTools, ok := s.toolCache.get(scope)
+	if !ok {
+		generation := s.toolCache.generation()
+		var err error
+		if mcpTools, err = s.mcpClient.ListTools(ctx); err != nil {
+			return nil, err
+		}
+		s.toolCache.put(scope, generation, mcpTools)
 	}
 
 	var adkTools []tool.Tool
 	for _, mcpTool := range mcpTools {
-		t, err := convertTool(mcpTool, s.mcpClient, s.requireConfirmation, s.requireConfirmationProvider)
+		t, err := convertTool(mcpTool, s.mcpClient, s.requireConfirmation, s.requireConfirmationProvider, s.elicitations, s.media)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert MCP tool %q to adk tool: %w", mcpTool.Name, err)
 		}
@@ -181,5 +369,22 @@ func (s *set) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
 		adkTools = append(adkTools, t)
 	}
 
+	var resourceTools []tool.Tool
+	if s.loadResourceTool {
+		t, err := newLoadResourceTool(ctx, s.resources)
+		if err != nil {
+			return nil, err
+		}
+		resourceTools = append(resourceTools, t)
+	}
+	if len(s.contextResources) > 0 {
+		resourceTools = append(resourceTools, &resourceContextTool{resources: s.resources, uris: s.contextResources})
+	}
+	for _, t := range resourceTools {
+		if s.toolFilter == nil || s.toolFilter(ctx, t) {
+			adkTools = append(adkTools, t)
+		}
+	}
+
 	return adkTools, nil
 }
@@ -17,7 +17,6 @@ package mcptoolset
 import (
 	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"google.golang.org/genai"
@@ -29,7 +28,7 @@ import (
 	"google.golang.org/adk/v2/tool/toolutils"
 )
 
-func convertTool(t *mcp.Tool, client MCPClient, requireConfirmation bool, requireConfirmationProvider tool.ConfirmationProvider) (tool.Tool, error) {
+func convertTool(t *mcp.Tool, client MCPClient, requireConfirmation bool, requireConfirmationProvider tool.ConfirmationProvider, elicitations *elicitations, media MediaConfig) (tool.Tool, error) {
 	mcp := &mcpTool{
 		name:        t.Name,
 		description: t.Description,
@@ -40,6 +39,8 @@ func convertTool(t *mcp.Tool, client MCPClient, requireConfirmation bool, requir
 		mcpClient:                   client,
 		requireConfirmation:         requireConfirmation,
 		requireConfirmationProvider: requireConfirmationProvider,
+		elicitations:                elicitations,
+		media:                       media,
 	}
 
 	// Since t.InputSchema and t.OutputSchema are pointers (*jsonschema.Schema) and the destination ResponseJsonSchema
@@ -66,6 +67,12 @@ type mcpTool struct {
 	requireConfirmation bool
 
 	requireConfirmationProvider tool.ConfirmationProvider
+
+	// elicitations is set when the server's elicitations are mapped
+	// onto tool confirmations; see ElicitationRequest.
+	elicitations *elicitations
+
+	media MediaConfig
 }
 
 // Name implements the tool.Tool.
@@ -83,6 +90,19 @@ func (t *mcpTool) IsLongRunning() bool {
 	return false
 }
 
+// RequiresConfirmation reports whether the calls of the tool may ask for a
+// tool confirmation, to approve the call or answer an elicitation.
+func (t *mcpTool) RequiresConfirmation() bool {
+	return t.requireConfirmation || t.requireConfirmationProvider != nil || t.elicitations != nil
+}
+
+// ExecutionOptions implements tool.ExecutionOptionsProvider. The calls
+// of the tools answering elicitations run alone, as an elicitation can
+// only be attributed to a call while no other is in flight.
+func (t *mcpTool) ExecutionOptions() tool.ExecutionOptions {
+	return tool.ExecutionOptions{Sequential: t.elicitations != nil}
+}
+
 func (t *mcpTool) ProcessRequest(ctx agent.Context, req *model.LLMRequest) error {
 	return toolutils.PackTool(req, t)
 }
@@ -92,7 +112,22 @@ func (t *mcpTool) Declaration() *genai.FunctionDeclaration {
 }
 
 func (t *mcpTool) Run(ctx agent.Context, args any) (map[string]any, error) {
-	if confirmation := ctx.ToolConfirmation(); confirmation != nil {
+	var rec elicitationRecord
+	if t.elicitations != nil {
+		var err error
+		if rec, err = loadElicitationRecord(ctx); err != nil {
+			return nil, fmt.Errorf("tool %q: %w", t.Name(), err)
+		}
+	}
+
+	if confirmation := ctx.ToolConfirmation(); confirmation != nil && rec.Pending != nil {
+		// The confirmation answers an elicitation of a previous run;
+		// the call itself was confirmed, if needed, before it.
+		rec.answer(confirmation)
+		if err := rec.save(ctx); err != nil {
+			return nil, fmt.Errorf("tool %q: %w", t.Name(), err)
+		}
+	} else if confirmation != nil {
 		if !confirmation.Confirmed {
 			return nil, fmt.Errorf("error tool %q %w", t.Name(), tool.ErrConfirmationRejected)
 		}
@@ -117,63 +152,69 @@ func (t *mcpTool) Run(ctx agent.Context, args any) (map[string]any, error) {
 		}
 	}
 
+	var call *elicitationCall
+	if t.elicitations != nil {
+		var end func()
+		call, end = t.elicitations.begin(ctx, rec.Answers)
+		defer end()
+	}
 	res, err := t.mcpClient.CallTool(ctx, &mcp.CallToolParams{
 		Name:      t.name,
 		Arguments: args,
 	})
+	if pending := t.pendingElicitation(call); pending != nil {
+		// The server asked for input the user has yet to give: ask
+		// for it, and make the call again once it is given.
+		rec.Pending = pending
+		if err := rec.save(ctx); err != nil {
+			return nil, fmt.Errorf("tool %q: %w", t.Name(), err)
+		}
+		if err := ctx.RequestConfirmation(rec.Pending.Message, rec.Pending); err != nil {
+			return nil, err
+		}
+		ctx.Actions().SkipSummarization = true
+		return nil, fmt.Errorf("error tool %q %w", t.Name(), tool.ErrConfirmationRequired)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to call MCP tool %q with err: %w", t.name, err)
 	}
 
 	if res.IsError {
-		details := strings.Builder{}
-		for _, c := range res.Content {
-			textContent, ok := c.(*mcp.TextContent)
-			if !ok {
-				continue
-			}
-			if _, err := details.WriteString(textContent.Text); err != nil {
-				return nil, fmt.Errorf("failed to write error details: %w", err)
-			}
-		}
-
-		errMsg := "Tool execution failed."
-		if details.Len() > 0 {
-			errMsg += " Details: " + details.String()
-		}
-
-		return nil, errors.New(errMsg)
+		content := t.media.convertContent(ctx, t.name, res.Content, false)
+		return nil, &ToolError{Details: content.text.String(), Media: content.media}
 	}
 
+	content := t.media.convertContent(ctx, t.name, res.Content, true)
+	result := map[string]any{}
 	if res.StructuredContent != nil {
-		return map[string]any{
-			"output": res.StructuredContent,
-		}, nil
+		result["output"] = res.StructuredContent
+	} else if content.text.Len() > 0 {
+		result["output"] = content.text.String()
 	}
-
-	textResponse := strings.Builder{}
-
-	for _, c := range res.Content {
-		textContent, ok := c.(*mcp.TextContent)
-		if !ok {
-			continue
-		}
-
-		if _, err := textResponse.WriteString(textContent.Text); err != nil {
-			return nil, fmt.Errorf("failed to write text response: %w", err)
-		}
+	if len(content.resources) > 0 {
+		result["resources"] = content.resources
 	}
-
-	if textResponse.Len() == 0 {
-		return nil, errors.New("no text content in tool response")
+	if len(content.media) > 0 {
+		result["media"] = content.media
+	}
+	if len(content.parts) > 0 {
+		result[toolinternal.ResponsePartsKey] = content.parts
 	}
+	if len(result) == 0 {
+		return nil, errors.New("no content in tool response")
+	}
+	return result, nil
+}
 
-	return map[string]any{
-		"output": textResponse.String(),
-	}, nil
+func (t *mcpTool) pendingElicitation(call *elicitationCall) *ElicitationRequest {
+	if call == nil {
+		return nil
+	}
+	return t.elicitations.pending(call)
 }
 
 var (
 	_ toolinternal.FunctionTool     = (*mcpTool)(nil)
 	_ toolinternal.RequestProcessor = (*mcpTool)(nil)
+	_ tool.ExecutionOptionsProvider = (*mcpTool)(nil)
 )
@@ -0,0 +1,216 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultChunkSize is the default maximum size of the chunks, in bytes.
+const DefaultChunkSize = 1000
+
+// Chunk is a passage of the text of a document.
+type Chunk struct {
+	Text string
+	// Offset is the byte offset of the chunk in the text of the document.
+	Offset int
+	// Section, if known, is the title of the section holding the chunk,
+	// e.g. "Install > Linux".
+	Section string
+}
+
+// Chunker splits the text of a document into chunks.
+type Chunker func(text string) []Chunk
+
+// FixedSize returns a Chunker splitting texts into chunks of up to size
+// bytes, overlapping by about overlap bytes. Chunks are cut at word
+// boundaries when possible.
+func FixedSize(size, overlap int) Chunker {
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+	if overlap < 0 || overlap >= size {
+		overlap = 0
+	}
+	return func(text string) []Chunk {
+		return splitFixed(text, 0, size, overlap)
+	}
+}
+
+// Paragraphs returns a Chunker splitting texts at blank lines, and
+// merging consecutive paragraphs into chunks of up to maxSize bytes.
+// Longer paragraphs are split at word boundaries.
+func Paragraphs(maxSize int) Chunker {
+	if maxSize <= 0 {
+		maxSize = DefaultChunkSize
+	}
+	return func(text string) []Chunk {
+		return splitParagraphs(text, 0, maxSize, "")
+	}
+}
+
+// Markdown returns a Chunker splitting markdown texts into their
+// sections, recording the titles of the sections. Sections longer than
+// maxSize bytes are split into paragraphs.
+func Markdown(maxSize int) Chunker {
+	if maxSize <= 0 {
+		maxSize = DefaultChunkSize
+	}
+	return func(text string) []Chunk {
+		var (
+			chunks   []Chunk
+			headings []string
+			start    int
+			section  string
+			fenced   bool
+		)
+		flush := func(end int) {
+			chunks = append(chunks, splitParagraphs(text[start:end], start, maxSize, section)...)
+			start = end
+		}
+		for offset := 0; offset < len(text); {
+			line, _, _ := strings.Cut(text[offset:], "\n")
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				fenced = !fenced
+			}
+			if m := markdownHeading.FindStringSubmatch(line); m != nil && !fenced {
+				flush(offset)
+				level := len(m[1])
+				for len(headings) >= level {
+					headings = headings[:len(headings)-1]
+				}
+				for len(headings) < level-1 {
+					headings = append(headings, "")
+				}
+				headings = append(headings, strings.TrimSpace(strings.TrimRight(m[2], "#")))
+				section = joinHeadings(headings)
+			}
+			offset += len(line) + 1
+		}
+		flush(len(text))
+		return chunks
+	}
+}
+
+var (
+	markdownHeading = regexp.MustCompile(`^ {0,3}(#{1,6})[ \t]+(.*)$`)
+	blankLine       = regexp.MustCompile(`\n[ \t]*\n\s*`)
+)
+
+func joinHeadings(headings []string) string {
+	var nonEmpty []string
+	for _, h := range headings {
+		if h != "" {
+			nonEmpty = append(nonEmpty, h)
+		}
+	}
+	return strings.Join(nonEmpty, " > ")
+}
+
+// splitParagraphs splits text, at offset in the document, into chunks of
+// consecutive paragraphs.
+func splitParagraphs(text string, offset, maxSize int, section string) []Chunk {
+	var (
+		chunks     []Chunk
+		start, end = -1, -1
+	)
+	flush := func() {
+		if start >= 0 {
+			if s := strings.TrimSpace(text[start:end]); s != "" {
+				chunks = append(chunks, Chunk{Text: s, Offset: offset + start, Section: section})
+			}
+		}
+		start, end = -1, -1
+	}
+	paragraph := func(ps, pe int) {
+		for ps < pe && isSpace(text[ps]) {
+			ps++
+		}
+		if ps == pe {
+			return
+		}
+		if pe-ps > maxSize {
+			flush()
+			for _, c := range splitFixed(text[ps:pe], offset+ps, maxSize, 0) {
+				c.Section = section
+				chunks = append(chunks, c)
+			}
+			return
+		}
+		if start >= 0 && pe-start > maxSize {
+			flush()
+		}
+		if start < 0 {
+			start = ps
+		}
+		end = pe
+	}
+	prev := 0
+	for _, loc := range blankLine.FindAllStringIndex(text, -1) {
+		paragraph(prev, loc[0])
+		prev = loc[1]
+	}
+	paragraph(prev, len(text))
+	flush()
+	return chunks
+}
+
+// splitFixed splits text, at offset in the document, into chunks of up to
+// size bytes overlapping by about overlap bytes.
+func splitFixed(text string, offset, size, overlap int) []Chunk {
+	var chunks []Chunk
+	for start := 0; start < len(text); {
+		for start < len(text) && isSpace(text[start]) {
+			start++
+		}
+		if start == len(text) {
+			break
+		}
+		end := start + size
+		if end >= len(text) {
+			end = len(text)
+		} else if i := strings.LastIndexAny(text[start+size/2:end], " \t\n"); i >= 0 {
+			end = start + size/2 + i
+		} else {
+			for end > start+1 && !utf8.RuneStart(text[end]) {
+				end--
+			}
+		}
+		chunks = append(chunks, Chunk{Text: strings.TrimSpace(text[start:end]), Offset: offset + start})
+		if end == len(text) {
+			break
+		}
+		next := end
+		if overlap > 0 {
+			next = max(end-overlap, start+1)
+			for next < end && !utf8.RuneStart(text[next]) {
+				next++
+			}
+			// Start the overlap at a word.
+			if i := strings.IndexAny(text[next:end], " \t\n"); i >= 0 {
+				next += i
+			}
+		}
+		start = next
+	}
+	return chunks
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/genai"
+)
+
+// Embedder computes the embeddings of texts, for semantic search.
+//
+// Implementations must be safe for concurrent use.
+type Embedder interface {
+	// EmbedDocuments returns the embeddings of chunks of documents, in
+	// order.
+	EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error)
+	// EmbedQuery returns the embedding of a search query.
+	EmbedQuery(ctx context.Context, text string) ([]float32, error)
+}
+
+// GenAIEmbedderConfig configures an Embedder using an embedding model of
+// the Gemini API or Vertex AI.
+type GenAIEmbedderConfig struct {
+	Client *genai.Client
+	// Model is the name of the embedding model, e.g. "gemini-embedding-001".
+	Model string
+	// OutputDimensionality, if set, truncates the embeddings.
+	OutputDimensionality int32
+	// BatchSize is the number of texts embedded per request. Defaults to
+	// 100; set to 1 for the models embedding one text at a time.
+	BatchSize int
+}
+
+// NewGenAIEmbedder returns an Embedder using an embedding model of the
+// Gemini API or Vertex AI.
+func NewGenAIEmbedder(cfg GenAIEmbedderConfig) (Embedder, error) {
+	if cfg.Client == nil || cfg.Model == "" {
+		return nil, fmt.Errorf("client and model must be provided")
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	return &genaiEmbedder{cfg: cfg}, nil
+}
+
+type genaiEmbedder struct {
+	cfg GenAIEmbedderConfig
+}
+
+func (e *genaiEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, 0, len(texts))
+	for i := 0; i < len(texts); i += e.cfg.BatchSize {
+		batch, err := e.embed(ctx, texts[i:min(i+e.cfg.BatchSize, len(texts))], "RETRIEVAL_DOCUMENT")
+		if err != nil {
+			return nil, err
+		}
+		embeddings = append(embeddings, batch...)
+	}
+	return embeddings, nil
+}
+
+func (e *genaiEmbedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := e.embed(ctx, []string{text}, "RETRIEVAL_QUERY")
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+func (e *genaiEmbedder) embed(ctx context.Context, texts []string, taskType string) ([][]float32, error) {
+	contents := make([]*genai.Content, len(texts))
+	for i, text := range texts {
+		contents[i] = genai.NewContentFromText(text, genai.RoleUser)
+	}
+	cfg := &genai.EmbedContentConfig{TaskType: taskType}
+	if e.cfg.OutputDimensionality > 0 {
+		cfg.OutputDimensionality = &e.cfg.OutputDimensionality
+	}
+	resp, err := e.cfg.Client.Models.EmbedContent(ctx, e.cfg.Model, contents, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("embed content: %w", err)
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("embed content: got %d embeddings for %d texts", len(resp.Embeddings), len(texts))
+	}
+	embeddings := make([][]float32, len(texts))
+	for i, embedding := range resp.Embeddings {
+		if embedding == nil {
+			return nil, fmt.Errorf("embed content: missing embedding %d", i)
+		}
+		embeddings[i] = embedding.Values
+	}
+	return embeddings, nil
+}
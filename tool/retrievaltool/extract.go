@@ -0,0 +1,43 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// supported reports whether the text of documents of the MIME type can
+// be extracted.
+func supported(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "text/") || mimeType == "application/pdf" ||
+		mimeType == "application/json" || mimeType == "application/xml"
+}
+
+// extractText returns the text of a document.
+func extractText(mimeType string, data []byte) (string, error) {
+	switch {
+	case mimeType == "application/pdf":
+		return pdfText(data)
+	case supported(mimeType):
+		if !utf8.Valid(data) {
+			return "", fmt.Errorf("content is not valid UTF-8 text")
+		}
+		return strings.ReplaceAll(string(data), "\r\n", "\n"), nil
+	default:
+		return "", fmt.Errorf("unsupported MIME type %q", mimeType)
+	}
+}
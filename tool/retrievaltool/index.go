@@ -0,0 +1,464 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// DefaultMaxResults is the default number of passages returned by a
+// search.
+const DefaultMaxResults = 5
+
+const (
+	// BM25 parameters.
+	bm25K1 = 1.2
+	bm25B  = 0.75
+	// rrfK is the constant of the reciprocal rank fusion of the keyword
+	// and semantic rankings.
+	rrfK = 60
+)
+
+// IndexConfig configures an Index.
+type IndexConfig struct {
+	// Sources are the corpora to index.
+	Sources []Source
+	// Chunker splits the documents into chunks. By default, markdown
+	// documents are split with Markdown, and others with Paragraphs.
+	Chunker Chunker
+	// Embedder, if set, computes the embeddings of the chunks, and the
+	// searches rank the chunks by both their similarity to the query and
+	// their keywords. Without embedder, or when the query can't be
+	// embedded, the chunks are ranked by their keywords with BM25.
+	Embedder Embedder
+	// RefreshInterval, if positive, is how often the searches refresh the
+	// index. Otherwise, the index is refreshed by the first search, and
+	// then only by Refresh.
+	RefreshInterval time.Duration
+	// OnRefreshError, if set, is called with the errors of the refreshes
+	// made by the searches.
+	OnRefreshError func(error)
+}
+
+// Index is a search index of the chunks of the documents of sources.
+// It is safe for concurrent use.
+type Index struct {
+	cfg IndexConfig
+
+	// refreshMu serializes the refreshes.
+	refreshMu sync.Mutex
+
+	mu        sync.RWMutex
+	docs      map[docKey]*indexedDoc
+	refreshed time.Time
+	// Statistics of the chunks, for BM25.
+	df          map[string]int
+	chunks      int
+	totalLength int
+}
+
+type docKey struct {
+	source int
+	name   string
+}
+
+type indexedDoc struct {
+	Document
+	hash [sha256.Size]byte
+	// embedded reports whether the chunks have embeddings.
+	embedded bool
+	chunks   []*indexedChunk
+}
+
+type indexedChunk struct {
+	Chunk
+	line int
+	tf   map[string]int
+	// length is the number of terms of the chunk.
+	length int
+	// vector is the normalized embedding of the chunk.
+	vector []float32
+}
+
+// NewIndex returns an empty Index of the documents of cfg.Sources, filled
+// by Refresh or by the first search.
+func NewIndex(cfg IndexConfig) *Index {
+	return &Index{cfg: cfg, docs: map[docKey]*indexedDoc{}, df: map[string]int{}}
+}
+
+// Refresh updates the index with the documents of the sources: it
+// indexes the new and modified documents, and removes the deleted ones.
+// The documents whose version didn't change aren't read again, and those
+// whose content didn't change aren't embedded again.
+//
+// Errors don't stop the refresh: the documents which can't be read or
+// extracted are left out of the index, and those which can't be embedded
+// are indexed for keyword search only until the next refresh. The errors
+// are returned joined.
+func (ix *Index) Refresh(ctx context.Context) error {
+	ix.refreshMu.Lock()
+	defer ix.refreshMu.Unlock()
+	var errs []error
+	for i, src := range ix.cfg.Sources {
+		docs, err := src.Documents(ctx)
+		if err != nil {
+			// Keep the documents of the source.
+			errs = append(errs, err)
+			continue
+		}
+		seen := map[string]bool{}
+		for _, doc := range docs {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			seen[doc.Name] = true
+			if err := ix.refreshDocument(ctx, docKey{i, doc.Name}, src, doc); err != nil {
+				errs = append(errs, fmt.Errorf("index %q: %w", doc.Name, err))
+			}
+		}
+		ix.mu.Lock()
+		for key := range ix.docs {
+			if key.source == i && !seen[key.name] {
+				ix.remove(key)
+			}
+		}
+		ix.mu.Unlock()
+	}
+	ix.mu.Lock()
+	ix.refreshed = time.Now()
+	ix.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+func (ix *Index) refreshDocument(ctx context.Context, key docKey, src Source, doc Document) error {
+	ix.mu.RLock()
+	old := ix.docs[key]
+	ix.mu.RUnlock()
+	upToDate := func() bool {
+		return ix.cfg.Embedder == nil || old.embedded
+	}
+	if old != nil && old.Version == doc.Version && upToDate() {
+		ix.mu.Lock()
+		old.Metadata = doc.Metadata
+		ix.mu.Unlock()
+		return nil
+	}
+
+	data, err := src.Read(ctx, doc)
+	if err == nil && len(data) == 0 {
+		err = errors.New("empty document")
+	}
+	if err != nil {
+		ix.mu.Lock()
+		ix.remove(key)
+		ix.mu.Unlock()
+		return err
+	}
+	hash := sha256.Sum256(data)
+	if old != nil && old.hash == hash && upToDate() {
+		ix.mu.Lock()
+		old.Version, old.Metadata = doc.Version, doc.Metadata
+		ix.mu.Unlock()
+		return nil
+	}
+	text, err := extractText(doc.MIMEType, data)
+	if err != nil {
+		ix.mu.Lock()
+		ix.remove(key)
+		ix.mu.Unlock()
+		return err
+	}
+
+	chunker := ix.cfg.Chunker
+	if chunker == nil {
+		chunker = Paragraphs(DefaultChunkSize)
+		if doc.MIMEType == "text/markdown" {
+			chunker = Markdown(DefaultChunkSize)
+		}
+	}
+	d := &indexedDoc{Document: doc, hash: hash}
+	line, lineOffset := 1, 0
+	for _, c := range chunker(text) {
+		if c.Text == "" {
+			continue
+		}
+		line += strings.Count(text[lineOffset:c.Offset], "\n")
+		lineOffset = c.Offset
+		chunk := &indexedChunk{Chunk: c, line: line, tf: map[string]int{}}
+		for _, term := range terms(c.Text) {
+			chunk.tf[term]++
+			chunk.length++
+		}
+		d.chunks = append(d.chunks, chunk)
+	}
+	if ix.cfg.Embedder != nil && len(d.chunks) > 0 {
+		texts := make([]string, len(d.chunks))
+		for i, c := range d.chunks {
+			texts[i] = c.Text
+		}
+		vectors, embedErr := ix.cfg.Embedder.EmbedDocuments(ctx, texts)
+		if embedErr == nil && len(vectors) != len(texts) {
+			embedErr = fmt.Errorf("got %d embeddings for %d chunks", len(vectors), len(texts))
+		}
+		if embedErr == nil {
+			for i, c := range d.chunks {
+				c.vector = normalize(vectors[i])
+			}
+			d.embedded = true
+		}
+		err = embedErr
+	}
+	ix.mu.Lock()
+	ix.remove(key)
+	ix.add(key, d)
+	ix.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("embed: %w", err)
+	}
+	return nil
+}
+
+// add adds a document to the index. ix.mu must be locked.
+func (ix *Index) add(key docKey, d *indexedDoc) {
+	ix.docs[key] = d
+	for _, c := range d.chunks {
+		for term := range c.tf {
+			ix.df[term]++
+		}
+		ix.chunks++
+		ix.totalLength += c.length
+	}
+}
+
+// remove removes a document from the index, if present. ix.mu must be
+// locked.
+func (ix *Index) remove(key docKey) {
+	d, ok := ix.docs[key]
+	if !ok {
+		return
+	}
+	delete(ix.docs, key)
+	for _, c := range d.chunks {
+		for term := range c.tf {
+			if ix.df[term]--; ix.df[term] == 0 {
+				delete(ix.df, term)
+			}
+		}
+		ix.chunks--
+		ix.totalLength -= c.length
+	}
+}
+
+// Filter restricts the searches to the documents whose metadata value of
+// Key matches Value, a pattern in the syntax of path.Match.
+type Filter struct {
+	Key   string `json:"key" jsonschema:"The metadata key."`
+	Value string `json:"value" jsonschema:"The value of the metadata, or a pattern where * matches any sequence of characters except /."`
+}
+
+func (f Filter) matches(metadata map[string]string) bool {
+	v, ok := metadata[f.Key]
+	if !ok {
+		return false
+	}
+	matched, err := path.Match(f.Value, v)
+	return matched || (err != nil && f.Value == v)
+}
+
+// SearchOptions are the options of Index.Search.
+type SearchOptions struct {
+	// MaxResults is the maximum number of passages returned. Defaults to
+	// DefaultMaxResults.
+	MaxResults int
+	// Filters restrict the search to the documents matching all of them.
+	Filters []Filter
+}
+
+// Passage is a chunk of a document found by a search.
+type Passage struct {
+	// Source is the name of the document, e.g. its path.
+	Source string `json:"source"`
+	// Section is the title of the section of the document holding the
+	// passage, if known.
+	Section string `json:"section,omitempty"`
+	// Line is the line of the text of the document where the passage
+	// starts.
+	Line     int               `json:"line"`
+	Text     string            `json:"text"`
+	Score    float64           `json:"score"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Search returns the passages of the indexed documents most relevant to
+// query, best first. It refreshes the index first if it was never
+// refreshed, or if it's older than the refresh interval.
+func (ix *Index) Search(ctx context.Context, query string, opts SearchOptions) ([]Passage, error) {
+	if err := ix.refreshIfStale(ctx); err != nil {
+		return nil, err
+	}
+	if opts.MaxResults <= 0 {
+		opts.MaxResults = DefaultMaxResults
+	}
+	var queryVector []float32
+	if ix.cfg.Embedder != nil {
+		// Fall back to keyword search if the query can't be embedded.
+		if v, err := ix.cfg.Embedder.EmbedQuery(ctx, query); err == nil {
+			queryVector = normalize(v)
+		}
+	}
+	queryTerms := terms(query)
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	type candidate struct {
+		doc                  *indexedDoc
+		chunk                *indexedChunk
+		keyword, similarity  float64
+		keywordRank, simRank int
+	}
+	var candidates []*candidate
+	avgLength := float64(ix.totalLength) / float64(max(ix.chunks, 1))
+	for _, d := range ix.docs {
+		if !slices.ContainsFunc(opts.Filters, func(f Filter) bool { return !f.matches(d.Metadata) }) {
+			for _, c := range d.chunks {
+				cand := &candidate{doc: d, chunk: c, similarity: math.Inf(-1)}
+				for _, term := range queryTerms {
+					tf := float64(c.tf[term])
+					if tf == 0 {
+						continue
+					}
+					df := float64(ix.df[term])
+					idf := math.Log(1 + (float64(ix.chunks)-df+0.5)/(df+0.5))
+					cand.keyword += idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(c.length)/avgLength))
+				}
+				if queryVector != nil && c.vector != nil {
+					cand.similarity = dot(queryVector, c.vector)
+				}
+				candidates = append(candidates, cand)
+			}
+		}
+	}
+	// Break the ties by position.
+	byPosition := func(a, b *candidate) int {
+		if c := strings.Compare(a.doc.Name, b.doc.Name); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.chunk.Offset, b.chunk.Offset)
+	}
+
+	score := func(c *candidate) float64 { return c.keyword }
+	if queryVector != nil {
+		slices.SortFunc(candidates, func(a, b *candidate) int {
+			return cmp.Or(cmp.Compare(b.keyword, a.keyword), byPosition(a, b))
+		})
+		for i, c := range candidates {
+			if c.keyword > 0 {
+				c.keywordRank = i + 1
+			}
+		}
+		slices.SortFunc(candidates, func(a, b *candidate) int {
+			return cmp.Or(cmp.Compare(b.similarity, a.similarity), byPosition(a, b))
+		})
+		for i, c := range candidates {
+			if !math.IsInf(c.similarity, -1) {
+				c.simRank = i + 1
+			}
+		}
+		score = func(c *candidate) float64 {
+			s := 0.0
+			if c.keywordRank > 0 {
+				s += 1.0 / float64(rrfK+c.keywordRank)
+			}
+			if c.simRank > 0 {
+				s += 1.0 / float64(rrfK+c.simRank)
+			}
+			return s
+		}
+	}
+	var passages []Passage
+	slices.SortFunc(candidates, func(a, b *candidate) int {
+		return cmp.Or(cmp.Compare(score(b), score(a)), byPosition(a, b))
+	})
+	for _, c := range candidates {
+		s := score(c)
+		if s <= 0 || len(passages) == opts.MaxResults {
+			break
+		}
+		passages = append(passages, Passage{
+			Source:   c.doc.Name,
+			Section:  c.chunk.Section,
+			Line:     c.chunk.line,
+			Text:     c.chunk.Text,
+			Score:    s,
+			Metadata: c.doc.Metadata,
+		})
+	}
+	return passages, nil
+}
+
+func (ix *Index) refreshIfStale(ctx context.Context) error {
+	ix.mu.RLock()
+	refreshed := ix.refreshed
+	ix.mu.RUnlock()
+	if !refreshed.IsZero() && (ix.cfg.RefreshInterval <= 0 || time.Since(refreshed) < ix.cfg.RefreshInterval) {
+		return nil
+	}
+	err := ix.Refresh(ctx)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if err != nil && ix.cfg.OnRefreshError != nil {
+		ix.cfg.OnRefreshError(err)
+	}
+	return nil
+}
+
+// terms returns the lowercase words of s.
+func terms(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func normalize(v []float32) []float32 {
+	norm := math.Sqrt(dot(v, v))
+	if norm == 0 {
+		return nil
+	}
+	n := make([]float32, len(v))
+	for i, x := range v {
+		n[i] = float32(float64(x) / norm)
+	}
+	return n
+}
+
+func dot(a, b []float32) float64 {
+	s := 0.0
+	for i := range min(len(a), len(b)) {
+		s += float64(a[i]) * float64(b[i])
+	}
+	return s
+}
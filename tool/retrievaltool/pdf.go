@@ -0,0 +1,433 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+const (
+	// maxPDFStreamSize bounds the size of the content streams, both
+	// encoded and decoded.
+	maxPDFStreamSize = 16 * 1024 * 1024
+	// maxPDFArrayDepth bounds the nesting of arrays; deeper arrays are
+	// dropped.
+	maxPDFArrayDepth = 32
+)
+
+var (
+	pdfStream = regexp.MustCompile(`\bstream\r?\n`)
+	pdfSpaces = regexp.MustCompile(`[ \t]+`)
+	pdfLines  = regexp.MustCompile(`\n{3,}`)
+)
+
+// pdfText returns the text of the content streams of a PDF file, in the
+// order of the file.
+//
+// It is a best effort extractor, without a full PDF parser: it handles
+// uncompressed and FlateDecode streams, and strings in the standard
+// encodings and UTF-16. The text of encrypted files, of fonts with custom
+// encodings, and of scanned pages is not extracted.
+func pdfText(data []byte) (string, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return "", errors.New("not a PDF file")
+	}
+	if bytes.Contains(data, []byte("/Encrypt")) {
+		return "", errors.New("encrypted PDF files are not supported")
+	}
+	var b strings.Builder
+	for _, loc := range pdfStream.FindAllIndex(data, -1) {
+		start := loc[1]
+		end := bytes.Index(data[start:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		// The dictionary of the stream follows the last "obj" keyword.
+		dict := data[:loc[0]]
+		if i := bytes.LastIndex(dict, []byte("obj")); i >= 0 {
+			dict = dict[i:]
+		}
+		content, ok := pdfStreamContent(dict, bytes.TrimRight(data[start:start+end], "\r\n"))
+		if !ok {
+			continue
+		}
+		if text := pdfContentText(content); strings.TrimSpace(text) != "" {
+			b.WriteString(text)
+			b.WriteString("\n\n")
+		}
+	}
+	text := pdfSpaces.ReplaceAllString(b.String(), " ")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	text = strings.TrimSpace(pdfLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+	if text == "" {
+		return "", errors.New("no text found in the PDF file")
+	}
+	return text, nil
+}
+
+// pdfStreamContent returns the decoded content of a stream, unless it
+// isn't a content stream or its encoding is unsupported.
+func pdfStreamContent(dict, raw []byte) ([]byte, bool) {
+	if len(raw) > maxPDFStreamSize {
+		return nil, false
+	}
+	for _, skipped := range []string{"/Subtype /Image", "/Subtype/Image", "/ObjStm", "/XRef", "/Length1", "/Length2", "/FontFile", "/Metadata"} {
+		if bytes.Contains(dict, []byte(skipped)) {
+			return nil, false
+		}
+	}
+	if !bytes.Contains(dict, []byte("/Filter")) {
+		return raw, true
+	}
+	if bytes.Count(dict, []byte("Decode")) != 1 || !bytes.Contains(dict, []byte("/FlateDecode")) {
+		return nil, false
+	}
+	r, err := zlib.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, false
+	}
+	content, err := io.ReadAll(io.LimitReader(r, maxPDFStreamSize))
+	if err != nil && len(content) == 0 {
+		return nil, false
+	}
+	return content, true
+}
+
+// pdfContentText returns the text shown by the operators of a content
+// stream.
+func pdfContentText(content []byte) string {
+	var (
+		b        strings.Builder
+		operands []any
+		lastY    float64
+	)
+	newline := func() {
+		if s := b.String(); s != "" && !strings.HasSuffix(s, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	number := func(i int) float64 {
+		if i < 0 || i >= len(operands) {
+			return 0
+		}
+		f, _ := operands[i].(float64)
+		return f
+	}
+	show := func(v any) {
+		switch v := v.(type) {
+		case string:
+			b.WriteString(v)
+		case []any:
+			for _, e := range v {
+				switch e := e.(type) {
+				case string:
+					b.WriteString(e)
+				case float64:
+					// Large negative offsets separate words.
+					if e < -200 {
+						b.WriteString(" ")
+					}
+				}
+			}
+		}
+	}
+	lex := &pdfLexer{data: content}
+	for {
+		tok, ok := lex.next()
+		if !ok {
+			break
+		}
+		op, isOp := tok.(pdfOperator)
+		if !isOp {
+			operands = append(operands, tok)
+			continue
+		}
+		switch op {
+		case "Tj":
+			if len(operands) > 0 {
+				show(operands[len(operands)-1])
+			}
+		case "TJ":
+			if len(operands) > 0 {
+				show(operands[len(operands)-1])
+			}
+		case "'", "\"":
+			newline()
+			if len(operands) > 0 {
+				show(operands[len(operands)-1])
+			}
+		case "Td", "TD":
+			if number(len(operands)-1) != 0 {
+				newline()
+			} else if number(len(operands)-2) > 0 {
+				b.WriteString(" ")
+			}
+		case "Tm":
+			if y := number(len(operands) - 1); y != lastY {
+				newline()
+				lastY = y
+			} else {
+				b.WriteString(" ")
+			}
+		case "T*", "ET":
+			newline()
+		case "BI":
+			lex.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+	return b.String()
+}
+
+// pdfOperator is an operator of a content stream.
+type pdfOperator string
+
+// pdfLexer returns the tokens of a content stream: strings, numbers,
+// arrays, names and operators.
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0 || isPDFSpace(c)
+}
+
+func isPDFSpace(c byte) bool {
+	return strings.IndexByte(" \t\r\n\f\x00", c) >= 0
+}
+
+// next returns the next token, or false at the end of the stream.
+// Arrays are returned whole, as []any; arrays nested deeper than
+// maxPDFArrayDepth are dropped.
+func (l *pdfLexer) next() (any, bool) {
+	tok, ok := l.token()
+	if !ok || tok != pdfOperator("[") {
+		return tok, ok
+	}
+	stack := [][]any{nil}
+	dropped := 0
+	for {
+		tok, ok := l.token()
+		switch {
+		case !ok:
+			// Close the arrays left open at the end of the stream.
+			for len(stack) > 1 {
+				array := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				stack[len(stack)-1] = append(stack[len(stack)-1], array)
+			}
+			return stack[0], true
+		case dropped > 0:
+			switch tok {
+			case pdfOperator("["):
+				dropped++
+			case pdfOperator("]"):
+				dropped--
+			}
+		case tok == pdfOperator("["):
+			if len(stack) == maxPDFArrayDepth {
+				dropped++
+				continue
+			}
+			stack = append(stack, nil)
+		case tok == pdfOperator("]"):
+			array := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return array, true
+			}
+			stack[len(stack)-1] = append(stack[len(stack)-1], array)
+		default:
+			stack[len(stack)-1] = append(stack[len(stack)-1], tok)
+		}
+	}
+}
+
+// token returns the next token, with the brackets of arrays as
+// operators, or false at the end of the stream.
+func (l *pdfLexer) token() (any, bool) {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case isPDFSpace(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		case c == '(':
+			l.pos++
+			return l.literal(), true
+		case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+			l.pos += 2
+			return pdfOperator("<<"), true
+		case c == '>' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '>':
+			l.pos += 2
+			return pdfOperator(">>"), true
+		case c == '<':
+			l.pos++
+			return l.hex(), true
+		case c == '[' || c == ']':
+			l.pos++
+			return pdfOperator(l.data[l.pos-1 : l.pos]), true
+		case c == '/':
+			start := l.pos
+			l.pos++
+			for l.pos < len(l.data) && !isPDFDelimiter(l.data[l.pos]) {
+				l.pos++
+			}
+			return string(l.data[start:l.pos]), true
+		default:
+			start := l.pos
+			l.pos++
+			for l.pos < len(l.data) && !isPDFDelimiter(l.data[l.pos]) {
+				l.pos++
+			}
+			word := string(l.data[start:l.pos])
+			if f, err := strconv.ParseFloat(word, 64); err == nil {
+				return f, true
+			}
+			return pdfOperator(word), true
+		}
+	}
+	return nil, false
+}
+
+// literal returns the text of a literal string, after its opening
+// parenthesis.
+func (l *pdfLexer) literal() string {
+	var s []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return pdfString(s)
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				continue
+			}
+			c = l.data[l.pos]
+			l.pos++
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r', '\n':
+				// A line continuation.
+				if c == '\r' && l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '0', '1', '2', '3', '4', '5', '6', '7':
+				n := int(c - '0')
+				for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+					n = n*8 + int(l.data[l.pos]-'0')
+					l.pos++
+				}
+				c = byte(n)
+			}
+		}
+		s = append(s, c)
+	}
+	return pdfString(s)
+}
+
+// hex returns the text of a hexadecimal string, after its opening angle
+// bracket.
+func (l *pdfLexer) hex() string {
+	end := bytes.IndexByte(l.data[l.pos:], '>')
+	if end < 0 {
+		end = len(l.data) - l.pos
+	}
+	var digits []byte
+	for _, c := range l.data[l.pos : l.pos+end] {
+		if unicode.Is(unicode.ASCII_Hex_Digit, rune(c)) {
+			digits = append(digits, c)
+		}
+	}
+	l.pos += end + 1
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	s := make([]byte, len(digits)/2)
+	for i := range s {
+		v, _ := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		s[i] = byte(v)
+	}
+	return pdfString(s)
+}
+
+// skipInlineImage skips the data of an inline image, up to its EI
+// operator.
+func (l *pdfLexer) skipInlineImage() {
+	for i := l.pos; i+2 < len(l.data); i++ {
+		if isPDFSpace(l.data[i]) && l.data[i+1] == 'E' && l.data[i+2] == 'I' && (i+3 == len(l.data) || isPDFDelimiter(l.data[i+3])) {
+			l.pos = i + 3
+			return
+		}
+	}
+	l.pos = len(l.data)
+}
+
+// pdfString decodes a string, in UTF-16 if it starts with a byte order
+// mark and in Latin-1, close to the standard encodings, otherwise.
+// Strings with control characters, likely glyph identifiers of fonts
+// with custom encodings, are dropped.
+func pdfString(s []byte) string {
+	var runes []rune
+	if len(s) >= 2 && s[0] == 0xfe && s[1] == 0xff {
+		units := make([]uint16, 0, len(s)/2)
+		for i := 2; i+1 < len(s); i += 2 {
+			units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+		}
+		runes = utf16.Decode(units)
+	} else {
+		runes = make([]rune, len(s))
+		for i, c := range s {
+			runes[i] = rune(c)
+		}
+	}
+	for _, r := range runes {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' && r != '\r' {
+			return ""
+		}
+	}
+	return string(runes)
+}
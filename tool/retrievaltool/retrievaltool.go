@@ -0,0 +1,98 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retrievaltool provides a tool searching local document corpora,
+// for retrieval augmented generation.
+//
+// An Index splits the documents of its sources, files of an fs.FS or
+// artifacts, into chunks, and ranks them by BM25 keyword search and, with
+// an Embedder, by semantic similarity. Text, markdown and PDF documents
+// are supported.
+//
+//	index := retrievaltool.NewIndex(retrievaltool.IndexConfig{
+//		Sources: []retrievaltool.Source{
+//			retrievaltool.NewFSSource(retrievaltool.FSSourceConfig{FS: os.DirFS("docs")}),
+//		},
+//	})
+//	search, err := retrievaltool.New(retrievaltool.Config{Index: index})
+package retrievaltool
+
+import (
+	"fmt"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/functiontool"
+)
+
+// Config configures the search tool.
+type Config struct {
+	// Name is the name of the tool. Defaults to "search_documents".
+	Name string
+	// Description is the description of the tool, which should tell the
+	// model what the documents are about.
+	Description string
+	// Index is the index searched.
+	Index *Index
+	// MaxResults is the number of passages returned to the model.
+	// Defaults to DefaultMaxResults.
+	MaxResults int
+	// Filters restrict all the searches, in addition to the filters
+	// chosen by the model.
+	Filters []Filter
+}
+
+// Args is the input of the search tool.
+type Args struct {
+	Query   string   `json:"query" jsonschema:"The search query."`
+	Filters []Filter `json:"filters,omitempty" jsonschema:"Restricts the search to the documents whose metadata match all the filters, e.g. path."`
+}
+
+// Result is the output of the search tool.
+type Result struct {
+	Passages []Passage `json:"passages"`
+}
+
+// New returns a tool searching the passages of the documents of
+// cfg.Index relevant to a query.
+func New(cfg Config) (tool.Tool, error) {
+	if cfg.Index == nil {
+		return nil, fmt.Errorf("index must be provided")
+	}
+	if cfg.Name == "" {
+		cfg.Name = "search_documents"
+	}
+	if cfg.Description == "" {
+		cfg.Description = "Searches the documents for the passages relevant to a query. Returns the passages with the documents and lines they come from."
+	}
+	return functiontool.New(
+		functiontool.Config{Name: cfg.Name, Description: cfg.Description},
+		func(ctx agent.Context, args Args) (*Result, error) {
+			if args.Query == "" {
+				return nil, fmt.Errorf("query is required to search")
+			}
+			passages, err := cfg.Index.Search(ctx, args.Query, SearchOptions{
+				MaxResults: cfg.MaxResults,
+				Filters:    append(append([]Filter(nil), cfg.Filters...), args.Filters...),
+			})
+			if err != nil {
+				return nil, err
+			}
+			if passages == nil {
+				passages = []Passage{}
+			}
+			return &Result{Passages: passages}, nil
+		},
+	)
+}
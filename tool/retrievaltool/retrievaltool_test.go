@@ -0,0 +1,517 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/artifact"
+	icontext "google.golang.org/adk/v2/internal/context"
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/tool/retrievaltool"
+)
+
+func TestChunkers(t *testing.T) {
+	tests := []struct {
+		name    string
+		chunker retrievaltool.Chunker
+		text    string
+		want    []retrievaltool.Chunk
+	}{
+		{
+			name:    "fixed size",
+			chunker: retrievaltool.FixedSize(12, 0),
+			text:    "one two three four five",
+			want: []retrievaltool.Chunk{
+				{Text: "one two", Offset: 0},
+				{Text: "three four", Offset: 8},
+				{Text: "five", Offset: 19},
+			},
+		},
+		{
+			name:    "fixed size with overlap",
+			chunker: retrievaltool.FixedSize(12, 5),
+			text:    "one two three four",
+			want: []retrievaltool.Chunk{
+				{Text: "one two", Offset: 0},
+				{Text: "two three", Offset: 4},
+				{Text: "three four", Offset: 8},
+			},
+		},
+		{
+			name:    "paragraphs",
+			chunker: retrievaltool.Paragraphs(20),
+			text:    "first para\n\nsecond\n\n  \nthird paragraph is long\n",
+			want: []retrievaltool.Chunk{
+				{Text: "first para\n\nsecond", Offset: 0},
+				{Text: "third paragraph is", Offset: 23},
+				{Text: "long", Offset: 42},
+			},
+		},
+		{
+			name:    "markdown",
+			chunker: retrievaltool.Markdown(100),
+			text:    "Intro.\n\n# Install\n\nRun it.\n\n## Linux\n\n```\n# not a heading\n```\n\n# Usage\nUse it.\n",
+			want: []retrievaltool.Chunk{
+				{Text: "Intro.", Offset: 0},
+				{Text: "# Install\n\nRun it.", Offset: 8, Section: "Install"},
+				{Text: "## Linux\n\n```\n# not a heading\n```", Offset: 28, Section: "Install > Linux"},
+				{Text: "# Usage\nUse it.", Offset: 63, Section: "Usage"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.chunker(tt.text)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("chunker() mismatch (-want +got):\n%s", diff)
+			}
+			for _, c := range got {
+				if !strings.HasPrefix(tt.text[c.Offset:], c.Text) {
+					t.Errorf("chunk %q is not at offset %d", c.Text, c.Offset)
+				}
+			}
+		})
+	}
+}
+
+// pdf returns a PDF file whose pages show the lines, the first page in
+// an uncompressed content stream and the others compressed.
+func pdf(t *testing.T, pages ...[]string) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\n")
+	for i, lines := range pages {
+		var content bytes.Buffer
+		content.WriteString("BT /F1 12 Tf 72 720 Td\n")
+		for _, line := range lines {
+			words := strings.Fields(line)
+			content.WriteString("[")
+			for j, w := range words {
+				if j > 0 {
+					content.WriteString(" -250 ")
+				}
+				fmt.Fprintf(&content, "(%s)", strings.NewReplacer("(", `\(`, ")", `\)`).Replace(w))
+			}
+			content.WriteString("] TJ T*\n")
+		}
+		content.WriteString("ET\n")
+		data, filter := content.Bytes(), ""
+		if i > 0 {
+			var z bytes.Buffer
+			w := zlib.NewWriter(&z)
+			w.Write(data)
+			w.Close()
+			data, filter = z.Bytes(), " /Filter /FlateDecode"
+		}
+		fmt.Fprintf(&b, "%d 0 obj << /Length %d%s >>\nstream\n", i+2, len(data), filter)
+		b.Write(data)
+		b.WriteString("\nendstream\nendobj\n")
+	}
+	b.WriteString("%%EOF\n")
+	return b.Bytes()
+}
+
+func testFS(t *testing.T) fstest.MapFS {
+	return fstest.MapFS{
+		"guide.md":      {Data: []byte("# Setup\n\nInstall the gizmo with the installer.\n\n# Care\n\nClean the gizmo weekly.\n"), ModTime: time.Unix(1, 0)},
+		"notes/faq.txt": {Data: []byte("Is the widget waterproof?\n\nNo, the widget is not waterproof.\n"), ModTime: time.Unix(1, 0)},
+		"manual.pdf":    {Data: pdf(t, []string{"Widget manual"}, []string{"Charge the (widget) battery", "overnight"}), ModTime: time.Unix(1, 0)},
+		"logo.png":      {Data: []byte("\x89PNG"), ModTime: time.Unix(1, 0)},
+		".git/config":   {Data: []byte("widget"), ModTime: time.Unix(1, 0)},
+	}
+}
+
+func TestIndex_Search(t *testing.T) {
+	index := retrievaltool.NewIndex(retrievaltool.IndexConfig{
+		Sources: []retrievaltool.Source{retrievaltool.NewFSSource(retrievaltool.FSSourceConfig{
+			FS:       testFS(t),
+			Metadata: func(path string) map[string]string { return map[string]string{"team": "docs"} },
+		})},
+	})
+	if err := index.Refresh(t.Context()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		opts  retrievaltool.SearchOptions
+		want  []retrievaltool.Passage
+	}{
+		{
+			name:  "markdown",
+			query: "how to clean the gizmo",
+			opts:  retrievaltool.SearchOptions{MaxResults: 1},
+			want: []retrievaltool.Passage{{
+				Source: "guide.md", Section: "Care", Line: 5, Text: "# Care\n\nClean the gizmo weekly.",
+				Metadata: map[string]string{"path": "guide.md", "mime_type": "text/markdown", "team": "docs"},
+			}},
+		},
+		{
+			name:  "pdf",
+			query: "battery",
+			want: []retrievaltool.Passage{{
+				Source: "manual.pdf", Line: 1, Text: "Widget manual\n\nCharge the (widget) battery\novernight",
+				Metadata: map[string]string{"path": "manual.pdf", "mime_type": "application/pdf", "team": "docs"},
+			}},
+		},
+		{
+			name:  "filters",
+			query: "widget",
+			opts:  retrievaltool.SearchOptions{Filters: []retrievaltool.Filter{{Key: "path", Value: "notes/*"}, {Key: "team", Value: "docs"}}},
+			want: []retrievaltool.Passage{{
+				Source: "notes/faq.txt", Line: 1, Text: "Is the widget waterproof?\n\nNo, the widget is not waterproof.",
+				Metadata: map[string]string{"path": "notes/faq.txt", "mime_type": "text/plain", "team": "docs"},
+			}},
+		},
+		{
+			name:  "no match",
+			query: "unrelated",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := index.Search(t.Context(), tt.query, tt.opts)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(retrievaltool.Passage{}, "Score")); diff != "" {
+				t.Errorf("Search(%q) mismatch (-want +got):\n%s", tt.query, diff)
+			}
+		})
+	}
+}
+
+// countingSource counts the documents read.
+type countingSource struct {
+	retrievaltool.Source
+	reads atomic.Int32
+}
+
+func (s *countingSource) Read(ctx context.Context, doc retrievaltool.Document) ([]byte, error) {
+	s.reads.Add(1)
+	return s.Source.Read(ctx, doc)
+}
+
+func sources(t *testing.T, index *retrievaltool.Index, query string) []string {
+	t.Helper()
+	passages, err := index.Search(t.Context(), query, retrievaltool.SearchOptions{MaxResults: 10})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	var got []string
+	for _, p := range passages {
+		got = append(got, p.Source)
+	}
+	return got
+}
+
+func TestIndex_Refresh(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.txt": {Data: []byte("apple"), ModTime: time.Unix(1, 0)},
+		"b.txt": {Data: []byte("banana"), ModTime: time.Unix(1, 0)},
+	}
+	src := &countingSource{Source: retrievaltool.NewFSSource(retrievaltool.FSSourceConfig{FS: fsys})}
+	index := retrievaltool.NewIndex(retrievaltool.IndexConfig{Sources: []retrievaltool.Source{src}})
+
+	// The first search refreshes the index.
+	if got := sources(t, index, "apple"); !cmp.Equal(got, []string{"a.txt"}) {
+		t.Errorf("Search(apple) = %v, want [a.txt]", got)
+	}
+	if got := src.reads.Load(); got != 2 {
+		t.Errorf("read %d documents, want 2", got)
+	}
+
+	fsys["a.txt"] = &fstest.MapFile{Data: []byte("cherry"), ModTime: time.Unix(2, 0)}
+	delete(fsys, "b.txt")
+	fsys["c.txt"] = &fstest.MapFile{Data: []byte("apple banana"), ModTime: time.Unix(1, 0)}
+	// Without refresh interval, the index is refreshed explicitly.
+	if got := sources(t, index, "apple"); !cmp.Equal(got, []string{"a.txt"}) {
+		t.Errorf("Search(apple) before Refresh = %v, want [a.txt]", got)
+	}
+	src.reads.Store(0)
+	if err := index.Refresh(t.Context()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if got := src.reads.Load(); got != 2 {
+		t.Errorf("read %d documents, want the 2 modified ones", got)
+	}
+	for query, want := range map[string][]string{"apple": {"c.txt"}, "banana": {"c.txt"}, "cherry": {"a.txt"}} {
+		if got := sources(t, index, query); !cmp.Equal(got, want) {
+			t.Errorf("Search(%s) = %v, want %v", query, got, want)
+		}
+	}
+
+	// Unchanged documents aren't read.
+	src.reads.Store(0)
+	if err := index.Refresh(t.Context()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if got := src.reads.Load(); got != 0 {
+		t.Errorf("read %d documents, want 0", got)
+	}
+}
+
+func TestIndex_NestedPDFArrays(t *testing.T) {
+	// Deeply nested arrays used to overflow the stack of the lexer, which
+	// no recover catches.
+	var content bytes.Buffer
+	content.WriteString("BT (apple) Tj ET\n")
+	content.WriteString(strings.Repeat("[", 12<<20))
+	var z bytes.Buffer
+	w := zlib.NewWriter(&z)
+	w.Write(content.Bytes())
+	w.Close()
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%%PDF-1.4\n1 0 obj << /Length %d /Filter /FlateDecode >>\nstream\n", z.Len())
+	b.Write(z.Bytes())
+	b.WriteString("\nendstream\nendobj\n%%EOF\n")
+
+	index := retrievaltool.NewIndex(retrievaltool.IndexConfig{
+		Sources: []retrievaltool.Source{retrievaltool.NewFSSource(retrievaltool.FSSourceConfig{FS: fstest.MapFS{
+			"nested.pdf": {Data: b.Bytes()},
+		}})},
+	})
+	if got := sources(t, index, "apple"); !cmp.Equal(got, []string{"nested.pdf"}) {
+		t.Errorf("Search(apple) = %v, want [nested.pdf]", got)
+	}
+}
+
+func TestIndex_RefreshErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"good.txt": {Data: []byte("apple")},
+		"bad.txt":  {Data: []byte("apple \xff")},
+		"bad.pdf":  {Data: []byte("not a pdf")},
+	}
+	var refreshErr error
+	index := retrievaltool.NewIndex(retrievaltool.IndexConfig{
+		Sources:        []retrievaltool.Source{retrievaltool.NewFSSource(retrievaltool.FSSourceConfig{FS: fsys})},
+		OnRefreshError: func(err error) { refreshErr = err },
+	})
+	if got := sources(t, index, "apple"); !cmp.Equal(got, []string{"good.txt"}) {
+		t.Errorf("Search(apple) = %v, want [good.txt]", got)
+	}
+	if refreshErr == nil || !strings.Contains(refreshErr.Error(), `"bad.txt"`) || !strings.Contains(refreshErr.Error(), `"bad.pdf"`) {
+		t.Errorf("OnRefreshError got %v, want errors of bad.txt and bad.pdf", refreshErr)
+	}
+}
+
+// fakeEmbedder embeds texts as vectors of concepts, each concept having
+// synonyms.
+type fakeEmbedder struct {
+	fail     atomic.Bool
+	embedded atomic.Int32
+}
+
+var concepts = [][]string{
+	{"car", "automobile", "vehicle"},
+	{"cat", "kitten", "feline"},
+	{"money", "cash", "payment"},
+}
+
+func (e *fakeEmbedder) embed(text string) []float32 {
+	v := make([]float32, len(concepts))
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		for i, synonyms := range concepts {
+			for _, s := range synonyms {
+				if strings.Trim(word, ".,?!") == s {
+					v[i]++
+				}
+			}
+		}
+	}
+	return v
+}
+
+func (e *fakeEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	if e.fail.Load() {
+		return nil, errors.New("embedder unavailable")
+	}
+	e.embedded.Add(int32(len(texts)))
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.embed(text)
+	}
+	return vectors, nil
+}
+
+func (e *fakeEmbedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	if e.fail.Load() {
+		return nil, errors.New("embedder unavailable")
+	}
+	return e.embed(text), nil
+}
+
+func TestIndex_Embedder(t *testing.T) {
+	fsys := fstest.MapFS{
+		"cars.txt":     {Data: []byte("The automobile needs fuel.")},
+		"pets.txt":     {Data: []byte("A kitten sleeps all day.")},
+		"payments.txt": {Data: []byte("Payment by card is accepted.")},
+	}
+	embedder := &fakeEmbedder{}
+	index := retrievaltool.NewIndex(retrievaltool.IndexConfig{
+		Sources:  []retrievaltool.Source{retrievaltool.NewFSSource(retrievaltool.FSSourceConfig{FS: fsys})},
+		Embedder: embedder,
+	})
+	if err := index.Refresh(t.Context()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	first := func(query string) string {
+		t.Helper()
+		got := sources(t, index, query)
+		if len(got) == 0 {
+			return ""
+		}
+		return got[0]
+	}
+
+	// Semantic matches, without common keywords.
+	if got := first("cat"); got != "pets.txt" {
+		t.Errorf("Search(cat) = %q, want pets.txt", got)
+	}
+	if got := first("vehicle"); got != "cars.txt" {
+		t.Errorf("Search(vehicle) = %q, want cars.txt", got)
+	}
+	// Keyword matches, without semantic similarity.
+	if got := first("card"); got != "payments.txt" {
+		t.Errorf("Search(card) = %q, want payments.txt", got)
+	}
+
+	// Searches fall back to keywords when the query can't be embedded.
+	embedder.fail.Store(true)
+	if got := first("fuel"); got != "cars.txt" {
+		t.Errorf("Search(fuel) with failing embedder = %q, want cars.txt", got)
+	}
+	if got := first("vehicle"); got != "" {
+		t.Errorf("Search(vehicle) with failing embedder = %q, want no result", got)
+	}
+
+	// Documents which can't be embedded are indexed for keywords, and
+	// embedded by the next refresh.
+	fsys["pets.txt"] = &fstest.MapFile{Data: []byte("A feline hunts mice."), ModTime: time.Unix(1, 0)}
+	if err := index.Refresh(t.Context()); err == nil {
+		t.Error("Refresh() with failing embedder succeeded, want error")
+	}
+	if got := first("mice"); got != "pets.txt" {
+		t.Errorf("Search(mice) = %q, want pets.txt", got)
+	}
+	embedder.fail.Store(false)
+	embedder.embedded.Store(0)
+	if err := index.Refresh(t.Context()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if got := embedder.embedded.Load(); got != 1 {
+		t.Errorf("embedded %d chunks, want 1", got)
+	}
+	if got := first("kitten"); got != "pets.txt" {
+		t.Errorf("Search(kitten) = %q, want pets.txt", got)
+	}
+}
+
+func TestArtifactSource(t *testing.T) {
+	service := artifact.InMemoryService()
+	save := func(name string, part *genai.Part) {
+		t.Helper()
+		if _, err := service.Save(t.Context(), &artifact.SaveRequest{
+			AppName: "app", UserID: "user", SessionID: "session", FileName: name, Part: part,
+		}); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	save("notes.txt", genai.NewPartFromText("old notes"))
+	save("notes.txt", genai.NewPartFromText("new notes about rockets"))
+	save("user:manual.pdf", genai.NewPartFromBytes(pdf(t, []string{"rocket manual"}), "application/pdf"))
+	save("image.png", genai.NewPartFromBytes([]byte("\x89PNG"), "image/png"))
+
+	index := retrievaltool.NewIndex(retrievaltool.IndexConfig{
+		Sources: []retrievaltool.Source{retrievaltool.NewArtifactSource(retrievaltool.ArtifactSourceConfig{
+			Service: service, AppName: "app", UserID: "user", SessionID: "session",
+		})},
+	})
+	if err := index.Refresh(t.Context()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	got, err := index.Search(t.Context(), "rockets rocket notes", retrievaltool.SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	want := []retrievaltool.Passage{
+		{Source: "notes.txt", Line: 1, Text: "new notes about rockets", Metadata: map[string]string{"path": "notes.txt", "mime_type": "text/plain"}},
+		{Source: "user:manual.pdf", Line: 1, Text: "rocket manual", Metadata: map[string]string{"path": "user:manual.pdf", "mime_type": "application/pdf"}},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(retrievaltool.Passage{}, "Score")); diff != "" {
+		t.Errorf("Search() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTool(t *testing.T) {
+	index := retrievaltool.NewIndex(retrievaltool.IndexConfig{
+		Sources: []retrievaltool.Source{retrievaltool.NewFSSource(retrievaltool.FSSourceConfig{FS: testFS(t)})},
+	})
+	searchTool, err := retrievaltool.New(retrievaltool.Config{
+		Index:   index,
+		Filters: []retrievaltool.Filter{{Key: "mime_type", Value: "text/*"}},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if got := searchTool.Name(); got != "search_documents" {
+		t.Errorf("Name() = %q, want search_documents", got)
+	}
+	ctx := agent.NewToolContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{}), "call1", nil, nil)
+	run := func(args map[string]any) map[string]any {
+		t.Helper()
+		got, err := searchTool.(toolinternal.FunctionTool).Run(ctx, args)
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		return got
+	}
+
+	got := run(map[string]any{"query": "widget", "filters": []any{map[string]any{"key": "path", "value": "notes/faq.txt"}}})
+	want := map[string]any{"passages": []any{map[string]any{
+		"source":   "notes/faq.txt",
+		"line":     float64(1),
+		"text":     "Is the widget waterproof?\n\nNo, the widget is not waterproof.",
+		"metadata": map[string]any{"path": "notes/faq.txt", "mime_type": "text/plain"},
+	}}}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreMapEntries(func(k string, _ any) bool { return k == "score" })); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+
+	// The filters of the config apply: the PDF is left out.
+	if got := run(map[string]any{"query": "battery"}); !cmp.Equal(got, map[string]any{"passages": []any{}}) {
+		t.Errorf("Run(battery) = %v, want no passages", got)
+	}
+
+	if _, err := retrievaltool.New(retrievaltool.Config{}); err == nil {
+		t.Error("New() without index succeeded, want error")
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"mime"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/adk/v2/artifact"
+)
+
+// Metadata keys set on all documents.
+const (
+	// MetadataPath is the name of the document in its source.
+	MetadataPath = "path"
+	// MetadataMIMEType is the MIME type of the document.
+	MetadataMIMEType = "mime_type"
+)
+
+// Document is a document listed by a Source.
+type Document struct {
+	// Name identifies the document in its source, e.g. its path.
+	Name string
+	// Version changes whenever the content of the document does. The
+	// index only reads the documents whose version changed.
+	Version string
+	// MIMEType selects how the text of the document is extracted.
+	MIMEType string
+	// Metadata can be matched by the filters of the searches.
+	Metadata map[string]string
+}
+
+// Source is a corpus of documents.
+type Source interface {
+	// Documents lists the documents of the source.
+	Documents(ctx context.Context) ([]Document, error)
+	// Read returns the content of a document listed by Documents.
+	Read(ctx context.Context, doc Document) ([]byte, error)
+}
+
+// FSSourceConfig configures a Source reading files from a file system.
+type FSSourceConfig struct {
+	FS fs.FS
+	// Patterns, if set, restricts the files to those whose path matches
+	// one of the patterns, in the syntax of path.Match. By default, all
+	// the files with a supported extension are indexed.
+	Patterns []string
+	// Metadata, if set, returns additional metadata of a file.
+	Metadata func(path string) map[string]string
+}
+
+// NewFSSource returns a Source of the files of a file system. The
+// versions of the files are derived from their size and modification
+// time.
+func NewFSSource(cfg FSSourceConfig) Source {
+	return &fsSource{cfg: cfg}
+}
+
+type fsSource struct {
+	cfg FSSourceConfig
+}
+
+func (s *fsSource) Documents(ctx context.Context) ([]Document, error) {
+	var docs []Document
+	err := fs.WalkDir(s.cfg.FS, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != "." && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		mimeType := mimeTypeOf(p)
+		if len(s.cfg.Patterns) > 0 {
+			if !slices.ContainsFunc(s.cfg.Patterns, func(pattern string) bool {
+				ok, _ := path.Match(pattern, p)
+				return ok
+			}) {
+				return nil
+			}
+		} else if !supported(mimeType) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		doc := Document{
+			Name:     p,
+			Version:  fmt.Sprintf("%d-%d", info.Size(), info.ModTime().UnixNano()),
+			MIMEType: mimeType,
+			Metadata: map[string]string{},
+		}
+		if s.cfg.Metadata != nil {
+			for k, v := range s.cfg.Metadata(p) {
+				doc.Metadata[k] = v
+			}
+		}
+		doc.Metadata[MetadataPath] = p
+		doc.Metadata[MetadataMIMEType] = mimeType
+		docs = append(docs, doc)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list files: %w", err)
+	}
+	return docs, nil
+}
+
+func (s *fsSource) Read(ctx context.Context, doc Document) ([]byte, error) {
+	return fs.ReadFile(s.cfg.FS, doc.Name)
+}
+
+// ArtifactSourceConfig configures a Source reading artifacts.
+type ArtifactSourceConfig struct {
+	Service artifact.Service
+	// AppName, UserID and SessionID identify the session holding the
+	// artifacts. The "user:" artifacts of the user are listed too.
+	AppName, UserID, SessionID string
+	// Patterns, if set, restricts the artifacts to those whose file name
+	// matches one of the patterns, in the syntax of path.Match. By
+	// default, all the artifacts of a supported MIME type are indexed.
+	Patterns []string
+}
+
+// NewArtifactSource returns a Source of the latest versions of the
+// artifacts of a session. The custom metadata of the artifacts are
+// copied to the metadata of the documents.
+func NewArtifactSource(cfg ArtifactSourceConfig) Source {
+	return &artifactSource{cfg: cfg}
+}
+
+type artifactSource struct {
+	cfg ArtifactSourceConfig
+}
+
+func (s *artifactSource) Documents(ctx context.Context) ([]Document, error) {
+	resp, err := s.cfg.Service.List(ctx, &artifact.ListRequest{
+		AppName: s.cfg.AppName, UserID: s.cfg.UserID, SessionID: s.cfg.SessionID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list artifacts: %w", err)
+	}
+	var docs []Document
+	for _, name := range resp.FileNames {
+		if len(s.cfg.Patterns) > 0 && !slices.ContainsFunc(s.cfg.Patterns, func(pattern string) bool {
+			ok, _ := path.Match(pattern, name)
+			return ok
+		}) {
+			continue
+		}
+		v, err := s.cfg.Service.GetArtifactVersion(ctx, &artifact.GetArtifactVersionRequest{
+			AppName: s.cfg.AppName, UserID: s.cfg.UserID, SessionID: s.cfg.SessionID, FileName: name,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("get artifact %q: %w", name, err)
+		}
+		version := v.ArtifactVersion
+		mimeType := version.MimeType
+		if mimeType == "" || mimeType == "application/octet-stream" {
+			mimeType = mimeTypeOf(name)
+		}
+		if len(s.cfg.Patterns) == 0 && !supported(mimeType) {
+			continue
+		}
+		doc := Document{
+			Name:     name,
+			Version:  strconv.FormatInt(version.Version, 10),
+			MIMEType: mimeType,
+			Metadata: map[string]string{},
+		}
+		for k, v := range version.CustomMetadata {
+			doc.Metadata[k] = fmt.Sprint(v)
+		}
+		doc.Metadata[MetadataPath] = name
+		doc.Metadata[MetadataMIMEType] = mimeType
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+func (s *artifactSource) Read(ctx context.Context, doc Document) ([]byte, error) {
+	version, err := strconv.ParseInt(doc.Version, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact version %q", doc.Version)
+	}
+	resp, err := s.cfg.Service.Load(ctx, &artifact.LoadRequest{
+		AppName: s.cfg.AppName, UserID: s.cfg.UserID, SessionID: s.cfg.SessionID, FileName: doc.Name, Version: version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load artifact %q: %w", doc.Name, err)
+	}
+	switch part := resp.Part; {
+	case part == nil:
+		return nil, fmt.Errorf("artifact %q is empty", doc.Name)
+	case part.InlineData != nil:
+		return part.InlineData.Data, nil
+	default:
+		return []byte(part.Text), nil
+	}
+}
+
+// mimeTypeOf returns the MIME type of a file from its extension.
+func mimeTypeOf(name string) string {
+	switch ext := strings.ToLower(path.Ext(name)); ext {
+	case ".md", ".markdown":
+		return "text/markdown"
+	case ".txt", ".text":
+		return "text/plain"
+	case ".pdf":
+		return "application/pdf"
+	default:
+		mediaType, _, _ := mime.ParseMediaType(mime.TypeByExtension(ext))
+		return mediaType
+	}
+}
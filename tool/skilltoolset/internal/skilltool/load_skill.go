@@ -45,15 +45,24 @@ type LoadSkillResult struct {
 	Frontmatter  *FrontmatterJSON `json:"frontmatter,omitempty"`
 }
 
-// LoadSkill creates a tool.Tool to load a skill's instructions.
-func LoadSkill(source skill.Source) (tool.Tool, error) {
+// LoadSkill creates a tool.Tool to load a skill's instructions. If
+// activeSkillKey is set, the name of the loaded skill is recorded in the
+// session state under that key, as the active skill.
+func LoadSkill(source skill.Source, activeSkillKey string) (tool.Tool, error) {
 	return functiontool.New(
 		functiontool.Config{
 			Name:        "load_skill",
 			Description: "Loads the SKILL.md instructions for a given skill.",
 		},
 		func(ctx agent.Context, args LoadSkillArgs) (*LoadSkillResult, error) {
-			return loadSkill(ctx, args, source)
+			result, err := loadSkill(ctx, args, source)
+			if err != nil || activeSkillKey == "" {
+				return result, err
+			}
+			if err := ctx.State().Set(activeSkillKey, args.Name); err != nil {
+				return nil, fmt.Errorf("activate skill %q: %w", args.Name, err)
+			}
+			return result, nil
 		},
 	)
 }
@@ -0,0 +1,316 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skilltool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/functiontool"
+	"google.golang.org/adk/v2/tool/skilltoolset/skill"
+)
+
+const (
+	// maxOutputFiles is the number of files produced by a script saved
+	// as artifacts.
+	maxOutputFiles = 20
+	// waitDelay is how long to wait for the output of a script after
+	// it's killed.
+	waitDelay = time.Second
+)
+
+// RunScriptConfig configures the run_skill_script tool.
+type RunScriptConfig struct {
+	// Interpreters maps script file extensions to the command running
+	// them, e.g. ".py" to {"python3"}.
+	Interpreters map[string][]string
+	// Timeout bounds the run time of a script.
+	Timeout time.Duration
+	// Env holds extra environment variables of the scripts, as
+	// "KEY=value".
+	Env []string
+	// SandboxCommand prefixes the command running a script.
+	SandboxCommand []string
+	// MaxOutputSize bounds the stdout and stderr returned in the result.
+	MaxOutputSize int
+}
+
+// RunSkillScriptArgs represents the input to run a skill's script.
+type RunSkillScriptArgs struct {
+	SkillName  string   `json:"skill_name" jsonschema:"The name of the skill."`
+	ScriptPath string   `json:"script_path" jsonschema:"The relative path to the script (e.g., 'scripts/convert.py')."`
+	Args       []string `json:"args,omitempty" jsonschema:"The command line arguments of the script."`
+}
+
+// RunSkillScriptResult represents the outcome of a skill's script.
+type RunSkillScriptResult struct {
+	SkillName  string `json:"skill_name"`
+	ScriptPath string `json:"script_path"`
+	ExitCode   int    `json:"exit_code"`
+	TimedOut   bool   `json:"timed_out,omitempty"`
+	Stdout     string `json:"stdout,omitempty"`
+	Stderr     string `json:"stderr,omitempty"`
+	// Artifacts lists the artifacts holding the full stdout and stderr
+	// and the files the script produced.
+	Artifacts []string `json:"artifacts,omitempty"`
+}
+
+// RunSkillScript creates a tool.Tool to run a script of a skill.
+//
+// The script runs with a copy of the skill's resources, which it finds
+// in the directory $SKILL_DIR, in an empty working directory which is
+// also its $HOME and $TMPDIR. Its environment holds PATH and cfg.Env
+// only. The files it leaves in the working directory are saved as
+// artifacts, along with its stdout and stderr. Both directories are
+// removed once it exits.
+func RunSkillScript(source skill.Source, cfg RunScriptConfig) (tool.Tool, error) {
+	if len(cfg.Interpreters) == 0 {
+		return nil, fmt.Errorf("at least one interpreter is required to run skill scripts")
+	}
+	extensions := make([]string, 0, len(cfg.Interpreters))
+	for ext := range cfg.Interpreters {
+		extensions = append(extensions, ext)
+	}
+	slices.Sort(extensions)
+	return functiontool.New(
+		functiontool.Config{
+			Name: "run_skill_script",
+			Description: fmt.Sprintf("Runs a script from the scripts/ directory of the specified skill with arguments, "+
+				"and returns its exit code, stdout and stderr. Supported script types: %s.", strings.Join(extensions, ", ")),
+		},
+		func(ctx agent.Context, args RunSkillScriptArgs) (*RunSkillScriptResult, error) {
+			return runSkillScript(ctx, args, source, cfg)
+		},
+	)
+}
+
+func runSkillScript(ctx agent.Context, args RunSkillScriptArgs, source skill.Source, cfg RunScriptConfig) (*RunSkillScriptResult, error) {
+	if args.SkillName == "" {
+		return nil, fmt.Errorf("skill name is required to run a script")
+	}
+	scriptPath := path.Clean(args.ScriptPath)
+	if !strings.HasPrefix(scriptPath, "scripts/") {
+		return nil, fmt.Errorf("%w: script %q must be within 'scripts/'", skill.ErrInvalidResourcePath, args.ScriptPath)
+	}
+	interpreter, ok := cfg.Interpreters[path.Ext(scriptPath)]
+	if !ok || len(interpreter) == 0 {
+		return nil, fmt.Errorf("script %q of skill %q has no allowed interpreter", scriptPath, args.SkillName)
+	}
+
+	dir, err := os.MkdirTemp("", "skill-"+args.SkillName+"-")
+	if err != nil {
+		return nil, fmt.Errorf("create script directory: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	skillDir, workDir := filepath.Join(dir, "skill"), filepath.Join(dir, "work")
+	if err := os.Mkdir(workDir, 0o700); err != nil {
+		return nil, fmt.Errorf("create working directory: %w", err)
+	}
+	if err := copySkillResources(ctx, source, args.SkillName, skillDir); err != nil {
+		return nil, err
+	}
+	script := filepath.Join(skillDir, filepath.FromSlash(scriptPath))
+	if _, err := os.Stat(script); err != nil {
+		return nil, fmt.Errorf("%w: %q", skill.ErrResourceNotFound, scriptPath)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+	argv := slices.Concat(cfg.SandboxCommand, interpreter, []string{script}, args.Args)
+	cmd := exec.CommandContext(runCtx, argv[0], argv[1:]...)
+	cmd.Dir = workDir
+	cmd.Env = append([]string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + workDir,
+		"TMPDIR=" + workDir,
+		"SKILL_DIR=" + skillDir,
+	}, cfg.Env...)
+	cmd.WaitDelay = waitDelay
+	stdout, stderr := &limitedBuffer{limit: maxResourceSize}, &limitedBuffer{limit: maxResourceSize}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+
+	result := &RunSkillScriptResult{SkillName: args.SkillName, ScriptPath: scriptPath}
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case runCtx.Err() != nil && ctx.Err() == nil:
+		result.TimedOut = true
+		result.ExitCode = -1
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		return nil, fmt.Errorf("run script %q of skill %q: %w", scriptPath, args.SkillName, err)
+	}
+	result.Stdout = truncate(stdout.String(), cfg.MaxOutputSize)
+	result.Stderr = truncate(stderr.String(), cfg.MaxOutputSize)
+
+	prefix := args.SkillName + "_" + ctx.FunctionCallID() + "_"
+	for name, b := range map[string]*limitedBuffer{"stdout.txt": stdout, "stderr.txt": stderr} {
+		if b.Len() == 0 {
+			continue
+		}
+		if err := saveArtifact(ctx, prefix+name, b.Bytes(), "text/plain"); err != nil {
+			return nil, err
+		}
+		result.Artifacts = append(result.Artifacts, prefix+name)
+	}
+	files, err := outputFiles(workDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Join(workDir, f))
+		if err != nil {
+			return nil, fmt.Errorf("read output file %q: %w", f, err)
+		}
+		name := prefix + strings.ReplaceAll(filepath.ToSlash(f), "/", "_")
+		if err := saveArtifact(ctx, name, data, mimeType(f, data)); err != nil {
+			return nil, err
+		}
+		result.Artifacts = append(result.Artifacts, name)
+	}
+	slices.Sort(result.Artifacts)
+	return result, nil
+}
+
+// copySkillResources writes the resources of a skill to dir.
+func copySkillResources(ctx context.Context, source skill.Source, name, dir string) error {
+	resources, err := source.ListResources(ctx, name, "")
+	if err != nil {
+		return fmt.Errorf("list resources of skill %q: %w", name, err)
+	}
+	for _, resource := range resources {
+		dst := filepath.Join(dir, filepath.FromSlash(path.Clean(resource)))
+		if !strings.HasPrefix(dst, dir+string(filepath.Separator)) {
+			return fmt.Errorf("%w: %q", skill.ErrInvalidResourcePath, resource)
+		}
+		if err := copyResource(ctx, source, name, resource, dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyResource(ctx context.Context, source skill.Source, name, resource, dst string) error {
+	reader, err := source.LoadResource(ctx, name, resource)
+	if err != nil {
+		return fmt.Errorf("load resource '%s' from skill '%s': %w", resource, name, err)
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	if err := os.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+		return fmt.Errorf("create directory of resource %q: %w", resource, err)
+	}
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o700)
+	if err != nil {
+		return fmt.Errorf("create resource %q: %w", resource, err)
+	}
+	n, err := io.Copy(f, io.LimitReader(reader, maxResourceSize+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("copy resource %q: %w", resource, err)
+	}
+	if n > maxResourceSize {
+		return fmt.Errorf("resource '%s' from skill '%s' is too large (limit: %d bytes)", resource, name, maxResourceSize)
+	}
+	return nil
+}
+
+// outputFiles returns the regular files under dir, relative to it, up to
+// maxOutputFiles, skipping those larger than maxResourceSize.
+func outputFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || len(files) >= maxOutputFiles {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() > maxResourceSize {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list output files: %w", err)
+	}
+	return files, nil
+}
+
+func saveArtifact(ctx agent.Context, name string, data []byte, mimeType string) error {
+	if _, err := ctx.Artifacts().Save(ctx, name, genai.NewPartFromBytes(data, mimeType)); err != nil {
+		return fmt.Errorf("save artifact %q: %w", name, err)
+	}
+	return nil
+}
+
+func mimeType(name string, data []byte) string {
+	if t := mime.TypeByExtension(filepath.Ext(name)); t != "" {
+		return t
+	}
+	return http.DetectContentType(data)
+}
+
+// truncate returns s cut to at most n bytes, if n is positive.
+func truncate(s string, n int) string {
+	if n <= 0 || len(s) <= n {
+		return s
+	}
+	return s[:n] + "\n... (truncated)"
+}
+
+// limitedBuffer is a bytes.Buffer discarding what is written beyond
+// limit.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room < len(p) {
+		b.Buffer.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
@@ -17,14 +17,19 @@ package skilltool_test
 import (
 	"context"
 	"io"
+	"os/exec"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
 	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/artifact"
+	artifactinternal "google.golang.org/adk/v2/internal/artifact"
 	icontext "google.golang.org/adk/v2/internal/context"
 	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/session"
 	"google.golang.org/adk/v2/tool/skilltoolset/internal/skilltool"
 	"google.golang.org/adk/v2/tool/skilltoolset/skill"
 )
@@ -126,7 +131,7 @@ func TestLoadSkill(t *testing.T) {
 			"skill1": "instructions1",
 		},
 	}
-	tool, err := skilltool.LoadSkill(source)
+	tool, err := skilltool.LoadSkill(source, "")
 	if err != nil {
 		t.Fatalf("LoadSkill failed: %v", err)
 	}
@@ -185,3 +190,85 @@ func TestLoadSkillResource(t *testing.T) {
 		t.Errorf("result mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestRunSkillScript(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	source := &mockSource{
+		resources: map[string]map[string]string{
+			"skill1": {
+				"scripts/report.sh":   "cat \"$SKILL_DIR/assets/greeting.txt\"\necho \"args: $*\"\necho warning >&2\necho done > report.txt\nexit 3\n",
+				"scripts/sleep.sh":    "sleep 10\n",
+				"scripts/run.rb":      "puts 1\n",
+				"assets/greeting.txt": "hello\n",
+			},
+		},
+	}
+	tool, err := skilltool.RunSkillScript(source, skilltool.RunScriptConfig{
+		Interpreters:  map[string][]string{".sh": {"sh"}},
+		Timeout:       time.Second,
+		MaxOutputSize: 100,
+	})
+	if err != nil {
+		t.Fatalf("RunSkillScript: %v", err)
+	}
+	functionTool := tool.(toolinternal.FunctionTool)
+
+	sessions := session.InMemoryService()
+	resp, err := sessions.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	artifacts := artifact.InMemoryService()
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+		Session: resp.Session,
+		Artifacts: &artifactinternal.Artifacts{
+			Service: artifacts, AppName: "app", UserID: "user", SessionID: resp.Session.ID(),
+		},
+	})
+	toolCtx := agent.NewToolContext(invCtx, "call1", &session.EventActions{}, nil)
+
+	got, err := functionTool.Run(toolCtx, map[string]any{
+		"skill_name":  "skill1",
+		"script_path": "scripts/report.sh",
+		"args":        []any{"a", "b"},
+	})
+	if err != nil {
+		t.Fatalf("tool.Run failed: %v", err)
+	}
+	want := map[string]any{
+		"skill_name":  "skill1",
+		"script_path": "scripts/report.sh",
+		"exit_code":   float64(3),
+		"stdout":      "hello\nargs: a b\n",
+		"stderr":      "warning\n",
+		"artifacts":   []any{"skill1_call1_report.txt", "skill1_call1_stderr.txt", "skill1_call1_stdout.txt"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+	loaded, err := artifacts.Load(t.Context(), &artifact.LoadRequest{
+		AppName: "app", UserID: "user", SessionID: resp.Session.ID(), FileName: "skill1_call1_report.txt",
+	})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := string(loaded.Part.InlineData.Data); got != "done\n" {
+		t.Errorf("report.txt = %q, want %q", got, "done\n")
+	}
+
+	got, err = functionTool.Run(toolCtx, map[string]any{"skill_name": "skill1", "script_path": "scripts/sleep.sh"})
+	if err != nil {
+		t.Fatalf("tool.Run failed: %v", err)
+	}
+	if got["timed_out"] != true {
+		t.Errorf("timed_out = %v, want true", got["timed_out"])
+	}
+
+	for _, scriptPath := range []string{"scripts/run.rb", "assets/greeting.txt", "scripts/../../x.sh", "scripts/missing.sh"} {
+		if _, err := functionTool.Run(toolCtx, map[string]any{"skill_name": "skill1", "script_path": scriptPath}); err == nil {
+			t.Errorf("tool.Run(%q) succeeded, want an error", scriptPath)
+		}
+	}
+}
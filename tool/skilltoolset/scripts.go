@@ -0,0 +1,88 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skilltoolset
+
+import (
+	"maps"
+	"time"
+
+	"google.golang.org/adk/v2/tool/skilltoolset/internal/skilltool"
+)
+
+const (
+	// DefaultScriptTimeout is the default ScriptConfig.Timeout.
+	DefaultScriptTimeout = 30 * time.Second
+	// DefaultMaxScriptOutputSize is the default ScriptConfig.MaxOutputSize.
+	DefaultMaxScriptOutputSize = 16 * 1024
+)
+
+// DefaultInterpreters is the default ScriptConfig.Interpreters: Python
+// and shell scripts.
+var DefaultInterpreters = map[string][]string{
+	".py": {"python3"},
+	".sh": {"sh"},
+}
+
+// ScriptConfig configures the run_skill_script tool, which runs the
+// scripts in the scripts/ directory of the skills.
+//
+// A script runs with a copy of the skill's resources, found in the
+// directory $SKILL_DIR, in an empty temporary working directory, with an
+// environment holding PATH, HOME, TMPDIR and SKILL_DIR only, plus Env.
+// Its stdout and stderr, and the files it leaves in its working
+// directory, are saved as artifacts of the session, so the agent needs
+// an artifact service. This isolates scripts from each other, but not
+// from the host: use SandboxCommand to run untrusted scripts in a
+// sandbox.
+type ScriptConfig struct {
+	// Interpreters allowlists the scripts which can be run, by file
+	// extension, mapped to the command running them; the script path and
+	// arguments are appended to it. Defaults to DefaultInterpreters.
+	Interpreters map[string][]string
+	// Timeout bounds the run time of a script, which is killed after
+	// it. Defaults to DefaultScriptTimeout.
+	Timeout time.Duration
+	// Env holds extra environment variables of the scripts, as
+	// "KEY=value".
+	Env []string
+	// SandboxCommand, if set, prefixes the command running a script,
+	// e.g. {"bwrap", "--unshare-net", ...} or {"nsjail", ...}, so that
+	// it runs in a sandbox.
+	SandboxCommand []string
+	// MaxOutputSize bounds the stdout and stderr returned to the model,
+	// in bytes; the artifacts hold the full output. Defaults to
+	// DefaultMaxScriptOutputSize.
+	MaxOutputSize int
+}
+
+func (cfg *ScriptConfig) runScriptConfig() skilltool.RunScriptConfig {
+	rc := skilltool.RunScriptConfig{
+		Interpreters:   maps.Clone(cfg.Interpreters),
+		Timeout:        cfg.Timeout,
+		Env:            cfg.Env,
+		SandboxCommand: cfg.SandboxCommand,
+		MaxOutputSize:  cfg.MaxOutputSize,
+	}
+	if rc.Interpreters == nil {
+		rc.Interpreters = maps.Clone(DefaultInterpreters)
+	}
+	if rc.Timeout <= 0 {
+		rc.Timeout = DefaultScriptTimeout
+	}
+	if rc.MaxOutputSize <= 0 {
+		rc.MaxOutputSize = DefaultMaxScriptOutputSize
+	}
+	return rc
+}
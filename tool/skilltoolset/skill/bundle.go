@@ -0,0 +1,281 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skill
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/adk/v2/artifact"
+)
+
+const maxBundleSize = 256 * 1024 * 1024 // 256MB
+
+// ErrBundleNotFound is returned by BundleStore implementations when the
+// requested bundle version doesn't exist.
+var ErrBundleNotFound = errors.New("bundle not found")
+
+// Bundle is a version of a skill bundle: a zip, tar or gzipped tar
+// archive holding skills as top-level directories, laid out as expected
+// by NewFileSystemSource.
+type Bundle struct {
+	// Version is the version of the bundle.
+	Version string
+	// Data is the content of the archive.
+	Data []byte
+	// Signature is the detached ed25519 signature of the
+	// BundleManifest of Version and Data, if the store has one.
+	Signature []byte
+}
+
+// BundleStore is the interface for fetching versioned skill bundles.
+//
+// Implementations must be safe for concurrent use.
+type BundleStore interface {
+	// Fetch returns the bundle at version, or the latest bundle if version
+	// is empty.
+	Fetch(ctx context.Context, version string) (*Bundle, error)
+}
+
+// HTTPBundleStoreConfig configures a BundleStore fetching bundles over
+// HTTP.
+type HTTPBundleStoreConfig struct {
+	// URL is the URL of the bundles, in which "{version}" is replaced by
+	// the requested version, or "latest". The version of a latest bundle
+	// is read from the VersionHeader header of the response, and is
+	// "latest" if it's missing.
+	URL string
+	// SignatureURL, if set, is the URL of the signatures of the bundles,
+	// with the same substitution.
+	SignatureURL string
+	// VersionHeader is the response header holding the version of the
+	// bundle. Defaults to "X-Bundle-Version".
+	VersionHeader string
+	// Client sends the requests. Defaults to http.DefaultClient. Set a
+	// client with credentials to fetch bundles from private endpoints.
+	Client *http.Client
+}
+
+// NewHTTPBundleStore returns a BundleStore fetching bundles over HTTP.
+func NewHTTPBundleStore(cfg HTTPBundleStoreConfig) BundleStore {
+	if cfg.VersionHeader == "" {
+		cfg.VersionHeader = "X-Bundle-Version"
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	return &httpBundleStore{cfg: cfg}
+}
+
+type httpBundleStore struct {
+	cfg HTTPBundleStoreConfig
+}
+
+func (s *httpBundleStore) Fetch(ctx context.Context, version string) (*Bundle, error) {
+	urlVersion := version
+	if urlVersion == "" {
+		urlVersion = "latest"
+	}
+	data, header, err := s.get(ctx, s.cfg.URL, urlVersion)
+	if err != nil {
+		return nil, fmt.Errorf("fetch bundle %q: %w", urlVersion, err)
+	}
+	b := &Bundle{Version: version, Data: data}
+	if b.Version == "" {
+		b.Version = header.Get(s.cfg.VersionHeader)
+	}
+	if b.Version == "" {
+		b.Version = urlVersion
+	}
+	if s.cfg.SignatureURL != "" {
+		if b.Signature, _, err = s.get(ctx, s.cfg.SignatureURL, b.Version); err != nil {
+			return nil, fmt.Errorf("fetch signature of bundle %q: %w", b.Version, err)
+		}
+	}
+	return b, nil
+}
+
+func (s *httpBundleStore) get(ctx context.Context, url, version string) ([]byte, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.ReplaceAll(url, "{version}", version), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := s.cfg.Client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil, ErrBundleNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected HTTP status %s", resp.Status)
+	}
+	data, err := readLimited(resp.Body, maxBundleSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, resp.Header, nil
+}
+
+// ArtifactBundleStoreConfig configures a BundleStore loading bundles from
+// an artifact service. The versions of the bundles are those of the
+// artifact.
+type ArtifactBundleStoreConfig struct {
+	Service artifact.Service
+	// AppName, UserID and SessionID identify the session holding the
+	// artifact. Prefix FileName with "user:" for bundles shared by the
+	// sessions of the user.
+	AppName, UserID, SessionID string
+	// FileName is the name of the bundle artifact.
+	FileName string
+	// SignatureFileName, if set, is the name of the artifact holding the
+	// signatures of the bundles, at the same versions.
+	SignatureFileName string
+}
+
+// NewArtifactBundleStore returns a BundleStore loading bundles from an
+// artifact service.
+func NewArtifactBundleStore(cfg ArtifactBundleStoreConfig) BundleStore {
+	return &artifactBundleStore{cfg: cfg}
+}
+
+type artifactBundleStore struct {
+	cfg ArtifactBundleStoreConfig
+}
+
+func (s *artifactBundleStore) Fetch(ctx context.Context, version string) (*Bundle, error) {
+	var v int64
+	if version == "" {
+		resp, err := s.cfg.Service.Versions(ctx, &artifact.VersionsRequest{
+			AppName: s.cfg.AppName, UserID: s.cfg.UserID, SessionID: s.cfg.SessionID, FileName: s.cfg.FileName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list versions of bundle %q: %w", s.cfg.FileName, err)
+		}
+		if len(resp.Versions) == 0 {
+			return nil, fmt.Errorf("%w: %q", ErrBundleNotFound, s.cfg.FileName)
+		}
+		v = slices.Max(resp.Versions)
+	} else {
+		var err error
+		if v, err = strconv.ParseInt(version, 10, 64); err != nil || v <= 0 {
+			return nil, fmt.Errorf("invalid artifact bundle version %q: must be a positive integer", version)
+		}
+	}
+	b := &Bundle{Version: strconv.FormatInt(v, 10)}
+	var err error
+	if b.Data, err = s.load(ctx, s.cfg.FileName, v); err != nil {
+		return nil, fmt.Errorf("load bundle %q at version %d: %w", s.cfg.FileName, v, err)
+	}
+	if s.cfg.SignatureFileName != "" {
+		if b.Signature, err = s.load(ctx, s.cfg.SignatureFileName, v); err != nil {
+			return nil, fmt.Errorf("load signature %q at version %d: %w", s.cfg.SignatureFileName, v, err)
+		}
+	}
+	return b, nil
+}
+
+func (s *artifactBundleStore) load(ctx context.Context, name string, version int64) ([]byte, error) {
+	resp, err := s.cfg.Service.Load(ctx, &artifact.LoadRequest{
+		AppName: s.cfg.AppName, UserID: s.cfg.UserID, SessionID: s.cfg.SessionID, FileName: name, Version: version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Part == nil || resp.Part.InlineData == nil {
+		return nil, fmt.Errorf("%w: artifact %q has no inline data", ErrBundleNotFound, name)
+	}
+	return resp.Part.InlineData.Data, nil
+}
+
+// bundleFS returns the file system of a bundle archive.
+func bundleFS(data []byte) (fs.FS, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")), bytes.HasPrefix(data, []byte("PK\x05\x06")):
+		r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("read zip bundle: %w", err)
+		}
+		return r, nil
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("read gzip bundle: %w", err)
+		}
+		tarData, err := readLimited(gz, maxBundleSize)
+		if err != nil {
+			return nil, fmt.Errorf("read gzip bundle: %w", err)
+		}
+		return tarFS(tarData)
+	default:
+		return tarFS(data)
+	}
+}
+
+// tarFS returns the file system of a tar archive, by converting it to
+// zip, whose reader implements fs.FS.
+func tarFS(data []byte) (fs.FS, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read tar bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue // Directories are implied by the files; links are not supported.
+		}
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: strings.TrimPrefix(hdr.Name, "./"), Method: zip.Store})
+		if err != nil {
+			return nil, fmt.Errorf("convert tar bundle: %w", err)
+		}
+		if _, err := io.Copy(w, tr); err != nil {
+			return nil, fmt.Errorf("read tar bundle: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("convert tar bundle: %w", err)
+	}
+	return zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+}
+
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("bundle exceeds %d bytes limit", limit)
+	}
+	return data, nil
+}
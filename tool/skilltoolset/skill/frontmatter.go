@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"path"
 	"slices"
 	"strings"
 
@@ -43,6 +44,27 @@ type Frontmatter struct {
 	AllowedTools  []string          `yaml:"allowed-tools,omitempty"`
 }
 
+// AllowsTool reports whether the skill allows the agent to use the tool
+// name. A skill without allowed-tools allows every tool. Entries are
+// tool names or path.Match patterns, e.g. "bigquery_*"; the argument
+// qualifier of entries like "Bash(git:*)" is ignored, and the entry
+// matched by the tool name only.
+func (fm *Frontmatter) AllowsTool(name string) bool {
+	if len(fm.AllowedTools) == 0 {
+		return true
+	}
+	for _, entry := range fm.AllowedTools {
+		pattern, _, _ := strings.Cut(strings.TrimSpace(entry), "(")
+		if pattern == name {
+			return true
+		}
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
 // Parse reads and validates YAML frontmatter from a SKILL.md reader.
 // On success, the reader will contain the remaining Markdown instruction.
 // Use when reading the entire file into memory is expensive.
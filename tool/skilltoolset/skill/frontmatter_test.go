@@ -356,3 +356,25 @@ func TestBuild_ValidationError(t *testing.T) {
 		t.Fatalf("Build: expected validation error, got nil")
 	}
 }
+
+func TestAllowsTool(t *testing.T) {
+	fm := &Frontmatter{AllowedTools: []string{"get_weather", "bq_*", "Bash(git:*)"}}
+	tests := []struct {
+		tool string
+		want bool
+	}{
+		{tool: "get_weather", want: true},
+		{tool: "bq_query", want: true},
+		{tool: "Bash", want: true},
+		{tool: "get_time", want: false},
+		{tool: "bq", want: false},
+	}
+	for _, tt := range tests {
+		if got := fm.AllowsTool(tt.tool); got != tt.want {
+			t.Errorf("AllowsTool(%q) = %v, want %v", tt.tool, got, tt.want)
+		}
+	}
+	if !(&Frontmatter{}).AllowsTool("anything") {
+		t.Error("AllowsTool() = false for a skill without allowed-tools, want true")
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skill
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/mod/semver"
+)
+
+// Errors returned when a bundle fails its verification.
+var (
+	ErrChecksumMismatch = errors.New("bundle checksum mismatch")
+	ErrInvalidSignature = errors.New("invalid bundle signature")
+	ErrVersionRollback  = errors.New("bundle version rollback")
+)
+
+// BundleManifest returns the message signed by the signature of a
+// bundle: its version and the SHA-256 checksum of its data, so that a
+// signed bundle can't be served as another version.
+func BundleManifest(version string, data []byte) []byte {
+	sum := sha256.Sum256(data)
+	m, _ := json.Marshal(struct {
+		Version string `json:"version"`
+		SHA256  string `json:"sha256"`
+	}{version, hex.EncodeToString(sum[:])})
+	return m
+}
+
+// RemoteSourceConfig configures a RemoteSource.
+type RemoteSourceConfig struct {
+	// Store provides the skill bundles.
+	Store BundleStore
+	// Version pins the version of the bundle. If empty, the latest bundle
+	// is used, and refreshed every RefreshInterval.
+	Version string
+	// SHA256, if set, is the expected hex-encoded SHA-256 checksum of the
+	// bundle.
+	SHA256 string
+	// PublicKeys, if set, are the ed25519 keys the bundles must be
+	// signed with: the signature of a bundle must be a valid signature
+	// of its BundleManifest for one of them.
+	PublicKeys []ed25519.PublicKey
+	// Lockfile, if set, is the path of a lockfile pinning the version and
+	// checksum of the bundle. If it exists, it overrides Version and
+	// SHA256; otherwise, it's written once the bundle is loaded, so that
+	// later runs load the same bundle.
+	Lockfile string
+	// RefreshInterval, if set, is the interval at which the latest bundle
+	// is fetched anew, for sources without a pinned version. The skills
+	// of the current bundle are served until the new one is loaded. A
+	// latest bundle older than the current one is refused with
+	// ErrVersionRollback: versions are compared as semantic versions,
+	// with an optional "v" prefix, which includes plain integers, and
+	// other versions as strings.
+	RefreshInterval time.Duration
+	// OnRefreshError, if set, is called with the errors of the background
+	// refreshes. By default, they are logged.
+	OnRefreshError func(error)
+}
+
+// Lock identifies the bundle of a RemoteSource, as recorded in its
+// lockfile.
+type Lock struct {
+	Version string `json:"version"`
+	SHA256  string `json:"sha256"`
+}
+
+// RemoteSource is a Source serving the skills of a bundle fetched from a
+// BundleStore. The bundle is fully preloaded into memory, as with
+// WithCompletePreloadSource.
+type RemoteSource struct {
+	cfg    RemoteSourceConfig
+	pinned bool
+
+	mu      sync.RWMutex
+	current Source
+	lock    Lock
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewRemoteSource returns a RemoteSource, once its bundle is loaded and
+// verified. Close it to stop its background refresh.
+func NewRemoteSource(ctx context.Context, cfg RemoteSourceConfig) (*RemoteSource, error) {
+	if cfg.Store == nil {
+		return nil, fmt.Errorf("bundle store must be provided")
+	}
+	if cfg.Lockfile != "" {
+		lock, err := readLockfile(cfg.Lockfile)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		if err == nil {
+			cfg.Version, cfg.SHA256 = lock.Version, lock.SHA256
+		}
+	}
+	s := &RemoteSource{
+		cfg:    cfg,
+		pinned: cfg.Version != "",
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if err := s.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	if cfg.Lockfile != "" {
+		if err := writeLockfile(cfg.Lockfile, s.Lock()); err != nil {
+			return nil, err
+		}
+	}
+	if s.pinned || cfg.RefreshInterval <= 0 {
+		close(s.done)
+		return s, nil
+	}
+	go s.refreshLoop()
+	return s, nil
+}
+
+// Lock returns the version and checksum of the current bundle.
+func (s *RemoteSource) Lock() Lock {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lock
+}
+
+// Refresh fetches and verifies the bundle, and serves its skills if it
+// changed.
+func (s *RemoteSource) Refresh(ctx context.Context) error {
+	b, err := s.cfg.Store.Fetch(ctx, s.cfg.Version)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(b.Data)
+	lock := Lock{Version: b.Version, SHA256: hex.EncodeToString(sum[:])}
+	if s.cfg.SHA256 != "" && lock.SHA256 != s.cfg.SHA256 {
+		return fmt.Errorf("%w: bundle %q has checksum %s, want %s", ErrChecksumMismatch, b.Version, lock.SHA256, s.cfg.SHA256)
+	}
+	if err := s.verifySignature(b); err != nil {
+		return err
+	}
+	current := s.Lock()
+	if current == lock {
+		return nil
+	}
+	if !s.pinned && current.Version != "" && compareVersions(b.Version, current.Version) < 0 {
+		return fmt.Errorf("%w: bundle %q is older than %q", ErrVersionRollback, b.Version, current.Version)
+	}
+	fsys, err := bundleFS(b.Data)
+	if err != nil {
+		return fmt.Errorf("open bundle %q: %w", b.Version, err)
+	}
+	preloaded, _, err := WithCompletePreloadSource(ctx, NewFileSystemSource(fsys))
+	if err != nil {
+		return fmt.Errorf("load bundle %q: %w", b.Version, err)
+	}
+	s.mu.Lock()
+	s.current, s.lock = preloaded, lock
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *RemoteSource) verifySignature(b *Bundle) error {
+	if len(s.cfg.PublicKeys) == 0 {
+		return nil
+	}
+	if len(b.Signature) == 0 {
+		return fmt.Errorf("%w: bundle %q is not signed", ErrInvalidSignature, b.Version)
+	}
+	manifest := BundleManifest(b.Version, b.Data)
+	for _, key := range s.cfg.PublicKeys {
+		if ed25519.Verify(key, manifest, b.Signature) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: bundle %q", ErrInvalidSignature, b.Version)
+}
+
+// compareVersions compares bundle versions as semantic versions if
+// both are, and as strings otherwise.
+func compareVersions(a, b string) int {
+	sa, sb := "v"+strings.TrimPrefix(a, "v"), "v"+strings.TrimPrefix(b, "v")
+	if semver.IsValid(sa) && semver.IsValid(sb) {
+		return semver.Compare(sa, sb)
+	}
+	return strings.Compare(a, b)
+}
+
+func (s *RemoteSource) refreshLoop() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.cfg.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), s.cfg.RefreshInterval)
+		err := s.Refresh(ctx)
+		cancel()
+		if err == nil {
+			continue
+		}
+		if s.cfg.OnRefreshError != nil {
+			s.cfg.OnRefreshError(err)
+		} else {
+			log.Printf("failed to refresh skill bundle: %v", err)
+		}
+	}
+}
+
+// Close stops the background refresh of the source. The source keeps
+// serving the skills of its current bundle.
+func (s *RemoteSource) Close() error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	<-s.done
+	return nil
+}
+
+func (s *RemoteSource) source() Source {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// ListFrontmatters implements Source.
+func (s *RemoteSource) ListFrontmatters(ctx context.Context) ([]*Frontmatter, error) {
+	return s.source().ListFrontmatters(ctx)
+}
+
+// ListResources implements Source.
+func (s *RemoteSource) ListResources(ctx context.Context, name, subpath string) ([]string, error) {
+	return s.source().ListResources(ctx, name, subpath)
+}
+
+// LoadFrontmatter implements Source.
+func (s *RemoteSource) LoadFrontmatter(ctx context.Context, name string) (*Frontmatter, error) {
+	return s.source().LoadFrontmatter(ctx, name)
+}
+
+// LoadInstructions implements Source.
+func (s *RemoteSource) LoadInstructions(ctx context.Context, name string) (string, error) {
+	return s.source().LoadInstructions(ctx, name)
+}
+
+// LoadResource implements Source.
+func (s *RemoteSource) LoadResource(ctx context.Context, name, resourcePath string) (io.ReadCloser, error) {
+	return s.source().LoadResource(ctx, name, resourcePath)
+}
+
+func readLockfile(path string) (*Lock, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read lockfile: %w", err)
+	}
+	var lock Lock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("parse lockfile %q: %w", path, err)
+	}
+	if lock.Version == "" {
+		return nil, fmt.Errorf("parse lockfile %q: missing version", path)
+	}
+	return &lock, nil
+}
+
+func writeLockfile(path string, lock Lock) error {
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode lockfile: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write lockfile: %w", err)
+	}
+	return nil
+}
+
+var _ Source = (*RemoteSource)(nil)
@@ -0,0 +1,333 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skill
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/artifact"
+)
+
+func skillFiles(version string) map[string]string {
+	return map[string]string{
+		"greeter/SKILL.md":          "---\nname: greeter\ndescription: Greets in version " + version + ".\n---\nSay hello.\n",
+		"greeter/assets/hello.txt":  "hello " + version,
+		"greeter/scripts/greet.sh":  "echo hello\n",
+		"not-a-skill/README.md":     "ignored",
+		"greeter/unlisted/note.txt": "not a resource",
+	}
+}
+
+func zipBundle(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func tarGzBundle(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := w.WriteHeader(&tar.Header{Name: "./" + name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("WriteHeader: %v", err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func readResource(t *testing.T, s Source, name, resourcePath string) string {
+	t.Helper()
+	r, err := s.LoadResource(t.Context(), name, resourcePath)
+	if err != nil {
+		t.Fatalf("LoadResource: %v", err)
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	return string(data)
+}
+
+// bundleServer serves signed zip bundles at /v1 and /v2, v2 being the
+// latest.
+func bundleServer(t *testing.T, key ed25519.PrivateKey) *httptest.Server {
+	t.Helper()
+	bundles := map[string][]byte{
+		"v1": zipBundle(t, skillFiles("v1")),
+		"v2": zipBundle(t, skillFiles("v2")),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version, sig := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/"), ".sig")
+		if version == "latest" {
+			version = "v2"
+			w.Header().Set("X-Bundle-Version", version)
+		}
+		data, ok := bundles[version]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if sig {
+			data = ed25519.Sign(key, BundleManifest(version, data))
+		}
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRemoteSource_HTTP(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	server := bundleServer(t, key)
+	store := NewHTTPBundleStore(HTTPBundleStoreConfig{
+		URL:          server.URL + "/{version}",
+		SignatureURL: server.URL + "/{version}.sig",
+	})
+	lockfile := filepath.Join(t.TempDir(), "skills.lock")
+
+	// Without a lockfile, the latest bundle is loaded and locked.
+	s, err := NewRemoteSource(t.Context(), RemoteSourceConfig{Store: store, PublicKeys: []ed25519.PublicKey{pub}, Lockfile: lockfile})
+	if err != nil {
+		t.Fatalf("NewRemoteSource: %v", err)
+	}
+	if got := s.Lock().Version; got != "v2" {
+		t.Errorf("Lock().Version = %q, want v2", got)
+	}
+	frontmatters, err := s.ListFrontmatters(t.Context())
+	if err != nil {
+		t.Fatalf("ListFrontmatters: %v", err)
+	}
+	want := []*Frontmatter{{Name: "greeter", Description: "Greets in version v2."}}
+	if diff := cmp.Diff(want, frontmatters); diff != "" {
+		t.Errorf("ListFrontmatters mismatch (-want +got):\n%s", diff)
+	}
+	if got := readResource(t, s, "greeter", "assets/hello.txt"); got != "hello v2" {
+		t.Errorf("LoadResource = %q, want %q", got, "hello v2")
+	}
+	resources, err := s.ListResources(t.Context(), "greeter", ".")
+	if err != nil {
+		t.Fatalf("ListResources: %v", err)
+	}
+	if diff := cmp.Diff([]string{"assets/hello.txt", "scripts/greet.sh"}, resources); diff != "" {
+		t.Errorf("ListResources mismatch (-want +got):\n%s", diff)
+	}
+	lock := s.Lock()
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// The lockfile pins the bundle.
+	s, err = NewRemoteSource(t.Context(), RemoteSourceConfig{Store: store, Lockfile: lockfile})
+	if err != nil {
+		t.Fatalf("NewRemoteSource: %v", err)
+	}
+	if got := s.Lock(); got != lock {
+		t.Errorf("Lock() = %v, want %v", got, lock)
+	}
+
+	// A lockfile whose checksum doesn't match fails.
+	if err := os.WriteFile(lockfile, []byte(`{"version": "v1", "sha256": "`+lock.SHA256+`"}`), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := NewRemoteSource(t.Context(), RemoteSourceConfig{Store: store, Lockfile: lockfile}); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("NewRemoteSource with a wrong checksum: got error %v, want %v", err, ErrChecksumMismatch)
+	}
+
+	// Bundles signed with other keys are rejected.
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	_, err = NewRemoteSource(t.Context(), RemoteSourceConfig{Store: store, Version: "v1", PublicKeys: []ed25519.PublicKey{otherPub}})
+	if !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("NewRemoteSource with another key: got error %v, want %v", err, ErrInvalidSignature)
+	}
+
+	if _, err := NewRemoteSource(t.Context(), RemoteSourceConfig{Store: store, Version: "v3"}); !errors.Is(err, ErrBundleNotFound) {
+		t.Errorf("NewRemoteSource with a missing version: got error %v, want %v", err, ErrBundleNotFound)
+	}
+}
+
+func TestRemoteSource_ArtifactRefresh(t *testing.T) {
+	service := artifact.InMemoryService()
+	save := func(version string) {
+		t.Helper()
+		_, err := service.Save(t.Context(), &artifact.SaveRequest{
+			AppName: "app", UserID: "user", SessionID: "session", FileName: "user:skills.tar.gz",
+			Part: genai.NewPartFromBytes(tarGzBundle(t, skillFiles(version)), "application/gzip"),
+		})
+		if err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	save("v1")
+	store := NewArtifactBundleStore(ArtifactBundleStoreConfig{
+		Service: service, AppName: "app", UserID: "user", SessionID: "session", FileName: "user:skills.tar.gz",
+	})
+
+	s, err := NewRemoteSource(t.Context(), RemoteSourceConfig{Store: store, RefreshInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewRemoteSource: %v", err)
+	}
+	defer func() {
+		_ = s.Close()
+	}()
+	if got := readResource(t, s, "greeter", "assets/hello.txt"); got != "hello v1" {
+		t.Errorf("LoadResource = %q, want %q", got, "hello v1")
+	}
+
+	save("v2")
+	deadline := time.Now().Add(5 * time.Second)
+	for s.Lock().Version != "2" {
+		if time.Now().After(deadline) {
+			t.Fatal("the source wasn't refreshed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := readResource(t, s, "greeter", "assets/hello.txt"); got != "hello v2" {
+		t.Errorf("LoadResource = %q, want %q", got, "hello v2")
+	}
+
+	// A pinned source serves its version.
+	pinned, err := NewRemoteSource(t.Context(), RemoteSourceConfig{Store: store, Version: "1"})
+	if err != nil {
+		t.Fatalf("NewRemoteSource: %v", err)
+	}
+	if got := readResource(t, pinned, "greeter", "assets/hello.txt"); got != "hello v1" {
+		t.Errorf("LoadResource = %q, want %q", got, "hello v1")
+	}
+}
+
+// bundleStoreFunc adapts a function to BundleStore.
+type bundleStoreFunc func(ctx context.Context, version string) (*Bundle, error)
+
+func (f bundleStoreFunc) Fetch(ctx context.Context, version string) (*Bundle, error) {
+	return f(ctx, version)
+}
+
+func TestRemoteSource_SignedVersion(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	signed := func(version string) *Bundle {
+		data := zipBundle(t, skillFiles(version))
+		return &Bundle{Version: version, Data: data, Signature: ed25519.Sign(key, BundleManifest(version, data))}
+	}
+	v1, v2 := signed("v1"), signed("v2")
+	latest := v2
+	store := bundleStoreFunc(func(ctx context.Context, version string) (*Bundle, error) {
+		if version == "v1" {
+			return v1, nil
+		}
+		return latest, nil
+	})
+	s, err := NewRemoteSource(t.Context(), RemoteSourceConfig{Store: store, PublicKeys: []ed25519.PublicKey{pub}})
+	if err != nil {
+		t.Fatalf("NewRemoteSource: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name    string
+		latest  *Bundle
+		wantErr error
+	}{
+		{"relabeled", &Bundle{Version: "v3", Data: v1.Data, Signature: v1.Signature}, ErrInvalidSignature},
+		{"data signature", &Bundle{Version: "v3", Data: v1.Data, Signature: ed25519.Sign(key, v1.Data)}, ErrInvalidSignature},
+		{"rollback", v1, ErrVersionRollback},
+	} {
+		latest = tt.latest
+		if err := s.Refresh(t.Context()); !errors.Is(err, tt.wantErr) {
+			t.Errorf("Refresh() to a %s bundle error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+		if got := s.Lock().Version; got != "v2" {
+			t.Errorf("Lock().Version after a %s bundle = %q, want v2", tt.name, got)
+		}
+	}
+
+	// A pinned source serves its version.
+	pinned, err := NewRemoteSource(t.Context(), RemoteSourceConfig{Store: store, Version: "v1", PublicKeys: []ed25519.PublicKey{pub}})
+	if err != nil {
+		t.Fatalf("NewRemoteSource pinned to v1: %v", err)
+	}
+	if got := pinned.Lock().Version; got != "v1" {
+		t.Errorf("pinned Lock().Version = %q, want v1", got)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		want int
+	}{
+		{"v1", "v2", -1},
+		{"9", "10", -1},
+		{"v1.10.0", "1.9.0", 1},
+		{"v2", "v2", 0},
+		{"2026-01-02", "2026-01-10", -1},
+	} {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
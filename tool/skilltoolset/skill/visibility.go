@@ -0,0 +1,124 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skill
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"google.golang.org/adk/v2/agent"
+)
+
+// Metadata keys of the skills read by MetadataVisibility. Their values
+// are lists of user IDs or app names, separated by commas or spaces.
+const (
+	MetadataVisibleToUsers = "visible-to-users"
+	MetadataVisibleToApps  = "visible-to-apps"
+)
+
+// VisibilityRule reports whether the skill of fm is visible in ctx, the
+// context of the agent using the skill. ctx is nil when the source is
+// used outside of an agent run, e.g. to describe the agent in an A2A
+// agent card.
+type VisibilityRule func(ctx agent.ReadonlyContext, fm *Frontmatter) bool
+
+// WithVisibility returns a Source proxy that only exposes the skills of
+// source visible according to rule: the other skills are left out of
+// ListFrontmatters, and loading them fails with ErrSkillNotFound.
+func WithVisibility(source Source, rule VisibilityRule) Source {
+	return &visibilitySource{base: source, rule: rule}
+}
+
+// MetadataVisibility is a VisibilityRule reading the visibility of the
+// skills from their metadata: a skill with MetadataVisibleToUsers or
+// MetadataVisibleToApps is only visible to the listed users or apps,
+// and outside of agent runs, to no one.
+func MetadataVisibility(ctx agent.ReadonlyContext, fm *Frontmatter) bool {
+	visibleTo := func(key string, id func() string) bool {
+		list, ok := fm.Metadata[key]
+		if !ok {
+			return true
+		}
+		if ctx == nil {
+			return false
+		}
+		return slices.Contains(strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' }), id())
+	}
+	return visibleTo(MetadataVisibleToUsers, func() string { return ctx.UserID() }) &&
+		visibleTo(MetadataVisibleToApps, func() string { return ctx.AppName() })
+}
+
+type visibilitySource struct {
+	base Source
+	rule VisibilityRule
+}
+
+func (s *visibilitySource) visible(ctx context.Context, fm *Frontmatter) bool {
+	rc, _ := ctx.(agent.ReadonlyContext)
+	return s.rule(rc, fm)
+}
+
+// check returns ErrSkillNotFound if the skill name isn't visible in ctx.
+func (s *visibilitySource) check(ctx context.Context, name string) error {
+	fm, err := s.base.LoadFrontmatter(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !s.visible(ctx, fm) {
+		return fmt.Errorf("%w: %q", ErrSkillNotFound, name)
+	}
+	return nil
+}
+
+func (s *visibilitySource) ListFrontmatters(ctx context.Context) ([]*Frontmatter, error) {
+	frontmatters, err := s.base.ListFrontmatters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(slices.Clone(frontmatters), func(fm *Frontmatter) bool {
+		return !s.visible(ctx, fm)
+	}), nil
+}
+
+func (s *visibilitySource) ListResources(ctx context.Context, name, subpath string) ([]string, error) {
+	if err := s.check(ctx, name); err != nil {
+		return nil, err
+	}
+	return s.base.ListResources(ctx, name, subpath)
+}
+
+func (s *visibilitySource) LoadFrontmatter(ctx context.Context, name string) (*Frontmatter, error) {
+	if err := s.check(ctx, name); err != nil {
+		return nil, err
+	}
+	return s.base.LoadFrontmatter(ctx, name)
+}
+
+func (s *visibilitySource) LoadInstructions(ctx context.Context, name string) (string, error) {
+	if err := s.check(ctx, name); err != nil {
+		return "", err
+	}
+	return s.base.LoadInstructions(ctx, name)
+}
+
+func (s *visibilitySource) LoadResource(ctx context.Context, name, resourcePath string) (io.ReadCloser, error) {
+	if err := s.check(ctx, name); err != nil {
+		return nil, err
+	}
+	return s.base.LoadResource(ctx, name, resourcePath)
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skill
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+
+	icontext "google.golang.org/adk/v2/internal/context"
+	"google.golang.org/adk/v2/session"
+)
+
+func userContext(t *testing.T, app, user string) context.Context {
+	t.Helper()
+	resp, err := session.InMemoryService().Create(t.Context(), &session.CreateRequest{AppName: app, UserID: user})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return icontext.NewReadonlyContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Session: resp.Session}))
+}
+
+func TestWithVisibility(t *testing.T) {
+	source := WithVisibility(NewFileSystemSource(fstest.MapFS{
+		"public/SKILL.md":    {Data: []byte("---\nname: public\ndescription: For everyone.\n---\n")},
+		"admins/SKILL.md":    {Data: []byte("---\nname: admins\ndescription: For admins.\nmetadata:\n  visible-to-users: alice, bob\n---\n")},
+		"support/SKILL.md":   {Data: []byte("---\nname: support\ndescription: For the support app.\nmetadata:\n  visible-to-apps: support\n---\n")},
+		"admins/assets/a.md": {Data: []byte("a")},
+	}), MetadataVisibility)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want []string
+	}{
+		{name: "alice in support", ctx: userContext(t, "support", "alice"), want: []string{"admins", "public", "support"}},
+		{name: "carol in support", ctx: userContext(t, "support", "carol"), want: []string{"public", "support"}},
+		{name: "bob in sales", ctx: userContext(t, "sales", "bob"), want: []string{"admins", "public"}},
+		{name: "outside of agent runs", ctx: t.Context(), want: []string{"public"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frontmatters, err := source.ListFrontmatters(tt.ctx)
+			if err != nil {
+				t.Fatalf("ListFrontmatters: %v", err)
+			}
+			var got []string
+			for _, fm := range frontmatters {
+				got = append(got, fm.Name)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ListFrontmatters mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	carol := userContext(t, "support", "carol")
+	if _, err := source.LoadInstructions(carol, "admins"); !errors.Is(err, ErrSkillNotFound) {
+		t.Errorf("LoadInstructions of a hidden skill: got error %v, want %v", err, ErrSkillNotFound)
+	}
+	if _, err := source.LoadResource(carol, "admins", "assets/a.md"); !errors.Is(err, ErrSkillNotFound) {
+		t.Errorf("LoadResource of a hidden skill: got error %v, want %v", err, ErrSkillNotFound)
+	}
+	if _, err := source.LoadResource(userContext(t, "support", "alice"), "admins", "assets/a.md"); err != nil {
+		t.Errorf("LoadResource of a visible skill: %v", err)
+	}
+}
@@ -16,11 +16,17 @@ package skilltoolset
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"slices"
+
+	"google.golang.org/genai"
 
 	"google.golang.org/adk/v2/agent"
 	"google.golang.org/adk/v2/internal/utils"
 	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/session"
 	"google.golang.org/adk/v2/tool"
 	"google.golang.org/adk/v2/tool/skilltoolset/internal/skilltool"
 	"google.golang.org/adk/v2/tool/skilltoolset/skill"
@@ -44,6 +50,14 @@ This is very important:
 		"3. The `load_skill_resource` tool is for viewing files within a skill's directory (e.g., `references/*`, `assets/*`, `scripts/*`). Do NOT use other tools to access these files.\n"
 )
 
+const (
+	scriptsInstruction = "4. The `run_skill_script` tool runs a script from a skill's `scripts/` directory. Use it instead of reading the script when the instructions tell you to run it.\n"
+
+	// activeSkillKeyPrefix prefixes the session state key holding the
+	// name of the skill last loaded with the toolset in the invocation.
+	activeSkillKeyPrefix = session.KeyPrefixTemp + "active_skill:"
+)
+
 // Config holds the configuration for creating a Skill Toolset.
 type Config struct {
 	Source skill.Source
@@ -51,9 +65,17 @@ type Config struct {
 	Name string
 	// Optional system instruction. If empty, default instruction will be used.
 	SystemInstruction string
+	// Optional configuration of the run_skill_script tool. If nil, skill
+	// scripts can only be read, not run.
+	Scripts *ScriptConfig
 }
 
 // SkillToolset provides a toolset for skills.
+//
+// Once the agent loads a skill, the skill is active for the rest of the
+// invocation, or until another skill is loaded. While a skill declaring
+// allowed-tools is active, the other tools of the agent are withheld
+// from the model, except those of the toolset.
 type SkillToolset struct {
 	name              string
 	tools             []tool.Tool
@@ -71,6 +93,9 @@ func New(ctx context.Context, cfg Config) (*SkillToolset, error) {
 		name = cfg.Name
 	}
 	instruction := defaultSkillSystemInstruction
+	if cfg.Scripts != nil {
+		instruction += scriptsInstruction
+	}
 	if cfg.SystemInstruction != "" {
 		instruction = cfg.SystemInstruction
 	}
@@ -78,7 +103,7 @@ func New(ctx context.Context, cfg Config) (*SkillToolset, error) {
 	if err != nil {
 		return nil, fmt.Errorf("create list skills tool: %w", err)
 	}
-	loadTool, err := skilltool.LoadSkill(cfg.Source)
+	loadTool, err := skilltool.LoadSkill(cfg.Source, activeSkillKeyPrefix+name)
 	if err != nil {
 		return nil, fmt.Errorf("create load skill tool: %w", err)
 	}
@@ -86,9 +111,17 @@ func New(ctx context.Context, cfg Config) (*SkillToolset, error) {
 	if err != nil {
 		return nil, fmt.Errorf("create load skill resource tool: %w", err)
 	}
+	tools := []tool.Tool{listTool, loadTool, loadResourceTool}
+	if cfg.Scripts != nil {
+		runScriptTool, err := skilltool.RunSkillScript(cfg.Source, cfg.Scripts.runScriptConfig())
+		if err != nil {
+			return nil, fmt.Errorf("create run skill script tool: %w", err)
+		}
+		tools = append(tools, runScriptTool)
+	}
 	return &SkillToolset{
 		name:              name,
-		tools:             []tool.Tool{listTool, loadTool, loadResourceTool},
+		tools:             tools,
 		source:            cfg.Source,
 		systemInstruction: instruction,
 	}, nil
@@ -101,9 +134,16 @@ func (ts *SkillToolset) Name() string { return ts.name }
 // interact with skills.
 func (ts *SkillToolset) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) { return ts.tools, nil }
 
+// Skills returns frontmatters of the skills available through the toolset.
+// It can be used to describe agent capabilities, for example in an A2A agent card.
+func (ts *SkillToolset) Skills(ctx context.Context) ([]*skill.Frontmatter, error) {
+	return ts.source.ListFrontmatters(ctx)
+}
+
 // ProcessRequest implements toolinternal.RequestProcessor. It attaches
 // the list of available skills and the system instruction explaining to the
-// agent what it can do with these skills.
+// agent what it can do with these skills, and restricts the tools of the
+// request to the allowed-tools of the active skill.
 func (ts *SkillToolset) ProcessRequest(ctx agent.Context, req *model.LLMRequest) error {
 	skills, err := ts.source.ListFrontmatters(ctx)
 	if err != nil {
@@ -113,5 +153,56 @@ func (ts *SkillToolset) ProcessRequest(ctx agent.Context, req *model.LLMRequest)
 		return nil
 	}
 	utils.AppendInstructions(req, ts.systemInstruction, skilltool.SkillsToXML(skills))
+	return ts.restrictTools(ctx, req)
+}
+
+// restrictTools removes the tools the active skill doesn't allow from req.
+func (ts *SkillToolset) restrictTools(ctx agent.Context, req *model.LLMRequest) error {
+	if ctx == nil {
+		return nil
+	}
+	v, err := ctx.State().Get(activeSkillKeyPrefix + ts.name)
+	if errors.Is(err, session.ErrStateKeyNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get active skill: %w", err)
+	}
+	name, _ := v.(string)
+	if name == "" {
+		return nil
+	}
+	fm, err := ts.source.LoadFrontmatter(ctx, name)
+	if err != nil {
+		return fmt.Errorf("load frontmatter for active skill %q: %w", name, err)
+	}
+	if len(fm.AllowedTools) == 0 {
+		return nil
+	}
+	allowed := func(toolName string) bool {
+		return fm.AllowsTool(toolName) || slices.ContainsFunc(ts.tools, func(t tool.Tool) bool { return t.Name() == toolName })
+	}
+	for toolName := range req.Tools {
+		if !allowed(toolName) {
+			delete(req.Tools, toolName)
+		}
+	}
+	if req.Config != nil {
+		req.Config.Tools = slices.DeleteFunc(req.Config.Tools, func(t *genai.Tool) bool {
+			if t == nil || t.FunctionDeclarations == nil {
+				return false
+			}
+			t.FunctionDeclarations = slices.DeleteFunc(t.FunctionDeclarations, func(decl *genai.FunctionDeclaration) bool {
+				return decl != nil && !allowed(decl.Name)
+			})
+			if len(t.FunctionDeclarations) > 0 {
+				return false
+			}
+			// Drop the tools left empty, keeping built-in ones.
+			t.FunctionDeclarations = nil
+			return reflect.ValueOf(*t).IsZero()
+		})
+	}
+	utils.AppendInstructions(req, fmt.Sprintf("The skill %q is active: only the tools it allows are available.", name))
 	return nil
 }
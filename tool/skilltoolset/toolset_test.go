@@ -16,15 +16,24 @@ package skilltoolset_test
 
 import (
 	"context"
+	"slices"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/genai"
 
+	"google.golang.org/adk/v2/agent"
+	icontext "google.golang.org/adk/v2/internal/context"
+	"google.golang.org/adk/v2/internal/toolinternal"
 	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
 	"google.golang.org/adk/v2/tool/skilltoolset"
 	"google.golang.org/adk/v2/tool/skilltoolset/skill"
+	"google.golang.org/adk/v2/tool/toolutils"
 )
 
 type mockSource struct {
@@ -123,3 +132,100 @@ func TestTools(t *testing.T) {
 		t.Errorf("Tools result mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestProcessRequest_AllowedTools(t *testing.T) {
+	source := skill.NewFileSystemSource(fstest.MapFS{
+		"weather/SKILL.md":  {Data: []byte("---\nname: weather\ndescription: Weather reports.\nallowed-tools:\n  - get_weather\n---\nCall get_weather.\n")},
+		"anything/SKILL.md": {Data: []byte("---\nname: anything\ndescription: Anything.\n---\nDo anything.\n")},
+	})
+	ts, err := skilltoolset.New(t.Context(), skilltoolset.Config{Source: source})
+	if err != nil {
+		t.Fatalf("skilltoolset.New failed: %v", err)
+	}
+	skillTools, err := ts.Tools(nil)
+	if err != nil {
+		t.Fatalf("Tools failed: %v", err)
+	}
+	resp, err := session.InMemoryService().Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Session: resp.Session})
+
+	toolNames := func(skillName string) []string {
+		t.Helper()
+		if skillName != "" {
+			var loadSkill toolinternal.FunctionTool
+			for _, tl := range skillTools {
+				if tl.Name() == "load_skill" {
+					loadSkill = tl.(toolinternal.FunctionTool)
+				}
+			}
+			toolCtx := agent.NewToolContext(invCtx, "call", &session.EventActions{StateDelta: map[string]any{}}, nil)
+			if _, err := loadSkill.Run(toolCtx, map[string]any{"name": skillName}); err != nil {
+				t.Fatalf("load_skill failed: %v", err)
+			}
+		}
+		req := &model.LLMRequest{}
+		for _, tl := range skillTools {
+			if err := toolutils.PackTool(req, tl.(toolutils.Tool)); err != nil {
+				t.Fatalf("PackTool failed: %v", err)
+			}
+		}
+		for _, name := range []string{"get_weather", "get_time"} {
+			if err := toolutils.PackTool(req, &fakeTool{name: name}); err != nil {
+				t.Fatalf("PackTool failed: %v", err)
+			}
+		}
+		if err := ts.ProcessRequest(agent.NewToolContext(invCtx, "", nil, nil), req); err != nil {
+			t.Fatalf("ProcessRequest failed: %v", err)
+		}
+		var declared []string
+		for _, decl := range req.Config.Tools[0].FunctionDeclarations {
+			declared = append(declared, decl.Name)
+			if _, ok := req.Tools[decl.Name]; !ok {
+				t.Errorf("tool %q is declared but not in req.Tools", decl.Name)
+			}
+		}
+		if len(declared) != len(req.Tools) {
+			t.Errorf("req.Tools has %d tools, want %d", len(req.Tools), len(declared))
+		}
+		return declared
+	}
+
+	all := []string{"list_skills", "load_skill", "load_skill_resource", "get_weather", "get_time"}
+	if diff := cmp.Diff(all, toolNames("")); diff != "" {
+		t.Errorf("tools without an active skill mismatch (-want +got):\n%s", diff)
+	}
+	want := []string{"list_skills", "load_skill", "load_skill_resource", "get_weather"}
+	if diff := cmp.Diff(want, toolNames("weather")); diff != "" {
+		t.Errorf("tools with an active skill mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(all, toolNames("anything")); diff != "" {
+		t.Errorf("tools with an active skill without allowed-tools mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTools_Scripts(t *testing.T) {
+	toolset, err := skilltoolset.New(t.Context(), skilltoolset.Config{Source: &mockSource{}, Scripts: &skilltoolset.ScriptConfig{}})
+	if err != nil {
+		t.Fatalf("skilltoolset.New failed: %v", err)
+	}
+	tools, err := toolset.Tools(nil)
+	if err != nil {
+		t.Fatalf("Tools failed: %v", err)
+	}
+	if !slices.ContainsFunc(tools, func(tl tool.Tool) bool { return tl.Name() == "run_skill_script" }) {
+		t.Error("Tools: run_skill_script is missing with Config.Scripts set")
+	}
+}
+
+type fakeTool struct {
+	name string
+}
+
+func (f *fakeTool) Name() string { return f.name }
+
+func (f *fakeTool) Declaration() *genai.FunctionDeclaration {
+	return &genai.FunctionDeclaration{Name: f.name}
+}
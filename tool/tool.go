@@ -190,21 +190,15 @@ func (t *confirmationTool) Declaration() *genai.FunctionDeclaration {
 }
 
 func (t *confirmationTool) ProcessRequest(ctx agent.Context, req *model.LLMRequest) error {
-	if rp, ok := t.runnableTool.(interface {
-		ProcessRequest(ctx agent.Context, req *model.LLMRequest) error
-	}); ok {
-		_, existedBefore := req.Tools[t.Name()]
-		if err := rp.ProcessRequest(ctx, req); err != nil {
-			return err
-		}
-		// If the inner tool packed itself into req.Tools during ProcessRequest,
-		// replace it with the confirmation wrapper so confirmationTool.Run is invoked.
-		if !existedBefore && req.Tools != nil && req.Tools[t.Name()] != nil {
-			req.Tools[t.Name()] = t
-			return nil
-		}
-	}
-	return toolutils.PackTool(req, t)
+	return toolutils.PackWrappedTool(ctx, req, t.runnableTool, t)
+}
+
+func (t *confirmationTool) ExecutionOptions() ExecutionOptions {
+	return ExecutionOptionsOf(t.runnableTool)
+}
+
+func (t *confirmationTool) RequiresConfirmation() bool {
+	return t.requireConfirmation || t.provider != nil
 }
 
 func (t *confirmationTool) Run(ctx agent.Context, args any) (map[string]any, error) {
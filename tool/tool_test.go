@@ -123,8 +123,11 @@ type testContext struct {
 	toolConfirmationResult    *toolconfirmation.ToolConfirmation
 	requestConfirmationCalled bool
 	eventActions              *session.EventActions
+	state                     session.State
 }
 
+func (c *testContext) State() session.State { return c.state }
+
 // Deadline implements [agent.InvocationContext].
 func (c *testContext) Deadline() (deadline time.Time, ok bool) {
 	return c.Context.Deadline()
@@ -172,6 +175,12 @@ func (c *testContext) SessionID() string                                       {
 func (c *testContext) UserID() string                                          { return "test-user-id" }
 func (m *testContext) WithContext(ctx context.Context) agent.InvocationContext { return m }
 
+// Principal implements [agent.InvocationContext].
+func (c *testContext) Principal() *agent.Principal {
+	principal, _ := agent.PrincipalFromContext(c.Context)
+	return principal
+}
+
 var _ agent.InvocationContext = (*testContext)(nil)
 
 type testToolset struct {
@@ -0,0 +1,158 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolcache
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/session"
+)
+
+// DefaultStoreSize is the number of results kept by the default in-memory
+// store.
+const DefaultStoreSize = 1000
+
+// Store keeps the cached results of tool calls. The keys identify the
+// tool, the arguments and the scope of the calls; the scope tells which
+// calls share the results.
+//
+// The results returned by Get may be modified by the caller, and so may
+// the results passed to Set after it returns: stores keep copies.
+type Store interface {
+	// Get returns the result cached under key, if it didn't expire.
+	Get(ctx agent.Context, scope Scope, key string) (result map[string]any, ok bool, err error)
+	// Set caches result under key for ttl, or until evicted if ttl isn't
+	// positive.
+	Set(ctx agent.Context, scope Scope, key string, result map[string]any, ttl time.Duration) error
+}
+
+// NewInMemoryStore returns a Store keeping the last used size results in
+// memory, for the lifetime of the process.
+func NewInMemoryStore(size int) Store {
+	cache, err := lru.New[string, memoryEntry](max(size, 1))
+	if err != nil {
+		// Unreachable: the size is positive.
+		panic(err)
+	}
+	return &memoryStore{cache: cache}
+}
+
+type memoryStore struct {
+	cache *lru.Cache[string, memoryEntry]
+}
+
+type memoryEntry struct {
+	result  map[string]any
+	expires time.Time
+}
+
+func (s *memoryStore) Get(ctx agent.Context, scope Scope, key string) (map[string]any, bool, error) {
+	e, ok := s.cache.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		s.cache.Remove(key)
+		return nil, false, nil
+	}
+	return maps.Clone(e.result), true, nil
+}
+
+func (s *memoryStore) Set(ctx agent.Context, scope Scope, key string, result map[string]any, ttl time.Duration) error {
+	s.cache.Add(key, memoryEntry{result: maps.Clone(result), expires: expiry(ttl)})
+	return nil
+}
+
+// StateKeyPrefix prefixes the keys of the session state holding the
+// results cached by the store returned by NewStateStore.
+const StateKeyPrefix = "tool_cache:"
+
+// NewStateStore returns a Store keeping the results in the session state,
+// persisted by the session service. The results of ScopeInvocation are
+// kept in the temporary state, those of ScopeUser in the user state and
+// those of ScopeApp in the app state; see session.KeyPrefixTemp,
+// session.KeyPrefixUser and session.KeyPrefixApp. The results must be
+// encodable to JSON.
+//
+// Expired results are only replaced by the next call of their tool with
+// the same arguments; they are never removed from the state.
+func NewStateStore() Store {
+	return stateStore{}
+}
+
+type stateStore struct{}
+
+func (stateStore) stateKey(scope Scope, key string) string {
+	switch scope {
+	case ScopeInvocation:
+		return session.KeyPrefixTemp + StateKeyPrefix + key
+	case ScopeUser:
+		return session.KeyPrefixUser + StateKeyPrefix + key
+	case ScopeApp:
+		return session.KeyPrefixApp + StateKeyPrefix + key
+	}
+	return StateKeyPrefix + key
+}
+
+func (s stateStore) Get(ctx agent.Context, scope Scope, key string) (map[string]any, bool, error) {
+	v, err := ctx.State().Get(s.stateKey(scope, key))
+	if errors.Is(err, session.ErrStateKeyNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	e, ok := v.(map[string]any)
+	if !ok {
+		return nil, false, fmt.Errorf("cached result %q is a %T, want a map", key, v)
+	}
+	if expires, ok := e["expires"].(string); ok {
+		t, err := time.Parse(time.RFC3339Nano, expires)
+		if err != nil {
+			return nil, false, fmt.Errorf("cached result %q: %w", key, err)
+		}
+		if time.Now().After(t) {
+			return nil, false, nil
+		}
+	}
+	result, ok := e["result"].(map[string]any)
+	if !ok {
+		return nil, false, fmt.Errorf("cached result %q has no result", key)
+	}
+	return maps.Clone(result), true, nil
+}
+
+func (s stateStore) Set(ctx agent.Context, scope Scope, key string, result map[string]any, ttl time.Duration) error {
+	e := map[string]any{"result": maps.Clone(result)}
+	if expires := expiry(ttl); !expires.IsZero() {
+		e["expires"] = expires.Format(time.RFC3339Nano)
+	}
+	return ctx.State().Set(s.stateKey(scope, key), e)
+}
+
+// expiry returns the expiry time of a result cached for ttl, or the zero
+// time if it doesn't expire.
+func expiry(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
@@ -0,0 +1,245 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package toolcache provides a cache of the results of tool calls, for the
+// read-only tools agents often call with the same arguments.
+//
+// The results are keyed by the name of the tool, its arguments and a
+// Scope: the calls of an invocation, a session, a user or the whole app
+// share them. Calls served from the cache don't run the tool, so its side
+// effects, e.g. state changes, don't happen: only wrap read-only tools.
+//
+//	weather := toolcache.New(getWeather, toolcache.Config{
+//		Scope: toolcache.ScopeSession,
+//		TTL:   10 * time.Minute,
+//	})
+//
+// The function response events holding cached responses list the IDs of
+// their function calls in their CustomMetadata, under HitsMetadataKey.
+//
+// Long-running tools and tools which may ask for a tool confirmation are
+// never cached, nor are results carrying function response parts, such as
+// the images returned by MCP tools, which the stores can't encode.
+package toolcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/toolutils"
+)
+
+// HitsMetadataKey is the key of the CustomMetadata of the function
+// response events listing the IDs of the function calls served from a
+// cache.
+const HitsMetadataKey = toolinternal.CacheHitsMetadataKey
+
+// Scope is the set of calls sharing cached results.
+type Scope int
+
+const (
+	// ScopeInvocation shares the results between the calls of an
+	// invocation.
+	ScopeInvocation Scope = iota
+	// ScopeSession shares the results between the calls of a session.
+	ScopeSession
+	// ScopeUser shares the results between the calls of a user, across
+	// sessions.
+	ScopeUser
+	// ScopeApp shares the results between all the calls of the app.
+	ScopeApp
+)
+
+func (s Scope) String() string {
+	switch s {
+	case ScopeInvocation:
+		return "invocation"
+	case ScopeSession:
+		return "session"
+	case ScopeUser:
+		return "user"
+	case ScopeApp:
+		return "app"
+	}
+	return "unknown"
+}
+
+// Config configures a cache.
+type Config struct {
+	// Scope is the set of calls sharing the cached results. Defaults to
+	// ScopeInvocation.
+	Scope Scope
+	// TTL, if positive, is how long the results are cached. Otherwise they
+	// are cached until the store evicts them.
+	TTL time.Duration
+	// Store keeps the cached results. Defaults to an in-memory store of
+	// DefaultStoreSize results.
+	Store Store
+	// OnStoreError, if set, is called with the errors of the store. The
+	// calls run uncached when the store fails.
+	OnStoreError func(error)
+}
+
+func (cfg Config) withDefaults() Config {
+	if cfg.Store == nil {
+		cfg.Store = NewInMemoryStore(DefaultStoreSize)
+	}
+	return cfg
+}
+
+// New wraps t to cache the results of its calls. Tools which can't be
+// cached, such as long-running tools, tools which may ask for a tool
+// confirmation and tools which don't provide a FunctionDeclaration and a
+// Run method, are returned unmodified.
+func New(t tool.Tool, cfg Config) tool.Tool {
+	return wrap(t, cfg.withDefaults())
+}
+
+// NewToolset wraps ts to cache the results of the calls of its tools, as
+// New does. The tools of ts share the store of cfg.
+func NewToolset(ts tool.Toolset, cfg Config) tool.Toolset {
+	if ts == nil {
+		panic("toolset must not be nil")
+	}
+	return &cachedToolset{toolset: ts, cfg: cfg.withDefaults()}
+}
+
+type cachedToolset struct {
+	toolset tool.Toolset
+	cfg     Config
+}
+
+func (s *cachedToolset) Name() string { return s.toolset.Name() }
+
+func (s *cachedToolset) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	tools, err := s.toolset.Tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := make([]tool.Tool, 0, len(tools))
+	for _, t := range tools {
+		wrapped = append(wrapped, wrap(t, s.cfg))
+	}
+	return wrapped, nil
+}
+
+func wrap(t tool.Tool, cfg Config) tool.Tool {
+	ft, ok := t.(toolinternal.FunctionTool)
+	if !ok || !cacheable(t) {
+		return t
+	}
+	return &cachedTool{FunctionTool: ft, cfg: cfg}
+}
+
+// cacheable reports whether the results of the calls of t can be cached.
+func cacheable(t tool.Tool) bool {
+	if t.IsLongRunning() {
+		return false
+	}
+	if c, ok := t.(toolinternal.ConfirmationRequirer); ok && c.RequiresConfirmation() {
+		return false
+	}
+	if d, ok := t.(toolinternal.ResponseDeferrer); ok && d.DefersResponse() {
+		return false
+	}
+	_, streaming := t.(toolinternal.StreamingFunctionTool)
+	return !streaming
+}
+
+// cachedTool is a wrapper around a tool caching the results of its calls.
+type cachedTool struct {
+	toolinternal.FunctionTool
+	cfg Config
+}
+
+func (t *cachedTool) Declaration() *genai.FunctionDeclaration {
+	return t.FunctionTool.Declaration()
+}
+
+func (t *cachedTool) ProcessRequest(ctx agent.Context, req *model.LLMRequest) error {
+	return toolutils.PackWrappedTool(ctx, req, t.FunctionTool, t)
+}
+
+func (t *cachedTool) ExecutionOptions() tool.ExecutionOptions {
+	return tool.ExecutionOptionsOf(t.FunctionTool)
+}
+
+func (t *cachedTool) Run(ctx agent.Context, args any) (map[string]any, error) {
+	if ctx.ToolConfirmation() != nil {
+		// The call resumes after a confirmation: it's never cached.
+		return t.FunctionTool.Run(ctx, args)
+	}
+	key, err := t.key(ctx, args)
+	if err != nil {
+		// The arguments can't be canonicalized.
+		return t.FunctionTool.Run(ctx, args)
+	}
+	result, ok, err := t.cfg.Store.Get(ctx, t.cfg.Scope, key)
+	if err != nil {
+		t.storeError(err)
+	} else if ok {
+		result[toolinternal.CacheHitKey] = true
+		return result, nil
+	}
+
+	result, err = t.FunctionTool.Run(ctx, args)
+	if err != nil || result == nil || len(ctx.Actions().RequestedToolConfirmations) > 0 {
+		return result, err
+	}
+	if _, ok := result[toolinternal.ResponsePartsKey]; ok {
+		return result, nil
+	}
+	if err := t.cfg.Store.Set(ctx, t.cfg.Scope, key, result, t.cfg.TTL); err != nil {
+		t.storeError(err)
+	}
+	return result, nil
+}
+
+// key returns the key of the result of the call of the tool with args: the
+// name of the tool followed by a hash of the scope of the call and of the
+// canonical JSON encoding of args, whose object keys are sorted.
+func (t *cachedTool) key(ctx agent.ReadonlyContext, args any) (string, error) {
+	scope := []string{ctx.AppName()}
+	switch t.cfg.Scope {
+	case ScopeInvocation:
+		scope = append(scope, ctx.UserID(), ctx.SessionID(), ctx.InvocationID())
+	case ScopeSession:
+		scope = append(scope, ctx.UserID(), ctx.SessionID())
+	case ScopeUser:
+		scope = append(scope, ctx.UserID())
+	}
+	b, err := json.Marshal(struct {
+		Scope []string `json:"scope"`
+		Args  any      `json:"args"`
+	}{scope, args})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return t.Name() + ":" + hex.EncodeToString(sum[:]), nil
+}
+
+func (t *cachedTool) storeError(err error) {
+	if t.cfg.OnStoreError != nil {
+		t.cfg.OnStoreError(err)
+	}
+}
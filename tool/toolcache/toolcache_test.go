@@ -0,0 +1,360 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolcache_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/agent/llmagent"
+	icontext "google.golang.org/adk/v2/internal/context"
+	"google.golang.org/adk/v2/internal/testutil"
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/functiontool"
+	"google.golang.org/adk/v2/tool/toolcache"
+)
+
+type lookupArgs struct {
+	City string `json:"city"`
+}
+
+// newLookup returns a tool counting its runs.
+func newLookup(t *testing.T, cfg functiontool.Config, runs *atomic.Int32) tool.Tool {
+	t.Helper()
+	if cfg.Name == "" {
+		cfg.Name = "lookup"
+	}
+	lookup, err := functiontool.New(cfg, func(ctx agent.Context, args lookupArgs) (map[string]any, error) {
+		n := runs.Add(1)
+		if args.City == "" {
+			return nil, errors.New("no city")
+		}
+		return map[string]any{"city": args.City, "run": n}, nil
+	})
+	if err != nil {
+		t.Fatalf("functiontool.New: %v", err)
+	}
+	return lookup
+}
+
+func lookupCall(id, city string) *genai.Part {
+	return &genai.Part{FunctionCall: &genai.FunctionCall{ID: id, Name: "lookup", Args: map[string]any{"city": city}}}
+}
+
+// turn is an invocation whose model makes the calls of each response in
+// turn.
+type turn struct {
+	userID, sessionID string
+	responses         [][]*genai.Part
+}
+
+// runTurns runs the turns, and returns the number of calls and the number
+// of function responses marked as cache hits.
+func runTurns(t *testing.T, tl tool.Tool, turns ...turn) (calls, hits int) {
+	t.Helper()
+	var responses []*genai.Content
+	for _, tn := range turns {
+		for _, parts := range tn.responses {
+			responses = append(responses, &genai.Content{Role: genai.RoleModel, Parts: parts})
+			calls += len(parts)
+		}
+		responses = append(responses, genai.NewContentFromText("done", genai.RoleModel))
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "agent",
+		Model: &testutil.MockModel{Responses: responses},
+		Tools: []tool.Tool{tl},
+	})
+	if err != nil {
+		t.Fatalf("llmagent.New: %v", err)
+	}
+	r, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: session.InMemoryService(), AutoCreateSession: true})
+	if err != nil {
+		t.Fatalf("runner.New: %v", err)
+	}
+	for _, tn := range turns {
+		for ev, err := range r.Run(t.Context(), tn.userID, tn.sessionID, genai.NewContentFromText("go", genai.RoleUser), agent.RunConfig{}) {
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			ids, _ := ev.CustomMetadata[toolcache.HitsMetadataKey].([]string)
+			hits += len(ids)
+			for _, p := range ev.Content.Parts {
+				if p.FunctionResponse != nil {
+					if _, ok := p.FunctionResponse.Response[toolinternal.CacheHitKey]; ok {
+						t.Errorf("function response %v holds the cache hit key", p.FunctionResponse.Response)
+					}
+				}
+			}
+		}
+	}
+	return calls, hits
+}
+
+func TestCache_Scopes(t *testing.T) {
+	turns := []turn{
+		// A second call in the same invocation.
+		{"user1", "session1", [][]*genai.Part{{lookupCall("1", "Paris")}, {lookupCall("2", "Paris")}}},
+		// A second invocation of the session.
+		{"user1", "session1", [][]*genai.Part{{lookupCall("3", "Paris")}}},
+		// Another session of the user.
+		{"user1", "session2", [][]*genai.Part{{lookupCall("4", "Paris")}}},
+		// Another user.
+		{"user2", "session3", [][]*genai.Part{{lookupCall("5", "Paris")}}},
+	}
+	stores := map[string]func() toolcache.Store{
+		"memory": func() toolcache.Store { return toolcache.NewInMemoryStore(10) },
+		"state":  toolcache.NewStateStore,
+	}
+	tests := []struct {
+		scope    toolcache.Scope
+		wantRuns int
+	}{
+		{toolcache.ScopeInvocation, 4},
+		{toolcache.ScopeSession, 3},
+		{toolcache.ScopeUser, 2},
+		{toolcache.ScopeApp, 1},
+	}
+	for storeName, newStore := range stores {
+		for _, tt := range tests {
+			t.Run(storeName+"/"+tt.scope.String(), func(t *testing.T) {
+				var runs atomic.Int32
+				var storeErr error
+				tl := toolcache.New(newLookup(t, functiontool.Config{}, &runs), toolcache.Config{
+					Scope:        tt.scope,
+					Store:        newStore(),
+					OnStoreError: func(err error) { storeErr = err },
+				})
+				calls, hits := runTurns(t, tl, turns...)
+
+				if storeErr != nil {
+					t.Errorf("store error: %v", storeErr)
+				}
+				if got := int(runs.Load()); got != tt.wantRuns {
+					t.Errorf("ran the tool %d times, want %d", got, tt.wantRuns)
+				}
+				if want := calls - tt.wantRuns; hits != want {
+					t.Errorf("marked %d cache hits, want %d", hits, want)
+				}
+			})
+		}
+	}
+}
+
+func TestCache_Toolset(t *testing.T) {
+	var runs atomic.Int32
+	ts := toolcache.NewToolset(testToolset{newLookup(t, functiontool.Config{}, &runs)}, toolcache.Config{Scope: toolcache.ScopeSession})
+	tools, err := ts.Tools(nil)
+	if err != nil {
+		t.Fatalf("Tools: %v", err)
+	}
+	calls, hits := runTurns(t, tools[0],
+		turn{"user", "session", [][]*genai.Part{{lookupCall("1", "Paris"), lookupCall("2", "Rome")}}},
+		turn{"user", "session", [][]*genai.Part{{lookupCall("3", "Paris"), lookupCall("4", "Rome"), lookupCall("5", "Oslo")}}},
+	)
+	if runs.Load() != 3 || calls-hits != 3 {
+		t.Errorf("ran the tool %d times, with %d cache hits out of %d calls, want 3 runs", runs.Load(), hits, calls)
+	}
+}
+
+type testToolset []tool.Tool
+
+func (ts testToolset) Name() string { return "test" }
+
+func (ts testToolset) Tools(agent.ReadonlyContext) ([]tool.Tool, error) { return ts, nil }
+
+func newToolContext(t *testing.T) agent.Context {
+	t.Helper()
+	resp, err := session.InMemoryService().Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Session: resp.Session})
+	return agent.NewToolContext(invCtx, "call", &session.EventActions{StateDelta: map[string]any{}}, nil)
+}
+
+func run(t *testing.T, ctx agent.Context, tl tool.Tool, args map[string]any) (map[string]any, error) {
+	t.Helper()
+	return tl.(toolinternal.FunctionTool).Run(ctx, args)
+}
+
+func TestCache_Run(t *testing.T) {
+	var runs atomic.Int32
+	tl := toolcache.New(newLookup(t, functiontool.Config{}, &runs), toolcache.Config{})
+	ctx := newToolContext(t)
+
+	first, err := run(t, ctx, tl, map[string]any{"city": "Paris"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// The result must not change the cached one.
+	first["city"] = "changed"
+
+	got, err := run(t, ctx, tl, map[string]any{"city": "Paris"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := map[string]any{"city": "Paris", "run": float64(1), toolinternal.CacheHitKey: true}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("cached result mismatch (-want +got):\n%s", diff)
+	}
+
+	// Other arguments miss the cache.
+	if _, err := run(t, ctx, tl, map[string]any{"city": "Rome"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// Errors aren't cached.
+	for range 2 {
+		if _, err := run(t, ctx, tl, map[string]any{"city": ""}); err == nil {
+			t.Fatal("Run succeeded, want an error")
+		}
+	}
+	if got := runs.Load(); got != 4 {
+		t.Errorf("ran the tool %d times, want 4", got)
+	}
+}
+
+func TestCache_TTL(t *testing.T) {
+	for name, store := range map[string]toolcache.Store{
+		"memory": toolcache.NewInMemoryStore(10),
+		"state":  toolcache.NewStateStore(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var runs atomic.Int32
+			tl := toolcache.New(newLookup(t, functiontool.Config{}, &runs), toolcache.Config{TTL: 20 * time.Millisecond, Store: store})
+			ctx := newToolContext(t)
+			args := map[string]any{"city": "Paris"}
+
+			for range 2 {
+				if _, err := run(t, ctx, tl, args); err != nil {
+					t.Fatalf("Run: %v", err)
+				}
+			}
+			if got := runs.Load(); got != 1 {
+				t.Fatalf("ran the tool %d times before the expiry, want 1", got)
+			}
+			time.Sleep(30 * time.Millisecond)
+			if _, err := run(t, ctx, tl, args); err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if got := runs.Load(); got != 2 {
+				t.Errorf("ran the tool %d times after the expiry, want 2", got)
+			}
+		})
+	}
+}
+
+func TestCache_InMemoryStoreEviction(t *testing.T) {
+	var runs atomic.Int32
+	tl := toolcache.New(newLookup(t, functiontool.Config{}, &runs), toolcache.Config{Store: toolcache.NewInMemoryStore(1)})
+	ctx := newToolContext(t)
+	for _, city := range []string{"Paris", "Rome", "Paris"} {
+		if _, err := run(t, ctx, tl, map[string]any{"city": city}); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+	}
+	if got := runs.Load(); got != 3 {
+		t.Errorf("ran the tool %d times, want 3", got)
+	}
+}
+
+// imageTool is a tool returning function response parts, as the tools of
+// MCP servers returning images do.
+type imageTool struct {
+	runs atomic.Int32
+}
+
+func (*imageTool) Name() string        { return "image" }
+func (*imageTool) Description() string { return "Returns an image." }
+func (*imageTool) IsLongRunning() bool { return false }
+
+func (*imageTool) Declaration() *genai.FunctionDeclaration {
+	return &genai.FunctionDeclaration{Name: "image", Description: "Returns an image."}
+}
+
+func (tl *imageTool) Run(agent.Context, any) (map[string]any, error) {
+	tl.runs.Add(1)
+	parts := []*genai.FunctionResponsePart{genai.NewFunctionResponsePartFromBytes([]byte("png"), "image/png")}
+	return map[string]any{"status": "ok", toolinternal.ResponsePartsKey: parts}, nil
+}
+
+func TestCache_ResponseParts(t *testing.T) {
+	for name, store := range map[string]toolcache.Store{
+		"memory": toolcache.NewInMemoryStore(10),
+		"state":  toolcache.NewStateStore(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			image := &imageTool{}
+			tl := toolcache.New(image, toolcache.Config{Store: store})
+			ctx := newToolContext(t)
+
+			for range 2 {
+				got, err := run(t, ctx, tl, map[string]any{})
+				if err != nil {
+					t.Fatalf("Run: %v", err)
+				}
+				if parts, ok := got[toolinternal.ResponsePartsKey].([]*genai.FunctionResponsePart); !ok || len(parts) != 1 {
+					t.Errorf("result parts = %#v, want the function response parts", got[toolinternal.ResponsePartsKey])
+				}
+			}
+			if got := image.runs.Load(); got != 2 {
+				t.Errorf("ran the tool %d times, want 2: results with response parts must not be cached", got)
+			}
+		})
+	}
+}
+
+func TestCache_Uncacheable(t *testing.T) {
+	var runs atomic.Int32
+	lookup := newLookup(t, functiontool.Config{}, &runs)
+	confirmed, err := tool.WithConfirmation(testToolset{lookup}, true, nil).Tools(nil)
+	if err != nil {
+		t.Fatalf("Tools: %v", err)
+	}
+	tests := []struct {
+		name string
+		tool tool.Tool
+	}{
+		{"long-running", newLookup(t, functiontool.Config{IsLongRunning: true}, &runs)},
+		{"require confirmation", newLookup(t, functiontool.Config{RequireConfirmation: true}, &runs)},
+		{"confirmation provider", newLookup(t, functiontool.Config{RequireConfirmationProvider: func(lookupArgs) bool { return false }}, &runs)},
+		{"with confirmation", confirmed[0]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toolcache.New(tt.tool, toolcache.Config{}); got != tt.tool {
+				t.Errorf("New wrapped the tool")
+			}
+		})
+	}
+}
+
+func TestCache_ExecutionOptions(t *testing.T) {
+	var runs atomic.Int32
+	opts := tool.ExecutionOptions{Timeout: time.Second}
+	tl := toolcache.New(newLookup(t, functiontool.Config{Execution: opts}, &runs), toolcache.Config{})
+	if got := tool.ExecutionOptionsOf(tl); got != opts {
+		t.Errorf("ExecutionOptionsOf = %+v, want %+v", got, opts)
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	"google.golang.org/genai"
 
+	"google.golang.org/adk/v2/agent"
 	"google.golang.org/adk/v2/model"
 )
 
@@ -72,3 +73,26 @@ func PackTool(req *model.LLMRequest, t Tool) error {
 	}
 	return nil
 }
+
+// PackWrappedTool packs wrapper, a tool wrapping inner, into req in place of
+// inner. If inner packs itself into req in its ProcessRequest, e.g. to add
+// instructions along with its declaration, its ProcessRequest is honored and
+// wrapper replaces inner in req.Tools so that the Run method of wrapper is
+// invoked.
+func PackWrappedTool(ctx agent.Context, req *model.LLMRequest, inner, wrapper Tool) error {
+	if rp, ok := inner.(interface {
+		ProcessRequest(ctx agent.Context, req *model.LLMRequest) error
+	}); ok {
+		_, existedBefore := req.Tools[wrapper.Name()]
+		if err := rp.ProcessRequest(ctx, req); err != nil {
+			return err
+		}
+		// If the inner tool packed itself into req.Tools during ProcessRequest,
+		// replace it with the wrapper so the wrapper's Run is invoked.
+		if !existedBefore && req.Tools != nil && req.Tools[wrapper.Name()] != nil {
+			req.Tools[wrapper.Name()] = wrapper
+			return nil
+		}
+	}
+	return PackTool(req, wrapper)
+}
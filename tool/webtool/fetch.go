@@ -0,0 +1,310 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webtool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/netguard"
+	"google.golang.org/adk/v2/internal/version"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/functiontool"
+)
+
+// Defaults of FetchConfig.
+const (
+	DefaultMaxBodySize    = 5 * 1024 * 1024 // 5MB
+	DefaultMaxContentSize = 100 * 1024      // 100KB
+	DefaultCacheSize      = 128
+	DefaultCacheTTL       = 15 * time.Minute
+)
+
+// ErrBlocked is returned when fetching a URL is forbidden by the domain
+// lists of the tool or the robots.txt of the site.
+var ErrBlocked = errors.New("fetching the URL is not allowed")
+
+// FetchConfig configures the web_fetch tool.
+type FetchConfig struct {
+	// Client sends the requests. Defaults to a client like
+	// http.DefaultClient, without proxy. Unless AllowPrivateNetworks is
+	// set, its Transport must be nil or an *http.Transport without a
+	// custom dialer, to which the tool adds its own.
+	Client *http.Client
+	// UserAgent is the User-Agent of the requests, whose product token
+	// also selects the robots.txt rules. Defaults to "Google-ADK/<version>".
+	UserAgent string
+	// MaxBodySize bounds the size of the responses, in bytes. Defaults to
+	// DefaultMaxBodySize.
+	MaxBodySize int64
+	// MaxContentSize bounds the content returned to the model, in bytes;
+	// longer content is truncated. Defaults to DefaultMaxContentSize.
+	MaxContentSize int
+	// AllowedDomains, if set, restricts the URLs which can be fetched to
+	// those of the domains, and their subdomains.
+	AllowedDomains []string
+	// DeniedDomains forbids fetching the URLs of the domains, and their
+	// subdomains. It has precedence over AllowedDomains.
+	DeniedDomains []string
+	// AllowPrivateNetworks allows fetching loopback, link-local and
+	// private addresses. By default, connections to addresses which are
+	// not publicly routable are refused after DNS resolution, redirects
+	// included, so that pages read by the model can't have it fetch
+	// internal endpoints such as cloud metadata servers.
+	AllowPrivateNetworks bool
+	// IgnoreRobots disables checking the robots.txt of the sites.
+	IgnoreRobots bool
+	// CacheSize is the number of pages, and robots.txt files, cached.
+	// Defaults to DefaultCacheSize; a negative size disables the cache.
+	CacheSize int
+	// CacheTTL is how long the pages are cached. Defaults to
+	// DefaultCacheTTL.
+	CacheTTL time.Duration
+}
+
+// FetchArgs is the input of the web_fetch tool.
+type FetchArgs struct {
+	URL string `json:"url" jsonschema:"The http or https URL of the page to fetch."`
+}
+
+// FetchResult is the output of the web_fetch tool.
+type FetchResult struct {
+	// URL is the URL of the page, after redirects.
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"`
+	// Content is the content of the page: markdown for HTML pages, and
+	// text otherwise.
+	Content   string `json:"content"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+type fetcher struct {
+	cfg    FetchConfig
+	client *http.Client
+	pages  *expirable.LRU[string, *FetchResult]
+	robots *expirable.LRU[string, *robotsRules]
+}
+
+// NewFetch returns the web_fetch tool, fetching web pages and returning
+// their content as markdown.
+func NewFetch(cfg FetchConfig) (tool.Tool, error) {
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = "Google-ADK/" + version.Version
+	}
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = DefaultMaxBodySize
+	}
+	if cfg.MaxContentSize <= 0 {
+		cfg.MaxContentSize = DefaultMaxContentSize
+	}
+	if cfg.CacheSize == 0 {
+		cfg.CacheSize = DefaultCacheSize
+	}
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = DefaultCacheTTL
+	}
+	f := &fetcher{cfg: cfg}
+	c := http.Client{}
+	if cfg.Client != nil {
+		c = *cfg.Client
+	}
+	if !cfg.AllowPrivateNetworks {
+		transport, err := netguard.PublicTransport(c.Transport, ErrBlocked)
+		if err != nil {
+			return nil, fmt.Errorf("webtool: FetchConfig.Client: %w; block private networks in it and set AllowPrivateNetworks", err)
+		}
+		c.Transport = transport
+	}
+	// Check the domains of the redirects too.
+	checkRedirect := c.CheckRedirect
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := f.checkDomain(req.URL); err != nil {
+			return err
+		}
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	f.client = &c
+	if cfg.CacheSize > 0 {
+		f.pages = expirable.NewLRU[string, *FetchResult](cfg.CacheSize, nil, cfg.CacheTTL)
+		f.robots = expirable.NewLRU[string, *robotsRules](cfg.CacheSize, nil, cfg.CacheTTL)
+	}
+	return functiontool.New(
+		functiontool.Config{
+			Name:        "web_fetch",
+			Description: "Fetches a web page and returns its content as markdown.",
+		},
+		f.run,
+	)
+}
+
+func (f *fetcher) run(ctx agent.Context, args FetchArgs) (*FetchResult, error) {
+	result, err := f.fetch(ctx, args.URL)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordGrounding(ctx, "", Citation{URL: result.URL, Title: result.Title}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (f *fetcher) fetch(ctx context.Context, rawURL string) (*FetchResult, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL %q: must be an absolute http or https URL", rawURL)
+	}
+	u.Fragment = ""
+	key := u.String()
+	if f.pages != nil {
+		if result, ok := f.pages.Get(key); ok {
+			return result, nil
+		}
+	}
+	if err := f.checkDomain(u); err != nil {
+		return nil, err
+	}
+	if !f.cfg.IgnoreRobots {
+		if !f.robotsRules(ctx, u).allowed(u.RequestURI()) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %s is disallowed by robots.txt", ErrBlocked, key)
+		}
+	}
+
+	resp, err := f.get(ctx, u)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", key, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: unexpected HTTP status %s", key, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.cfg.MaxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", key, err)
+	}
+	if int64(len(body)) > f.cfg.MaxBodySize {
+		return nil, fmt.Errorf("fetch %s: the page exceeds %d bytes limit", key, f.cfg.MaxBodySize)
+	}
+
+	result := &FetchResult{URL: resp.Request.URL.String()}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "" {
+		mediaType, _, _ = mime.ParseMediaType(http.DetectContentType(body))
+	}
+	switch {
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		if result.Title, result.Content, err = htmlToMarkdown(bytes.NewReader(body), resp.Request.URL); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", key, err)
+		}
+	case strings.HasPrefix(mediaType, "text/"), mediaType == "application/json", strings.HasSuffix(mediaType, "+json"),
+		mediaType == "application/xml", strings.HasSuffix(mediaType, "+xml"):
+		result.Content = string(body)
+	default:
+		return nil, fmt.Errorf("fetch %s: unsupported content type %q", key, mediaType)
+	}
+	if len(result.Content) > f.cfg.MaxContentSize {
+		cut := f.cfg.MaxContentSize
+		for cut > 0 && !utf8.RuneStart(result.Content[cut]) {
+			cut--
+		}
+		result.Content, result.Truncated = result.Content[:cut], true
+	}
+	if f.pages != nil {
+		f.pages.Add(key, result)
+	}
+	return result, nil
+}
+
+func (f *fetcher) get(ctx context.Context, u *url.URL) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", f.cfg.UserAgent)
+	return f.client.Do(req)
+}
+
+// checkDomain returns ErrBlocked if the domain lists forbid fetching u.
+func (f *fetcher) checkDomain(u *url.URL) error {
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	inDomain := func(domain string) bool {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSuffix(domain, "."), "."))
+		return host == domain || strings.HasSuffix(host, "."+domain)
+	}
+	if slices.ContainsFunc(f.cfg.DeniedDomains, inDomain) {
+		return fmt.Errorf("%w: domain %q is denied", ErrBlocked, host)
+	}
+	if len(f.cfg.AllowedDomains) > 0 && !slices.ContainsFunc(f.cfg.AllowedDomains, inDomain) {
+		return fmt.Errorf("%w: domain %q is not allowed", ErrBlocked, host)
+	}
+	return nil
+}
+
+// robotsRules returns the robots.txt rules of the site of u, as
+// specified by RFC 9309: sites whose robots.txt is unavailable, with a
+// 4xx status, allow everything, and sites whose robots.txt is
+// unreachable, because of a 5xx status or a network error, disallow
+// everything.
+func (f *fetcher) robotsRules(ctx context.Context, u *url.URL) *robotsRules {
+	site := u.Scheme + "://" + u.Host
+	if f.robots != nil {
+		if rules, ok := f.robots.Get(site); ok {
+			return rules
+		}
+	}
+	rules := disallowAll
+	resp, err := f.get(ctx, &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"})
+	if err == nil {
+		switch {
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			agent, _, _ := strings.Cut(f.cfg.UserAgent, "/")
+			rules = parseRobots(resp.Body, agent)
+		case resp.StatusCode >= 400 && resp.StatusCode < 500:
+			rules = nil
+		}
+		_ = resp.Body.Close()
+	}
+	// Don't remember the failures of canceled requests.
+	if f.robots != nil && ctx.Err() == nil {
+		f.robots.Add(site, rules)
+	}
+	return rules
+}
+
+// disallowAll are the rules of sites whose robots.txt is unreachable.
+var disallowAll = &robotsRules{rules: []robotsRule{{pattern: "/", re: robotsPattern("/")}}}
@@ -0,0 +1,159 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webtool
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const snippetSize = 200
+
+// Document is a page of a LocalIndex.
+type Document struct {
+	URL     string
+	Title   string
+	Content string
+}
+
+// LocalIndex is an offline SearchProvider searching the documents added
+// to it in memory, ranked by TF-IDF. It suits tests and small, fixed
+// corpora.
+type LocalIndex struct {
+	mu   sync.RWMutex
+	docs map[string]*indexedDocument
+	// df counts the documents of each term.
+	df map[string]int
+}
+
+type indexedDocument struct {
+	Document
+	// tf counts the occurrences of each term, those of the title
+	// weighing double.
+	tf map[string]int
+}
+
+// NewLocalIndex returns a LocalIndex of docs.
+func NewLocalIndex(docs ...Document) *LocalIndex {
+	idx := &LocalIndex{docs: map[string]*indexedDocument{}, df: map[string]int{}}
+	idx.Add(docs...)
+	return idx
+}
+
+// Add indexes docs, replacing the documents with the same URLs.
+func (idx *LocalIndex) Add(docs ...Document) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, doc := range docs {
+		if old, ok := idx.docs[doc.URL]; ok {
+			for term := range old.tf {
+				idx.df[term]--
+			}
+		}
+		d := &indexedDocument{Document: doc, tf: map[string]int{}}
+		for _, term := range terms(doc.Title) {
+			d.tf[term] += 2
+		}
+		for _, term := range terms(doc.Content) {
+			d.tf[term]++
+		}
+		for term := range d.tf {
+			idx.df[term]++
+		}
+		idx.docs[doc.URL] = d
+	}
+}
+
+// Search implements SearchProvider.
+func (idx *LocalIndex) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	queryTerms := terms(query)
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	type scored struct {
+		doc   *indexedDocument
+		score float64
+	}
+	var matches []scored
+	for _, doc := range idx.docs {
+		score := 0.0
+		for _, term := range queryTerms {
+			if tf := doc.tf[term]; tf > 0 {
+				score += (1 + math.Log(float64(tf))) * math.Log(1+float64(len(idx.docs))/float64(idx.df[term]))
+			}
+		}
+		if score > 0 {
+			matches = append(matches, scored{doc, score})
+		}
+	}
+	slices.SortFunc(matches, func(a, b scored) int {
+		if c := cmp.Compare(b.score, a.score); c != 0 {
+			return c
+		}
+		return strings.Compare(a.doc.URL, b.doc.URL)
+	})
+	if len(matches) > maxResults {
+		matches = matches[:maxResults]
+	}
+	results := make([]SearchResult, len(matches))
+	for i, m := range matches {
+		results[i] = SearchResult{URL: m.doc.URL, Title: m.doc.Title, Snippet: snippet(m.doc.Content, queryTerms)}
+	}
+	return results, nil
+}
+
+// terms returns the lowercase words of s.
+func terms(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// snippet returns the part of content around the first occurrence of one
+// of the query terms.
+func snippet(content string, queryTerms []string) string {
+	content = strings.Join(strings.Fields(content), " ")
+	lower := strings.ToLower(content)
+	start := len(content)
+	for _, term := range queryTerms {
+		if i := strings.Index(lower, term); i >= 0 && i < start {
+			start = i
+		}
+	}
+	if start == len(content) {
+		start = 0
+	}
+	// Start at the beginning of the word, with some context before.
+	start = max(start-snippetSize/4, 0)
+	if i := strings.LastIndexByte(content[:start], ' '); start > 0 && i >= 0 {
+		start = i + 1
+	}
+	end := min(start+snippetSize, len(content))
+	if i := strings.LastIndexByte(content[start:end], ' '); end < len(content) && i > 0 {
+		end = start + i
+	}
+	s := content[start:end]
+	if start > 0 {
+		s = "..." + s
+	}
+	if end < len(content) {
+		s += "..."
+	}
+	return s
+}
@@ -0,0 +1,264 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webtool
+
+import (
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// skippedElements are left out of the markdown of a page, with their
+// content.
+var skippedElements = map[atom.Atom]bool{
+	atom.Head: true, atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Svg: true, atom.Iframe: true, atom.Object: true, atom.Form: true, atom.Button: true,
+	atom.Nav: true, atom.Footer: true, atom.Aside: true,
+}
+
+// blockElements start a new paragraph.
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true, atom.Main: true, atom.Header: true,
+	atom.Ul: true, atom.Ol: true, atom.Table: true, atom.Figure: true, atom.Dl: true, atom.Hr: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Pre: true, atom.Blockquote: true,
+}
+
+var (
+	spaces     = regexp.MustCompile(`[ \t\r\n\f]+`)
+	blankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToMarkdown returns the title and the content of an HTML page, as
+// markdown. The content is that of the main or article element if the
+// page has one, and of the body otherwise; links are resolved against
+// base.
+func htmlToMarkdown(r io.Reader, base *url.URL) (title, markdown string, err error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return "", "", err
+	}
+	if t := find(doc, atom.Title); t != nil {
+		title = strings.TrimSpace(spaces.ReplaceAllString(textOf(t), " "))
+	}
+	root := find(doc, atom.Main)
+	if root == nil {
+		root = find(doc, atom.Article)
+	}
+	if root == nil {
+		root = doc
+	}
+	m := &markdownWriter{base: base}
+	m.children(root)
+	out := blankLines.ReplaceAllString(m.b.String(), "\n\n")
+	return title, strings.TrimSpace(out), nil
+}
+
+// find returns the first element a under n, depth first.
+func find(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := find(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func textOf(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+type markdownWriter struct {
+	b    strings.Builder
+	base *url.URL
+	// pre is set within pre elements, whose whitespace is kept.
+	pre bool
+	// prefix starts the lines, e.g. "> " in blockquotes.
+	prefix string
+}
+
+func (m *markdownWriter) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		m.node(c)
+	}
+}
+
+// paragraph ends the current paragraph, if any.
+func (m *markdownWriter) paragraph() {
+	s := m.b.String()
+	if s == "" || strings.HasSuffix(s, "\n\n") {
+		return
+	}
+	if strings.HasSuffix(s, "\n") {
+		m.b.WriteString(strings.TrimRight(m.prefix, " ") + "\n")
+		return
+	}
+	m.b.WriteString("\n" + strings.TrimRight(m.prefix, " ") + "\n")
+}
+
+// newline ends the current line.
+func (m *markdownWriter) newline() {
+	if s := m.b.String(); s != "" && !strings.HasSuffix(s, "\n") {
+		m.b.WriteString("\n")
+	}
+}
+
+// write writes inline text, starting lines with the prefix.
+func (m *markdownWriter) write(s string) {
+	if strings.HasSuffix(m.b.String(), "\n") || m.b.Len() == 0 {
+		m.b.WriteString(m.prefix)
+		s = strings.TrimLeft(s, " ")
+	}
+	m.b.WriteString(s)
+}
+
+func (m *markdownWriter) resolve(ref string) string {
+	u, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil || m.base == nil {
+		return ref
+	}
+	return m.base.ResolveReference(u).String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func (m *markdownWriter) node(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		if m.pre {
+			m.b.WriteString(n.Data)
+			return
+		}
+		text := spaces.ReplaceAllString(n.Data, " ")
+		if text != " " || !strings.HasSuffix(m.b.String(), " ") {
+			m.write(text)
+		}
+		return
+	case html.ElementNode:
+	default:
+		m.children(n)
+		return
+	}
+	if skippedElements[n.DataAtom] || attr(n, "hidden") != "" || attr(n, "aria-hidden") == "true" {
+		return
+	}
+	if blockElements[n.DataAtom] {
+		m.paragraph()
+		defer m.paragraph()
+	}
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		m.write(strings.Repeat("#", level) + " " + strings.TrimSpace(spaces.ReplaceAllString(textOf(n), " ")))
+	case atom.Br:
+		m.b.WriteString("\n")
+	case atom.Hr:
+		m.write("---")
+	case atom.A:
+		text := strings.TrimSpace(spaces.ReplaceAllString(textOf(n), " "))
+		href := attr(n, "href")
+		if text == "" || href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+			m.children(n)
+			return
+		}
+		m.write("[" + text + "](" + m.resolve(href) + ")")
+	case atom.Img:
+		if alt := strings.TrimSpace(attr(n, "alt")); alt != "" {
+			m.write("![" + alt + "](" + m.resolve(attr(n, "src")) + ")")
+		}
+	case atom.Strong, atom.B:
+		m.inline(n, "**")
+	case atom.Em, atom.I:
+		m.inline(n, "*")
+	case atom.Code:
+		if m.pre {
+			m.children(n)
+			return
+		}
+		m.write("`" + textOf(n) + "`")
+	case atom.Pre:
+		m.write("```\n")
+		m.pre = true
+		m.children(n)
+		m.pre = false
+		m.newline()
+		m.b.WriteString(m.prefix + "```")
+	case atom.Blockquote:
+		prefix := m.prefix
+		m.prefix += "> "
+		m.children(n)
+		m.prefix = prefix
+	case atom.Ul, atom.Ol:
+		i := 0
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || c.DataAtom != atom.Li {
+				continue
+			}
+			i++
+			m.newline()
+			marker := "- "
+			if n.DataAtom == atom.Ol {
+				marker = strconv.Itoa(i) + ". "
+			}
+			m.write(marker)
+			m.children(c)
+		}
+	case atom.Tr:
+		m.newline()
+		m.write("|")
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && (c.DataAtom == atom.Td || c.DataAtom == atom.Th) {
+				m.b.WriteString(" " + strings.TrimSpace(spaces.ReplaceAllString(textOf(c), " ")) + " |")
+			}
+		}
+	default:
+		m.children(n)
+	}
+}
+
+// inline writes the content of n wrapped in marker, e.g. "**".
+func (m *markdownWriter) inline(n *html.Node, marker string) {
+	text := strings.TrimSpace(spaces.ReplaceAllString(textOf(n), " "))
+	if text == "" {
+		return
+	}
+	m.write(marker + text + marker)
+}
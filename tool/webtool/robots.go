@@ -0,0 +1,110 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webtool
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// robotsRules are the rules of a robots.txt file applying to a user
+// agent, as specified by RFC 9309.
+type robotsRules struct {
+	rules []robotsRule
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+	re      *regexp.Regexp
+}
+
+// parseRobots returns the rules of the robots.txt file r for the user
+// agent product token agent: those of the groups naming it, or else
+// those of the "*" groups.
+func parseRobots(r io.Reader, agent string) *robotsRules {
+	agent = strings.ToLower(agent)
+	var (
+		matched, wildcard []robotsRule
+		groupAgents       []string
+		inRules           bool
+	)
+	scanner := bufio.NewScanner(io.LimitReader(r, 512*1024))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch key {
+		case "user-agent":
+			if inRules {
+				groupAgents, inRules = nil, false
+			}
+			groupAgents = append(groupAgents, strings.ToLower(value))
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				continue // An empty disallow allows everything.
+			}
+			rule := robotsRule{allow: key == "allow", pattern: value, re: robotsPattern(value)}
+			for _, a := range groupAgents {
+				switch {
+				case a == "*":
+					wildcard = append(wildcard, rule)
+				case strings.Contains(agent, a):
+					matched = append(matched, rule)
+				}
+			}
+		}
+	}
+	if matched != nil {
+		return &robotsRules{rules: matched}
+	}
+	return &robotsRules{rules: wildcard}
+}
+
+// robotsPattern compiles a path pattern, in which "*" matches any
+// sequence of characters and a final "$" the end of the path.
+func robotsPattern(pattern string) *regexp.Regexp {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
+
+// allowed reports whether the rules allow fetching path, which includes
+// the query. The longest matching rule wins, allow rules winning ties.
+func (r *robotsRules) allowed(path string) bool {
+	if r == nil {
+		return true
+	}
+	allow, length := true, -1
+	for _, rule := range r.rules {
+		if !rule.re.MatchString(path) {
+			continue
+		}
+		if len(rule.pattern) > length || (len(rule.pattern) == length && rule.allow) {
+			allow, length = rule.allow, len(rule.pattern)
+		}
+	}
+	return allow
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webtool
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/functiontool"
+)
+
+// DefaultMaxResults is the default SearchConfig.MaxResults.
+const DefaultMaxResults = 5
+
+// SearchResult is a page found by a SearchProvider.
+type SearchResult struct {
+	URL     string `json:"url"`
+	Title   string `json:"title,omitempty"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// SearchProvider is the interface of the search engines of the
+// web_search tool.
+//
+// Implementations must be safe for concurrent use.
+type SearchProvider interface {
+	// Search returns up to maxResults results for query, best first.
+	Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error)
+}
+
+// SearchConfig configures the web_search tool.
+type SearchConfig struct {
+	// Provider runs the searches.
+	Provider SearchProvider
+	// MaxResults is the number of results returned to the model.
+	// Defaults to DefaultMaxResults.
+	MaxResults int
+}
+
+// SearchArgs is the input of the web_search tool.
+type SearchArgs struct {
+	Query string `json:"query" jsonschema:"The search query."`
+}
+
+// SearchOutput is the output of the web_search tool.
+type SearchOutput struct {
+	Results []SearchResult `json:"results"`
+}
+
+// NewSearch returns the web_search tool, searching the web with
+// cfg.Provider.
+func NewSearch(cfg SearchConfig) (tool.Tool, error) {
+	if cfg.Provider == nil {
+		return nil, fmt.Errorf("search provider must be provided")
+	}
+	if cfg.MaxResults <= 0 {
+		cfg.MaxResults = DefaultMaxResults
+	}
+	return functiontool.New(
+		functiontool.Config{
+			Name:        "web_search",
+			Description: "Searches the web, and returns the URLs, titles and snippets of the pages found. Use web_fetch to read a page.",
+		},
+		func(ctx agent.Context, args SearchArgs) (*SearchOutput, error) {
+			if args.Query == "" {
+				return nil, fmt.Errorf("query is required to search")
+			}
+			results, err := cfg.Provider.Search(ctx, args.Query, cfg.MaxResults)
+			if err != nil {
+				return nil, fmt.Errorf("search %q: %w", args.Query, err)
+			}
+			if len(results) > cfg.MaxResults {
+				results = results[:cfg.MaxResults]
+			}
+			citations := make([]Citation, len(results))
+			for i, r := range results {
+				citations[i] = Citation{URL: r.URL, Title: r.Title}
+			}
+			if err := recordGrounding(ctx, args.Query, citations...); err != nil {
+				return nil, err
+			}
+			return &SearchOutput{Results: results}, nil
+		},
+	)
+}
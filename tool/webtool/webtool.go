@@ -0,0 +1,108 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webtool provides tools to fetch web pages and search the web,
+// which work with any model, unlike the built-in Gemini tools of
+// geminitool.
+//
+// The tools record the pages they return as citations; add
+// GroundingCallback to the AfterModelCallbacks of the agent to report
+// them in the GroundingMetadata of its responses.
+package webtool
+
+import (
+	"net/url"
+	"slices"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/session"
+)
+
+// groundingStateKey is the session state key holding the grounding of
+// the invocation: the pages returned by the tools since the last final
+// response.
+const groundingStateKey = session.KeyPrefixTemp + "webtool_grounding"
+
+// Citation identifies a web page returned by a tool.
+type Citation struct {
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"`
+}
+
+type grounding struct {
+	Citations []Citation
+	Queries   []string
+}
+
+func loadGrounding(state session.State) grounding {
+	v, err := state.Get(groundingStateKey)
+	if err != nil {
+		return grounding{}
+	}
+	g, _ := v.(grounding)
+	return g
+}
+
+// recordGrounding adds the citations and search query of a tool call to
+// the grounding of the invocation.
+func recordGrounding(ctx agent.Context, query string, citations ...Citation) error {
+	g := loadGrounding(ctx.State())
+	for _, c := range citations {
+		if !slices.ContainsFunc(g.Citations, func(o Citation) bool { return o.URL == c.URL }) {
+			g.Citations = append(g.Citations, c)
+		}
+	}
+	if query != "" && !slices.Contains(g.Queries, query) {
+		g.Queries = append(g.Queries, query)
+	}
+	return ctx.State().Set(groundingStateKey, g)
+}
+
+// GroundingCallback is an llmagent.AfterModelCallback reporting the pages
+// returned by the tools of this package, and the searches made, in the
+// GroundingMetadata of the final responses of the agent.
+func GroundingCallback(ctx agent.Context, resp *model.LLMResponse, respErr error) (*model.LLMResponse, error) {
+	if respErr != nil || resp == nil || resp.Partial || resp.Content == nil {
+		return nil, nil
+	}
+	for _, p := range resp.Content.Parts {
+		if p != nil && p.FunctionCall != nil {
+			return nil, nil // Not final: keep collecting.
+		}
+	}
+	g := loadGrounding(ctx.State())
+	if len(g.Citations) == 0 && len(g.Queries) == 0 {
+		return nil, nil
+	}
+	if err := ctx.State().Set(groundingStateKey, grounding{}); err != nil {
+		return nil, err
+	}
+	if resp.GroundingMetadata == nil {
+		resp.GroundingMetadata = &genai.GroundingMetadata{}
+	}
+	for _, c := range g.Citations {
+		domain := ""
+		if u, err := url.Parse(c.URL); err == nil {
+			domain = u.Hostname()
+		}
+		resp.GroundingMetadata.GroundingChunks = append(resp.GroundingMetadata.GroundingChunks, &genai.GroundingChunk{
+			Web: &genai.GroundingChunkWeb{URI: c.URL, Title: c.Title, Domain: domain},
+		})
+	}
+	resp.GroundingMetadata.WebSearchQueries = append(resp.GroundingMetadata.WebSearchQueries, g.Queries...)
+	return resp, nil
+}
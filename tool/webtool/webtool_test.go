@@ -0,0 +1,403 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webtool_test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	icontext "google.golang.org/adk/v2/internal/context"
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/webtool"
+)
+
+func newToolContext(t *testing.T) agent.Context {
+	t.Helper()
+	sessions := session.InMemoryService()
+	resp, err := sessions.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Session: resp.Session})
+	return agent.NewToolContext(invCtx, "call1", &session.EventActions{}, nil)
+}
+
+func run(t *testing.T, ctx agent.Context, tl tool.Tool, args map[string]any) (map[string]any, error) {
+	t.Helper()
+	return tl.(toolinternal.FunctionTool).Run(ctx, args)
+}
+
+// newFetch returns the web_fetch tool of cfg, allowed to fetch the
+// loopback addresses of the test servers.
+func newFetch(cfg webtool.FetchConfig) (tool.Tool, error) {
+	cfg.AllowPrivateNetworks = true
+	return webtool.NewFetch(cfg)
+}
+
+const page = `<!DOCTYPE html>
+<html>
+<head><title>The  Page</title><script>var x = 1;</script></head>
+<body>
+<nav><a href="/home">Home</a></nav>
+<main>
+<h1>Heading</h1>
+<p>Some <strong>bold</strong> and <em>italic</em> text with a <a href="/other">link</a>.</p>
+<ul><li>one</li><li>two</li></ul>
+<pre><code>code  block</code></pre>
+<table><tr><th>a</th><th>b</th></tr><tr><td>1</td><td>2</td></tr></table>
+</main>
+<footer>Footer</footer>
+</body>
+</html>`
+
+func newServer(t *testing.T, fetches *atomic.Int32) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "User-agent: *\nDisallow: /private\nAllow: /private/public\n")
+	})
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		if fetches != nil {
+			fetches.Add(1)
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, page)
+	})
+	mux.HandleFunc("/private/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "private")
+	})
+	mux.HandleFunc("/text", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, strings.Repeat("é", 100))
+	})
+	mux.HandleFunc("/image", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		fmt.Fprint(w, "png")
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://denied.example/page", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFetch(t *testing.T) {
+	server := newServer(t, nil)
+	fetch, err := newFetch(webtool.FetchConfig{})
+	if err != nil {
+		t.Fatalf("NewFetch: %v", err)
+	}
+
+	got, err := run(t, newToolContext(t), fetch, map[string]any{"url": server.URL + "/page#section"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := map[string]any{
+		"url":   server.URL + "/page",
+		"title": "The Page",
+		"content": "# Heading\n\n" +
+			"Some **bold** and *italic* text with a [link](" + server.URL + "/other).\n\n" +
+			"- one\n- two\n\n" +
+			"```\ncode  block\n```\n\n" +
+			"| a | b |\n| 1 | 2 |",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFetch_Errors(t *testing.T) {
+	server := newServer(t, nil)
+	host := strings.TrimPrefix(server.URL, "http://")
+	host, _, _ = strings.Cut(host, ":")
+
+	tests := []struct {
+		name        string
+		cfg         webtool.FetchConfig
+		url         string
+		wantBlocked bool
+		wantErr     string
+	}{
+		{name: "relative URL", url: "/page", wantErr: "invalid URL"},
+		{name: "unsupported scheme", url: "file:///etc/passwd", wantErr: "invalid URL"},
+		{name: "robots", url: server.URL + "/private/page", wantBlocked: true},
+		{name: "denied domain", cfg: webtool.FetchConfig{DeniedDomains: []string{host}}, url: server.URL + "/page", wantBlocked: true},
+		{name: "not allowed domain", cfg: webtool.FetchConfig{AllowedDomains: []string{"example.com"}}, url: server.URL + "/page", wantBlocked: true},
+		{name: "denied redirect", cfg: webtool.FetchConfig{DeniedDomains: []string{"denied.example"}}, url: server.URL + "/redirect", wantBlocked: true},
+		{name: "not found", url: server.URL + "/missing", wantErr: "404"},
+		{name: "unsupported content", url: server.URL + "/image", wantErr: "unsupported content type"},
+		{name: "too large", cfg: webtool.FetchConfig{MaxBodySize: 10}, url: server.URL + "/page", wantErr: "exceeds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetch, err := newFetch(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewFetch: %v", err)
+			}
+			_, err = run(t, newToolContext(t), fetch, map[string]any{"url": tt.url})
+			if err == nil {
+				t.Fatal("Run() succeeded, want error")
+			}
+			if got := errors.Is(err, webtool.ErrBlocked); got != tt.wantBlocked {
+				t.Errorf("Run() error = %v, errors.Is(err, ErrBlocked) = %v, want %v", err, got, tt.wantBlocked)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Run() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFetch_Robots(t *testing.T) {
+	server := newServer(t, nil)
+	for _, tt := range []struct {
+		cfg  webtool.FetchConfig
+		path string
+	}{
+		{path: "/private/public"},
+		{cfg: webtool.FetchConfig{IgnoreRobots: true}, path: "/private/page"},
+	} {
+		fetch, err := newFetch(tt.cfg)
+		if err != nil {
+			t.Fatalf("NewFetch: %v", err)
+		}
+		got, err := run(t, newToolContext(t), fetch, map[string]any{"url": server.URL + tt.path})
+		if err != nil {
+			t.Fatalf("Run(%q): %v", tt.path, err)
+		}
+		if got["content"] != "private" {
+			t.Errorf("Run(%q) content = %q, want %q", tt.path, got["content"], "private")
+		}
+	}
+}
+
+func TestFetch_RobotsUnavailable(t *testing.T) {
+	for _, tt := range []struct {
+		status      int
+		wantBlocked bool
+	}{
+		{status: http.StatusNotFound},
+		{status: http.StatusForbidden},
+		{status: http.StatusUnauthorized},
+		{status: http.StatusInternalServerError, wantBlocked: true},
+		{status: http.StatusServiceUnavailable, wantBlocked: true},
+	} {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			})
+			mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, "page")
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			fetch, err := newFetch(webtool.FetchConfig{})
+			if err != nil {
+				t.Fatalf("NewFetch: %v", err)
+			}
+			_, err = run(t, newToolContext(t), fetch, map[string]any{"url": server.URL + "/page"})
+			if got := errors.Is(err, webtool.ErrBlocked); got != tt.wantBlocked {
+				t.Errorf("Run() error = %v, errors.Is(err, ErrBlocked) = %v, want %v", err, got, tt.wantBlocked)
+			}
+			if !tt.wantBlocked && err != nil {
+				t.Errorf("Run() error = %v, want success", err)
+			}
+		})
+	}
+}
+
+func TestFetch_PrivateNetworks(t *testing.T) {
+	server := newServer(t, nil)
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, server.URL+"/page", http.StatusFound)
+	}))
+	defer redirect.Close()
+
+	fetch, err := webtool.NewFetch(webtool.FetchConfig{IgnoreRobots: true})
+	if err != nil {
+		t.Fatalf("NewFetch: %v", err)
+	}
+	for _, u := range []string{server.URL + "/page", redirect.URL, "http://169.254.169.254/computeMetadata/v1/"} {
+		if _, err := run(t, newToolContext(t), fetch, map[string]any{"url": u}); !errors.Is(err, webtool.ErrBlocked) {
+			t.Errorf("Run(%q) error = %v, want %v", u, err, webtool.ErrBlocked)
+		}
+	}
+
+	custom := &http.Client{Transport: &http.Transport{DialContext: (&net.Dialer{}).DialContext}}
+	if _, err := webtool.NewFetch(webtool.FetchConfig{Client: custom}); err == nil {
+		t.Error("NewFetch() with a custom dialer succeeded, want error")
+	}
+	if _, err := webtool.NewFetch(webtool.FetchConfig{Client: custom, AllowPrivateNetworks: true}); err != nil {
+		t.Errorf("NewFetch() with a custom dialer and AllowPrivateNetworks: %v", err)
+	}
+}
+
+func TestFetch_Truncated(t *testing.T) {
+	server := newServer(t, nil)
+	fetch, err := newFetch(webtool.FetchConfig{MaxContentSize: 11})
+	if err != nil {
+		t.Fatalf("NewFetch: %v", err)
+	}
+	got, err := run(t, newToolContext(t), fetch, map[string]any{"url": server.URL + "/text"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// "é" is 2 bytes long: the content is cut before the incomplete rune.
+	if want := strings.Repeat("é", 5); got["content"] != want || got["truncated"] != true {
+		t.Errorf("Run() = %v, want truncated content %q", got, want)
+	}
+}
+
+func TestFetch_Cache(t *testing.T) {
+	var fetches atomic.Int32
+	server := newServer(t, &fetches)
+	for _, tt := range []struct {
+		cacheSize int
+		want      int32
+	}{
+		{cacheSize: 0, want: 1},
+		{cacheSize: -1, want: 2},
+	} {
+		fetches.Store(0)
+		fetch, err := newFetch(webtool.FetchConfig{CacheSize: tt.cacheSize})
+		if err != nil {
+			t.Fatalf("NewFetch: %v", err)
+		}
+		for range 2 {
+			if _, err := run(t, newToolContext(t), fetch, map[string]any{"url": server.URL + "/page"}); err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+		}
+		if got := fetches.Load(); got != tt.want {
+			t.Errorf("CacheSize %d: fetched the page %d times, want %d", tt.cacheSize, got, tt.want)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	index := webtool.NewLocalIndex(
+		webtool.Document{URL: "https://a.example/go", Title: "Go", Content: "Go is a programming language designed at Google."},
+		webtool.Document{URL: "https://b.example/python", Title: "Python", Content: "Python is a programming language."},
+		webtool.Document{URL: "https://c.example/coffee", Title: "Coffee", Content: "Coffee is a drink."},
+	)
+	search, err := webtool.NewSearch(webtool.SearchConfig{Provider: index, MaxResults: 1})
+	if err != nil {
+		t.Fatalf("NewSearch: %v", err)
+	}
+	got, err := run(t, newToolContext(t), search, map[string]any{"query": "Go programming language"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := map[string]any{"results": []any{
+		map[string]any{"url": "https://a.example/go", "title": "Go", "snippet": "Go is a programming language designed at Google."},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := webtool.NewSearch(webtool.SearchConfig{}); err == nil {
+		t.Error("NewSearch() without provider succeeded, want error")
+	}
+}
+
+func TestLocalIndex(t *testing.T) {
+	long := strings.Repeat("filler ", 100) + "needle " + strings.Repeat("filler ", 100)
+	index := webtool.NewLocalIndex(
+		webtool.Document{URL: "https://a.example", Title: "A", Content: "apple banana"},
+		webtool.Document{URL: "https://b.example", Title: "B", Content: long},
+	)
+	// Replacing a document reindexes it.
+	index.Add(webtool.Document{URL: "https://a.example", Title: "A", Content: "cherry"})
+
+	if got, _ := index.Search(t.Context(), "apple", 5); len(got) != 0 {
+		t.Errorf("Search(apple) = %v, want no results", got)
+	}
+	got, err := index.Search(t.Context(), "Needle", 5)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(got) != 1 || got[0].URL != "https://b.example" {
+		t.Fatalf("Search(Needle) = %v, want https://b.example", got)
+	}
+	snippet := got[0].Snippet
+	if !strings.Contains(snippet, "needle") || !strings.HasPrefix(snippet, "...") || !strings.HasSuffix(snippet, "...") || len(snippet) > 210 {
+		t.Errorf("Search(Needle) snippet = %q, want an excerpt around needle", snippet)
+	}
+}
+
+func TestGroundingCallback(t *testing.T) {
+	server := newServer(t, nil)
+	ctx := newToolContext(t)
+	fetch, err := newFetch(webtool.FetchConfig{})
+	if err != nil {
+		t.Fatalf("NewFetch: %v", err)
+	}
+	search, err := webtool.NewSearch(webtool.SearchConfig{Provider: webtool.NewLocalIndex(
+		webtool.Document{URL: server.URL + "/page", Title: "The Page", Content: "heading"},
+	)})
+	if err != nil {
+		t.Fatalf("NewSearch: %v", err)
+	}
+	if _, err := run(t, ctx, search, map[string]any{"query": "heading"}); err != nil {
+		t.Fatalf("Run(search): %v", err)
+	}
+	if _, err := run(t, ctx, fetch, map[string]any{"url": server.URL + "/page"}); err != nil {
+		t.Fatalf("Run(fetch): %v", err)
+	}
+
+	// Responses calling tools don't get the grounding.
+	call := &model.LLMResponse{Content: genai.NewContentFromFunctionCall("web_fetch", nil, genai.RoleModel)}
+	if got, err := webtool.GroundingCallback(ctx, call, nil); got != nil || err != nil {
+		t.Errorf("GroundingCallback(function call) = %v, %v, want nil, nil", got, err)
+	}
+
+	got, err := webtool.GroundingCallback(ctx, &model.LLMResponse{Content: genai.NewContentFromText("answer", genai.RoleModel)}, nil)
+	if err != nil {
+		t.Fatalf("GroundingCallback: %v", err)
+	}
+	want := &genai.GroundingMetadata{
+		GroundingChunks: []*genai.GroundingChunk{
+			{Web: &genai.GroundingChunkWeb{URI: server.URL + "/page", Title: "The Page", Domain: "127.0.0.1"}},
+		},
+		WebSearchQueries: []string{"heading"},
+	}
+	if got == nil {
+		t.Fatal("GroundingCallback() = nil, want response with grounding metadata")
+	}
+	if diff := cmp.Diff(want, got.GroundingMetadata); diff != "" {
+		t.Errorf("GroundingMetadata mismatch (-want +got):\n%s", diff)
+	}
+
+	// The grounding is reported once.
+	if got, err := webtool.GroundingCallback(ctx, &model.LLMResponse{Content: genai.NewContentFromText("again", genai.RoleModel)}, nil); got != nil || err != nil {
+		t.Errorf("second GroundingCallback() = %v, %v, want nil, nil", got, err)
+	}
+}
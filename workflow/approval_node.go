@@ -0,0 +1,220 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"time"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/approval"
+	"google.golang.org/adk/v2/session"
+)
+
+// ApprovedRoute and RejectedRoute are the routes of the output of an
+// ApprovalNode.
+const (
+	ApprovedRoute StringRoute = "approved"
+	RejectedRoute StringRoute = "rejected"
+)
+
+// ApprovalResult is the output of an ApprovalNode.
+type ApprovalResult struct {
+	Approved bool `json:"approved"`
+	// Expired is set when the request expired without a quorum.
+	Expired bool `json:"expired,omitempty"`
+	// Input is the input of the node, the subject of the approval.
+	Input any `json:"input,omitempty"`
+	// Decisions are the decisions made, in order, including ignored
+	// ones.
+	Decisions []approval.Decision `json:"decisions,omitempty"`
+}
+
+// ApprovalNode pauses the run until its input is approved or rejected
+// according to an approval.Policy. Each round of the request is an
+// interrupt answered with an approval.Decision, validated against the
+// schema of the policy; the node is re-run on every answer (it always
+// runs with NodeConfig.RerunOnResume) and asks again until the quorum
+// is reached or the request expires.
+//
+// Its output is an ApprovalResult routed with ApprovedRoute or
+// RejectedRoute. The request, every decision and the outcome are
+// recorded under approval.AuditKey in the metadata of its events.
+type ApprovalNode struct {
+	BaseNode
+	policy approval.Policy
+}
+
+// NewApprovalNode returns an ApprovalNode applying policy.
+func NewApprovalNode(name string, policy approval.Policy, cfg NodeConfig) (*ApprovalNode, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("approval node %s: %w", name, err)
+	}
+	rerun := true
+	cfg.RerunOnResume = &rerun
+	return &ApprovalNode{BaseNode: NewBaseNode(name, "", cfg), policy: policy}, nil
+}
+
+// interruptID returns the ID of the given round of the request of the
+// node: stable across the re-runs of an invocation so that answers
+// correlate, and unique per invocation.
+func (n *ApprovalNode) interruptID(ctx agent.Context, round int) string {
+	return fmt.Sprintf("%s-%s-%d", n.Name(), ctx.InvocationID(), round)
+}
+
+// Run asks for, or tallies, the decisions on the input.
+func (n *ApprovalNode) Run(ctx agent.Context, input any) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		now := time.Now()
+		audits := n.audits(ctx)
+
+		req, ok := audits.request(n.interruptID(ctx, 0))
+		fresh := !ok
+		if fresh {
+			var err error
+			if req, err = n.policy.NewRequest(n.interruptID(ctx, 0), n.Name(), input, now); err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+
+		var decisions []approval.Decision
+		round := 0
+		for ; ; round++ {
+			id := n.interruptID(ctx, round)
+			answer, ok := ctx.ResumedInput(id)
+			if !ok {
+				break
+			}
+			if d, ok := audits.decision(id); ok {
+				decisions = append(decisions, d)
+				continue
+			}
+			d, err := n.policy.Decide(ctx, req, decisions, answer, approval.IdentityOf(ctx), now)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			decisions = append(decisions, d)
+			audit := &approval.Audit{Request: withID(req, id), Decision: &d, Tally: n.policy.Tally(decisions)}
+			if !yield(n.auditEvent(ctx, audit), nil) {
+				return
+			}
+		}
+
+		tally := n.policy.Tally(decisions)
+		if tally.Outcome != approval.Pending {
+			result := ApprovalResult{Approved: tally.Outcome == approval.Approved, Input: input, Decisions: decisions}
+			result.Expired = len(decisions) > 0 && decisions[len(decisions)-1].Expired
+			audit := &approval.Audit{Request: req, Tally: tally}
+			audit.NotifyError = notifyError(n.policy.Notify(ctx, approval.Notification{Kind: approval.Decided, Request: req, Tally: tally}))
+			ev := n.auditEvent(ctx, audit)
+			ev.Output = result
+			ev.Routes = []string{string(RejectedRoute)}
+			if result.Approved {
+				ev.Routes = []string{string(ApprovedRoute)}
+			}
+			yield(ev, nil)
+			return
+		}
+
+		next := withID(req, n.interruptID(ctx, round))
+		ev := NewRequestInputEvent(ctx, session.RequestInput{
+			InterruptID:    next.ID,
+			Message:        next.Message,
+			Payload:        input,
+			ResponseSchema: n.policy.ResponseSchema(),
+		})
+		audit := &approval.Audit{Request: next, Tally: tally}
+		if fresh {
+			audit.NotifyError = notifyError(n.policy.Notify(ctx, approval.Notification{Kind: approval.Requested, Request: next, Tally: tally}))
+		}
+		ev.CustomMetadata = map[string]any{approval.AuditKey: audit}
+		yield(ev, nil)
+	}
+}
+
+// Sweep sends the reminders of the node's policy due between since and
+// now for its requests pending in sess, and returns the requests which
+// expired; see approval.Policy.Sweep. Answering an expired request with
+// approval.ExpiredAnswer, as an approver, resumes the run with the
+// expiry outcome.
+func (n *ApprovalNode) Sweep(ctx context.Context, sess session.Session, since, now time.Time) ([]approval.Request, error) {
+	requests := slices.DeleteFunc(approval.OpenRequests(sess.Events()), func(r approval.Request) bool {
+		return r.Source != n.Name()
+	})
+	return n.policy.Sweep(ctx, requests, since, now)
+}
+
+func (n *ApprovalNode) auditEvent(ctx agent.Context, audit *approval.Audit) *session.Event {
+	ev := session.NewEvent(ctx, ctx.InvocationID())
+	ev.Author = n.Name()
+	ev.CustomMetadata = map[string]any{approval.AuditKey: audit}
+	return ev
+}
+
+// approvalAudits are the audits recorded by an ApprovalNode in the
+// session, keyed by request ID.
+type approvalAudits struct {
+	requests  map[string]approval.Request
+	decisions map[string]approval.Decision
+}
+
+func (n *ApprovalNode) audits(ctx agent.Context) approvalAudits {
+	a := approvalAudits{requests: map[string]approval.Request{}, decisions: map[string]approval.Decision{}}
+	if ctx.Session() == nil {
+		return a
+	}
+	for ev := range ctx.Session().Events().All() {
+		if ev == nil || ev.InvocationID != ctx.InvocationID() {
+			continue
+		}
+		audit, ok := approval.AuditOf(ev)
+		if !ok || audit.Request.Source != n.Name() {
+			continue
+		}
+		if audit.Decision != nil {
+			a.decisions[audit.Request.ID] = *audit.Decision
+		} else if audit.Tally.Outcome == approval.Pending {
+			a.requests[audit.Request.ID] = audit.Request
+		}
+	}
+	return a
+}
+
+func (a approvalAudits) request(id string) (approval.Request, bool) {
+	r, ok := a.requests[id]
+	return r, ok
+}
+
+func (a approvalAudits) decision(id string) (approval.Decision, bool) {
+	d, ok := a.decisions[id]
+	return d, ok
+}
+
+func withID(req approval.Request, id string) approval.Request {
+	req.ID = id
+	return req
+}
+
+func notifyError(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
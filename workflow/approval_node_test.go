@@ -0,0 +1,244 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/agent/workflowagent"
+	"google.golang.org/adk/v2/approval"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/workflow"
+)
+
+// approvalHarness runs a workflow in which node approve gates node
+// publish, and records the notifications of the policy.
+type approvalHarness struct {
+	t      *testing.T
+	r      *runner.Runner
+	svc    session.Service
+	node   *workflow.ApprovalNode
+	mu     sync.Mutex
+	notifs []approval.Notification
+}
+
+func newApprovalHarness(t *testing.T, policy approval.Policy) *approvalHarness {
+	t.Helper()
+	h := &approvalHarness{t: t, svc: session.InMemoryService()}
+	policy.Notifier = approval.NotifierFunc(func(_ context.Context, n approval.Notification) error {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.notifs = append(h.notifs, n)
+		return nil
+	})
+	node, err := workflow.NewApprovalNode("approve", policy, workflow.NodeConfig{})
+	if err != nil {
+		t.Fatalf("NewApprovalNode: %v", err)
+	}
+	h.node = node
+	publish := workflow.NewFunctionNode("publish", func(_ agent.Context, in workflow.ApprovalResult) (string, error) {
+		return "published", nil
+	}, workflow.NodeConfig{})
+	discard := workflow.NewFunctionNode("discard", func(_ agent.Context, in workflow.ApprovalResult) (string, error) {
+		return "discarded", nil
+	}, workflow.NodeConfig{})
+	a, err := workflowagent.New(workflowagent.Config{
+		Name: "approval_flow",
+		Edges: []workflow.Edge{
+			{From: workflow.Start, To: node},
+			{From: node, To: publish, Route: workflow.ApprovedRoute},
+			{From: node, To: discard, Route: workflow.RejectedRoute},
+		},
+	})
+	if err != nil {
+		t.Fatalf("workflowagent.New: %v", err)
+	}
+	h.r, err = runner.New(runner.Config{AppName: "app", Agent: a, SessionService: h.svc, AutoCreateSession: true})
+	if err != nil {
+		t.Fatalf("runner.New: %v", err)
+	}
+	return h
+}
+
+// turn runs one turn and returns its events.
+func (h *approvalHarness) turn(msg *genai.Content) []*session.Event {
+	h.t.Helper()
+	return h.turnCtx(h.t.Context(), msg)
+}
+
+func (h *approvalHarness) turnCtx(ctx context.Context, msg *genai.Content) []*session.Event {
+	h.t.Helper()
+	var events []*session.Event
+	for ev, err := range h.r.Run(ctx, "u", "s", msg, agent.RunConfig{}) {
+		if err != nil {
+			h.t.Fatalf("Run: %v", err)
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+// answer answers the open request of events with answer, authenticated
+// as its approver if it names one.
+func (h *approvalHarness) answer(events []*session.Event, answer map[string]any) []*session.Event {
+	h.t.Helper()
+	req := requestOf(events)
+	if req == nil {
+		h.t.Fatal("no open approval request")
+	}
+	ctx := h.t.Context()
+	if approver, ok := answer["approver"].(string); ok {
+		ctx = agent.NewContextWithPrincipal(ctx, &agent.Principal{Subject: approver})
+	}
+	return h.turnCtx(ctx, &genai.Content{Role: genai.RoleUser, Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{
+		ID:       req.InterruptID,
+		Name:     workflow.WorkflowInputFunctionCallName,
+		Response: map[string]any{"payload": answer},
+	}}}})
+}
+
+func requestOf(events []*session.Event) *session.RequestInput {
+	for _, ev := range events {
+		if ev.RequestedInput != nil {
+			return ev.RequestedInput
+		}
+	}
+	return nil
+}
+
+func finalOutput(events []*session.Event) any {
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Output != nil {
+			return events[i].Output
+		}
+	}
+	return nil
+}
+
+func TestApprovalNode_Quorum(t *testing.T) {
+	h := newApprovalHarness(t, approval.Policy{
+		Users:   []string{"alice", "bob", "carol"},
+		Quorum:  approval.Quorum{Approvals: 2},
+		Message: "Publish {{.}}?",
+	})
+
+	events := h.turn(genai.NewContentFromText("post", genai.RoleUser))
+	req := requestOf(events)
+	if req == nil || req.Message != "Publish post?" || req.ResponseSchema == nil {
+		t.Fatalf("request = %+v, want the rendered message with a response schema", req)
+	}
+
+	events = h.answer(events, map[string]any{"approved": true, "approver": "alice"})
+	events = h.answer(events, map[string]any{"approved": true, "approver": "mallory"})
+	events = h.answer(events, map[string]any{"approved": true, "approver": "alice"})
+	if requestOf(events) == nil {
+		t.Fatal("request settled without a quorum of distinct approvers")
+	}
+	events = h.answer(events, map[string]any{"approved": true, "approver": "bob", "comment": "ship it"})
+	if got := finalOutput(events); got != "published" {
+		t.Fatalf("final output = %v, want %q", got, "published")
+	}
+
+	var decisions []*approval.Audit
+	var result *approval.Audit
+	for _, ev := range events {
+		if a, ok := approval.AuditOf(ev); ok {
+			if a.Decision != nil {
+				decisions = append(decisions, a)
+			} else if a.Tally.Outcome != approval.Pending {
+				result = a
+			}
+		}
+	}
+	if len(decisions) != 1 || decisions[0].Decision.Approver != "bob" || decisions[0].Decision.Comment != "ship it" {
+		t.Errorf("decision audits of the last turn = %+v, want bob's", decisions)
+	}
+	if result == nil || result.Tally.Outcome != approval.Approved || result.Tally.Approvals != 2 || len(result.Tally.Decisions) != 4 {
+		t.Fatalf("outcome audit = %+v, want approved by 2 of 4 decisions", result)
+	}
+	if ignored := result.Tally.Decisions[1].Ignored; ignored == "" {
+		t.Error("decision of a user who isn't an approver counted")
+	}
+
+	kinds := map[approval.NotificationKind]int{}
+	for _, n := range h.notifs {
+		kinds[n.Kind]++
+	}
+	if kinds[approval.Requested] != 1 || kinds[approval.Decided] != 1 {
+		t.Errorf("notifications = %v, want one request and one decision", kinds)
+	}
+}
+
+func TestApprovalNode_Rejection(t *testing.T) {
+	h := newApprovalHarness(t, approval.Policy{Users: []string{"alice", "bob"}, Quorum: approval.Quorum{All: true}})
+	events := h.turn(genai.NewContentFromText("post", genai.RoleUser))
+	events = h.answer(events, map[string]any{"approved": false, "approver": "bob"})
+	if got := finalOutput(events); got != "discarded" {
+		t.Fatalf("final output = %v, want %q", got, "discarded")
+	}
+}
+
+func TestApprovalNode_Expiry(t *testing.T) {
+	const expiry = 200 * time.Millisecond
+	h := newApprovalHarness(t, approval.Policy{
+		Expiry:        expiry,
+		ExpiryOutcome: approval.Approved,
+		Reminders:     []approval.Reminder{{After: expiry / 2, EscalateTo: []string{"boss"}}},
+	})
+	events := h.turn(genai.NewContentFromText("post", genai.RoleUser))
+
+	resp, err := h.svc.Get(t.Context(), &session.GetRequest{AppName: "app", UserID: "u", SessionID: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	open := approval.OpenRequests(resp.Session.Events())
+	if len(open) != 1 {
+		t.Fatalf("OpenRequests() = %v, want one request", open)
+	}
+	at := open[0].RequestedAt
+	expired, err := h.node.Sweep(t.Context(), resp.Session, at, at.Add(expiry*3/4))
+	if err != nil || len(expired) != 0 {
+		t.Fatalf("Sweep() = %v, %v, want nothing expired", expired, err)
+	}
+	if n := h.notifs[len(h.notifs)-1]; n.Kind != approval.Escalated || n.EscalateTo[0] != "boss" {
+		t.Errorf("last notification = %+v, want an escalation to boss", n)
+	}
+	expired, err = h.node.Sweep(t.Context(), resp.Session, at.Add(expiry*3/4), at.Add(2*expiry))
+	if err != nil || len(expired) != 1 || expired[0].ID != requestOf(events).InterruptID {
+		t.Fatalf("Sweep() = %v, %v, want the request expired", expired, err)
+	}
+
+	time.Sleep(time.Until(expired[0].ExpiresAt))
+	events = h.answer(events, approval.ExpiredAnswer())
+	if got := finalOutput(events); got != "published" {
+		t.Fatalf("final output = %v, want %q (the expiry outcome)", got, "published")
+	}
+}
+
+func TestApprovalNode_ExpiryNotClaimable(t *testing.T) {
+	h := newApprovalHarness(t, approval.Policy{Expiry: time.Hour, ExpiryOutcome: approval.Approved})
+	events := h.turn(genai.NewContentFromText("post", genai.RoleUser))
+	events = h.answer(events, map[string]any{"approved": false, "expired": true})
+	if got := finalOutput(events); got != "discarded" {
+		t.Fatalf("final output = %v, want %q: the answer can't expire the request", got, "discarded")
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/typeutil"
+	"google.golang.org/adk/v2/session"
+)
+
+// ErrCompensationFailed wraps the errors of compensation hooks which
+// failed. The run's error joins it with the error which triggered the
+// compensation.
+var ErrCompensationFailed = errors.New("workflow: compensation failed")
+
+// CompensateFunc undoes the effects of a completed node activation,
+// e.g. refunds a payment a node made or deletes a resource it
+// created. input and output are the activation's input and output.
+//
+// The scheduler calls it, in reverse completion order, once the run
+// has failed or its context was cancelled and no node is running any
+// more. ctx is not cancelled with the run, so that the hook can still
+// reach external systems when the run timed out; it carries the
+// activation's branch and path, and is cancelled after
+// NodeConfig.CompensateTimeout, when the hook fails with
+// ErrCompensationTimeout whether it returned or not. Hooks run one at
+// a time; the failure of one does not stop the others.
+//
+// The activations a run completed before it paused for human input,
+// or before a durable run was interrupted, are recorded in its
+// RunState and compensated when the Resume or Recover call continuing
+// it fails; their hooks then receive the JSON-decoded input and
+// output. A nested workflow compensates its own nodes when it fails.
+// A run stopped because its caller stopped consuming events is not
+// compensated: a durable run may still be recovered.
+type CompensateFunc func(ctx agent.Context, input, output any) error
+
+// ErrCompensationTimeout is the error of a compensation hook which did
+// not return within NodeConfig.CompensateTimeout. It matches
+// context.DeadlineExceeded.
+var ErrCompensationTimeout = fmt.Errorf("workflow: compensation timed out: %w", context.DeadlineExceeded)
+
+// defaultCompensateTimeout bounds the compensation hooks of nodes
+// without a NodeConfig.CompensateTimeout.
+const defaultCompensateTimeout = time.Minute
+
+// CompensationKey is the Event.CustomMetadata key of the Compensation
+// recorded by compensation events.
+const CompensationKey = "workflow_compensation"
+
+// Compensation records that the scheduler ran the compensation hook of
+// a node. It is emitted, once per hook, as an event without content
+// carrying it under Event.CustomMetadata[CompensationKey], so that the
+// session keeps an audit trail of what was undone.
+type Compensation struct {
+	// Node is the name of the compensated node.
+	Node string `json:"node"`
+	// Error is the error of the hook; empty when it succeeded.
+	Error string `json:"error,omitempty"`
+}
+
+// CompensationOf returns the Compensation recorded by ev, if any. It
+// accepts events read back from a session service which stores
+// metadata in its JSON-decoded form.
+func CompensationOf(ev *session.Event) (*Compensation, bool) {
+	if ev == nil {
+		return nil, false
+	}
+	return typeutil.Decode[Compensation](ev.CustomMetadata[CompensationKey])
+}
+
+// CompensableActivation is a completed activation of a node with a
+// compensation hook, kept in the RunState in case the run fails.
+type CompensableActivation struct {
+	// Node is the name of the node.
+	Node string `json:"node"`
+	// Input and Output are the input and output of the activation.
+	Input  any `json:"input,omitempty"`
+	Output any `json:"output,omitempty"`
+	// Branch and Path are the branch and path of the activation.
+	Branch string `json:"branch,omitempty"`
+	Path   string `json:"path,omitempty"`
+}
+
+// compensableKey is the Event.CustomMetadata key of the
+// compensableRecord saved by a paused run.
+const compensableKey = "workflow_compensable"
+
+// compensableRecord is the record of the compensable activations of a
+// paused run, from which ReconstructRunState restores them on resume.
+type compensableRecord struct {
+	Workflow    string                  `json:"workflow"`
+	Activations []CompensableActivation `json:"activations"`
+}
+
+// compensableEvent returns the event recording the compensable
+// activations of the scheduler's run as it pauses.
+func (s *scheduler) compensableEvent() *session.Event {
+	ev := session.NewEvent(s.parentCtx, s.parentCtx.InvocationID())
+	ev.Author = s.name
+	ev.Branch = s.parentCtx.Branch()
+	ev.CustomMetadata = map[string]any{compensableKey: &compensableRecord{
+		Workflow:    s.name,
+		Activations: slices.Clone(s.state.Compensable),
+	}}
+	return ev
+}
+
+// compensableActivations returns the compensable activations recorded
+// by the last pause of the run of the workflow named name in events,
+// unless they were compensated since.
+func compensableActivations(events session.Events, name, invocationID string) []CompensableActivation {
+	var activations []CompensableActivation
+	for ev := range events.All() {
+		if ev == nil || (invocationID != "" && ev.InvocationID != invocationID) {
+			continue
+		}
+		if rec, ok := typeutil.Decode[compensableRecord](ev.CustomMetadata[compensableKey]); ok && rec.Workflow == name {
+			activations = rec.Activations
+		} else if _, ok := CompensationOf(ev); ok {
+			activations = nil
+		}
+	}
+	return activations
+}
+
+// compensate runs the compensation hooks of the completed activations
+// in reverse completion order, yielding an event per hook. It returns
+// whether the consumer is gone, and the joined errors of the hooks
+// which failed, wrapped in ErrCompensationFailed.
+//
+// compensate runs only on the consumer goroutine, after every node
+// goroutine has returned.
+func (s *scheduler) compensate(yield func(*session.Event, error) bool) (consumerGone bool, err error) {
+	var errs []error
+	for _, a := range slices.Backward(s.state.Compensable) {
+		hookErr := s.runCompensation(a)
+		c := &Compensation{Node: a.Node}
+		if hookErr != nil {
+			c.Error = hookErr.Error()
+			errs = append(errs, fmt.Errorf("node %q: %w", a.Node, hookErr))
+		}
+		if consumerGone {
+			continue
+		}
+		ev := session.NewEvent(s.parentCtx, s.parentCtx.InvocationID())
+		ev.Branch = a.Branch
+		ev.NodeInfo = &session.NodeInfo{Path: a.Path}
+		ev.CustomMetadata = map[string]any{CompensationKey: c}
+		if !yield(ev, nil) {
+			consumerGone = true
+		}
+	}
+	s.state.Compensable = nil
+	if len(errs) > 0 {
+		err = fmt.Errorf("%w: %w", ErrCompensationFailed, errors.Join(errs...))
+	}
+	return consumerGone, err
+}
+
+// runCompensation runs the hook of the node of a, in a context which
+// outlives the run but not the node's CompensateTimeout, reporting a
+// panic as an error. A hook which doesn't return in time is left
+// running.
+func (s *scheduler) runCompensation(a CompensableActivation) error {
+	node := s.nodesByName[a.Node]
+	if node == nil || node.Config().Compensate == nil {
+		return errors.New("node has no compensation hook")
+	}
+	timeout := cmp.Or(node.Config().CompensateTimeout, defaultCompensateTimeout)
+	hookCtx, cancel := context.WithTimeoutCause(context.WithoutCancel(s.parentCtx), timeout, ErrCompensationTimeout)
+	defer cancel()
+	ctx := s.parentCtx.WithAgentContext(hookCtx).WithDelta(&agent.CommonContextDelta{
+		InvocationContextDelta: &agent.InvocationContextDelta{
+			Branch: &a.Branch,
+		},
+		Path: &a.Path,
+	})
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("compensation panicked: %v", r)
+			}
+		}()
+		done <- node.Config().Compensate(ctx, a.Input, a.Output)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-hookCtx.Done():
+		return context.Cause(hookCtx)
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"errors"
+	"iter"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/session"
+)
+
+// compensationLog records the compensation hooks run, in order.
+type compensationLog struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+// node returns a node outputting name + "-out" whose compensation
+// hook logs "undo <name>(<input>,<output>)" and returns hookErr.
+func (l *compensationLog) node(name string, hookErr error) Node {
+	return NewFunctionNode(name, func(ctx agent.Context, input any) (string, error) {
+		return name + "-out", nil
+	}, NodeConfig{Compensate: func(ctx agent.Context, input, output any) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.entries = append(l.entries, "undo "+name+"("+input.(string)+","+output.(string)+")")
+		return hookErr
+	}})
+}
+
+// erringNode returns a node which fails with err.
+func erringNode(name string, err error) Node {
+	return NewFunctionNode(name, func(ctx agent.Context, input any) (string, error) {
+		return "", err
+	}, defaultNodeConfig)
+}
+
+// runCollectingCompensations runs w and returns the compensations
+// recorded by its events and its error.
+func runCollectingCompensations(t *testing.T, w *Workflow) ([]Compensation, error) {
+	t.Helper()
+	return collectCompensations(w.Run(newSeededMockCtx(t)))
+}
+
+func TestCompensate_ReverseOrderOnFailure(t *testing.T) {
+	var log compensationLog
+	boom := errors.New("boom")
+	plain := NewFunctionNode("plain", func(ctx agent.Context, input any) (string, error) {
+		return "plain-out", nil
+	}, defaultNodeConfig)
+	w := mustNew(t, Chain(Start, log.node("a", nil), plain, log.node("b", nil), erringNode("c", boom)))
+
+	got, err := runCollectingCompensations(t, w)
+	if !errors.Is(err, boom) {
+		t.Errorf("Run() error = %v, want %v", err, boom)
+	}
+	if diff := cmp.Diff([]Compensation{{Node: "b"}, {Node: "a"}}, got); diff != "" {
+		t.Errorf("compensation events mismatch (-want +got):\n%s", diff)
+	}
+	want := []string{"undo b(plain-out,b-out)", "undo a(seed,a-out)"}
+	if diff := cmp.Diff(want, log.entries); diff != "" {
+		t.Errorf("compensations run mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCompensate_OnTimeout(t *testing.T) {
+	var log compensationLog
+	w, err := New("", Chain(Start, log.node("a", nil), untilDoneNode("slow", defaultNodeConfig)), WithTimeout(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	got, err := runCollectingCompensations(t, w)
+	if !errors.Is(err, ErrWorkflowTimeout) {
+		t.Errorf("Run() error = %v, want %v", err, ErrWorkflowTimeout)
+	}
+	if diff := cmp.Diff([]Compensation{{Node: "a"}}, got); diff != "" {
+		t.Errorf("compensation events mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"undo a(seed,a-out)"}, log.entries); diff != "" {
+		t.Errorf("compensations run mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCompensate_HookFailureJoined(t *testing.T) {
+	var log compensationLog
+	boom := errors.New("boom")
+	undoErr := errors.New("refund rejected")
+	w := mustNew(t, Chain(Start, log.node("a", nil), log.node("b", undoErr), erringNode("c", boom)))
+
+	got, err := runCollectingCompensations(t, w)
+	for _, want := range []error{boom, ErrCompensationFailed, undoErr} {
+		if !errors.Is(err, want) {
+			t.Errorf("Run() error = %v, want it to match %v", err, want)
+		}
+	}
+	want := []Compensation{{Node: "b", Error: undoErr.Error()}, {Node: "a"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("compensation events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCompensate_NotOnSuccess(t *testing.T) {
+	var log compensationLog
+	w := mustNew(t, Chain(Start, log.node("a", nil), log.node("b", nil)))
+
+	got, err := runCollectingCompensations(t, w)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(got) != 0 || len(log.entries) != 0 {
+		t.Errorf("compensations = %v, %q, want none", got, log.entries)
+	}
+}
+
+// collectCompensations drains events and returns the compensations
+// they record and the error of the run.
+func collectCompensations(events iter.Seq2[*session.Event, error]) ([]Compensation, error) {
+	var got []Compensation
+	var gotErr error
+	for ev, err := range events {
+		if err != nil {
+			gotErr = err
+			continue
+		}
+		if c, ok := CompensationOf(ev); ok {
+			got = append(got, *c)
+		}
+	}
+	return got, gotErr
+}
+
+func TestCompensate_AfterResume(t *testing.T) {
+	var log compensationLog
+	boom := errors.New("boom")
+	review := newHitlNode("review", func(ctx agent.Context, _ any, yield func(*session.Event, error) bool) {
+		yield(NewRequestInputEvent(ctx, session.RequestInput{InterruptID: "review"}), nil)
+	})
+	w := mustNew(t, Chain(Start, log.node("book", nil), review, erringNode("pay", boom)))
+
+	events := drain(t, w.Run(newSeededMockCtx(t)))
+	answer := &session.Event{Author: "user", InvocationID: "test-invocation-id"}
+	answer.Content = genai.NewContentFromFunctionResponse("review", map[string]any{"payload": "ok"}, genai.RoleUser)
+	answer.Content.Parts[0].FunctionResponse.ID = "review"
+	state, err := w.ReconstructRunState(fakeSession{events: sliceEvents(append(events, answer))}, "test-invocation-id")
+	if err != nil {
+		t.Fatalf("ReconstructRunState: %v", err)
+	}
+
+	got, err := collectCompensations(w.Resume(agent.NewContext(newMockCtx(t)), state, map[string]any{"review": "ok"}))
+	if !errors.Is(err, boom) {
+		t.Errorf("Resume() error = %v, want %v", err, boom)
+	}
+	if diff := cmp.Diff([]Compensation{{Node: "book"}}, got); diff != "" {
+		t.Errorf("compensation events mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"undo book(seed,book-out)"}, log.entries); diff != "" {
+		t.Errorf("compensations run mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCompensate_AfterRecover(t *testing.T) {
+	var log compensationLog
+	boom := errors.New("boom")
+	w, err := New("wf", Chain(Start, log.node("a", nil), log.node("b", nil), erringNode("c", boom)), WithDurableExecution())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	cp := interruptAfter(t, w, "b")
+
+	got, err := collectCompensations(w.Recover(newMockCtx(t), cp))
+	if !errors.Is(err, boom) {
+		t.Errorf("Recover() error = %v, want %v", err, boom)
+	}
+	if diff := cmp.Diff([]Compensation{{Node: "b"}, {Node: "a"}}, got); diff != "" {
+		t.Errorf("compensation events mismatch (-want +got):\n%s", diff)
+	}
+	want := []string{"undo b(a-out,b-out)", "undo a(seed,a-out)"}
+	if diff := cmp.Diff(want, log.entries); diff != "" {
+		t.Errorf("compensations run mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCompensate_HookTimeout(t *testing.T) {
+	boom := errors.New("boom")
+	hang := make(chan struct{})
+	defer close(hang)
+	a := NewFunctionNode("a", func(ctx agent.Context, input any) (string, error) {
+		return "a-out", nil
+	}, NodeConfig{
+		Compensate: func(ctx agent.Context, input, output any) error {
+			<-hang
+			return nil
+		},
+		CompensateTimeout: 10 * time.Millisecond,
+	})
+	w := mustNew(t, Chain(Start, a, erringNode("b", boom)))
+
+	got, err := runCollectingCompensations(t, w)
+	if !errors.Is(err, ErrCompensationTimeout) || !errors.Is(err, boom) {
+		t.Errorf("Run() error = %v, want it to match %v and %v", err, boom, ErrCompensationTimeout)
+	}
+	if len(got) != 1 || got[0].Error == "" {
+		t.Errorf("compensations = %+v, want a failed one of a", got)
+	}
+}
+
+func TestCompensationOf_DecodedMetadata(t *testing.T) {
+	ev := &session.Event{}
+	ev.CustomMetadata = map[string]any{CompensationKey: map[string]any{"node": "a", "error": "x"}}
+	got, ok := CompensationOf(ev)
+	if !ok || *got != (Compensation{Node: "a", Error: "x"}) {
+		t.Errorf("CompensationOf() = %+v, %v, want node a with error x", got, ok)
+	}
+	if _, ok := CompensationOf(&session.Event{}); ok {
+		t.Error("CompensationOf() of an event without compensation ok = true, want false")
+	}
+}
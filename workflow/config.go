@@ -17,6 +17,8 @@ package workflow
 import (
 	"errors"
 	"time"
+
+	"google.golang.org/adk/v2/agent"
 )
 
 // defaultRetryConfig is the default retry configuration for a node.
@@ -83,6 +85,24 @@ type NodeConfig struct {
 	// context's deadline, if any.
 	Timeout time.Duration
 
+	// Compensate, when non-nil, undoes a completed activation of the
+	// node when the workflow run later fails or is cancelled. The
+	// scheduler runs the hooks of completed nodes in reverse
+	// completion order and records each in the session; see
+	// CompensateFunc.
+	Compensate CompensateFunc
+
+	// CompensateTimeout, when > 0, bounds each run of Compensate.
+	// Zero means one minute.
+	CompensateTimeout time.Duration
+
+	// StateKeys declares the session state keys the node reads or
+	// writes, e.g. with agent.StateKey Get and Set. New checks that
+	// the workflow's state schema (see WithStateSchema) declares each
+	// of them with a type accepting its values, and that nodes
+	// declaring the same key agree on its type.
+	StateKeys []agent.StateKeyDescriptor
+
 	// EmitsOwnSpan, when true, tells the scheduler not to wrap the node
 	// in an "invoke_node" telemetry span because the node body already
 	// starts its own span (e.g. an LlmAgent node whose wrapped agent
@@ -0,0 +1,103 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrWorkflowTimeout is returned by a run which exceeded the timeout
+	// set with WithTimeout. It matches context.DeadlineExceeded.
+	ErrWorkflowTimeout = fmt.Errorf("workflow: run timed out: %w", context.DeadlineExceeded)
+
+	// ErrBranchTimeout is the error of a node whose branch exceeded the
+	// timeout set with WithBranchTimeout. It matches
+	// context.DeadlineExceeded.
+	ErrBranchTimeout = fmt.Errorf("workflow: branch timed out: %w", context.DeadlineExceeded)
+)
+
+// WithTimeout bounds each run of the workflow — a call to Run, RunNode,
+// Resume or Recover — to d. When it elapses, in-flight nodes are
+// cancelled, compensation runs (see NodeConfig.Compensate) and the run
+// fails with ErrWorkflowTimeout. d <= 0 disables the timeout.
+//
+// Unlike NodeConfig.Timeout, which bounds one activation of one node,
+// the timeout covers the whole graph, including time nodes spend
+// queued behind WithMaxConcurrency or waiting for retries.
+func WithTimeout(d time.Duration) Option {
+	return func(o *workflowOptions) {
+		o.timeout = max(d, 0)
+	}
+}
+
+// WithBranchTimeout bounds each branch forked by a fan-out to d from
+// the moment it was forked: nodes of a branch run with a deadline of
+// the fork time plus d, and of the fork times of the enclosing
+// branches plus d for nested fan-outs. A node still running at the
+// deadline fails with ErrBranchTimeout, and is not retried. d <= 0
+// disables branch timeouts.
+//
+// Nodes on the workflow's own branch, e.g. a chain without fan-out or
+// a JoinNode merging the branches back, are not bounded.
+func WithBranchTimeout(d time.Duration) Option {
+	return func(o *workflowOptions) {
+		o.branchTimeout = max(d, 0)
+	}
+}
+
+// branchDeadline returns the deadline of nodes running on branch under
+// the workflow's branch timeout, and the error cancelling them at the
+// deadline. It records the fork time of branches seen for the first
+// time. ok is false when the branch is not bounded.
+//
+// branchDeadline runs only on the consumer goroutine.
+func (s *scheduler) branchDeadline(branch string) (deadline time.Time, cause error, ok bool) {
+	if s.branchTimeout <= 0 {
+		return time.Time{}, nil, false
+	}
+	// Only the segments the workflow forked itself count: the
+	// workflow's own branch belongs to its caller.
+	base := s.parentCtx.Branch()
+	rest := branch
+	if base != "" {
+		if branch == base || !strings.HasPrefix(branch, base+".") {
+			return time.Time{}, nil, false
+		}
+		rest = strings.TrimPrefix(branch, base+".")
+	}
+	if rest == "" {
+		return time.Time{}, nil, false
+	}
+
+	now := time.Now()
+	prefix := base
+	var bounding string
+	for _, seg := range strings.Split(rest, ".") {
+		prefix = deriveSubBranch(prefix, seg)
+		forked, seen := s.branchForks[prefix]
+		if !seen {
+			forked = now
+			s.branchForks[prefix] = forked
+		}
+		if d := forked.Add(s.branchTimeout); bounding == "" || d.Before(deadline) {
+			deadline, bounding = d, prefix
+		}
+	}
+	return deadline, fmt.Errorf("%w: branch %q", ErrBranchTimeout, bounding), true
+}
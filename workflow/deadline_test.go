@@ -0,0 +1,150 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/v2/agent"
+)
+
+// untilDoneNode returns a node which runs until its context is done.
+func untilDoneNode(name string, cfg NodeConfig) Node {
+	return NewFunctionNode(name, func(ctx agent.Context, input any) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}, cfg)
+}
+
+// sleepingNode returns a node which outputs its name after d.
+func sleepingNode(name string, d time.Duration) Node {
+	return NewFunctionNode(name, func(ctx agent.Context, input any) (string, error) {
+		select {
+		case <-time.After(d):
+			return name, nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}, defaultNodeConfig)
+}
+
+func TestWithTimeout_FailsRun(t *testing.T) {
+	w, err := New("", Chain(Start, upperNode, untilDoneNode("slow", defaultNodeConfig)), WithTimeout(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	err = drainErr(t, w.Run(newSeededMockCtx(t)))
+	if !errors.Is(err, ErrWorkflowTimeout) {
+		t.Errorf("Run() error = %v, want %v", err, ErrWorkflowTimeout)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run() error = %v, want it to match context.DeadlineExceeded", err)
+	}
+}
+
+func TestWithTimeout_NotReachedCompletes(t *testing.T) {
+	w, err := New("", Chain(Start, sleepingNode("a", time.Millisecond)), WithTimeout(time.Minute))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	events := drain(t, w.Run(newSeededMockCtx(t)))
+	if got := events[len(events)-1].Output; got != "a" {
+		t.Errorf("last output = %v, want %q", got, "a")
+	}
+}
+
+func TestWithBranchTimeout_FailsSlowBranch(t *testing.T) {
+	var attempts atomic.Int32
+	rc := DefaultRetryConfig()
+	rc.InitialDelay = time.Millisecond
+	slow := NewFunctionNode("slow", func(ctx agent.Context, input any) (string, error) {
+		attempts.Add(1)
+		<-ctx.Done()
+		return "", ctx.Err()
+	}, NodeConfig{RetryConfig: rc})
+	w, err := New("", []Edge{
+		{From: Start, To: sleepingNode("fast", time.Millisecond)},
+		{From: Start, To: slow},
+	}, WithBranchTimeout(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	var fastDone bool
+	var gotErr error
+	for ev, err := range w.Run(newSeededMockCtx(t)) {
+		if err != nil {
+			gotErr = err
+			continue
+		}
+		fastDone = fastDone || ev.Output == "fast"
+	}
+	if !errors.Is(gotErr, ErrBranchTimeout) {
+		t.Fatalf("Run() error = %v, want %v", gotErr, ErrBranchTimeout)
+	}
+	if !strings.Contains(gotErr.Error(), `"slow@1"`) {
+		t.Errorf("Run() error = %v, want it to name branch slow@1", gotErr)
+	}
+	if !fastDone {
+		t.Error("branch fast did not complete")
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("slow ran %d times, want 1: a timed out branch is not retried", got)
+	}
+}
+
+func TestWithBranchTimeout_RootBranchUnbounded(t *testing.T) {
+	w, err := New("", Chain(Start, sleepingNode("a", 30*time.Millisecond)), WithBranchTimeout(time.Millisecond))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	events := drain(t, w.Run(newSeededMockCtx(t)))
+	if got := events[len(events)-1].Output; got != "a" {
+		t.Errorf("last output = %v, want %q", got, "a")
+	}
+}
+
+func TestBranchDeadline_NestedBranchesUseEarliestFork(t *testing.T) {
+	w := mustNew(t, Chain(Start, newTestNode("a")))
+	mockCtx := newMockCtx(t)
+	mockCtx.branch = "outer"
+	s, _, cancel := w.newScheduler(agent.Promote(mockCtx))
+	defer cancel()
+	s.branchTimeout = time.Minute
+
+	if _, _, ok := s.branchDeadline("outer"); ok {
+		t.Error("branchDeadline() of the workflow's own branch ok = true, want false")
+	}
+	d1, _, ok := s.branchDeadline("outer.a@1")
+	if !ok {
+		t.Fatal("branchDeadline(outer.a@1) ok = false, want true")
+	}
+	d2, cause, ok := s.branchDeadline("outer.a@1.b@1")
+	if !ok {
+		t.Fatal("branchDeadline(outer.a@1.b@1) ok = false, want true")
+	}
+	if !d2.Equal(d1) {
+		t.Errorf("nested deadline = %v, want the enclosing branch's %v", d2, d1)
+	}
+	if !strings.Contains(cause.Error(), `"outer.a@1"`) {
+		t.Errorf("cause = %v, want it to name the enclosing branch", cause)
+	}
+}
@@ -0,0 +1,388 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/typeutil"
+	"google.golang.org/adk/v2/session"
+)
+
+// ErrNodeNotRerunnable is returned by Workflow.Recover when a node was
+// in flight when the run was interrupted and its
+// NodeConfig.RerunOnResume is &false: the node may have had side
+// effects, so it is not re-run and the run cannot continue.
+var ErrNodeNotRerunnable = errors.New("workflow: interrupted node cannot be re-run")
+
+// ErrNothingToRecover is returned by Workflow.Recover when the
+// checkpoint does not describe a run in progress.
+var ErrNothingToRecover = errors.New("workflow: checkpoint is not of a run in progress")
+
+// checkpointKeyPrefix prefixes the session.State keys of checkpoints.
+const checkpointKeyPrefix = "workflow_checkpoint:"
+
+// checkpointPartKeyPrefix prefixes the session.State keys of the parts
+// of checkpoints: the state of each node and each compensable
+// activation of a run is saved under a key of its own, so that a
+// checkpoint only saves what changed since the previous one.
+const checkpointPartKeyPrefix = "workflow_checkpoint_part:"
+
+// Kinds of checkpoint parts, following the run's prefix in their keys.
+const (
+	nodePart        = "node/"
+	compensablePart = "compensable/"
+)
+
+// RunStatus is the status of a workflow run recorded by a Checkpoint.
+type RunStatus string
+
+const (
+	// RunInProgress means the run has not finished. A checkpoint left
+	// in this status by a process that stopped is the point the run
+	// is recovered from.
+	RunInProgress RunStatus = "running"
+	// RunPaused means the run is waiting for human input and continues
+	// with Resume on a later turn.
+	RunPaused RunStatus = "paused"
+	// RunCompleted means the run finished successfully.
+	RunCompleted RunStatus = "completed"
+	// RunFailed means a node failed and the run stopped.
+	RunFailed RunStatus = "failed"
+)
+
+// Checkpoint is the durable record of a workflow run. Workflows created
+// with WithDurableExecution save one in session.State under
+// RunStateSessionKey as nodes complete, so that a run interrupted by a
+// process crash can be continued with Recover.
+type Checkpoint struct {
+	// Workflow is the name of the workflow.
+	Workflow string `json:"workflow"`
+	// InvocationID is the invocation of the run.
+	InvocationID string `json:"invocationId"`
+	// Status is the status of the run.
+	Status RunStatus `json:"status"`
+	// State is the lifecycle state of the nodes. Nil once the run
+	// completed, or failed and was compensated, since it can't
+	// continue.
+	State *RunState `json:"state,omitempty"`
+	// UpdatedAt is the time the checkpoint was saved.
+	UpdatedAt time.Time `json:"updatedAt"`
+	// Version is the version of the workflow which saved the
+	// checkpoint; see WithVersion.
+	Version string `json:"version,omitempty"`
+}
+
+// RunStateSessionKey returns the session.State key under which a
+// durable workflow saves the Checkpoint of a run. The state of its
+// nodes is saved under keys of its own; LoadCheckpoint puts them
+// together.
+func RunStateSessionKey(workflowName, invocationID string) string {
+	return checkpointKeyPrefix + workflowName + "/" + invocationID
+}
+
+// checkpointPartsKeyPrefix returns the prefix of the session.State keys
+// of the parts of the checkpoint of a run.
+func checkpointPartsKeyPrefix(workflowName, invocationID string) string {
+	return checkpointPartKeyPrefix + workflowName + "/" + invocationID + "/"
+}
+
+// WithDurableExecution makes the workflow checkpoint its run to the
+// session as nodes complete: each completion is followed by an event
+// whose state delta saves a Checkpoint with the status, input and
+// output of the nodes which changed. Once the run completed, or failed
+// and was compensated, the state of its nodes is deleted and only its
+// status is kept. Outputs must therefore be JSON-encodable, and
+// successors of recovered nodes receive their JSON-decoded outputs.
+//
+// Checkpoints are written after a node completes, so a node whose
+// completion was not saved before a crash runs again on recovery:
+// execution is at-least-once for nodes in flight and exactly-once for
+// checkpointed ones. Nested workflows are not checkpointed separately;
+// an interrupted WorkflowNode re-runs as a whole.
+//
+// Requires a non-empty workflow name.
+func WithDurableExecution() Option {
+	return func(o *workflowOptions) {
+		o.durable = true
+	}
+}
+
+// Durable reports whether the workflow was created with
+// WithDurableExecution.
+func (w *Workflow) Durable() bool {
+	return w.durable
+}
+
+// LoadCheckpoint returns the Checkpoint of the workflow run of the
+// given invocation saved in state, or nil if there is none.
+func LoadCheckpoint(state session.ReadonlyState, workflowName, invocationID string) (*Checkpoint, error) {
+	value, err := state.Get(RunStateSessionKey(workflowName, invocationID))
+	if errors.Is(err, session.ErrStateKeyNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cp, err := decodeCheckpoint(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := loadCheckpointParts(state, cp); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// Checkpoints returns all workflow run checkpoints saved in state,
+// ordered by workflow name and invocation ID.
+func Checkpoints(state session.ReadonlyState) ([]*Checkpoint, error) {
+	var checkpoints []*Checkpoint
+	for key, value := range state.All() {
+		if !strings.HasPrefix(key, checkpointKeyPrefix) || value == nil {
+			continue
+		}
+		cp, err := decodeCheckpoint(value)
+		if err == nil {
+			err = loadCheckpointParts(state, cp)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("checkpoint %q: %w", key, err)
+		}
+		checkpoints = append(checkpoints, cp)
+	}
+	slices.SortFunc(checkpoints, func(a, b *Checkpoint) int {
+		return strings.Compare(a.Workflow+"/"+a.InvocationID, b.Workflow+"/"+b.InvocationID)
+	})
+	return checkpoints, nil
+}
+
+// decodeCheckpoint converts a state value back to a Checkpoint. Values
+// are stored in their JSON-decoded form, so that session services which
+// keep state in memory and ones which serialize it behave the same.
+func decodeCheckpoint(value any) (*Checkpoint, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(b, &cp); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint: %w", err)
+	}
+	return &cp, nil
+}
+
+// encodeCheckpoint converts a Checkpoint to its state value: a deep copy
+// in JSON-decoded form, so later changes to the run state don't alter
+// the saved one.
+func encodeCheckpoint(cp *Checkpoint) (any, error) {
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	var value map[string]any
+	if err := json.Unmarshal(b, &value); err != nil {
+		return nil, fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	return value, nil
+}
+
+// loadCheckpointParts sets the nodes and compensable activations of
+// cp's state from the parts of the checkpoint saved in state. A
+// checkpoint without state, of a run which can't continue, has none.
+func loadCheckpointParts(state session.ReadonlyState, cp *Checkpoint) error {
+	if cp.State == nil {
+		return nil
+	}
+	prefix := checkpointPartsKeyPrefix(cp.Workflow, cp.InvocationID)
+	compensable := map[int]CompensableActivation{}
+	for key, value := range state.All() {
+		part, ok := strings.CutPrefix(key, prefix)
+		if !ok || value == nil {
+			continue
+		}
+		if name, ok := strings.CutPrefix(part, nodePart); ok {
+			ns, ok := typeutil.Decode[NodeState](value)
+			if !ok {
+				return fmt.Errorf("failed to decode checkpoint of node %q", name)
+			}
+			if cp.State.Nodes == nil {
+				cp.State.Nodes = map[string]*NodeState{}
+			}
+			cp.State.Nodes[name] = ns
+		} else if i, err := strconv.Atoi(strings.TrimPrefix(part, compensablePart)); err == nil {
+			a, ok := typeutil.Decode[CompensableActivation](value)
+			if !ok {
+				return fmt.Errorf("failed to decode compensable activation %d", i)
+			}
+			compensable[i] = *a
+		}
+	}
+	for _, i := range slices.Sorted(maps.Keys(compensable)) {
+		cp.State.Compensable = append(cp.State.Compensable, compensable[i])
+	}
+	return nil
+}
+
+// checkpointDelta returns the session state delta saving cp. saved
+// holds the JSON encodings of the parts of the checkpoint saved
+// before, keyed by state key, and is updated: parts which didn't
+// change are left out of the delta, and those cp no longer has are
+// deleted.
+func checkpointDelta(cp *Checkpoint, saved map[string]string) (map[string]any, error) {
+	header := *cp
+	parts := map[string][]byte{}
+	if cp.State != nil {
+		header.State = &RunState{}
+		prefix := checkpointPartsKeyPrefix(cp.Workflow, cp.InvocationID)
+		for name, ns := range cp.State.Nodes {
+			b, err := json.Marshal(ns)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode checkpoint of node %q: %w", name, err)
+			}
+			parts[prefix+nodePart+name] = b
+		}
+		for i, a := range cp.State.Compensable {
+			b, err := json.Marshal(a)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode compensable activation of node %q: %w", a.Node, err)
+			}
+			parts[prefix+compensablePart+strconv.Itoa(i)] = b
+		}
+	}
+	value, err := encodeCheckpoint(&header)
+	if err != nil {
+		return nil, err
+	}
+	delta := map[string]any{RunStateSessionKey(cp.Workflow, cp.InvocationID): value}
+	for key, b := range parts {
+		if saved[key] == string(b) {
+			continue
+		}
+		var v any
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, fmt.Errorf("failed to encode checkpoint: %w", err)
+		}
+		delta[key] = v
+		saved[key] = string(b)
+	}
+	for key := range saved {
+		if _, ok := parts[key]; !ok {
+			delta[key] = nil
+			delete(saved, key)
+		}
+	}
+	return delta, nil
+}
+
+// checkpointEvent returns the event saving the checkpoint of the
+// scheduler's run with the given status. The state of a run which is
+// done, since it completed or was compensated, is not kept.
+func (s *scheduler) checkpointEvent(status RunStatus, done bool) (*session.Event, error) {
+	cp := &Checkpoint{
+		Workflow:     s.durableName,
+		InvocationID: s.parentCtx.InvocationID(),
+		Status:       status,
+		UpdatedAt:    time.Now(),
+		Version:      s.version,
+	}
+	if !done {
+		cp.State = s.state
+	}
+	if s.checkpointed == nil {
+		s.checkpointed = map[string]string{}
+	}
+	delta, err := checkpointDelta(cp, s.checkpointed)
+	if err != nil {
+		return nil, err
+	}
+	ev := session.NewEvent(s.parentCtx, s.parentCtx.InvocationID())
+	ev.Author = s.durableName
+	ev.Branch = s.parentCtx.Branch()
+	ev.Actions.StateDelta = delta
+	return ev, nil
+}
+
+// Recover continues a durable run from its last checkpoint, typically
+// after the process running it stopped. Like Run, it drives the
+// workflow as a top-level run; ctx must carry the invocation ID of the
+// run, so that the recovered run keeps checkpointing under the same
+// key.
+//
+// Completed nodes are not re-run: their checkpointed outputs feed
+// their successors. Nodes which were running or pending are scheduled
+// again with their checkpointed input, unless their
+// NodeConfig.RerunOnResume is &false, in which case Recover yields
+// ErrNodeNotRerunnable. Nodes waiting for human input stay waiting.
+func (w *Workflow) Recover(ctx agent.InvocationContext, cp *Checkpoint) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		if cp == nil || cp.Status != RunInProgress || cp.State == nil {
+			yield(nil, ErrNothingToRecover)
+			return
+		}
+		state, err := w.migrate(cp.Version, cp.State)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		if state == nil {
+			yield(nil, ErrNothingToRecover)
+			return
+		}
+		s, _, cancel := w.newScheduler(w.runContext(ctx))
+		defer cancel()
+		s.state = state
+		if s.state.Nodes == nil {
+			s.state.Nodes = map[string]*NodeState{}
+		}
+
+		// Reschedule in a stable order so that recovery with a
+		// concurrency cap is deterministic.
+		var interrupted []string
+		for _, name := range slices.Sorted(maps.Keys(s.state.Nodes)) {
+			ns := s.state.Nodes[name]
+			if ns.Status != NodeRunning && ns.Status != NodePending {
+				continue
+			}
+			node := s.nodesByName[name]
+			if node == nil {
+				yield(nil, fmt.Errorf("workflow %q: checkpointed node %q is not in the graph", w.name, name))
+				return
+			}
+			if r := node.Config().RerunOnResume; r != nil && !*r {
+				ns.Status = NodeFailed
+				yield(nil, fmt.Errorf("%w: node %q", ErrNodeNotRerunnable, name))
+				return
+			}
+			interrupted = append(interrupted, name)
+		}
+		for _, name := range interrupted {
+			ns := s.state.Nodes[name]
+			s.scheduleResumedNode(s.nodesByName[name], ns.Input, ns.TriggeredBy, ns.Branch, ns.ResumedInputs)
+		}
+
+		s.run(yield)
+		s.wg.Wait()
+	}
+}
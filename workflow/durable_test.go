@@ -0,0 +1,260 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"errors"
+	"maps"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/session"
+)
+
+// checkpointOf applies the state delta of ev to state, as a session
+// would, and returns the checkpoint ev saves, or nil.
+func checkpointOf(t *testing.T, state mapState, ev *session.Event) *Checkpoint {
+	t.Helper()
+	if ev == nil {
+		return nil
+	}
+	maps.Copy(state, ev.Actions.StateDelta)
+	if _, ok := ev.Actions.StateDelta[RunStateSessionKey("wf", "test-invocation-id")]; !ok {
+		return nil
+	}
+	cp, err := LoadCheckpoint(state, "wf", "test-invocation-id")
+	if err != nil {
+		t.Fatalf("LoadCheckpoint: %v", err)
+	}
+	return cp
+}
+
+// newDurableTestWorkflow returns the durable workflow "wf" running
+// Start -> a -> b -> c, with counters of the runs of each node.
+func newDurableTestWorkflow(t *testing.T, bConfig NodeConfig) (*Workflow, map[string]*atomic.Int32) {
+	t.Helper()
+	calls := map[string]*atomic.Int32{"a": {}, "b": {}, "c": {}}
+	node := func(name string, cfg NodeConfig) Node {
+		return NewFunctionNode(name, func(ctx agent.Context, input any) (string, error) {
+			calls[name].Add(1)
+			return name, nil
+		}, cfg)
+	}
+	w, err := New("wf", Chain(Start, node("a", defaultNodeConfig), node("b", bConfig), node("c", defaultNodeConfig)), WithDurableExecution())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return w, calls
+}
+
+func TestDurableRunCheckpoints(t *testing.T) {
+	w, _ := newDurableTestWorkflow(t, defaultNodeConfig)
+
+	var checkpoints []*Checkpoint
+	state := mapState{}
+	for ev, err := range w.Run(newSeededMockCtx(t)) {
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		if cp := checkpointOf(t, state, ev); cp != nil {
+			checkpoints = append(checkpoints, cp)
+		}
+	}
+
+	// One when the run starts, one per completion of START, a, b and
+	// c, and the final one.
+	if got, want := len(checkpoints), 6; got != want {
+		t.Fatalf("got %d checkpoints, want %d", got, want)
+	}
+	for _, cp := range checkpoints[:5] {
+		if cp.Status != RunInProgress {
+			t.Errorf("intermediate checkpoint status = %q, want %q", cp.Status, RunInProgress)
+		}
+		if cp.Workflow != "wf" || cp.InvocationID != "test-invocation-id" {
+			t.Errorf("checkpoint of %q/%q, want wf/test-invocation-id", cp.Workflow, cp.InvocationID)
+		}
+	}
+	afterA := checkpoints[2].State.Nodes
+	if got := afterA["a"]; got == nil || got.Status != NodeCompleted || got.Output != "a" {
+		t.Errorf("checkpoint after a: node a = %+v, want completed with output %q", got, "a")
+	}
+	if got := afterA["b"]; got == nil || got.Status != NodeRunning || got.Input != "a" {
+		t.Errorf("checkpoint after a: node b = %+v, want running with input %q", got, "a")
+	}
+	last := checkpoints[len(checkpoints)-1]
+	if last.Status != RunCompleted || last.State != nil {
+		t.Errorf("final checkpoint = %+v, want completed without state", last)
+	}
+}
+
+// partsLeft returns the keys of the checkpoint parts saved in state.
+func partsLeft(state mapState) []string {
+	var keys []string
+	for key, value := range state {
+		if strings.HasPrefix(key, checkpointPartKeyPrefix) && value != nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func TestDurableRunCheckpoints_SavesChanges(t *testing.T) {
+	w, _ := newDurableTestWorkflow(t, defaultNodeConfig)
+
+	nodeA := checkpointPartsKeyPrefix("wf", "test-invocation-id") + nodePart + "a"
+	saves := 0
+	state := mapState{}
+	for ev, err := range w.Run(newSeededMockCtx(t)) {
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		checkpointOf(t, state, ev)
+		if v, ok := ev.Actions.StateDelta[nodeA]; ok && v != nil {
+			saves++
+		}
+	}
+
+	// Once running, once completed.
+	if saves != 2 {
+		t.Errorf("node a saved %d times, want 2", saves)
+	}
+	if left := partsLeft(state); len(left) > 0 {
+		t.Errorf("completed run left checkpoint parts %v", left)
+	}
+}
+
+func TestDurableRunCheckpoints_CompensatedFailure(t *testing.T) {
+	var log compensationLog
+	boom := errors.New("boom")
+	w, err := New("wf", Chain(Start, log.node("a", nil), erringNode("b", boom)), WithDurableExecution())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	var last *Checkpoint
+	state := mapState{}
+	for ev, err := range w.Run(newSeededMockCtx(t)) {
+		if err != nil && !errors.Is(err, boom) {
+			t.Fatalf("Run: %v", err)
+		}
+		if cp := checkpointOf(t, state, ev); cp != nil {
+			last = cp
+		}
+	}
+
+	if last == nil || last.Status != RunFailed || last.State != nil {
+		t.Errorf("final checkpoint = %+v, want failed without state", last)
+	}
+	if left := partsLeft(state); len(left) > 0 {
+		t.Errorf("compensated run left checkpoint parts %v", left)
+	}
+}
+
+func TestNew_DurableRequiresName(t *testing.T) {
+	if _, err := New("", Chain(Start, newTestNode("a")), WithDurableExecution()); err == nil {
+		t.Error("New() of an anonymous durable workflow succeeded, want error")
+	}
+}
+
+// interruptAfter runs w and stops consuming its events, as a crashed
+// process would, once node has completed. It returns the last
+// checkpoint saved.
+func interruptAfter(t *testing.T, w *Workflow, node string) *Checkpoint {
+	t.Helper()
+	var last *Checkpoint
+	state := mapState{}
+	for ev, err := range w.Run(newSeededMockCtx(t)) {
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		if cp := checkpointOf(t, state, ev); cp != nil {
+			last = cp
+			if ns := cp.State.Nodes[node]; ns != nil && ns.Status == NodeCompleted {
+				break
+			}
+		}
+	}
+	if last == nil || last.Status != RunInProgress {
+		t.Fatalf("last checkpoint = %+v, want one in progress", last)
+	}
+	return last
+}
+
+func TestRecover_SkipsCompletedNodes(t *testing.T) {
+	w, calls := newDurableTestWorkflow(t, defaultNodeConfig)
+	cp := interruptAfter(t, w, "a")
+
+	var outputs []any
+	var final *Checkpoint
+	state := mapState{}
+	for ev, err := range w.Recover(newMockCtx(t), cp) {
+		if err != nil {
+			t.Fatalf("Recover: %v", err)
+		}
+		if ev.Output != nil {
+			outputs = append(outputs, ev.Output)
+		}
+		if c := checkpointOf(t, state, ev); c != nil {
+			final = c
+		}
+	}
+
+	if got := calls["a"].Load(); got != 1 {
+		t.Errorf("node a ran %d times, want 1", got)
+	}
+	if got := calls["c"].Load(); got != 1 {
+		t.Errorf("node c ran %d times, want 1", got)
+	}
+	if len(outputs) == 0 || outputs[len(outputs)-1] != "c" {
+		t.Errorf("outputs = %v, want to end with %q", outputs, "c")
+	}
+	if final == nil || final.Status != RunCompleted {
+		t.Errorf("final checkpoint = %+v, want completed", final)
+	}
+}
+
+func TestRecover_NodeNotRerunnable(t *testing.T) {
+	rerun := false
+	w, calls := newDurableTestWorkflow(t, NodeConfig{RerunOnResume: &rerun})
+	cp := interruptAfter(t, w, "a")
+
+	var gotErr error
+	for _, err := range w.Recover(newMockCtx(t), cp) {
+		if err != nil {
+			gotErr = err
+		}
+	}
+	if !errors.Is(gotErr, ErrNodeNotRerunnable) {
+		t.Errorf("Recover() error = %v, want %v", gotErr, ErrNodeNotRerunnable)
+	}
+	if got := calls["c"].Load(); got != 0 {
+		t.Errorf("node c ran %d times, want 0", got)
+	}
+}
+
+func TestRecover_NothingToRecover(t *testing.T) {
+	w, _ := newDurableTestWorkflow(t, defaultNodeConfig)
+	for _, cp := range []*Checkpoint{nil, {Workflow: "wf", Status: RunCompleted}} {
+		var gotErr error
+		for _, err := range w.Recover(newMockCtx(t), cp) {
+			gotErr = err
+		}
+		if !errors.Is(gotErr, ErrNothingToRecover) {
+			t.Errorf("Recover(%+v) error = %v, want %v", cp, gotErr, ErrNothingToRecover)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"google.golang.org/adk/v2/session"
+)
+
+// statusColors are the fill colors of nodes by status in exported
+// graphs. Inactive nodes are not filled.
+var statusColors = map[NodeStatus]string{
+	NodePending:   "#FDE293",
+	NodeRunning:   "#8AB4F8",
+	NodeCompleted: "#69CB87",
+	NodeWaiting:   "#FCAD70",
+	NodeFailed:    "#F28B82",
+	NodeCancelled: "#BDC1C6",
+}
+
+type exportNodeKind int
+
+const (
+	exportStart exportNodeKind = iota
+	exportTask
+	exportJoin
+	exportParallel
+	exportWorkflow
+)
+
+// exportNode is a node of the format-independent view of a workflow
+// graph rendered by DOT and Mermaid.
+type exportNode struct {
+	// id is the node path within the root workflow: the node name,
+	// prefixed with the names of enclosing WorkflowNodes and "/".
+	id          string
+	label       string
+	description string
+	kind        exportNodeKind
+	status      NodeStatus
+	// sub is the nested graph of a WorkflowNode.
+	sub *exportGraph
+}
+
+type exportEdge struct {
+	from, to, label string
+}
+
+type exportGraph struct {
+	nodes []*exportNode
+	edges []exportEdge
+	// exits are the ids of the nodes whose completion completes the
+	// graph, used as the sources of edges leaving a nested workflow.
+	exits []string
+}
+
+// newExportGraph builds the view of g. Edges into a WorkflowNode
+// target the Start node of its nested graph, and edges out of it
+// leave from the nested terminal nodes.
+func newExportGraph(g *graph, prefix string, statuses map[string]NodeStatus) *exportGraph {
+	eg := &exportGraph{}
+	entries := map[Node]string{}
+	exits := map[Node][]string{}
+	for _, n := range g.orderedNodes() {
+		id := prefix + n.Name()
+		en := &exportNode{id: id, label: n.Name(), description: n.Description(), kind: exportTask, status: statuses[id]}
+		entries[n], exits[n] = id, []string{id}
+		switch n := n.(type) {
+		case *startNode:
+			en.kind = exportStart
+		case *JoinNode:
+			en.kind = exportJoin
+		case *ParallelWorker:
+			en.kind = exportParallel
+			en.label = n.Name() + "\n× " + n.wrapped.Name()
+		case *MapNode:
+			en.kind = exportParallel
+			en.label = n.Name() + "\n× " + n.mapper.Name()
+		case *WorkflowNode:
+			en.kind = exportWorkflow
+			en.sub = newExportGraph(n.subWorkflow.graph, id+"/", statuses)
+			entries[n] = id + "/" + Start.Name()
+			exits[n] = en.sub.exits
+		}
+		eg.nodes = append(eg.nodes, en)
+	}
+	for _, edge := range g.edges {
+		for _, from := range exits[edge.From] {
+			eg.edges = append(eg.edges, exportEdge{from: from, to: entries[edge.To], label: routeLabel(edge.Route)})
+		}
+	}
+	for _, n := range g.orderedNodes() {
+		if len(g.successorsOf(n)) == 0 {
+			eg.exits = append(eg.exits, exits[n]...)
+		}
+	}
+	if len(eg.exits) == 0 {
+		// Every node has successors, e.g. a graph which is a single
+		// loop: edges leave from the Start node.
+		eg.exits = []string{prefix + Start.Name()}
+	}
+	return eg
+}
+
+// routeLabel returns the label of an edge with the given route.
+func routeLabel(route Route) string {
+	switch r := route.(type) {
+	case nil:
+		return ""
+	case *defaultRoute:
+		return "default"
+	case StringRoute:
+		return string(r)
+	case ExprRoute:
+		return string(r)
+	case IntRoute, BoolRoute:
+		return fmt.Sprint(r)
+	case interface{ values() []string }:
+		return strings.Join(r.values(), ", ")
+	case fmt.Stringer:
+		return r.String()
+	default:
+		return fmt.Sprintf("%T", r)
+	}
+}
+
+// DOT renders the workflow graph in the Graphviz DOT language.
+//
+// Nodes are labelled with their names; JoinNodes are drawn as
+// diamonds, ParallelWorkers and MapNodes as 3D boxes labelled with
+// the wrapped node, and WorkflowNodes as clusters holding the nested
+// graph. Edges with routes are labelled with the route values, or
+// "default" for the Default route.
+//
+// statuses, keyed by node path as returned by NodeStatuses, overlays
+// the status of each node as its fill color and a second label line.
+// It may be nil.
+func (w *Workflow) DOT(statuses map[string]NodeStatus) string {
+	eg := newExportGraph(w.graph, "", statuses)
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(cmp.Or(w.name, "workflow")))
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=\"#ffffff\"];\n")
+	writeDOTGraph(&b, eg, "  ")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func writeDOTGraph(b *strings.Builder, eg *exportGraph, indent string) {
+	for _, n := range eg.nodes {
+		if n.kind == exportWorkflow {
+			fmt.Fprintf(b, "%ssubgraph %s {\n", indent, dotQuote("cluster_"+n.id))
+			fmt.Fprintf(b, "%s  label=%s;\n", indent, dotQuote(statusLabel(n.label, n.status, " ")))
+			b.WriteString(indent + "  style=rounded;\n")
+			if color, ok := statusColors[n.status]; ok {
+				fmt.Fprintf(b, "%s  color=%s;\n", indent, dotQuote(color))
+			}
+			writeDOTGraph(b, n.sub, indent+"  ")
+			b.WriteString(indent + "}\n")
+			continue
+		}
+		attrs := []string{"label=" + dotQuote(statusLabel(n.label, n.status, "\n"))}
+		switch n.kind {
+		case exportStart:
+			attrs = append(attrs, "shape=circle")
+		case exportJoin:
+			attrs = append(attrs, "shape=diamond")
+		case exportParallel:
+			attrs = append(attrs, "shape=box3d")
+		}
+		if color, ok := statusColors[n.status]; ok {
+			attrs = append(attrs, "fillcolor="+dotQuote(color))
+		}
+		if n.description != "" {
+			attrs = append(attrs, "tooltip="+dotQuote(n.description))
+		}
+		fmt.Fprintf(b, "%s%s [%s];\n", indent, dotQuote(n.id), strings.Join(attrs, ", "))
+	}
+	for _, e := range eg.edges {
+		fmt.Fprintf(b, "%s%s -> %s", indent, dotQuote(e.from), dotQuote(e.to))
+		if e.label != "" {
+			fmt.Fprintf(b, " [label=%s]", dotQuote(e.label))
+		}
+		b.WriteString(";\n")
+	}
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+// Mermaid renders the workflow graph as a Mermaid flowchart, with the
+// same shapes, labels and status overlay as DOT.
+func (w *Workflow) Mermaid(statuses map[string]NodeStatus) string {
+	eg := newExportGraph(w.graph, "", statuses)
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	// Mermaid ids are restricted to simple identifiers, so nodes are
+	// numbered in order of appearance.
+	ids := map[string]string{}
+	byStatus := map[NodeStatus][]string{}
+	writeMermaidGraph(&b, eg, "  ", ids, byStatus)
+	for _, status := range slices.Sorted(maps.Keys(byStatus)) {
+		color := statusColors[status]
+		fmt.Fprintf(&b, "  classDef %s fill:%s\n", status, color)
+		fmt.Fprintf(&b, "  class %s %s\n", strings.Join(byStatus[status], ","), status)
+	}
+	return b.String()
+}
+
+func writeMermaidGraph(b *strings.Builder, eg *exportGraph, indent string, ids map[string]string, byStatus map[NodeStatus][]string) {
+	mermaidID := func(id string) string {
+		if mid, ok := ids[id]; ok {
+			return mid
+		}
+		mid := fmt.Sprintf("n%d", len(ids))
+		ids[id] = mid
+		return mid
+	}
+	for _, n := range eg.nodes {
+		id := mermaidID(n.id)
+		if _, ok := statusColors[n.status]; ok {
+			byStatus[n.status] = append(byStatus[n.status], id)
+		}
+		label := mermaidQuote(statusLabel(n.label, n.status, "\n"))
+		switch n.kind {
+		case exportWorkflow:
+			fmt.Fprintf(b, "%ssubgraph %s [%s]\n", indent, id, mermaidQuote(statusLabel(n.label, n.status, " ")))
+			b.WriteString(indent + "  direction LR\n")
+			writeMermaidGraph(b, n.sub, indent+"  ", ids, byStatus)
+			b.WriteString(indent + "end\n")
+		case exportStart:
+			fmt.Fprintf(b, "%s%s((%s))\n", indent, id, label)
+		case exportJoin:
+			fmt.Fprintf(b, "%s%s{%s}\n", indent, id, label)
+		case exportParallel:
+			fmt.Fprintf(b, "%s%s[[%s]]\n", indent, id, label)
+		default:
+			fmt.Fprintf(b, "%s%s(%s)\n", indent, id, label)
+		}
+	}
+	for _, e := range eg.edges {
+		if e.label != "" {
+			fmt.Fprintf(b, "%s%s -->|%s| %s\n", indent, mermaidID(e.from), mermaidQuote(e.label), mermaidID(e.to))
+		} else {
+			fmt.Fprintf(b, "%s%s --> %s\n", indent, mermaidID(e.from), mermaidID(e.to))
+		}
+	}
+}
+
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;", "\n", "<br/>")
+
+func mermaidQuote(s string) string {
+	return `"` + mermaidEscaper.Replace(s) + `"`
+}
+
+// statusLabel appends the status to a node label, unless the node is
+// inactive.
+func statusLabel(label string, status NodeStatus, sep string) string {
+	if status == NodeInactive {
+		return label
+	}
+	return label + sep + "(" + status.String() + ")"
+}
+
+// NodeStatuses returns the status of the workflow's nodes in the given
+// invocation, reconstructed from the session history, for overlaying
+// on DOT and Mermaid graphs. Nodes of nested workflows are keyed by
+// their path, e.g. "review/approve" for node "approve" of WorkflowNode
+// "review". Empty invocationID considers all invocations.
+//
+// Nodes with interrupt history have the status inferred by
+// ReconstructRunState, e.g. NodeWaiting for unanswered input
+// requests. Other nodes which emitted events are reported as
+// NodeCompleted, and nodes absent from the result didn't run.
+func (w *Workflow) NodeStatuses(sess session.Session, invocationID string) (map[string]NodeStatus, error) {
+	statuses := map[string]NodeStatus{}
+	if sess == nil {
+		return statuses, nil
+	}
+	if err := w.collectNodeStatuses(sess, invocationID, "", statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+func (w *Workflow) collectNodeStatuses(sess session.Session, invocationID, prefix string, statuses map[string]NodeStatus) error {
+	nodesByName := buildNodesByName(w.graph)
+	_, ran := collectNodeOutputs(sess.Events(), nodesByName, invocationID)
+	for name := range ran {
+		statuses[prefix+name] = NodeCompleted
+	}
+	if len(ran) > 0 {
+		statuses[prefix+Start.Name()] = NodeCompleted
+	}
+	state, err := w.ReconstructRunState(sess, invocationID)
+	if err != nil {
+		return fmt.Errorf("failed to reconstruct state of workflow %q: %w", w.name, err)
+	}
+	if state != nil {
+		for name, ns := range state.Nodes {
+			statuses[prefix+name] = ns.Status
+		}
+	}
+	for name, n := range nodesByName {
+		if wn, ok := n.(*WorkflowNode); ok {
+			if err := wn.subWorkflow.collectNodeStatuses(sess, invocationID, prefix+name+"/", statuses); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func newExportTestWorkflow(t *testing.T) *Workflow {
+	t.Helper()
+	classify := newDummyNode("classify")
+	a, b, fallback := newDummyNode("a"), newDummyNode("b"), newDummyNode("fallback")
+	x, y := newDummyNode("x"), newDummyNode("y")
+	join := NewJoinNode("join")
+	pw, err := NewParallelWorker("pw", newDummyNode("item"), 0, NodeConfig{})
+	if err != nil {
+		t.Fatalf("NewParallelWorker() error = %v", err)
+	}
+	sub, err := NewWorkflowNode("sub", Chain(Start, newDummyNode("inner1"), newDummyNode("inner2")))
+	if err != nil {
+		t.Fatalf("NewWorkflowNode() error = %v", err)
+	}
+	edges := Concat(
+		Edge{From: Start, To: classify},
+		Edge{From: classify, To: a, Route: StringRoute("a")},
+		Edge{From: classify, To: b, Route: MultiRoute[string]{"b", "c"}},
+		Edge{From: classify, To: fallback, Route: Default},
+		NewEdgeBuilder().AddFanOut(fallback, x, y).AddFanIn(join, x, y).Build(),
+		Edge{From: a, To: pw},
+		Edge{From: b, To: sub},
+		Edge{From: sub, To: newDummyNode("done")},
+	)
+	wf, err := New("export", edges)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return wf
+}
+
+func TestWorkflowDOT(t *testing.T) {
+	wf := newExportTestWorkflow(t)
+
+	got := wf.DOT(map[string]NodeStatus{"classify": NodeCompleted, "sub": NodeWaiting, "sub/inner1": NodeWaiting})
+
+	want := `digraph "export" {
+  rankdir=LR;
+  node [shape=box, style="rounded,filled", fillcolor="#ffffff"];
+  "START" [label="START", shape=circle, tooltip="Start node"];
+  "classify" [label="classify\n(completed)", fillcolor="#69CB87"];
+  "a" [label="a"];
+  "b" [label="b"];
+  "fallback" [label="fallback"];
+  "x" [label="x"];
+  "y" [label="y"];
+  "join" [label="join", shape=diamond];
+  "pw" [label="pw\n× item", shape=box3d];
+  subgraph "cluster_sub" {
+    label="sub (waiting)";
+    style=rounded;
+    color="#FCAD70";
+    "sub/START" [label="START", shape=circle, tooltip="Start node"];
+    "sub/inner1" [label="inner1\n(waiting)", fillcolor="#FCAD70"];
+    "sub/inner2" [label="inner2"];
+    "sub/START" -> "sub/inner1";
+    "sub/inner1" -> "sub/inner2";
+  }
+  "done" [label="done"];
+  "START" -> "classify";
+  "classify" -> "a" [label="a"];
+  "classify" -> "b" [label="b, c"];
+  "classify" -> "fallback" [label="default"];
+  "fallback" -> "x";
+  "fallback" -> "y";
+  "x" -> "join";
+  "y" -> "join";
+  "a" -> "pw";
+  "b" -> "sub/START";
+  "sub/inner2" -> "done";
+}
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("DOT() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWorkflowMermaid(t *testing.T) {
+	wf := newExportTestWorkflow(t)
+
+	got := wf.Mermaid(map[string]NodeStatus{"classify": NodeCompleted, "a": NodeCompleted, "sub/inner1": NodeWaiting})
+
+	want := `flowchart LR
+  n0(("START"))
+  n1("classify<br/>(completed)")
+  n2("a<br/>(completed)")
+  n3("b")
+  n4("fallback")
+  n5("x")
+  n6("y")
+  n7{"join"}
+  n8[["pw<br/>× item"]]
+  subgraph n9 ["sub"]
+    direction LR
+    n10(("START"))
+    n11("inner1<br/>(waiting)")
+    n12("inner2")
+    n10 --> n11
+    n11 --> n12
+  end
+  n13("done")
+  n0 --> n1
+  n1 -->|"a"| n2
+  n1 -->|"b, c"| n3
+  n1 -->|"default"| n4
+  n4 --> n5
+  n4 --> n6
+  n5 --> n7
+  n6 --> n7
+  n2 --> n8
+  n3 --> n10
+  n12 --> n13
+  classDef completed fill:#69CB87
+  class n1,n2 completed
+  classDef waiting fill:#FCAD70
+  class n11 waiting
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Mermaid() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRouteLabel(t *testing.T) {
+	tests := []struct {
+		route Route
+		want  string
+	}{
+		{route: nil, want: ""},
+		{route: Default, want: "default"},
+		{route: StringRoute("yes"), want: "yes"},
+		{route: IntRoute(3), want: "3"},
+		{route: BoolRoute(false), want: "false"},
+		{route: MultiRoute[int]{1, 2}, want: "1, 2"},
+	}
+	for _, tt := range tests {
+		if got := routeLabel(tt.route); got != tt.want {
+			t.Errorf("routeLabel(%#v) = %q, want %q", tt.route, got, tt.want)
+		}
+	}
+}
+
+func TestWorkflowNodeStatuses(t *testing.T) {
+	ask := newDummyNode("ask")
+	inner := newDummyNode("inner")
+	sub, err := NewWorkflowNode("sub", []Edge{{From: Start, To: inner}})
+	if err != nil {
+		t.Fatalf("NewWorkflowNode() error = %v", err)
+	}
+	wf, err := New("statuses", Chain(Start, newDummyNode("first"), sub, ask, newDummyNode("last")))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	sess := fakeSession{events: sliceEvents{
+		{Author: "first", InvocationID: "inv1", Output: "out"},
+		{Author: "inner", InvocationID: "inv1", Output: "out"},
+		{Author: "sub", InvocationID: "inv1", Output: "out"},
+		{Author: "ask", InvocationID: "inv1", LongRunningToolIDs: []string{"iid"}},
+		{Author: "first", InvocationID: "inv0", Output: "old"},
+		{Author: "last", InvocationID: "inv0", Output: "old"},
+	}}
+
+	got, err := wf.NodeStatuses(sess, "inv1")
+	if err != nil {
+		t.Fatalf("NodeStatuses() error = %v", err)
+	}
+
+	want := map[string]NodeStatus{
+		"START":     NodeCompleted,
+		"first":     NodeCompleted,
+		"sub":       NodeCompleted,
+		"sub/START": NodeCompleted,
+		"sub/inner": NodeCompleted,
+		"ask":       NodeWaiting,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("NodeStatuses() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNodeStatusString(t *testing.T) {
+	if got := NodeWaiting.String(); got != "waiting" {
+		t.Errorf("NodeWaiting.String() = %q, want %q", got, "waiting")
+	}
+	if got := NodeStatus(42).String(); got != "NodeStatus(42)" {
+		t.Errorf("NodeStatus(42).String() = %q, want %q", got, "NodeStatus(42)")
+	}
+}
@@ -0,0 +1,998 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/session"
+)
+
+// ErrInvalidExpression is returned by New for an ExprRoute whose
+// expression does not compile.
+var ErrInvalidExpression = errors.New("invalid route expression")
+
+// ExprRoute is a route defined by a boolean expression over the
+// completed node's output, the session state and the routing event,
+// e.g.
+//
+//	workflow.ExprRoute(`output.score > 0.8 && state.tier == "gold"`)
+//
+// The expression language is a small subset of CEL over JSON values:
+//
+//   - the variables output, state (a map of the session state) and
+//     event (a map with author, branch, routes, text and output);
+//     values are compared in their JSON form, so a struct output
+//     exposes its JSON fields and numbers are float64;
+//   - literals: numbers, 'single' or "double" quoted strings, true,
+//     false, null and lists [a, b];
+//   - field selection x.f and indexing x[i], x["key"]; selecting a
+//     missing key yields null;
+//   - the operators ! - * / % + == != < <= > >= in && || and c ? a : b,
+//     with CEL's precedence; + also concatenates strings and lists;
+//   - the functions size(x) and has(x.f), and the string methods
+//     contains, startsWith, endsWith, matches (RE2) and size.
+//
+// New rejects an ExprRoute which does not compile with
+// ErrInvalidExpression. An expression failing at run time, e.g. by
+// selecting a field of null, does not match: guard optional fields
+// with has.
+type ExprRoute string
+
+// Matches evaluates the expression against event alone, with output
+// bound to the event's output and an empty state.
+func (r ExprRoute) Matches(event *session.Event) bool {
+	var output any
+	if event != nil {
+		output = event.Output
+	}
+	return r.MatchesContext(RouteContext{Output: output, Event: event})
+}
+
+// MatchesContext implements ContextualRoute.
+func (r ExprRoute) MatchesContext(rc RouteContext) bool {
+	c, err := r.compile()
+	if err != nil {
+		return false
+	}
+	ok, err := c.evalBool(&exprEnv{rc: rc})
+	return err == nil && ok
+}
+
+// compiledExprs caches compiled expressions by source.
+var compiledExprs sync.Map // string -> *compiledExpr
+
+func (r ExprRoute) compile() (*compiledExpr, error) {
+	if c, ok := compiledExprs.Load(string(r)); ok {
+		return c.(*compiledExpr), nil
+	}
+	c, err := compileExpr(string(r))
+	if err != nil {
+		return nil, err
+	}
+	compiledExprs.Store(string(r), c)
+	return c, nil
+}
+
+// Variables an expression may refer to.
+const (
+	exprVarOutput = "output"
+	exprVarState  = "state"
+	exprVarEvent  = "event"
+)
+
+// compiledExpr is a parsed and checked expression.
+type compiledExpr struct {
+	src  string
+	root exprNode
+}
+
+// compileExpr parses and checks src.
+func compileExpr(src string) (*compiledExpr, error) {
+	p := &exprParser{src: src}
+	if err := p.tokenize(); err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalidExpression, src, err)
+	}
+	root, err := p.parseExpr()
+	if err == nil && p.peek().kind != tokEOF {
+		err = p.errorf("unexpected %s", p.peek())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalidExpression, src, err)
+	}
+	if t := staticType(root); t != "" && t != "bool" {
+		return nil, fmt.Errorf("%w %q: yields %s, want bool", ErrInvalidExpression, src, t)
+	}
+	return &compiledExpr{src: src, root: root}, nil
+}
+
+// evalBool evaluates the expression in env, which must yield a bool.
+func (c *compiledExpr) evalBool(env *exprEnv) (bool, error) {
+	v, err := c.root.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q yields %s, want bool", c.src, typeName(v))
+	}
+	return b, nil
+}
+
+// staticType returns the type name x always yields, or "" when it
+// depends on the variables.
+func staticType(x exprNode) string {
+	switch x := x.(type) {
+	case *litExpr:
+		return typeName(x.v)
+	case *listExpr:
+		return "list"
+	case *hasExpr:
+		return "bool"
+	case *unaryExpr:
+		if x.op == "!" {
+			return "bool"
+		}
+		return "number"
+	case *binaryExpr:
+		switch x.op {
+		case "+":
+			return staticType(x.x)
+		case "-", "*", "/", "%":
+			return "number"
+		}
+		return "bool"
+	case *callExpr:
+		if x.name == "size" {
+			return "number"
+		}
+		return "bool"
+	}
+	return ""
+}
+
+// exprEnv binds the variables of an expression to a RouteContext. The
+// state is materialized on first use.
+type exprEnv struct {
+	rc    RouteContext
+	state map[string]any
+}
+
+func (e *exprEnv) lookup(name string) (any, error) {
+	switch name {
+	case exprVarOutput:
+		return jsonValue(e.rc.Output)
+	case exprVarState:
+		if e.state == nil {
+			e.state = map[string]any{}
+			if e.rc.State != nil {
+				for k, v := range e.rc.State.All() {
+					jv, err := jsonValue(v)
+					if err != nil {
+						return nil, fmt.Errorf("state key %q: %w", k, err)
+					}
+					e.state[k] = jv
+				}
+			}
+		}
+		return e.state, nil
+	case exprVarEvent:
+		ev := map[string]any{"author": "", "branch": "", "routes": []any{}, "text": "", "output": nil}
+		if e.rc.Event != nil {
+			ev["author"] = e.rc.Event.Author
+			ev["branch"] = e.rc.Event.Branch
+			routes := make([]any, len(e.rc.Event.Routes))
+			for i, r := range e.rc.Event.Routes {
+				routes[i] = r
+			}
+			ev["routes"] = routes
+			if e.rc.Event.Content != nil {
+				ev["text"], _ = modelText(e.rc.Event.Content)
+			}
+			out, err := jsonValue(e.rc.Event.Output)
+			if err != nil {
+				return nil, err
+			}
+			ev["output"] = out
+		}
+		return ev, nil
+	}
+	return nil, fmt.Errorf("undefined variable %q", name)
+}
+
+// jsonValue converts v to its JSON form: nil, bool, float64, string,
+// []any or map[string]any.
+func jsonValue(v any) (any, error) {
+	switch v := v.(type) {
+	case nil, bool, string, float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case float32:
+		return float64(v), nil
+	case *genai.Content:
+		text, _ := modelText(v)
+		return text, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "map"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// Tokens.
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokKind
+	text string // operator or identifier text, string value
+	num  float64
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// exprOps lists the operators, longest first.
+var exprOps = []string{"||", "&&", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "*", "/", "%", "(", ")", "[", "]", ".", ",", "?", ":"}
+
+type exprParser struct {
+	src    string
+	tokens []token
+	pos    int
+}
+
+func (p *exprParser) tokenize() error {
+	s := p.src
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r >= '0' && r <= '9':
+			j := i
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.' ||
+				s[j] == 'e' || s[j] == 'E' ||
+				(s[j] == '+' || s[j] == '-') && (s[j-1] == 'e' || s[j-1] == 'E')) {
+				j++
+			}
+			n, err := strconv.ParseFloat(s[i:j], 64)
+			if err != nil {
+				return fmt.Errorf("invalid number %q at offset %d", s[i:j], i)
+			}
+			p.tokens = append(p.tokens, token{kind: tokNumber, text: s[i:j], num: n, pos: i})
+			i = j
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(s) && s[j] != byte(r) {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				return fmt.Errorf("unterminated string at offset %d", i)
+			}
+			raw := s[i+1 : j]
+			if r == '\'' {
+				raw = strings.ReplaceAll(strings.ReplaceAll(raw, `\'`, `'`), `"`, `\"`)
+			}
+			v, err := strconv.Unquote(`"` + raw + `"`)
+			if err != nil {
+				return fmt.Errorf("invalid string at offset %d: %w", i, err)
+			}
+			p.tokens = append(p.tokens, token{kind: tokString, text: v, pos: i})
+			i = j + 1
+		case r == '_' || unicode.IsLetter(r):
+			j := i
+			for j < len(s) {
+				r, size := utf8.DecodeRuneInString(s[j:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				j += size
+			}
+			p.tokens = append(p.tokens, token{kind: tokIdent, text: s[i:j], pos: i})
+			i = j
+		default:
+			op := ""
+			for _, o := range exprOps {
+				if strings.HasPrefix(s[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return fmt.Errorf("unexpected character %q at offset %d", r, i)
+			}
+			p.tokens = append(p.tokens, token{kind: tokOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	p.tokens = append(p.tokens, token{kind: tokEOF, pos: len(s)})
+	return nil
+}
+
+func (p *exprParser) peek() token { return p.tokens[p.pos] }
+
+func (p *exprParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is the operator or keyword op.
+func (p *exprParser) accept(op string) bool {
+	t := p.peek()
+	if (t.kind == tokOp || t.kind == tokIdent) && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) expect(op string) error {
+	if !p.accept(op) {
+		return p.errorf("expected %q, found %s", op, p.peek())
+	}
+	return nil
+}
+
+func (p *exprParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("offset %d: %s", p.peek().pos, fmt.Sprintf(format, args...))
+}
+
+// Grammar, by increasing precedence:
+//
+//	expr     = or [ "?" expr ":" expr ]
+//	or       = and { "||" and }
+//	and      = relation { "&&" relation }
+//	relation = sum [ ("==" | "!=" | "<" | "<=" | ">" | ">=" | "in") sum ]
+//	sum      = product { ("+" | "-") product }
+//	product  = unary { ("*" | "/" | "%") unary }
+//	unary    = ("!" | "-") unary | member
+//	member   = primary { "." ident [ "(" args ")" ] | "[" expr "]" }
+//	primary  = number | string | "true" | "false" | "null" | ident
+//	         | ident "(" args ")" | "(" expr ")" | "[" args "]"
+
+func (p *exprParser) parseExpr() (exprNode, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil || !p.accept("?") {
+		return cond, err
+	}
+	then, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	els, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return &condExpr{cond: cond, then: then, els: els}, nil
+}
+
+// binaryLevels lists the binary operators by increasing precedence.
+var binaryLevels = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">=", "in"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *exprParser) parseBinary(level int) (exprNode, error) {
+	if level == len(binaryLevels) {
+		return p.parseUnary()
+	}
+	x, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := ""
+		for _, o := range binaryLevels[level] {
+			if p.accept(o) {
+				op = o
+				break
+			}
+		}
+		if op == "" {
+			return x, nil
+		}
+		y, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = &binaryExpr{op: op, x: x, y: y}
+		// Relations do not chain: a < b < c is an error.
+		if level == 2 {
+			return x, nil
+		}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	for _, op := range []string{"!", "-"} {
+		if p.accept(op) {
+			x, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			return &unaryExpr{op: op, x: x}, nil
+		}
+	}
+	return p.parseMember()
+}
+
+func (p *exprParser) parseMember() (exprNode, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("."):
+			t := p.next()
+			if t.kind != tokIdent {
+				return nil, p.errorf("expected field name after \".\"")
+			}
+			if !p.accept("(") {
+				x = &selectExpr{x: x, field: t.text}
+				continue
+			}
+			args, err := p.parseArgs(")")
+			if err != nil {
+				return nil, err
+			}
+			if x, err = newMethodCall(x, t.text, args); err != nil {
+				return nil, p.errorf("%v", err)
+			}
+		case p.accept("["):
+			index, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			x = &indexExpr{x: x, index: index}
+		default:
+			return x, nil
+		}
+	}
+}
+
+func (p *exprParser) parseArgs(closing string) ([]exprNode, error) {
+	var args []exprNode
+	if p.accept(closing) {
+		return nil, nil
+	}
+	for {
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if p.accept(closing) {
+			return args, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return &litExpr{v: t.num}, nil
+	case tokString:
+		return &litExpr{v: t.text}, nil
+	case tokIdent:
+		switch t.text {
+		case "true", "false":
+			return &litExpr{v: t.text == "true"}, nil
+		case "null":
+			return &litExpr{v: nil}, nil
+		case exprVarOutput, exprVarState, exprVarEvent:
+			return &identExpr{name: t.text}, nil
+		}
+		if !p.accept("(") {
+			return nil, fmt.Errorf("offset %d: undefined variable %q; use output, state or event", t.pos, t.text)
+		}
+		args, err := p.parseArgs(")")
+		if err != nil {
+			return nil, err
+		}
+		call, err := newFuncCall(t.text, args)
+		if err != nil {
+			return nil, fmt.Errorf("offset %d: %w", t.pos, err)
+		}
+		return call, nil
+	case tokOp:
+		switch t.text {
+		case "(":
+			x, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return x, p.expect(")")
+		case "[":
+			elems, err := p.parseArgs("]")
+			if err != nil {
+				return nil, err
+			}
+			return &listExpr{elems: elems}, nil
+		}
+	}
+	return nil, fmt.Errorf("offset %d: unexpected %s", t.pos, t)
+}
+
+// AST.
+
+type exprNode interface {
+	eval(env *exprEnv) (any, error)
+}
+
+type litExpr struct{ v any }
+
+func (e *litExpr) eval(*exprEnv) (any, error) { return e.v, nil }
+
+type identExpr struct{ name string }
+
+func (e *identExpr) eval(env *exprEnv) (any, error) { return env.lookup(e.name) }
+
+type listExpr struct{ elems []exprNode }
+
+func (e *listExpr) eval(env *exprEnv) (any, error) {
+	out := make([]any, len(e.elems))
+	for i, x := range e.elems {
+		v, err := x.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// selectExpr is x.field. A missing key selects null.
+type selectExpr struct {
+	x     exprNode
+	field string
+}
+
+func (e *selectExpr) eval(env *exprEnv) (any, error) {
+	x, err := e.x.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := x.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("cannot select field %q of %s", e.field, typeName(x))
+	}
+	return m[e.field], nil
+}
+
+// indexExpr is x[index], on a list or a map. A missing map key
+// selects null; an index out of range is an error.
+type indexExpr struct{ x, index exprNode }
+
+func (e *indexExpr) eval(env *exprEnv) (any, error) {
+	x, err := e.x.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	index, err := e.index.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch x := x.(type) {
+	case map[string]any:
+		key, ok := index.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot index map with %s", typeName(index))
+		}
+		return x[key], nil
+	case []any:
+		n, ok := index.(float64)
+		if !ok || n != math.Trunc(n) {
+			return nil, fmt.Errorf("cannot index list with %v", index)
+		}
+		if n < 0 || int(n) >= len(x) {
+			return nil, fmt.Errorf("index %v out of range [0, %d)", n, len(x))
+		}
+		return x[int(n)], nil
+	}
+	return nil, fmt.Errorf("cannot index %s", typeName(x))
+}
+
+type condExpr struct{ cond, then, els exprNode }
+
+func (e *condExpr) eval(env *exprEnv) (any, error) {
+	c, err := evalBoolOperand(e.cond, env, "?")
+	if err != nil {
+		return nil, err
+	}
+	if c {
+		return e.then.eval(env)
+	}
+	return e.els.eval(env)
+}
+
+type unaryExpr struct {
+	op string
+	x  exprNode
+}
+
+func (e *unaryExpr) eval(env *exprEnv) (any, error) {
+	if e.op == "!" {
+		b, err := evalBoolOperand(e.x, env, "!")
+		return !b, err
+	}
+	x, err := e.x.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	n, ok := x.(float64)
+	if !ok {
+		return nil, fmt.Errorf("cannot negate %s", typeName(x))
+	}
+	return -n, nil
+}
+
+func evalBoolOperand(x exprNode, env *exprEnv, op string) (bool, error) {
+	v, err := x.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("operand of %q is %s, want bool", op, typeName(v))
+	}
+	return b, nil
+}
+
+type binaryExpr struct {
+	op   string
+	x, y exprNode
+}
+
+func (e *binaryExpr) eval(env *exprEnv) (any, error) {
+	if e.op == "&&" || e.op == "||" {
+		x, err := evalBoolOperand(e.x, env, e.op)
+		if err != nil {
+			return nil, err
+		}
+		if x == (e.op == "||") {
+			return x, nil
+		}
+		return evalBoolOperand(e.y, env, e.op)
+	}
+	x, err := e.x.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	y, err := e.y.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "==":
+		return reflect.DeepEqual(x, y), nil
+	case "!=":
+		return !reflect.DeepEqual(x, y), nil
+	case "in":
+		return evalIn(x, y)
+	case "<", "<=", ">", ">=":
+		return evalCompare(e.op, x, y)
+	}
+	return evalArith(e.op, x, y)
+}
+
+func evalIn(x, y any) (any, error) {
+	switch y := y.(type) {
+	case []any:
+		for _, v := range y {
+			if reflect.DeepEqual(x, v) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]any:
+		key, ok := x.(string)
+		if !ok {
+			return nil, fmt.Errorf("map keys are strings, not %s", typeName(x))
+		}
+		_, found := y[key]
+		return found, nil
+	case string:
+		sub, ok := x.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot look for %s in a string", typeName(x))
+		}
+		return strings.Contains(y, sub), nil
+	}
+	return nil, fmt.Errorf("cannot look for a value in %s", typeName(y))
+}
+
+func evalCompare(op string, x, y any) (any, error) {
+	var c int
+	switch x := x.(type) {
+	case float64:
+		yn, ok := y.(float64)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare number with %s", typeName(y))
+		}
+		switch {
+		case x < yn:
+			c = -1
+		case x > yn:
+			c = 1
+		}
+	case string:
+		ys, ok := y.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare string with %s", typeName(y))
+		}
+		c = strings.Compare(x, ys)
+	default:
+		return nil, fmt.Errorf("cannot compare %s", typeName(x))
+	}
+	switch op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	}
+	return c >= 0, nil
+}
+
+func evalArith(op string, x, y any) (any, error) {
+	if op == "+" {
+		switch x := x.(type) {
+		case string:
+			if ys, ok := y.(string); ok {
+				return x + ys, nil
+			}
+		case []any:
+			if yl, ok := y.([]any); ok {
+				return append(append([]any{}, x...), yl...), nil
+			}
+		}
+	}
+	xn, xok := x.(float64)
+	yn, yok := y.(float64)
+	if !xok || !yok {
+		return nil, fmt.Errorf("invalid operands of %q: %s and %s", op, typeName(x), typeName(y))
+	}
+	switch op {
+	case "+":
+		return xn + yn, nil
+	case "-":
+		return xn - yn, nil
+	case "*":
+		return xn * yn, nil
+	}
+	if yn == 0 {
+		return nil, errors.New("division by zero")
+	}
+	if op == "/" {
+		return xn / yn, nil
+	}
+	return math.Mod(xn, yn), nil
+}
+
+// Functions.
+
+// callExpr is a call of a function or of a method on recv.
+type callExpr struct {
+	name string
+	recv exprNode // nil for a function
+	args []exprNode
+	re   *regexp.Regexp
+}
+
+// exprMethods maps the methods on strings to their arity.
+var exprMethods = map[string]int{"contains": 1, "startsWith": 1, "endsWith": 1, "matches": 1, "size": 0}
+
+func newMethodCall(recv exprNode, name string, args []exprNode) (exprNode, error) {
+	arity, ok := exprMethods[name]
+	if !ok {
+		return nil, fmt.Errorf("undefined method %q", name)
+	}
+	if len(args) != arity {
+		return nil, fmt.Errorf("method %q takes %d arguments, got %d", name, arity, len(args))
+	}
+	if name == "size" {
+		return &callExpr{name: name, args: []exprNode{recv}}, nil
+	}
+	call := &callExpr{name: name, recv: recv, args: args}
+	if lit, ok := args[0].(*litExpr); ok && name == "matches" {
+		pattern, ok := lit.v.(string)
+		if !ok {
+			return nil, fmt.Errorf("matches takes a string pattern")
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		call.re = re
+	}
+	return call, nil
+}
+
+func newFuncCall(name string, args []exprNode) (exprNode, error) {
+	switch name {
+	case "size":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("size takes 1 argument, got %d", len(args))
+		}
+		return &callExpr{name: name, args: args}, nil
+	case "has":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("has takes 1 argument, got %d", len(args))
+		}
+		switch args[0].(type) {
+		case *selectExpr, *indexExpr:
+			return &hasExpr{x: args[0]}, nil
+		}
+		return nil, fmt.Errorf("has takes a field selection, e.g. has(output.field)")
+	}
+	return nil, fmt.Errorf("undefined function %q", name)
+}
+
+func (e *callExpr) eval(env *exprEnv) (any, error) {
+	args := make([]any, len(e.args))
+	for i, a := range e.args {
+		v, err := a.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	if e.name == "size" {
+		switch v := args[0].(type) {
+		case string:
+			return float64(utf8.RuneCountInString(v)), nil
+		case []any:
+			return float64(len(v)), nil
+		case map[string]any:
+			return float64(len(v)), nil
+		}
+		return nil, fmt.Errorf("size of %s", typeName(args[0]))
+	}
+
+	recv, err := e.recv.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := recv.(string)
+	if !ok {
+		return nil, fmt.Errorf("method %q on %s, want string", e.name, typeName(recv))
+	}
+	arg, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("method %q takes a string, got %s", e.name, typeName(args[0]))
+	}
+	switch e.name {
+	case "contains":
+		return strings.Contains(s, arg), nil
+	case "startsWith":
+		return strings.HasPrefix(s, arg), nil
+	case "endsWith":
+		return strings.HasSuffix(s, arg), nil
+	}
+	re := e.re
+	if re == nil {
+		if re, err = regexp.Compile(arg); err != nil {
+			return nil, err
+		}
+	}
+	return re.MatchString(s), nil
+}
+
+// hasExpr is has(x.field) or has(x[key]): whether the map x has the
+// key.
+type hasExpr struct{ x exprNode }
+
+func (e *hasExpr) eval(env *exprEnv) (any, error) {
+	var (
+		mapExpr exprNode
+		key     any
+	)
+	switch x := e.x.(type) {
+	case *selectExpr:
+		mapExpr, key = x.x, x.field
+	case *indexExpr:
+		mapExpr = x.x
+		k, err := x.index.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		key = k
+	}
+	m, err := mapExpr.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return false, nil
+	}
+	return evalIn(key, m)
+}
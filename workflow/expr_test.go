@@ -0,0 +1,157 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"errors"
+	"iter"
+	"maps"
+	"testing"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/session"
+)
+
+// mapState is a session.State backed by a map.
+type mapState map[string]any
+
+func (s mapState) Get(key string) (any, error) {
+	v, ok := s[key]
+	if !ok {
+		return nil, session.ErrStateKeyNotExist
+	}
+	return v, nil
+}
+
+func (s mapState) Set(key string, v any) error {
+	s[key] = v
+	return nil
+}
+
+func (s mapState) All() iter.Seq2[string, any] { return maps.All(s) }
+
+// statefulSession is a mockSession with state.
+type statefulSession struct {
+	mockSession
+	state mapState
+}
+
+func (s *statefulSession) State() session.State { return s.state }
+
+func TestExprRoute_Eval(t *testing.T) {
+	type result struct {
+		Score float64  `json:"score"`
+		Tags  []string `json:"tags"`
+	}
+	rc := RouteContext{
+		Output: result{Score: 0.9, Tags: []string{"urgent", "billing"}},
+		State:  mapState{"tier": "gold", "attempts": 2, "limits": map[string]int{"max": 3}},
+		Event:  &session.Event{Author: "classify", Routes: []string{"escalate"}},
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`output.score > 0.8`, true},
+		{`output.score > 0.8 && state.tier == "gold"`, true},
+		{`output.score <= 0.8 || state.tier != 'gold'`, false},
+		{`"urgent" in output.tags`, true},
+		{`output.tags[1] == "billing"`, true},
+		{`size(output.tags) == 2 && output.tags.size() == 2`, true},
+		{`state.attempts < state.limits.max`, true},
+		{`state.attempts + 1 == 3 && state.attempts * 2 % 3 == 1`, true},
+		{`-state.attempts == 0 - 2`, true},
+		{`!(state.tier == "silver")`, true},
+		{`state.tier.startsWith("go") && state.tier.endsWith("ld") && state.tier.contains("ol")`, true},
+		{`state.tier.matches("^g.*d$")`, true},
+		{`state.tier + "!" == "gold!"`, true},
+		{`has(state.tier) && !has(state.missing)`, true},
+		{`state.missing == null`, true},
+		{`state.missing.field == 1`, false}, // selecting a field of null fails
+		{`"escalate" in event.routes && event.author == "classify"`, true},
+		{`"tier" in state`, true},
+		{`state.attempts > 1 ? output.score > 0.5 : false`, true},
+		{`[1, 2] + [3] == [1, 2, 3]`, true},
+		{`state.attempts / 0 == 1`, false},
+		{`state.tier`, false}, // not a bool
+	}
+	for _, tt := range tests {
+		if got := ExprRoute(tt.expr).MatchesContext(rc); got != tt.want {
+			t.Errorf("ExprRoute(%s).MatchesContext() = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestExprRoute_Matches(t *testing.T) {
+	ev := &session.Event{Routes: []string{"a"}}
+	ev.Output = map[string]any{"ok": true}
+	if !ExprRoute(`output.ok && "a" in event.routes`).Matches(ev) {
+		t.Error("Matches() = false, want true")
+	}
+	if ExprRoute(`has(state.x)`).Matches(nil) {
+		t.Error("Matches(nil) = true, want false")
+	}
+}
+
+func TestNew_InvalidRouteExpression(t *testing.T) {
+	for _, expr := range []string{
+		`output.score >`,
+		`score > 1`,
+		`output.score > 1 > 0`,
+		`output.x.lower() == "a"`,
+		`len(output) > 1`,
+		`output.x.matches("(")`,
+		`"unterminated`,
+		`1 + 2`,
+	} {
+		a := newTestNode("a")
+		_, err := New("", []Edge{
+			{From: Start, To: a},
+			{From: a, To: newTestNode("b"), Route: ExprRoute(expr)},
+		})
+		if !errors.Is(err, ErrInvalidExpression) {
+			t.Errorf("New() with route %s error = %v, want %v", expr, err, ErrInvalidExpression)
+		}
+	}
+}
+
+func TestExprRoute_RoutesOnOutputAndState(t *testing.T) {
+	score := NewFunctionNode("score", func(ctx agent.Context, input any) (map[string]any, error) {
+		return map[string]any{"score": 0.7}, nil
+	}, defaultNodeConfig)
+	high, low, fallback := newStubNode("high", "high"), newStubNode("low", "low"), newStubNode("fallback", "fallback")
+	edges := []Edge{
+		{From: Start, To: score},
+		{From: score, To: high, Route: ExprRoute(`output.score >= state.threshold`)},
+		{From: score, To: low, Route: ExprRoute(`output.score < state.threshold`)},
+		{From: score, To: fallback, Route: Default},
+	}
+
+	for _, tt := range []struct {
+		state mapState
+		want  string
+	}{
+		{mapState{"threshold": 0.5}, "high"},
+		{mapState{"threshold": 0.9}, "low"},
+		{mapState{}, "fallback"},
+	} {
+		ctx := newSeededMockCtx(t)
+		ctx.sess = &statefulSession{state: tt.state}
+		events := drain(t, mustNew(t, edges).Run(ctx))
+		if got := events[len(events)-1].Output; got != tt.want {
+			t.Errorf("with state %v, last output = %v, want %q", tt.state, got, tt.want)
+		}
+	}
+}
@@ -14,10 +14,15 @@
 
 package workflow
 
+import "slices"
+
 // graph is the precomputed structural view of a workflow's edges.
 // Built once at workflow construction; queried by the engine at
 // dispatch time.
 type graph struct {
+	// edges holds the edges in declaration order, so that views of
+	// the graph (e.g. exported diagrams) are deterministic.
+	edges         []Edge
 	successors    map[Node][]Edge
 	predecessors  map[Node][]Edge
 	isRootWrapper bool
@@ -34,16 +39,33 @@ func newGraph(edges []Edge) *graph {
 		succ[edge.From] = append(succ[edge.From], edge)
 		pred[edge.To] = append(pred[edge.To], edge)
 	}
-	return &graph{successors: succ, predecessors: pred}
+	return &graph{edges: slices.Clone(edges), successors: succ, predecessors: pred}
 }
 
-// allEdges returns all edges in the graph.
+// allEdges returns all edges in the graph in declaration order.
 func (g *graph) allEdges() []Edge {
-	var edges []Edge
-	for _, succs := range g.successors {
-		edges = append(edges, succs...)
+	return slices.Clone(g.edges)
+}
+
+// orderedNodes returns all nodes in the graph in order of their first
+// appearance in the edges, with the Start sentinel first if present.
+func (g *graph) orderedNodes() []Node {
+	seen := make(map[Node]bool)
+	var nodes []Node
+	add := func(n Node) {
+		if !seen[n] {
+			seen[n] = true
+			nodes = append(nodes, n)
+		}
+	}
+	if _, ok := g.successors[Start]; ok {
+		add(Start)
+	}
+	for _, edge := range g.edges {
+		add(edge.From)
+		add(edge.To)
 	}
-	return edges
+	return nodes
 }
 
 // successorsOf returns the outgoing edges for a node.
@@ -0,0 +1,337 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"google.golang.org/adk/v2/session"
+)
+
+// RunCancelled means the run was cancelled with CancelEvent; it is
+// neither resumed nor recovered.
+const RunCancelled RunStatus = "cancelled"
+
+// RunCancelledKey is the Event.CustomMetadata key marking the event
+// which cancelled a run. Its value is the name of the workflow.
+const RunCancelledKey = "workflow_run_cancelled"
+
+// NodeFailedKey is the Event.CustomMetadata key marking the event
+// which records the failure of a node, authored by the workflow. Its
+// value is the name of the node; the event's ErrorMessage is the
+// node's error.
+const NodeFailedKey = "workflow_node_failed"
+
+// ErrNodeNotFailed is returned by RetryCheckpoint for a node which did
+// not fail.
+var ErrNodeNotFailed = errors.New("workflow: node did not fail")
+
+// RunSnapshot is the state of a workflow run as recorded in its
+// session, as returned by InspectRun.
+type RunSnapshot struct {
+	// InvocationID is the invocation of the run.
+	InvocationID string
+	// Status is the status of the run: the checkpointed status for a
+	// durable workflow, RunCancelled for a cancelled run, RunFailed
+	// for a run with a failed node, RunPaused for a run with open
+	// interrupts, and empty otherwise, since the session doesn't tell
+	// a finished run from one still running without a checkpoint.
+	Status RunStatus
+	// Nodes is the state of the nodes which ran, keyed by node name.
+	// Without a checkpoint, only the status, output and interrupts of
+	// nodes are known.
+	Nodes map[string]*NodeState
+	// Retryable reports whether the failed nodes of the run can be
+	// run again with RetryCheckpoint. Only failed runs of durable
+	// workflows which weren't compensated are: other runs have no
+	// checkpointed input to run the nodes from.
+	Retryable bool
+	// Interrupts are the interrupts the run waits on, ordered by
+	// node name.
+	Interrupts []*OpenInterrupt
+}
+
+// Interrupt returns the open interrupt with the given ID, or nil.
+func (s *RunSnapshot) Interrupt(id string) *OpenInterrupt {
+	for _, i := range s.Interrupts {
+		if i.Request.InterruptID == id {
+			return i
+		}
+	}
+	return nil
+}
+
+// OpenInterrupt is a request for input a run waits on.
+type OpenInterrupt struct {
+	// Node is the name of the node which raised the interrupt.
+	Node string
+	// Request is the request, as emitted by the node. For interrupts
+	// raised by long-running tools, only its InterruptID is set.
+	Request *session.RequestInput
+}
+
+// Validate validates payload, an answer to the interrupt, against the
+// request's ResponseSchema and returns it coerced to the schema. It
+// returns an error wrapping ErrInvalidResumeResponse if the payload
+// doesn't conform.
+func (i *OpenInterrupt) Validate(payload any) (any, error) {
+	v, err := validateResumeResponse(payload, i.Request.ResponseSchema)
+	if err != nil {
+		return nil, fmt.Errorf("%w: interrupt %q: %w", ErrInvalidResumeResponse, i.Request.InterruptID, err)
+	}
+	return v, nil
+}
+
+// InspectRun returns the state of the run of the given invocation as
+// recorded in sess, merging the durable checkpoint of the run, if any,
+// with the state reconstructed from the session's events: the nodes
+// which ran and their outputs, and the interrupts the run waits on.
+func (w *Workflow) InspectRun(sess session.Session, invocationID string) (*RunSnapshot, error) {
+	snap := &RunSnapshot{InvocationID: invocationID, Nodes: map[string]*NodeState{}}
+	if sess == nil {
+		return snap, nil
+	}
+	nodesByName := buildNodesByName(w.graph)
+	events := sess.Events()
+
+	outputs, ran := collectNodeOutputs(events, nodesByName, invocationID)
+	for name := range ran {
+		ns := &NodeState{Status: NodeCompleted}
+		if out, ok := outputs[name]; ok {
+			ns.Output = out
+		}
+		snap.Nodes[name] = ns
+	}
+	retryable := false
+	if w.durable && sess.State() != nil {
+		cp, err := LoadCheckpoint(sess.State(), w.name, invocationID)
+		if err != nil {
+			return nil, err
+		}
+		if cp != nil {
+			snap.Status = cp.Status
+			if cp.State != nil {
+				retryable = true
+				for name, ns := range cp.State.Nodes {
+					snap.Nodes[name] = ns
+				}
+			}
+		}
+	}
+	if w.runCancelled(events, invocationID) {
+		snap.Status = RunCancelled
+		return snap, nil
+	}
+	snap.Retryable = retryable && snap.Status == RunFailed
+
+	state, err := w.ReconstructRunState(sess, invocationID)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		state = NewRunState()
+	}
+	for _, name := range slices.Sorted(maps.Keys(state.Nodes)) {
+		ns := state.Nodes[name]
+		snap.Nodes[name] = ns
+		for _, id := range ns.Interrupts {
+			req := requestFromEvents(events, invocationID, id)
+			if req.ResponseSchema == nil {
+				req.ResponseSchema = ns.interruptSchemas[id]
+			}
+			snap.Interrupts = append(snap.Interrupts, &OpenInterrupt{Node: name, Request: req})
+		}
+	}
+	// Without a checkpoint, the failures recorded in the events tell
+	// which nodes failed the run.
+	if snap.Status == "" {
+		for _, name := range w.failedNodes(events, invocationID) {
+			ns := snap.Nodes[name]
+			if ns == nil {
+				ns = &NodeState{}
+				snap.Nodes[name] = ns
+			}
+			ns.Status = NodeFailed
+			snap.Status = RunFailed
+		}
+	}
+	if len(snap.Interrupts) > 0 && (snap.Status == "" || snap.Status == RunPaused) {
+		snap.Status = RunPaused
+	}
+	return snap, nil
+}
+
+// failedNodes returns the names of the nodes of the run of the given
+// invocation whose failure was recorded by recordFailures.
+func (w *Workflow) failedNodes(events session.Events, invocationID string) []string {
+	var names []string
+	for i := 0; i < events.Len(); i++ {
+		ev := events.At(i)
+		if ev == nil || (invocationID != "" && ev.InvocationID != invocationID) || ev.Author != w.name {
+			continue
+		}
+		if name, ok := ev.CustomMetadata[NodeFailedKey].(string); ok && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// nodeFailure is the error a node failed the run with.
+type nodeFailure struct {
+	node string
+	err  error
+}
+
+// recordFailures yields an event recording each node failure of the
+// run, so that the failure can be told from the session even without
+// a checkpoint. It reports false if the consumer stopped the
+// iteration.
+func (s *scheduler) recordFailures(yield func(*session.Event, error) bool) bool {
+	if s.name == "" {
+		return true
+	}
+	for _, f := range s.failures {
+		ev := session.NewEvent(s.parentCtx, s.parentCtx.InvocationID())
+		ev.Author = s.name
+		ev.Branch = s.parentCtx.Branch()
+		ev.ErrorMessage = f.err.Error()
+		ev.CustomMetadata = map[string]any{NodeFailedKey: f.node}
+		if !yield(ev, nil) {
+			return false
+		}
+	}
+	return true
+}
+
+// requestFromEvents returns the request of interrupt id: the
+// RequestedInput of the event which raised it, or, for events recorded
+// without one, the request rebuilt from the arguments of its
+// WorkflowInputFunctionCallName FunctionCall.
+func requestFromEvents(events session.Events, invocationID, id string) *session.RequestInput {
+	for i := 0; i < events.Len(); i++ {
+		ev := events.At(i)
+		if ev == nil || (invocationID != "" && ev.InvocationID != invocationID) {
+			continue
+		}
+		if ev.RequestedInput != nil && ev.RequestedInput.InterruptID == id {
+			req := *ev.RequestedInput
+			return &req
+		}
+		if ev.Content == nil {
+			continue
+		}
+		for _, p := range ev.Content.Parts {
+			if p == nil || p.FunctionCall == nil || p.FunctionCall.ID != id || p.FunctionCall.Name != WorkflowInputFunctionCallName {
+				continue
+			}
+			req := &session.RequestInput{InterruptID: id, Payload: p.FunctionCall.Args["payload"]}
+			req.Message, _ = p.FunctionCall.Args["message"].(string)
+			req.ResponseSchema = schemaFromEvent(ev, id)
+			return req
+		}
+	}
+	return &session.RequestInput{InterruptID: id}
+}
+
+// CancelEvent returns the event cancelling the run of the given
+// invocation, to be appended to sess: once it is, the run's interrupts
+// can no longer be answered, and, for a durable workflow, its
+// checkpoint is saved with status RunCancelled so that it isn't
+// recovered. Stopping a run still executing is up to its caller, e.g.
+// by cancelling the context of the run.
+func (w *Workflow) CancelEvent(ctx context.Context, sess session.Session, invocationID string) (*session.Event, error) {
+	ev := session.NewEvent(ctx, invocationID)
+	ev.Author = w.name
+	ev.CustomMetadata = map[string]any{RunCancelledKey: w.name}
+	if !w.durable || sess == nil || sess.State() == nil {
+		return ev, nil
+	}
+	cp, err := LoadCheckpoint(sess.State(), w.name, invocationID)
+	if err != nil || cp == nil {
+		return ev, err
+	}
+	cp.Status = RunCancelled
+	cp.UpdatedAt = time.Now()
+	delta, err := cp.StateDelta()
+	if err != nil {
+		return nil, err
+	}
+	ev.Actions.StateDelta = delta
+	return ev, nil
+}
+
+// runCancelled reports whether the run of the given invocation was
+// cancelled by an event from CancelEvent.
+func (w *Workflow) runCancelled(events session.Events, invocationID string) bool {
+	if invocationID == "" {
+		return false
+	}
+	for i := 0; i < events.Len(); i++ {
+		ev := events.At(i)
+		if ev == nil || ev.InvocationID != invocationID {
+			continue
+		}
+		if name, ok := ev.CustomMetadata[RunCancelledKey]; ok && name == w.name {
+			return true
+		}
+	}
+	return false
+}
+
+// StateDelta returns the session state delta saving cp, for an event
+// which updates the checkpoint of a run, e.g. one from RetryCheckpoint.
+func (cp *Checkpoint) StateDelta() (map[string]any, error) {
+	return checkpointDelta(cp, map[string]string{})
+}
+
+// RetryCheckpoint returns a copy of cp, the checkpoint of a failed run,
+// in which the failed node is pending again with its attempts reset and
+// the run is in progress, so that Recover runs the node again from its
+// checkpointed input. Nodes cancelled when the node failed are pending
+// again too. It returns ErrNodeNotFailed if the node did not fail, and
+// ErrNothingToRecover if the run did not fail.
+func RetryCheckpoint(cp *Checkpoint, node string) (*Checkpoint, error) {
+	if cp == nil || cp.Status != RunFailed || cp.State == nil {
+		return nil, ErrNothingToRecover
+	}
+	value, err := encodeCheckpoint(cp)
+	if err != nil {
+		return nil, err
+	}
+	retried, err := decodeCheckpoint(value)
+	if err != nil {
+		return nil, err
+	}
+	ns := retried.State.Nodes[node]
+	if ns == nil || ns.Status != NodeFailed {
+		return nil, fmt.Errorf("%w: %q", ErrNodeNotFailed, node)
+	}
+	ns.Status = NodePending
+	ns.Attempt = 0
+	for _, other := range retried.State.Nodes {
+		if other.Status == NodeCancelled {
+			other.Status = NodePending
+		}
+	}
+	retried.Status = RunInProgress
+	retried.UpdatedAt = time.Now()
+	return retried, nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"google.golang.org/adk/v2/session"
+)
+
+// approvalSession returns a session holding a run of invocation "inv"
+// in which node a completed and node ask waits on interrupt "iid".
+func approvalSession() fakeSession {
+	done := &session.Event{InvocationID: "inv", NodeInfo: &session.NodeInfo{Path: "a"}}
+	done.Output = "drafted"
+	return fakeSession{events: sliceEvents{done, {
+		InvocationID:       "inv",
+		LongRunningToolIDs: []string{"iid"},
+		NodeInfo:           &session.NodeInfo{Path: "ask"},
+		RequestedInput: &session.RequestInput{
+			InterruptID:    "iid",
+			Message:        "Approve?",
+			ResponseSchema: &jsonschema.Schema{Type: "boolean"},
+		},
+	}}}
+}
+
+func newApprovalWorkflow(t *testing.T) *Workflow {
+	t.Helper()
+	w, err := New("wf", Chain(Start, newDummyNode("a"), newDummyNode("ask")))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return w
+}
+
+func TestInspectRun_Paused(t *testing.T) {
+	w := newApprovalWorkflow(t)
+	snap, err := w.InspectRun(approvalSession(), "inv")
+	if err != nil {
+		t.Fatalf("InspectRun: %v", err)
+	}
+	if snap.Status != RunPaused {
+		t.Errorf("Status = %q, want %q", snap.Status, RunPaused)
+	}
+	if ns := snap.Nodes["a"]; ns == nil || ns.Status != NodeCompleted || ns.Output != "drafted" {
+		t.Errorf("node a = %+v, want completed with output %q", ns, "drafted")
+	}
+	if ns := snap.Nodes["ask"]; ns == nil || ns.Status != NodeWaiting {
+		t.Errorf("node ask = %+v, want waiting", ns)
+	}
+
+	in := snap.Interrupt("iid")
+	if in == nil || in.Node != "ask" || in.Request.Message != "Approve?" {
+		t.Fatalf("Interrupt(iid) = %+v, want the request of node ask", in)
+	}
+	if got, err := in.Validate(true); err != nil || got != true {
+		t.Errorf("Validate(true) = %v, %v, want true", got, err)
+	}
+	if _, err := in.Validate("yes"); !errors.Is(err, ErrInvalidResumeResponse) {
+		t.Errorf("Validate(%q) error = %v, want %v", "yes", err, ErrInvalidResumeResponse)
+	}
+	if snap.Interrupt("other") != nil {
+		t.Error("Interrupt(other) != nil, want nil")
+	}
+}
+
+func TestCancelEvent_CancelsPausedRun(t *testing.T) {
+	w := newApprovalWorkflow(t)
+	sess := approvalSession()
+	ev, err := w.CancelEvent(t.Context(), sess, "inv")
+	if err != nil {
+		t.Fatalf("CancelEvent: %v", err)
+	}
+	sess.events = append(sess.events.(sliceEvents), ev)
+
+	snap, err := w.InspectRun(sess, "inv")
+	if err != nil {
+		t.Fatalf("InspectRun: %v", err)
+	}
+	if snap.Status != RunCancelled || len(snap.Interrupts) != 0 {
+		t.Errorf("InspectRun() = status %q, interrupts %v, want cancelled without interrupts", snap.Status, snap.Interrupts)
+	}
+	if state, err := w.ReconstructRunState(sess, "inv"); err != nil || state != nil {
+		t.Errorf("ReconstructRunState() = %v, %v, want nothing to resume", state, err)
+	}
+}
+
+func TestRetryCheckpoint(t *testing.T) {
+	cp := &Checkpoint{Workflow: "wf", InvocationID: "inv", Status: RunFailed, State: &RunState{Nodes: map[string]*NodeState{
+		"a": {Status: NodeCompleted, Output: "a"},
+		"b": {Status: NodeFailed, Input: "a", Attempt: 3},
+		"c": {Status: NodeCancelled, Input: "a"},
+	}}}
+
+	retried, err := RetryCheckpoint(cp, "b")
+	if err != nil {
+		t.Fatalf("RetryCheckpoint: %v", err)
+	}
+	if retried.Status != RunInProgress {
+		t.Errorf("Status = %q, want %q", retried.Status, RunInProgress)
+	}
+	for name, want := range map[string]NodeStatus{"a": NodeCompleted, "b": NodePending, "c": NodePending} {
+		if got := retried.State.Nodes[name].Status; got != want {
+			t.Errorf("node %s status = %v, want %v", name, got, want)
+		}
+	}
+	if ns := retried.State.Nodes["b"]; ns.Attempt != 0 || ns.Input != "a" {
+		t.Errorf("node b = %+v, want attempts reset and input kept", ns)
+	}
+	if cp.Status != RunFailed || cp.State.Nodes["b"].Status != NodeFailed {
+		t.Error("RetryCheckpoint modified its argument")
+	}
+
+	if _, err := RetryCheckpoint(cp, "a"); !errors.Is(err, ErrNodeNotFailed) {
+		t.Errorf("RetryCheckpoint(a) error = %v, want %v", err, ErrNodeNotFailed)
+	}
+	if _, err := RetryCheckpoint(retried, "b"); !errors.Is(err, ErrNothingToRecover) {
+		t.Errorf("RetryCheckpoint() of a run in progress error = %v, want %v", err, ErrNothingToRecover)
+	}
+}
+
+func TestInspectRun_FailedRunWithoutCheckpoint(t *testing.T) {
+	boom := errors.New("boom")
+	w, err := New("wf", Chain(Start, newDummyNode("a"), newErroringNode("b", boom)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	var events sliceEvents
+	var runErr error
+	for ev, err := range w.Run(newSeededMockCtx(t)) {
+		if err != nil {
+			runErr = err
+			continue
+		}
+		events = append(events, ev)
+	}
+	if !errors.Is(runErr, boom) {
+		t.Fatalf("Run() error = %v, want %v", runErr, boom)
+	}
+	if len(events) == 0 {
+		t.Fatal("Run() yielded no events")
+	}
+	last := events[len(events)-1]
+	if last.CustomMetadata[NodeFailedKey] != "b" || last.ErrorMessage != boom.Error() {
+		t.Errorf("last event = %+v, want the failure of node b", last)
+	}
+
+	snap, err := w.InspectRun(fakeSession{events: events}, last.InvocationID)
+	if err != nil {
+		t.Fatalf("InspectRun: %v", err)
+	}
+	if snap.Status != RunFailed || snap.Retryable {
+		t.Errorf("InspectRun() = status %q, retryable %v, want failed and not retryable", snap.Status, snap.Retryable)
+	}
+	if ns := snap.Nodes["b"]; ns == nil || ns.Status != NodeFailed {
+		t.Errorf("node b = %+v, want failed", ns)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"fmt"
+	"iter"
+	"time"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/loop"
+	"google.golang.org/adk/v2/session"
+)
+
+// ContinueRoute and ExitRoute are the routes of the output of a
+// LoopNode.
+const (
+	ContinueRoute StringRoute = "continue"
+	ExitRoute     StringRoute = "exit"
+)
+
+// LoopNode ends each iteration of a cycle of a workflow graph: on every
+// activation it checks its loop.Conditions and routes its input, as
+// its output, with ExitRoute once one holds and with ContinueRoute
+// otherwise. Place it on the cycle with a ContinueRoute edge back to
+// the start of the loop and an ExitRoute edge out of it.
+//
+// An iteration is made of the events of the run since the node's
+// previous activation, or since the start of the run for the first
+// one. The state keys the first iteration changed are not known, since
+// the state at the start of the run isn't recorded: all of them count
+// as changed. The fingerprint of the state after each iteration is
+// kept in temporary session state for the next one, so the first
+// iteration after the run resumes in another invocation counts all
+// keys as changed too. The loop.Summary of each iteration, including the reason the
+// loop stopped on the last one, is recorded under loop.SummaryKey in
+// the metadata of the node's output events.
+type LoopNode struct {
+	BaseNode
+	conditions []loop.Condition
+}
+
+// NewLoopNode returns a LoopNode stopping the loop on the first of
+// conditions which holds. At least one condition is required.
+func NewLoopNode(name string, conditions []loop.Condition, cfg NodeConfig) (*LoopNode, error) {
+	if len(conditions) == 0 {
+		return nil, fmt.Errorf("loop node %s: no termination condition", name)
+	}
+	return &LoopNode{BaseNode: NewBaseNode(name, "", cfg), conditions: conditions}, nil
+}
+
+// Run checks the conditions after the iteration which activated the
+// node.
+func (n *LoopNode) Run(ctx agent.Context, input any) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		prev, start, events := n.iteration(ctx)
+		state := ctx.ReadonlyState()
+		var before map[string]string
+		if prev != nil {
+			before = n.fingerprint(state)
+		}
+		after := loop.Fingerprint(state, checkpointKeyPrefix, checkpointPartKeyPrefix)
+		it := loop.NewIteration(prev, start, events, before, after, state)
+		stop, err := loop.Check(ctx, it, n.conditions)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		ev := session.NewEvent(ctx, ctx.InvocationID())
+		ev.Author = n.Name()
+		ev.Output = input
+		ev.CustomMetadata = map[string]any{loop.SummaryKey: it.Summary(stop)}
+		ev.Actions.StateDelta[n.fingerprintKey()] = after
+		ev.Routes = []string{string(ContinueRoute)}
+		if stop != nil {
+			ev.Routes = []string{string(ExitRoute)}
+		}
+		yield(ev, nil)
+	}
+}
+
+// loopFingerprintKeyPrefix prefixes the temporary session.State keys
+// holding the fingerprint of the state after the last iteration of a
+// LoopNode.
+const loopFingerprintKeyPrefix = session.KeyPrefixTemp + "workflow_loop_fingerprint:"
+
+func (n *LoopNode) fingerprintKey() string {
+	return loopFingerprintKeyPrefix + n.Name()
+}
+
+// fingerprint returns the fingerprint of the state after the node's
+// previous iteration, or nil if it isn't in state.
+func (n *LoopNode) fingerprint(state session.ReadonlyState) map[string]string {
+	if state == nil {
+		return nil
+	}
+	v, err := state.Get(n.fingerprintKey())
+	if err != nil {
+		return nil
+	}
+	fp, _ := v.(map[string]string)
+	return fp
+}
+
+// iteration returns, from the session of ctx, the summary of the
+// node's previous iteration in the run, the time the run started and
+// the events of the current iteration.
+func (n *LoopNode) iteration(ctx agent.Context) (prev *loop.Summary, start time.Time, events []*session.Event) {
+	start = time.Now()
+	if ctx.Session() == nil {
+		return nil, start, nil
+	}
+	for ev := range ctx.Session().Events().All() {
+		if ev == nil || ev.InvocationID != ctx.InvocationID() {
+			continue
+		}
+		if !ev.Timestamp.IsZero() && ev.Timestamp.Before(start) {
+			start = ev.Timestamp
+		}
+		if s, ok := loop.SummaryOf(ev); ok && ev.Author == n.Name() {
+			prev, events = s, nil
+			continue
+		}
+		events = append(events, ev)
+	}
+	return prev, start, events
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow_test
+
+import (
+	"testing"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/agent/workflowagent"
+	"google.golang.org/adk/v2/loop"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/workflow"
+)
+
+// runLoop runs a workflow in which node refine increments the "count"
+// state key up to limit, in a cycle ended by a LoopNode with
+// conditions, and returns the loop summaries and the final output.
+func runLoop(t *testing.T, limit int, conditions ...loop.Condition) ([]*loop.Summary, any) {
+	t.Helper()
+	refine := workflow.NewFunctionNode("refine", func(ctx agent.Context, in any) (int, error) {
+		count, _ := ctx.State().Get("count")
+		n, _ := count.(int)
+		n = min(n+1, limit)
+		return n, ctx.State().Set("count", n)
+	}, workflow.NodeConfig{})
+	check, err := workflow.NewLoopNode("check", conditions, workflow.NodeConfig{})
+	if err != nil {
+		t.Fatalf("NewLoopNode: %v", err)
+	}
+	done := workflow.NewFunctionNode("done", func(_ agent.Context, in int) (int, error) {
+		return in * 10, nil
+	}, workflow.NodeConfig{})
+	a, err := workflowagent.New(workflowagent.Config{
+		Name: "refine_flow",
+		Edges: []workflow.Edge{
+			{From: workflow.Start, To: refine},
+			{From: refine, To: check},
+			{From: check, To: refine, Route: workflow.ContinueRoute},
+			{From: check, To: done, Route: workflow.ExitRoute},
+		},
+	})
+	if err != nil {
+		t.Fatalf("workflowagent.New: %v", err)
+	}
+	r, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: session.InMemoryService(), AutoCreateSession: true})
+	if err != nil {
+		t.Fatalf("runner.New: %v", err)
+	}
+
+	var summaries []*loop.Summary
+	var output any
+	for ev, err := range r.Run(t.Context(), "u", "s", genai.NewContentFromText("go", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		if s, ok := loop.SummaryOf(ev); ok {
+			summaries = append(summaries, s)
+		}
+		if ev.Output != nil {
+			output = ev.Output
+		}
+	}
+	return summaries, output
+}
+
+func TestLoopNode_StopsOnCondition(t *testing.T) {
+	summaries, output := runLoop(t, 10, loop.UntilState(func(s session.ReadonlyState) (bool, error) {
+		v, _ := s.Get("count")
+		return v == 3, nil
+	}))
+	if len(summaries) != 3 {
+		t.Fatalf("got %d iteration summaries, want 3", len(summaries))
+	}
+	for i, s := range summaries {
+		if s.Iteration != i+1 || len(s.Changed) != 1 || s.Changed[0] != "count" {
+			t.Errorf("summary %d = %+v, want iteration %d changing count", i, s, i+1)
+		}
+	}
+	if stop := summaries[2].Stop; stop == nil || stop.Reason != loop.ReasonState {
+		t.Errorf("last summary stop = %+v, want reason %q", stop, loop.ReasonState)
+	}
+	if output != 30 {
+		t.Errorf("final output = %v, want 30", output)
+	}
+}
+
+func TestLoopNode_StopsWhenStable(t *testing.T) {
+	summaries, _ := runLoop(t, 2, loop.UntilStable(2), loop.MaxIterations(10))
+	if len(summaries) != 4 {
+		t.Fatalf("got %d iteration summaries, want 4", len(summaries))
+	}
+	if stop := summaries[3].Stop; stop == nil || stop.Reason != loop.ReasonStable {
+		t.Errorf("last summary stop = %+v, want reason %q", stop, loop.ReasonStable)
+	}
+}
+
+func TestNewLoopNode_RequiresCondition(t *testing.T) {
+	if _, err := workflow.NewLoopNode("check", nil, workflow.NodeConfig{}); err == nil {
+		t.Error("NewLoopNode() without conditions succeeded, want error")
+	}
+}
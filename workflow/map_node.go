@@ -0,0 +1,416 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/typeutil"
+	"google.golang.org/adk/v2/session"
+)
+
+// FailurePolicy decides how a MapNode handles items whose mapper
+// fails after exhausting its retries.
+type FailurePolicy int
+
+const (
+	// FailFast fails the MapNode on the first failed item and cancels
+	// the items in flight.
+	FailFast FailurePolicy = iota
+	// CollectErrors runs every item and then fails the MapNode with a
+	// *MapError listing all failed items, if any.
+	CollectErrors
+	// BestEffort runs every item and succeeds with the outputs of the
+	// successful ones, unless more than MapConfig.MaxFailures items
+	// failed, in which case it fails with a *MapError.
+	BestEffort
+)
+
+// MapConfig configures a MapNode.
+type MapConfig struct {
+	// MaxConcurrency bounds the number of items mapped at once. <= 0
+	// means no limit.
+	MaxConcurrency int
+	// FailurePolicy decides how failed items are handled. Defaults to
+	// FailFast.
+	FailurePolicy FailurePolicy
+	// MaxFailures is the number of failed items BestEffort tolerates.
+	// A negative value tolerates any number.
+	MaxFailures int
+	// Unordered makes the output list the outputs in completion order
+	// instead of input order.
+	Unordered bool
+}
+
+// MapProgressKey is the Event.CustomMetadata key of the MapProgress
+// reported by MapNode progress events.
+const MapProgressKey = "workflow_map_progress"
+
+// MapProgress reports the progress of a MapNode after an item
+// finished. MapNode emits it as an event without content carrying it
+// under Event.CustomMetadata[MapProgressKey].
+type MapProgress struct {
+	// Index is the position in the input list of the item which
+	// finished.
+	Index int `json:"index"`
+	// Total is the number of items.
+	Total int `json:"total"`
+	// Completed and Failed count the items finished so far.
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+	// Error is the error of the item, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+// MapProgressOf returns the MapProgress carried by ev, if any. It
+// accepts events read back from a session service which stores
+// metadata in its JSON-decoded form.
+func MapProgressOf(ev *session.Event) (*MapProgress, bool) {
+	if ev == nil {
+		return nil, false
+	}
+	return typeutil.Decode[MapProgress](ev.CustomMetadata[MapProgressKey])
+}
+
+// MapItemError is the error of one failed item of a MapNode.
+type MapItemError struct {
+	// Index is the position of the item in the input list.
+	Index int
+	Err   error
+}
+
+// MapError is returned by a MapNode with the CollectErrors or
+// BestEffort policy when items failed.
+type MapError struct {
+	// Node is the name of the MapNode.
+	Node string
+	// Total is the number of items.
+	Total int
+	// Failures lists the failed items in input order.
+	Failures []MapItemError
+}
+
+func (e *MapError) Error() string {
+	first := e.Failures[0]
+	return fmt.Sprintf("map node %s: %d of %d items failed, first item %d: %v", e.Node, len(e.Failures), e.Total, first.Index, first.Err)
+}
+
+// Unwrap returns the errors of the failed items.
+func (e *MapError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = f.Err
+	}
+	return errs
+}
+
+// MapNode fans out over a list computed at runtime: it runs a mapper
+// node once per item of its input and emits the list of the mapper's
+// outputs. Unlike ParallelWorker it reports progress as items finish
+// and supports partial-failure policies and unordered results; with
+// ForEach the mapper is a whole sub-graph.
+type MapNode struct {
+	BaseNode
+	mapper   Node
+	cfg      MapConfig
+	retryCfg *RetryConfig
+}
+
+// NewMapNode creates a MapNode running mapper over each item.
+//
+// cfg.RetryConfig applies to each item independently instead of to
+// the MapNode as a whole, so the mapper itself must not have one.
+func NewMapNode(name string, mapper Node, mapCfg MapConfig, cfg NodeConfig) (*MapNode, error) {
+	if mapper == nil {
+		return nil, fmt.Errorf("MapNode %s: mapper is required", name)
+	}
+	if mapper.Config().RetryConfig != nil {
+		return nil, fmt.Errorf("MapNode %s: mapper %s cannot have RetryConfig", name, mapper.Name())
+	}
+	if mapCfg.FailurePolicy < FailFast || mapCfg.FailurePolicy > BestEffort {
+		return nil, fmt.Errorf("MapNode %s: unknown failure policy %d", name, mapCfg.FailurePolicy)
+	}
+	retryCfg := cfg.RetryConfig
+	cfg.RetryConfig = nil // Retried per item, not by the scheduler.
+
+	return &MapNode{
+		BaseNode: BaseNode{name: name, config: cfg},
+		mapper:   mapper,
+		cfg:      mapCfg,
+		retryCfg: retryCfg,
+	}, nil
+}
+
+// ForEach creates a MapNode which runs the sub-graph described by body
+// once per item of its input, each item being the input of the
+// sub-graph's Start node. The sub-graph is a WorkflowNode named
+// "<name>_body".
+func ForEach(name string, body []Edge, mapCfg MapConfig, cfg NodeConfig) (*MapNode, error) {
+	sub, err := NewWorkflowNode(name+"_body", body)
+	if err != nil {
+		return nil, fmt.Errorf("ForEach %s: %w", name, err)
+	}
+	return NewMapNode(name, sub, mapCfg, cfg)
+}
+
+type mapItemResult struct {
+	index  int
+	output any
+	err    error
+}
+
+// Run maps the items of the input list, which must be a slice.
+//
+// Each item runs on its own sub-branch "<mapper>@<index+1>" of the
+// node's branch, so the mapper of one item doesn't see the events of
+// the others. Intermediate non-output events of the mapper are
+// suppressed; an event with MapProgress is emitted as each item
+// finishes instead. As in ParallelWorker, a mapper emitting several
+// outputs for an item contributes a list of them.
+//
+// The output is the list of item outputs, in input order unless
+// MapConfig.Unordered is set. Failed items tolerated by BestEffort
+// are left out.
+func (n *MapNode) Run(ctx agent.Context, input any) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		cancelCtx, cancelFunc := ctx.WithAgentCancel()
+		defer cancelFunc()
+		workerCtx := ctx.WithAgentContext(cancelCtx)
+
+		v := reflect.ValueOf(input)
+		if v.Kind() != reflect.Slice {
+			yield(nil, fmt.Errorf("map node %s expects a slice input, got %T", n.Name(), input))
+			return
+		}
+		total := v.Len()
+
+		results := make(chan mapItemResult, total)
+		go n.dispatch(workerCtx, v, results)
+
+		var (
+			outputs   = make([]any, total)
+			succeeded = make([]bool, total)
+			order     []int
+			failures  []MapItemError
+			stopped   bool
+			userGone  bool
+			progress  = &MapProgress{Total: total}
+		)
+		for res := range results {
+			if stopped {
+				// Fail-fast already cancelled the remaining items.
+				continue
+			}
+			if res.err != nil {
+				failures = append(failures, MapItemError{Index: res.index, Err: res.err})
+				progress.Failed++
+				if n.cfg.FailurePolicy == FailFast {
+					stopped = true
+					cancelFunc()
+				}
+			} else {
+				outputs[res.index] = res.output
+				succeeded[res.index] = true
+				order = append(order, res.index)
+				progress.Completed++
+			}
+			if userGone {
+				continue
+			}
+			p := *progress
+			p.Index = res.index
+			if res.err != nil {
+				p.Error = res.err.Error()
+			}
+			ev := session.NewEvent(ctx, ctx.InvocationID())
+			ev.CustomMetadata = map[string]any{MapProgressKey: &p}
+			if !yield(ev, nil) {
+				userGone = true
+				cancelFunc()
+			}
+		}
+		if userGone {
+			return
+		}
+
+		if err := n.failure(total, failures); err != nil {
+			yield(nil, err)
+			return
+		}
+
+		result := make([]any, 0, total)
+		if n.cfg.Unordered {
+			for _, i := range order {
+				result = append(result, outputs[i])
+			}
+		} else {
+			for i, ok := range succeeded {
+				if ok {
+					result = append(result, outputs[i])
+				}
+			}
+		}
+		ev := session.NewEvent(ctx, ctx.InvocationID())
+		ev.Output = result
+		yield(ev, nil)
+	}
+}
+
+// dispatch starts a worker per item, at most MaxConcurrency at once,
+// and closes results once all of them finished.
+func (n *MapNode) dispatch(ctx agent.Context, items reflect.Value, results chan<- mapItemResult) {
+	defer close(results)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	var sem chan struct{}
+	if n.cfg.MaxConcurrency > 0 {
+		sem = make(chan struct{}, n.cfg.MaxConcurrency)
+	}
+	mapperName := n.mapper.Name()
+	for i := range items.Len() {
+		if sem != nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				results <- mapItemResult{index: i, err: ctx.Err()}
+				continue
+			}
+		}
+		itemBranch := deriveSubBranch(ctx.Branch(), mapperName+"@"+strconv.Itoa(i+1))
+		itemCtx := ctx.WithDelta(&agent.CommonContextDelta{InvocationContextDelta: &agent.InvocationContextDelta{Branch: &itemBranch}})
+		wg.Add(1)
+		go func(i int, item any) {
+			defer wg.Done()
+			if sem != nil {
+				defer func() { <-sem }()
+			}
+			output, err := n.runItem(itemCtx, item)
+			results <- mapItemResult{index: i, output: output, err: err}
+		}(i, items.Index(i).Interface())
+	}
+}
+
+// runItem runs the mapper over item, retrying per the node's
+// RetryConfig.
+func (n *MapNode) runItem(ctx agent.Context, item any) (any, error) {
+	failedAttempts := 0
+	for {
+		outputs, err := runWrappedOnce(ctx, n.mapper, item)
+		if err == nil {
+			switch len(outputs) {
+			case 0:
+				return nil, nil
+			case 1:
+				return outputs[0], nil
+			default:
+				return outputs, nil
+			}
+		}
+		failedAttempts++
+		if !ShouldRetry(n.retryCfg, err, failedAttempts) {
+			return nil, err
+		}
+		select {
+		case <-time.After(CalculateDelay(n.retryCfg, failedAttempts)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// failure returns the error failing the node under its failure policy,
+// or nil if the node succeeds.
+func (n *MapNode) failure(total int, failures []MapItemError) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	if n.cfg.FailurePolicy == FailFast {
+		f := failures[0]
+		return fmt.Errorf("map node %s: item %d: %w", n.Name(), f.Index, f.Err)
+	}
+	if n.cfg.FailurePolicy == BestEffort && (n.cfg.MaxFailures < 0 || len(failures) <= n.cfg.MaxFailures) {
+		return nil
+	}
+	slices.SortFunc(failures, func(a, b MapItemError) int { return a.Index - b.Index })
+	return &MapError{Node: n.Name(), Total: total, Failures: failures}
+}
+
+// ReduceNode folds the items of its list input into a single output
+// with a reducer function, e.g. to aggregate the output of a MapNode.
+type ReduceNode struct {
+	BaseNode
+	reduce func(ctx agent.Context, items reflect.Value) (any, error)
+}
+
+// NewReduceNode creates a ReduceNode calling fn for each item of the
+// input list in order, starting from initial and passing the result of
+// each call to the next. Items which are not of type T are converted
+// through their JSON form.
+//
+// initial is shared by every run of the node, so fn must not modify it
+// in place, e.g. when ACC is a map or a slice.
+func NewReduceNode[ACC, T any](name string, initial ACC, fn func(ctx agent.Context, acc ACC, item T) (ACC, error), cfg NodeConfig) *ReduceNode {
+	reduce := func(ctx agent.Context, items reflect.Value) (any, error) {
+		acc := initial
+		for i := range items.Len() {
+			raw := items.Index(i).Interface()
+			item, ok := raw.(T)
+			if !ok && raw != nil {
+				var err error
+				item, err = typeutil.ConvertToWithJSONSchema[any, T](raw, nil)
+				if err != nil {
+					return nil, fmt.Errorf("reduce node %s: item %d: invalid type, expected %T: %w", name, i, item, err)
+				}
+			}
+			var err error
+			acc, err = fn(ctx, acc, item)
+			if err != nil {
+				return nil, fmt.Errorf("reduce node %s: item %d: %w", name, i, err)
+			}
+		}
+		return acc, nil
+	}
+	return &ReduceNode{
+		BaseNode: NewBaseNode(name, "", cfg),
+		reduce:   reduce,
+	}
+}
+
+// Run reduces the items of the input list, which must be a slice.
+func (n *ReduceNode) Run(ctx agent.Context, input any) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		v := reflect.ValueOf(input)
+		if v.Kind() != reflect.Slice {
+			yield(nil, fmt.Errorf("reduce node %s expects a slice input, got %T", n.Name(), input))
+			return
+		}
+		output, err := n.reduce(ctx, v)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		ev := session.NewEvent(ctx, ctx.InvocationID())
+		ev.Output = output
+		yield(ev, nil)
+	}
+}
@@ -0,0 +1,338 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/session"
+)
+
+// failOn returns a mapper upper-casing its input which fails for the
+// given inputs.
+func failOn(inputs ...string) Node {
+	return NewFunctionNode("mapper", func(ctx agent.Context, input string) (string, error) {
+		if slices.Contains(inputs, input) {
+			return "", errors.New("failed on " + input)
+		}
+		return strings.ToUpper(input), nil
+	}, defaultNodeConfig)
+}
+
+// runMapNode runs n over input and returns its output, its progress
+// reports and its error.
+func runMapNode(t *testing.T, n Node, input any) ([]any, []*MapProgress, error) {
+	t.Helper()
+	var output []any
+	var progress []*MapProgress
+	for ev, err := range n.Run(agent.NewContext(newMockCtx(t)), input) {
+		if err != nil {
+			return output, progress, err
+		}
+		if p, ok := MapProgressOf(ev); ok {
+			progress = append(progress, p)
+		}
+		if ev.Output != nil {
+			output = ev.Output.([]any)
+		}
+	}
+	return output, progress, nil
+}
+
+func TestMapNode_FailurePolicies(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          MapConfig
+		wantOutput   []any
+		wantFailures []int // indexes in the *MapError; nil for none
+		wantErr      bool
+	}{
+		{
+			name:    "FailFast",
+			cfg:     MapConfig{MaxConcurrency: 1},
+			wantErr: true,
+		},
+		{
+			name:         "CollectErrors",
+			cfg:          MapConfig{FailurePolicy: CollectErrors},
+			wantFailures: []int{1, 3},
+			wantErr:      true,
+		},
+		{
+			name:       "BestEffortWithinThreshold",
+			cfg:        MapConfig{FailurePolicy: BestEffort, MaxFailures: 2},
+			wantOutput: []any{"A", "C"},
+		},
+		{
+			name:         "BestEffortOverThreshold",
+			cfg:          MapConfig{FailurePolicy: BestEffort, MaxFailures: 1},
+			wantFailures: []int{1, 3},
+			wantErr:      true,
+		},
+		{
+			name:       "BestEffortUnlimited",
+			cfg:        MapConfig{FailurePolicy: BestEffort, MaxFailures: -1},
+			wantOutput: []any{"A", "C"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			n, err := NewMapNode("map", failOn("b", "d"), tc.cfg, defaultNodeConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			output, _, err := runMapNode(t, n, []any{"a", "b", "c", "d"})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "failed on b") {
+				t.Errorf("Run() error = %v, want the error of item b", err)
+			}
+			var mapErr *MapError
+			if errors.As(err, &mapErr) {
+				var got []int
+				for _, f := range mapErr.Failures {
+					got = append(got, f.Index)
+				}
+				if diff := cmp.Diff(tc.wantFailures, got); diff != "" {
+					t.Errorf("MapError failures mismatch (-want +got):\n%s", diff)
+				}
+			} else if tc.wantFailures != nil {
+				t.Errorf("Run() error = %v, want a *MapError", err)
+			}
+			if diff := cmp.Diff(tc.wantOutput, output); diff != "" {
+				t.Errorf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMapNode_Ordering(t *testing.T) {
+	// Item "slow" finishes last.
+	mapper := NewFunctionNode("mapper", func(ctx agent.Context, input string) (string, error) {
+		if input == "slow" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		return input, nil
+	}, defaultNodeConfig)
+
+	for _, tc := range []struct {
+		unordered bool
+		want      []any
+	}{
+		{unordered: false, want: []any{"slow", "fast"}},
+		{unordered: true, want: []any{"fast", "slow"}},
+	} {
+		n, err := NewMapNode("map", mapper, MapConfig{Unordered: tc.unordered}, defaultNodeConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		output, _, err := runMapNode(t, n, []string{"slow", "fast"})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if diff := cmp.Diff(tc.want, output); diff != "" {
+			t.Errorf("Unordered=%v: output mismatch (-want +got):\n%s", tc.unordered, diff)
+		}
+	}
+}
+
+func TestMapNode_Progress(t *testing.T) {
+	n, err := NewMapNode("map", failOn("b"), MapConfig{FailurePolicy: BestEffort, MaxFailures: 1, MaxConcurrency: 1}, defaultNodeConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, progress, err := runMapNode(t, n, []any{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := []*MapProgress{
+		{Index: 0, Total: 3, Completed: 1},
+		{Index: 1, Total: 3, Completed: 1, Failed: 1, Error: "failed on b"},
+		{Index: 2, Total: 3, Completed: 2, Failed: 1},
+	}
+	if diff := cmp.Diff(want, progress); diff != "" {
+		t.Errorf("progress mismatch (-want +got):\n%s", diff)
+	}
+
+	// Progress read back from a session service storing metadata in
+	// JSON-decoded form.
+	ev := &session.Event{}
+	ev.CustomMetadata = map[string]any{MapProgressKey: map[string]any{"index": 2.0, "total": 3.0, "completed": 2.0}}
+	got, ok := MapProgressOf(ev)
+	if !ok {
+		t.Fatal("MapProgressOf() of decoded metadata = false, want true")
+	}
+	if diff := cmp.Diff(&MapProgress{Index: 2, Total: 3, Completed: 2}, got); diff != "" {
+		t.Errorf("MapProgressOf() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMapNode_RetryPerItem(t *testing.T) {
+	var calls sync.Map
+	mapper := NewFunctionNode("mapper", func(ctx agent.Context, input string) (string, error) {
+		c, _ := calls.LoadOrStore(input, new(atomic.Int32))
+		if c.(*atomic.Int32).Add(1) == 1 && input == "b" {
+			return "", errors.New("transient")
+		}
+		return input, nil
+	}, defaultNodeConfig)
+	n, err := NewMapNode("map", mapper, MapConfig{}, NodeConfig{RetryConfig: &RetryConfig{MaxAttempts: 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Config().RetryConfig != nil {
+		t.Error("MapNode exposes its RetryConfig to the scheduler, want it applied per item")
+	}
+
+	output, _, err := runMapNode(t, n, []any{"a", "b"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if diff := cmp.Diff([]any{"a", "b"}, output); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+	for input, want := range map[string]int32{"a": 1, "b": 2} {
+		c, _ := calls.Load(input)
+		if got := c.(*atomic.Int32).Load(); got != want {
+			t.Errorf("mapper ran %d times for %q, want %d", got, input, want)
+		}
+	}
+}
+
+func TestMapNode_PerItemBranch(t *testing.T) {
+	var mu sync.Mutex
+	var branches []string
+	mapper := NewFunctionNode("mapper", func(ctx agent.Context, input string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		branches = append(branches, ctx.Branch())
+		return input, nil
+	}, defaultNodeConfig)
+	n, err := NewMapNode("map", mapper, MapConfig{}, defaultNodeConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := runMapNode(t, n, []any{"a", "b"}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	slices.Sort(branches)
+	if diff := cmp.Diff([]string{"mapper@1", "mapper@2"}, branches); diff != "" {
+		t.Errorf("branches mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMapNode_InvalidInput(t *testing.T) {
+	n, err := NewMapNode("map", upperNode, MapConfig{}, defaultNodeConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := runMapNode(t, n, "not a slice"); err == nil || !strings.Contains(err.Error(), "expects a slice input") {
+		t.Errorf("Run() error = %v, want a slice input error", err)
+	}
+	output, progress, err := runMapNode(t, n, []any{})
+	if err != nil || len(output) != 0 || len(progress) != 0 {
+		t.Errorf("Run() of an empty list = %v, %v, %v, want an empty output", output, progress, err)
+	}
+}
+
+func TestNewMapNode_Errors(t *testing.T) {
+	withRetry := NewFunctionNode("mapper", func(ctx agent.Context, input any) (any, error) { return input, nil }, NodeConfig{RetryConfig: DefaultRetryConfig()})
+	if _, err := NewMapNode("map", withRetry, MapConfig{}, defaultNodeConfig); err == nil {
+		t.Error("NewMapNode() with a retrying mapper succeeded, want error")
+	}
+	if _, err := NewMapNode("map", upperNode, MapConfig{FailurePolicy: 42}, defaultNodeConfig); err == nil {
+		t.Error("NewMapNode() with an unknown failure policy succeeded, want error")
+	}
+}
+
+func TestForEachReduce_WorkflowIntegration(t *testing.T) {
+	split := NewFunctionNode("split", func(ctx agent.Context, input string) ([]string, error) {
+		return strings.Split(input, ","), nil
+	}, defaultNodeConfig)
+	// The body of ForEach is a sub-graph run once per item.
+	double := NewFunctionNode("double", func(ctx agent.Context, input string) (string, error) {
+		return input + input, nil
+	}, defaultNodeConfig)
+	length := NewFunctionNode("length", func(ctx agent.Context, input string) (int, error) {
+		return len(input), nil
+	}, defaultNodeConfig)
+	each, err := ForEach("each", Chain(Start, double, length), MapConfig{MaxConcurrency: 2}, defaultNodeConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := NewReduceNode("sum", 0, func(ctx agent.Context, acc, item int) (int, error) {
+		return acc + item, nil
+	}, defaultNodeConfig)
+
+	w := mustNew(t, Chain(Start, split, each, sum))
+	ctx := newMockCtx(t)
+	ctx.userContent = &genai.Content{Parts: []*genai.Part{{Text: "a,bb,ccc"}}}
+
+	var last any
+	for ev, err := range w.Run(ctx) {
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if ev.Output != nil {
+			last = ev.Output
+		}
+	}
+	if last != 12 {
+		t.Errorf("output = %v, want 12", last)
+	}
+}
+
+func TestReduceNode(t *testing.T) {
+	concat := NewReduceNode("concat", "", func(ctx agent.Context, acc string, item struct{ Name string }) (string, error) {
+		if item.Name == "" {
+			return "", errors.New("empty name")
+		}
+		return acc + item.Name, nil
+	}, defaultNodeConfig)
+
+	run := func(input any) (any, error) {
+		var out any
+		for ev, err := range concat.Run(agent.NewContext(newMockCtx(t)), input) {
+			if err != nil {
+				return nil, err
+			}
+			out = ev.Output
+		}
+		return out, nil
+	}
+
+	// Items are converted through their JSON form.
+	got, err := run([]any{map[string]any{"Name": "a"}, map[string]any{"Name": "b"}})
+	if err != nil || got != "ab" {
+		t.Errorf("Run() = %v, %v, want %q", got, err, "ab")
+	}
+	if _, err := run([]any{map[string]any{"Name": "a"}, map[string]any{}}); err == nil || !strings.Contains(err.Error(), "item 1: empty name") {
+		t.Errorf("Run() error = %v, want the error of item 1", err)
+	}
+	if _, err := run("not a slice"); err == nil {
+		t.Error("Run() of a non-slice succeeded, want error")
+	}
+}
@@ -211,13 +211,19 @@ func (n *ParallelWorker) runWorker(ctx agent.Context, idx int, item any, sem cha
 // span. Kept a separate function so the deferred span.end() fires per
 // attempt instead of piling up across runWorker's retry loop.
 func (n *ParallelWorker) runWrappedOnce(ctx agent.Context, item any) (outputs []any, err error) {
-	span, ctx := startNodeSpan(ctx, n.wrapped)
+	return runWrappedOnce(ctx, n.wrapped, item)
+}
+
+// runWrappedOnce runs wrapped once for item under its own span and
+// collects its outputs. Shared by ParallelWorker and MapNode.
+func runWrappedOnce(ctx agent.Context, wrapped Node, item any) (outputs []any, err error) {
+	span, ctx := startNodeSpan(ctx, wrapped)
 	defer func() {
 		span.recordError(err, nil)
 		span.end()
 	}()
 
-	for ev, runErr := range n.wrapped.Run(ctx, item) {
+	for ev, runErr := range wrapped.Run(ctx, item) {
 		if runErr != nil {
 			err = runErr
 			break
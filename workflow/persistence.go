@@ -77,12 +77,23 @@ func (s *nodeScanState) addInterrupt(id string) {
 // run's invocation ID for the resume turn, so the pause and its reply
 // share it. Empty invocationID disables the filter (scan all history).
 // Mirrors adk-python _reconstruct_node_states' invocation_id gate.
+//
+// A run cancelled by an event from CancelEvent has nothing to resume.
+// The compensable activations recorded as the run paused are restored
+// in RunState.Compensable; see CompensateFunc.
+//
+// A run whose events were recorded by another version of the workflow
+// (see WithVersion) is passed through the workflow's MigrationFunc, or
+// refused with ErrVersionMismatch when it has none.
 func (w *Workflow) ReconstructRunState(sess session.Session, invocationID string) (*RunState, error) {
 	if sess == nil {
 		return nil, nil
 	}
 	nodesByName := buildNodesByName(w.graph)
 	events := sess.Events()
+	if w.runCancelled(events, invocationID) {
+		return nil, nil
+	}
 
 	// Stage 1: scan history into a per-node view of the pause
 	// (interrupts raised, responses that resolved them, schemas).
@@ -112,7 +123,8 @@ func (w *Workflow) ReconstructRunState(sess session.Session, invocationID string
 		}
 	}
 	state.completed = completed
-	return state, nil
+	state.Compensable = compensableActivations(events, w.name, invocationID)
+	return w.migrate(recordedVersion(events, nodesByName, invocationID), state)
 }
 
 // scanHistory walks session events once and returns, per static graph
@@ -80,7 +80,8 @@ func (w *Workflow) Resume(
 			return
 		}
 
-		s := newScheduler(ctx, w.graph, w.maxConcurrency)
+		s, _, cancel := w.newScheduler(ctx)
+		defer cancel()
 		s.state = state
 
 		// Resume runs in two passes so that when one call
@@ -195,12 +196,12 @@ func (w *Workflow) Resume(
 		// All matched askers are now NodeCompleted, so any
 		// downstream JoinNode sees a settled predecessor set.
 		for _, h := range deferredHandoffs {
-			// findSuccessors is called with event=nil, so
+			// findSuccessors is called without a routing event, so
 			// successors reached only via a concrete Route
 			// (StringRoute etc.) do not fire — the response is
 			// opaque to the routing layer. Successors reached via
-			// an unconditional edge or via the Default route fire
-			// as usual.
+			// an unconditional edge, the Default route or a
+			// ContextualRoute matching the response fire as usual.
 			// Handoff successors inherit the asker's branch so the
 			// downstream LLM history filter still scopes correctly
 			// when a parallel branch resumes via handoff.
@@ -208,7 +209,7 @@ func (w *Workflow) Resume(
 			if ns := s.state.Nodes[h.node.Name()]; ns != nil {
 				parentBranch = ns.Branch
 			}
-			for _, succ := range findSuccessors(s.graph, s.state, h.node, h.resp, nil, parentBranch) {
+			for _, succ := range findSuccessors(s.graph, s.state, h.node, h.resp, RouteContext{Output: h.resp, State: s.sessionState()}, parentBranch) {
 				// Skip a successor that already produced output on a
 				// prior turn: re-triggering it would re-run completed
 				// work (a duplicate resume). Keeps Resume idempotent.
@@ -106,6 +106,34 @@ type scheduler struct {
 	// in arrival order as in-flight nodes complete. Owned by the
 	// consumer goroutine.
 	pendingQueue []pendingActivation
+
+	// durableName is the name of a durable workflow whose run is
+	// checkpointed to the session after each completion. Empty
+	// disables checkpointing.
+	durableName string
+
+	// checkpointed holds the JSON encodings of the parts of the run's
+	// checkpoint saved so far, keyed by session.State key, for each
+	// checkpoint to save only the parts which changed.
+	checkpointed map[string]string
+
+	// name is the name of the workflow, the author of the events
+	// recording node failures; empty for a root wrapper, whose
+	// failures are its agent's own. failures holds the error of each
+	// node which failed the run, in order of failure. Owned by the
+	// consumer goroutine.
+	name     string
+	failures []nodeFailure
+
+	// branchTimeout bounds each branch forked by the workflow; 0
+	// disables it. branchForks records when each branch was first
+	// seen. Owned by the consumer goroutine.
+	branchTimeout time.Duration
+	branchForks   map[string]time.Time
+
+	// version is the workflow version stamped on the NodeInfo of the
+	// events of the workflow's nodes.
+	version string
 }
 
 // pendingActivation is a deferred scheduleResumedNode call kept on
@@ -255,6 +283,7 @@ func newScheduler(parent agent.Context, g *graph, maxConcurrency int) *scheduler
 		runsByName:     map[string]*nodeRun{},
 		runCancels:     map[string]context.CancelFunc{},
 		retryTimers:    map[string]*time.Timer{},
+		branchForks:    map[string]time.Time{},
 		eventQueue:     make(chan queueItem, defaultEventQueueCapacity),
 		parentCtx:      parent,
 		maxConcurrency: maxConcurrency,
@@ -391,6 +420,15 @@ func (s *scheduler) startNode(n Node, input any, triggeredBy, branch string, res
 	} else {
 		perNodeCtx, cancel = perNodeCtx.WithAgentCancel()
 	}
+	if deadline, cause, ok := s.branchDeadline(branch); ok {
+		c, cancelBranch := context.WithDeadlineCause(perNodeCtx, deadline, cause)
+		perNodeCtx = perNodeCtx.WithAgentContext(c)
+		cancelNode := cancel
+		cancel = func() {
+			cancelBranch()
+			cancelNode()
+		}
+	}
 
 	ns := s.state.EnsureNode(name)
 	ns.Status = NodeRunning
@@ -465,6 +503,11 @@ func runNode(
 	for ev, err := range n.Run(ctx, validated) {
 		if err != nil {
 			completion.err = err
+			// A node returning its context's error verbatim gets the
+			// cause instead, e.g. ErrBranchTimeout for a deadline.
+			if ctxErr := ctx.Err(); ctxErr != nil && err == ctxErr {
+				completion.err = context.Cause(ctx)
+			}
 			return
 		}
 		// Block on non-partial events until the consumer has persisted
@@ -477,14 +520,14 @@ func runNode(
 		select {
 		case out <- eventItem{nodeName: name, ev: ev, processed: processed}:
 		case <-ctx.Done():
-			completion.err = ctx.Err()
+			completion.err = context.Cause(ctx)
 			return
 		}
 		if processed != nil {
 			select {
 			case <-processed:
 			case <-ctx.Done():
-				completion.err = ctx.Err()
+				completion.err = context.Cause(ctx)
 				return
 			}
 		}
@@ -492,20 +535,22 @@ func runNode(
 	// If the node's iter returned cleanly but the context was
 	// cancelled or its deadline elapsed, surface that as the
 	// completion error: the node likely returned because it observed
-	// ctx.Done(), and the consumer needs to classify it.
-	if ctxErr := ctx.Err(); ctxErr != nil {
-		completion.err = ctxErr
+	// ctx.Done(), and the consumer needs to classify it. The cause
+	// tells a branch or workflow timeout from a plain deadline.
+	if ctx.Err() != nil {
+		completion.err = context.Cause(ctx)
 	}
 }
 
 // echoesCancellation reports whether err is the dying invocation
 // context reflected back by a node rather than a failure the node
-// determined for itself. runNode sets completion.err to ctx.Err() when
-// a node returns because its context died, so the echo takes three
-// shapes: the parent's own error (context.DeadlineExceeded for a
-// request timeout), its cause (set via context.WithCancelCause), or a
-// plain context.Canceled from the per-node cancel that cancelAll fires
-// in response — a node can observe that before the parent's deadline.
+// determined for itself. runNode sets completion.err to
+// context.Cause(ctx) when a node returns because its context died,
+// so the echo takes three shapes: the parent's own error
+// (context.DeadlineExceeded for a request timeout), its cause (set via
+// context.WithCancelCause, or ErrWorkflowTimeout), or a plain
+// context.Canceled from the per-node cancel that cancelAll fires in
+// response — a node can observe that before the parent's deadline.
 //
 // Only meaningful once s.parentCtx has failed; context.Cause returns
 // nil for a live context and errors.Is(err, nil) is never true.
@@ -555,9 +600,36 @@ func (s *scheduler) run(yield func(*session.Event, error) bool) {
 	var cancelErr error   // cause of an external cancellation; surfaced only when no node reported an error
 	draining := false     // true once cancelAll has run; remaining queue items are drained without yielding or scheduling new successors
 	consumerGone := false // true once the caller broke the range loop; no further yield is allowed
+	compensated := false  // true once a failed run was compensated; it can't continue
 
 	doneChan := s.parentCtx.Done()
 
+	// checkpoint saves the run state of a durable workflow by yielding
+	// an event carrying it as a state delta. A checkpoint which can't
+	// be encoded fails the run, as it could not be recovered.
+	checkpoint := func(status RunStatus) {
+		if s.durableName == "" || consumerGone {
+			return
+		}
+		ev, err := s.checkpointEvent(status, status == RunCompleted || compensated)
+		if err != nil {
+			if pendingErr == nil {
+				pendingErr = err
+			}
+			if !draining {
+				draining = true
+				s.cancelAll()
+			}
+			return
+		}
+		if !yield(ev, nil) {
+			draining = true
+			consumerGone = true
+			s.cancelAll()
+		}
+	}
+	checkpoint(RunInProgress)
+
 	for len(s.runsByName) > 0 || len(s.retryTimers) > 0 {
 		var item queueItem
 		select {
@@ -601,6 +673,9 @@ func (s *scheduler) run(yield func(*session.Event, error) bool) {
 			}
 		case completionItem:
 			err := s.handleCompletion(it, !draining)
+			if err != nil {
+				s.failures = append(s.failures, nodeFailure{node: it.nodeName, err: err})
+			}
 			if err != nil && pendingErr == nil {
 				pendingErr = err
 				if !draining {
@@ -612,6 +687,7 @@ func (s *scheduler) run(yield func(*session.Event, error) bool) {
 			// activations to running.
 			if !draining {
 				s.tryDispatchPending()
+				checkpoint(RunInProgress)
 			}
 		case retryItem:
 			delete(s.retryTimers, it.node.Name())
@@ -629,10 +705,30 @@ func (s *scheduler) run(yield func(*session.Event, error) bool) {
 	// A node's own error outranks the cancellation cause: when a caller
 	// cancels because a node failed, "context canceled" alone would hide
 	// the reason the run stopped.
+	//
+	// A failed or cancelled run undoes its completed nodes which have
+	// a compensation hook, and can't be recovered once compensated. A
+	// cancelled run which had nothing to compensate keeps its
+	// in-progress checkpoint, so that a durable run stopped with its
+	// invocation can be recovered.
 	runErr := pendingErr
 	if runErr == nil {
 		runErr = cancelErr
 	}
+	if runErr != nil && !consumerGone && len(s.state.Compensable) > 0 {
+		compensated = true
+		gone, err := s.compensate(yield)
+		consumerGone = consumerGone || gone
+		if err != nil {
+			runErr = errors.Join(runErr, err)
+		}
+	}
+	if !consumerGone {
+		consumerGone = !s.recordFailures(yield)
+	}
+	if pendingErr != nil || compensated {
+		checkpoint(RunFailed)
+	}
 	if runErr != nil && !consumerGone {
 		yield(nil, runErr)
 		return
@@ -642,9 +738,34 @@ func (s *scheduler) run(yield func(*session.Event, error) bool) {
 	// errored): the run did not complete normally.
 	if !draining {
 		if err := s.finalize(); err != nil {
-			yield(nil, err)
+			checkpoint(RunFailed)
+			if !consumerGone {
+				yield(nil, err)
+			}
+			return
+		}
+		if s.waiting() {
+			// Resume rebuilds the run state from the session events,
+			// so they must record what to compensate should the
+			// resumed run fail.
+			if len(s.state.Compensable) > 0 && !consumerGone && !yield(s.compensableEvent(), nil) {
+				consumerGone = true
+			}
+			checkpoint(RunPaused)
+		} else {
+			checkpoint(RunCompleted)
+		}
+	}
+}
+
+// waiting reports whether any node is paused in NodeWaiting.
+func (s *scheduler) waiting() bool {
+	for _, ns := range s.state.Nodes {
+		if ns.Status == NodeWaiting {
+			return true
 		}
 	}
+	return false
 }
 
 // finalize errors if more than one terminal node (no outgoing edges,
@@ -654,10 +775,8 @@ func (s *scheduler) run(yield func(*session.Event, error) bool) {
 // output. No-op while a node is interrupted: the run has not finished.
 // Mirrors adk-python's Workflow._finalize.
 func (s *scheduler) finalize() error {
-	for _, ns := range s.state.Nodes {
-		if ns.Status == NodeWaiting {
-			return nil
-		}
+	if s.waiting() {
+		return nil
 	}
 
 	var producers []string
@@ -762,6 +881,12 @@ func (s *scheduler) handleEvent(it eventItem) {
 		it.ev.NodeInfo.Path = expectedPath
 		path = expectedPath
 	}
+	// Record the version of the graph the event was produced by, so a
+	// resume after a deploy can tell whether the graph changed. Nested
+	// workflows stamp their own nodes' events before they get here.
+	if s.version != "" {
+		it.ev.NodeInfo.WorkflowVersion = s.version
+	}
 	if it.ev.Routes != nil {
 		nr.setRoutingEvent(it.ev, it.nodeName)
 	}
@@ -857,7 +982,8 @@ func (s *scheduler) handleCompletion(it completionItem, scheduleSuccessors bool)
 		currentNode := s.nodesByName[it.nodeName]
 		if currentNode != nil {
 			cfg := currentNode.Config()
-			if cfg.RetryConfig != nil {
+			// A branch past its deadline would only time out again.
+			if cfg.RetryConfig != nil && !errors.Is(it.err, ErrBranchTimeout) {
 				ns.Attempt = ns.Attempt + 1
 
 				if ShouldRetry(cfg.RetryConfig, it.err, ns.Attempt) {
@@ -903,6 +1029,18 @@ func (s *scheduler) handleCompletion(it completionItem, scheduleSuccessors bool)
 	if nr != nil && nr.hasOutput {
 		ns.Output = nr.output
 	}
+	if n := s.nodesByName[it.nodeName]; n != nil && n.Config().Compensate != nil {
+		a := CompensableActivation{
+			Node:   it.nodeName,
+			Input:  ns.Input,
+			Branch: ns.Branch,
+		}
+		if nr != nil {
+			a.Output = nr.output
+			a.Path = nr.nodePath
+		}
+		s.state.Compensable = append(s.state.Compensable, a)
+	}
 	// Release the accumulated re-entry response history; the node
 	// has finished and a future activation (if any, e.g. via
 	// loop-back routing) starts a fresh lifecycle.
@@ -931,7 +1069,8 @@ func (s *scheduler) handleCompletion(it completionItem, scheduleSuccessors bool)
 		input = ns.Input
 	}
 
-	for _, succ := range findSuccessors(s.graph, s.state, currentNode, input, routingEv, ns.Branch) {
+	rc := RouteContext{Output: input, State: s.sessionState(), Event: routingEv}
+	for _, succ := range findSuccessors(s.graph, s.state, currentNode, input, rc, ns.Branch) {
 		s.scheduleNode(succ.node, succ.input, succ.triggeredBy, succ.branch)
 	}
 	return nil
@@ -953,10 +1092,12 @@ type successor struct {
 }
 
 // findSuccessors evaluates the outgoing edges of currentNode against
-// the optional routing event and returns the dispatch list:
+// rc and returns the dispatch list:
 //
 //   - Edges with no Route always fire (and do not suppress Default).
-//   - Edges with a concrete Route fire only if Route.Matches(event) is true.
+//   - Edges with a ContextualRoute fire if MatchesContext(rc) is true.
+//   - Edges with another concrete Route fire only if rc carries a
+//     routing event and Route.Matches(rc.Event) is true.
 //   - Duplicate To targets are deduplicated (same target node may not
 //     be queued twice for one parent activation).
 //   - The Default edge fires when no concrete Route matched. An
@@ -983,7 +1124,7 @@ type successor struct {
 //   - JoinNode successors compute their own branch in
 //     appendSuccessor as the common dot-prefix of the branches of
 //     all completed predecessors — see aggregatePredecessorBranches.
-func findSuccessors(g *graph, state *RunState, currentNode Node, input any, event *session.Event, parentBranch string) []successor {
+func findSuccessors(g *graph, state *RunState, currentNode Node, input any, rc RouteContext, parentBranch string) []successor {
 	succs := g.successorsOf(currentNode)
 	if len(succs) == 0 {
 		return nil
@@ -1006,7 +1147,7 @@ func findSuccessors(g *graph, state *RunState, currentNode Node, input any, even
 			defaultRouteNode = edge.To
 			continue
 		}
-		if event != nil && edge.Route.Matches(event) {
+		if routeMatches(edge.Route, rc) {
 			out = appendSuccessor(out, g, state, edge.To, input, from, parentBranch)
 			added[edge.To] = struct{}{}
 			concreteMatched = true
@@ -1034,6 +1175,25 @@ func findSuccessors(g *graph, state *RunState, currentNode Node, input any, even
 	return out
 }
 
+// routeMatches reports whether the concrete route r matches rc.
+func routeMatches(r Route, rc RouteContext) bool {
+	if cr, ok := r.(ContextualRoute); ok {
+		return cr.MatchesContext(rc)
+	}
+	return rc.Event != nil && r.Matches(rc.Event)
+}
+
+// sessionState returns the state of the run's session, or nil when the
+// run has none.
+func (s *scheduler) sessionState() session.ReadonlyState {
+	if sess := s.parentCtx.Session(); sess != nil {
+		if state := sess.State(); state != nil {
+			return state
+		}
+	}
+	return nil
+}
+
 // appendSuccessor records a routing match in the dispatch list.
 // Non-JoinNode targets are recorded with parentBranch as their
 // initial branch; findSuccessors may upgrade to a sub-branch in a
@@ -14,7 +14,11 @@
 
 package workflow
 
-import "github.com/google/jsonschema-go/jsonschema"
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
 
 // NodeStatus is the lifecycle status of a node in the workflow graph.
 //
@@ -73,6 +77,24 @@ const (
 	NodeCancelled
 )
 
+var nodeStatusNames = [...]string{
+	NodeInactive:  "inactive",
+	NodePending:   "pending",
+	NodeRunning:   "running",
+	NodeCompleted: "completed",
+	NodeWaiting:   "waiting",
+	NodeFailed:    "failed",
+	NodeCancelled: "cancelled",
+}
+
+// String returns the lowercase name of the status, e.g. "completed".
+func (s NodeStatus) String() string {
+	if int(s) < len(nodeStatusNames) {
+		return nodeStatusNames[s]
+	}
+	return fmt.Sprintf("NodeStatus(%d)", s)
+}
+
 // NodeState is the per-node lifecycle record. A RunState holds one
 // of these for every node the engine has touched.
 //
@@ -160,6 +182,11 @@ type RunState struct {
 	// inactive.
 	Nodes map[string]*NodeState `json:"nodes,omitempty"`
 
+	// Compensable holds the completed activations of the nodes with a
+	// compensation hook, in completion order, for the run to undo if it
+	// fails; see NodeConfig.Compensate.
+	Compensable []CompensableActivation `json:"compensable,omitempty"`
+
 	// completed is the set of node names that already produced an
 	// output in session history. Reconstructed by ReconstructRunState
 	// and used by Resume to avoid re-triggering a handoff successor
@@ -64,6 +64,12 @@ var ErrSubWorkflowNameCollision = errors.New("sub-workflow name collision")
 // safely. Use a JoinNode to converge multiple branches.
 var ErrUnsupportedFanIn = errors.New("non-JoinNode fan-in is not yet supported")
 
+// ErrContractMismatch is returned when the schemas of a workflow's
+// contract, declared with WithInputSchema and WithOutputSchema, do not
+// match the schemas of its nodes, or when a parent edge into or out of
+// a WorkflowNode carries a schema other than the sub-workflow's.
+var ErrContractMismatch = errors.New("workflow contract mismatch")
+
 // validateNodes executes a set of edges validation checks.
 func validateNodes(edges []Edge) error {
 	if err := validateUniqueNames(edges); err != nil {
@@ -161,6 +167,9 @@ func validateWorkflow(workflow *graph, schema *jsonschema.Resolved) error {
 	if err := validateDefaultRoute(workflow); err != nil {
 		return err
 	}
+	if err := validateRouteExpressions(workflow); err != nil {
+		return err
+	}
 	if err := validateConnectivity(workflow); err != nil {
 		return err
 	}
@@ -176,6 +185,9 @@ func validateWorkflow(workflow *graph, schema *jsonschema.Resolved) error {
 	if err := validateStateSchemaConsistency(workflow, schema); err != nil {
 		return err
 	}
+	if err := validateStateKeys(workflow, schema); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -279,6 +291,23 @@ func validateDefaultRoute(workflow *graph) error {
 	return nil
 }
 
+// validateRouteExpressions checks that the expressions of all
+// ExprRoutes compile.
+func validateRouteExpressions(workflow *graph) error {
+	for node, edges := range workflow.successors {
+		for _, edge := range edges {
+			r, ok := edge.Route.(ExprRoute)
+			if !ok {
+				continue
+			}
+			if _, err := r.compile(); err != nil {
+				return fmt.Errorf("edge from %q to %q: %w", node.Name(), edge.To.Name(), err)
+			}
+		}
+	}
+	return nil
+}
+
 // validateConnectivity checks that all nodes in the edge set are reachable from the start node.
 func validateConnectivity(workflow *graph) error {
 	if len(workflow.successors) == 0 {
@@ -473,6 +502,96 @@ func validateStateSchemaConsistency(g *graph, schema *jsonschema.Resolved) error
 	return nil
 }
 
+// ErrStateKeyMismatch is returned by New when a state key declared in
+// NodeConfig.StateKeys doesn't match the workflow's state schema or the
+// declaration of the same key by another node.
+var ErrStateKeyMismatch = errors.New("workflow: state key mismatch")
+
+// validateStateKeys checks the state keys declared by nodes against the
+// state schema, if any, and against each other.
+func validateStateKeys(g *graph, schema *jsonschema.Resolved) error {
+	type declaration struct {
+		node   string
+		schema *jsonschema.Schema
+	}
+	declared := map[string]declaration{}
+	var fields map[string]*jsonschema.Schema
+	if schema != nil && schema.Schema() != nil {
+		fields = schema.Schema().Properties
+	}
+	for _, n := range g.allNodes() {
+		for _, key := range n.Config().StateKeys {
+			name := key.Name()
+			ks, err := key.Schema()
+			if err != nil {
+				return fmt.Errorf("%w: node %q: state key %q: %w", ErrStateKeyMismatch, n.Name(), name, err)
+			}
+			if prev, ok := declared[name]; ok {
+				if !schemaAssignable(ks, prev.schema) || !schemaAssignable(prev.schema, ks) {
+					return fmt.Errorf("%w: state key %q has different types in nodes %q and %q", ErrStateKeyMismatch, name, prev.node, n.Name())
+				}
+			} else {
+				declared[name] = declaration{node: n.Name(), schema: ks}
+			}
+			if schema == nil {
+				continue
+			}
+			field, ok := fields[name]
+			if !ok {
+				if strings.HasPrefix(name, session.KeyPrefixApp) ||
+					strings.HasPrefix(name, session.KeyPrefixUser) ||
+					strings.HasPrefix(name, session.KeyPrefixTemp) {
+					continue
+				}
+				return fmt.Errorf("%w: node %q declares state key %q which is not declared in StateSchema (declared: %v)", ErrStateKeyMismatch, n.Name(), name, extractFieldNames(schema))
+			}
+			if !schemaAssignable(ks, field) {
+				return fmt.Errorf("%w: node %q declares state key %q with a type the StateSchema doesn't accept", ErrStateKeyMismatch, n.Name(), name)
+			}
+		}
+	}
+	return nil
+}
+
+// schemaAssignable reports whether the values described by from have
+// types accepted by to, comparing the types of the schemas, array items
+// and the properties they both declare. Schemas without types accept,
+// and are assumed to fit, anything; null is ignored.
+func schemaAssignable(from, to *jsonschema.Schema) bool {
+	if from == nil || to == nil {
+		return true
+	}
+	toTypes := schemaTypes(to)
+	for _, ft := range schemaTypes(from) {
+		if ft == "null" {
+			continue
+		}
+		if len(toTypes) > 0 && !slices.Contains(toTypes, ft) && (ft != "integer" || !slices.Contains(toTypes, "number")) {
+			return false
+		}
+		switch ft {
+		case "array":
+			if !schemaAssignable(from.Items, to.Items) {
+				return false
+			}
+		case "object":
+			for name, fp := range from.Properties {
+				if !schemaAssignable(fp, to.Properties[name]) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func schemaTypes(s *jsonschema.Schema) []string {
+	if s.Type != "" {
+		return []string{s.Type}
+	}
+	return s.Types
+}
+
 func extractFieldNames(schema *jsonschema.Resolved) []string {
 	var fields []string
 	if schema != nil && schema.Schema() != nil && schema.Schema().Properties != nil {
@@ -497,6 +616,14 @@ func validateStaticSchemas(g *graph) error {
 				edge.From.Name(), edge.To.Name(), err)
 		}
 		if !eq {
+			if _, ok := edge.To.(*WorkflowNode); ok {
+				return fmt.Errorf("%w: output schema of %s does not match the input schema of sub-workflow %s",
+					ErrContractMismatch, edge.From.Name(), edge.To.Name())
+			}
+			if _, ok := edge.From.(*WorkflowNode); ok {
+				return fmt.Errorf("%w: output schema of sub-workflow %s does not match the input schema of %s",
+					ErrContractMismatch, edge.From.Name(), edge.To.Name())
+			}
 			return fmt.Errorf("graph validation failed: schema mismatch on edge %s -> %s",
 				edge.From.Name(), edge.To.Name())
 		}
@@ -504,6 +631,71 @@ func validateStaticSchemas(g *graph) error {
 	return nil
 }
 
+// validateContract checks the workflow's declared input and output
+// schemas against the nodes following Start and the terminal nodes,
+// and returns the schemas of its contract: the declared ones, or else
+// the ones shared by all those nodes.
+func validateContract(g *graph, in, out *jsonschema.Resolved) (*jsonschema.Resolved, *jsonschema.Resolved, error) {
+	var entries, exits []Node
+	for _, e := range g.successorsOf(Start) {
+		entries = append(entries, e.To)
+	}
+	terminals := g.terminalNodeNames()
+	for _, n := range g.allNodes() {
+		if terminals[n.Name()] {
+			exits = append(exits, n)
+		}
+	}
+	in, err := contractSchema("input", in, entries, Node.InputSchema)
+	if err != nil {
+		return nil, nil, err
+	}
+	out, err = contractSchema("output", out, exits, Node.OutputSchema)
+	if err != nil {
+		return nil, nil, err
+	}
+	return in, out, nil
+}
+
+// contractSchema checks that the nodes declaring a schema via schemaOf
+// declare declared, and returns declared. With no declared schema, it
+// returns the schema declared by all the nodes, or nil if one of them
+// declares none or they differ. kind names the schema in errors.
+func contractSchema(kind string, declared *jsonschema.Resolved, nodes []Node, schemaOf func(Node) *jsonschema.Resolved) (*jsonschema.Resolved, error) {
+	if declared != nil {
+		for _, n := range nodes {
+			s := schemaOf(n)
+			if s == nil {
+				continue
+			}
+			eq, err := schemasEqualCanonical(declared.Schema(), s.Schema())
+			if err != nil {
+				return nil, fmt.Errorf("comparing the %s schema of node %s: %w", kind, n.Name(), err)
+			}
+			if !eq {
+				return nil, fmt.Errorf("%w: %s schema of node %s does not match the workflow's %s schema",
+					ErrContractMismatch, kind, n.Name(), kind)
+			}
+		}
+		return declared, nil
+	}
+	var shared *jsonschema.Resolved
+	for _, n := range nodes {
+		s := schemaOf(n)
+		if s == nil {
+			return nil, nil
+		}
+		if shared == nil {
+			shared = s
+			continue
+		}
+		if eq, err := schemasEqualCanonical(shared.Schema(), s.Schema()); err != nil || !eq {
+			return nil, nil
+		}
+	}
+	return shared, nil
+}
+
 func schemasEqualCanonical(a, b *jsonschema.Schema) (bool, error) {
 	ac, err := utils.CanonicalSchemaJSON(a)
 	if err != nil {
@@ -851,3 +851,75 @@ func TestStaticSchemaValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkflowNodeContract(t *testing.T) {
+	type order struct {
+		ID string `json:"id"`
+	}
+	type receipt struct {
+		Total int `json:"total"`
+	}
+	orderSchema := resolveTestSchema[order](t)
+	receiptSchema := resolveTestSchema[receipt](t)
+	node := func(name string, in, out *jsonschema.Resolved) Node {
+		return &dummyNode{BaseNode: NewBaseNodeWithSchemas(name, "", NodeConfig{}, in, out)}
+	}
+
+	t.Run("derived from the sub-workflow's nodes", func(t *testing.T) {
+		sub, err := NewWorkflowNode("sub", Chain(Start, node("charge", orderSchema, receiptSchema)))
+		if err != nil {
+			t.Fatalf("NewWorkflowNode: %v", err)
+		}
+		if sub.InputSchema() != orderSchema || sub.OutputSchema() != receiptSchema {
+			t.Errorf("sub-workflow schemas = %v, %v, want the schemas of node charge", sub.InputSchema(), sub.OutputSchema())
+		}
+		if _, err := New("parent", Chain(Start, node("place", nil, orderSchema), sub, node("mail", receiptSchema, nil))); err != nil {
+			t.Errorf("New() with matching parent edges error = %v", err)
+		}
+		_, err = New("parent", Chain(Start, node("place", nil, receiptSchema), sub))
+		if !errors.Is(err, ErrContractMismatch) {
+			t.Errorf("New() with a mismatched edge into the sub-workflow error = %v, want %v", err, ErrContractMismatch)
+		}
+		_, err = New("parent", Chain(Start, sub, node("mail", orderSchema, nil)))
+		if !errors.Is(err, ErrContractMismatch) {
+			t.Errorf("New() with a mismatched edge out of the sub-workflow error = %v, want %v", err, ErrContractMismatch)
+		}
+	})
+
+	t.Run("not derived from differing nodes", func(t *testing.T) {
+		sub, err := NewWorkflowNode("sub", []Edge{
+			{From: Start, To: node("a", orderSchema, nil)},
+			{From: Start, To: node("b", receiptSchema, nil)},
+		})
+		if err != nil {
+			t.Fatalf("NewWorkflowNode: %v", err)
+		}
+		if sub.InputSchema() != nil {
+			t.Errorf("sub-workflow input schema = %v, want none", sub.InputSchema())
+		}
+	})
+
+	t.Run("declared", func(t *testing.T) {
+		sub, err := NewWorkflowNode("sub", Chain(Start, node("charge", nil, nil)), WithInputSchema(orderSchema), WithOutputSchema(receiptSchema))
+		if err != nil {
+			t.Fatalf("NewWorkflowNode: %v", err)
+		}
+		if sub.InputSchema() != orderSchema || sub.OutputSchema() != receiptSchema {
+			t.Errorf("sub-workflow schemas = %v, %v, want the declared ones", sub.InputSchema(), sub.OutputSchema())
+		}
+		if _, err := sub.ValidateInput(map[string]any{"id": 42}); err == nil {
+			t.Error("ValidateInput() of input breaking the contract succeeded, want error")
+		}
+	})
+
+	t.Run("declared schema mismatching the nodes", func(t *testing.T) {
+		_, err := New("sub", Chain(Start, node("charge", receiptSchema, nil)), WithInputSchema(orderSchema))
+		if !errors.Is(err, ErrContractMismatch) {
+			t.Errorf("New() error = %v, want %v", err, ErrContractMismatch)
+		}
+		_, err = New("sub", Chain(Start, node("charge", nil, orderSchema)), WithOutputSchema(receiptSchema))
+		if !errors.Is(err, ErrContractMismatch) {
+			t.Errorf("New() error = %v, want %v", err, ErrContractMismatch)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/adk/v2/session"
+)
+
+// ErrVersionMismatch is returned when a paused or interrupted run was
+// recorded by another version of the workflow than the one continuing
+// it, and the workflow has no MigrationFunc.
+var ErrVersionMismatch = errors.New("workflow: run recorded by another workflow version")
+
+// MigrationFunc adapts the state of a run recorded by version from of a
+// workflow to the current version of its graph. It is called by
+// ReconstructRunState and Recover, which refuse such runs with
+// ErrVersionMismatch when the workflow has none.
+//
+// For ReconstructRunState, state is rebuilt from the session against
+// the current graph, so nodes the graph no longer has are absent from
+// it; for Recover, it is the checkpointed state, which may name them.
+// A MigrationFunc returns the state to continue the run from, nil to
+// abandon the run, or an error to refuse it.
+type MigrationFunc func(from string, state *RunState) (*RunState, error)
+
+// WithVersion sets the version of the workflow's graph, e.g. a release
+// number or a hash of its definition. The version is recorded on the
+// NodeInfo of every event of the workflow's nodes and on durable
+// checkpoints, so that a run paused before a deploy which changed the
+// graph is not continued against the new graph unchecked. See
+// WithMigration.
+//
+// The empty version, the default, is a version like any other: runs
+// recorded before a version was set differ from the new version.
+func WithVersion(v string) Option {
+	return func(o *workflowOptions) {
+		o.version = v
+	}
+}
+
+// WithMigration sets the function adapting runs recorded by another
+// version of the workflow. Without one, such runs fail to resume with
+// ErrVersionMismatch.
+func WithMigration(fn MigrationFunc) Option {
+	return func(o *workflowOptions) {
+		o.migration = fn
+	}
+}
+
+// Version returns the workflow's version as set by WithVersion.
+func (w *Workflow) Version() string {
+	return w.version
+}
+
+// migrate adapts state, recorded by version from, to the current
+// version of the workflow.
+func (w *Workflow) migrate(from string, state *RunState) (*RunState, error) {
+	if state == nil || from == w.version {
+		return state, nil
+	}
+	if w.migration == nil {
+		return nil, fmt.Errorf("%w: workflow %q: run recorded by version %q, current version is %q", ErrVersionMismatch, w.name, from, w.version)
+	}
+	migrated, err := w.migration(from, state)
+	if err != nil {
+		return nil, fmt.Errorf("workflow %q: migrating run from version %q to %q: %w", w.name, from, w.version, err)
+	}
+	return migrated, nil
+}
+
+// recordedVersion returns the workflow version recorded on the events
+// of the run: the version on the last event emitted by one of the
+// workflow's own nodes. Events of nodes nested in them, e.g. of a
+// sub-workflow, carry the version of their own workflow and are
+// skipped. invocationID, when non-empty, restricts the scan to that
+// invocation's events.
+func recordedVersion(events session.Events, nodesByName map[string]Node, invocationID string) string {
+	version := ""
+	for i := 0; i < events.Len(); i++ {
+		ev := events.At(i)
+		if ev == nil || ev.NodeInfo == nil || ev.NodeInfo.Path == "" {
+			continue
+		}
+		if invocationID != "" && ev.InvocationID != invocationID {
+			continue
+		}
+		name := eventNodeName(ev, nodesByName)
+		if _, ok := nodesByName[name]; !ok || name != lastNodeName(ev.NodeInfo.Path) {
+			continue
+		}
+		version = ev.NodeInfo.WorkflowVersion
+	}
+	return version
+}
+
+// lastNodeName returns the node name of the last segment of a node
+// path, e.g. "b" for "wf@1/a@1/b@2".
+func lastNodeName(path string) string {
+	path = path[strings.LastIndexByte(path, '/')+1:]
+	if i := strings.IndexByte(path, '@'); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/adk/v2/session"
+)
+
+func TestWithVersion_StampsNodeEvents(t *testing.T) {
+	sub, err := NewWorkflowNode("sub", Chain(Start, newStubNode("inner", "inner")), WithVersion("s1"))
+	if err != nil {
+		t.Fatalf("NewWorkflowNode: %v", err)
+	}
+	w, err := New("wf", Chain(Start, newStubNode("a", "a"), sub), WithVersion("v1"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	got := map[string]string{}
+	for _, ev := range drain(t, w.Run(newSeededMockCtx(t))) {
+		if ev.NodeInfo != nil && ev.NodeInfo.Path != "" {
+			got[lastNodeName(ev.NodeInfo.Path)] = ev.NodeInfo.WorkflowVersion
+		}
+	}
+	want := map[string]string{"a": "v1", "inner": "s1", "sub": "v1"}
+	for node, version := range want {
+		if got[node] != version {
+			t.Errorf("events of node %s recorded version %q, want %q", node, got[node], version)
+		}
+	}
+}
+
+// pausedSession returns a session holding a run of invocation "inv"
+// paused by node ask, recorded by version.
+func pausedSession(version string) fakeSession {
+	return fakeSession{events: sliceEvents{{
+		InvocationID:       "inv",
+		LongRunningToolIDs: []string{"iid"},
+		NodeInfo:           &session.NodeInfo{Path: "wf@1/ask@1", WorkflowVersion: version},
+	}}}
+}
+
+func newVersionedWorkflow(t *testing.T, opts ...Option) *Workflow {
+	t.Helper()
+	w, err := New("wf", []Edge{{From: Start, To: newDummyNode("ask")}}, opts...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return w
+}
+
+func TestReconstructRunState_SameVersion(t *testing.T) {
+	w := newVersionedWorkflow(t, WithVersion("v1"))
+	state, err := w.ReconstructRunState(pausedSession("v1"), "inv")
+	if err != nil {
+		t.Fatalf("ReconstructRunState: %v", err)
+	}
+	if ns := nodeState(t, state, "ask"); ns.Status != NodeWaiting {
+		t.Errorf("ask = %+v, want waiting", ns)
+	}
+}
+
+func TestReconstructRunState_VersionMismatch(t *testing.T) {
+	for _, recorded := range []string{"v1", ""} {
+		w := newVersionedWorkflow(t, WithVersion("v2"))
+		_, err := w.ReconstructRunState(pausedSession(recorded), "inv")
+		if !errors.Is(err, ErrVersionMismatch) {
+			t.Errorf("ReconstructRunState() of a run recorded by %q error = %v, want %v", recorded, err, ErrVersionMismatch)
+		}
+	}
+}
+
+func TestReconstructRunState_Migration(t *testing.T) {
+	var from string
+	w := newVersionedWorkflow(t, WithVersion("v2"), WithMigration(func(f string, state *RunState) (*RunState, error) {
+		from = f
+		state.Nodes["ask"].Interrupts = []string{"migrated"}
+		return state, nil
+	}))
+
+	state, err := w.ReconstructRunState(pausedSession("v1"), "inv")
+	if err != nil {
+		t.Fatalf("ReconstructRunState: %v", err)
+	}
+	if from != "v1" {
+		t.Errorf("migration from = %q, want %q", from, "v1")
+	}
+	if ns := nodeState(t, state, "ask"); len(ns.Interrupts) != 1 || ns.Interrupts[0] != "migrated" {
+		t.Errorf("ask = %+v, want the migrated state", ns)
+	}
+
+	refuse := errors.New("unsupported")
+	w = newVersionedWorkflow(t, WithVersion("v2"), WithMigration(func(string, *RunState) (*RunState, error) {
+		return nil, refuse
+	}))
+	if _, err := w.ReconstructRunState(pausedSession("v1"), "inv"); !errors.Is(err, refuse) {
+		t.Errorf("ReconstructRunState() error = %v, want %v", err, refuse)
+	}
+}
+
+func TestRecover_VersionMismatch(t *testing.T) {
+	w, _ := newDurableTestWorkflow(t, defaultNodeConfig)
+	cp := interruptAfter(t, w, "a")
+	cp.Version = "v0"
+
+	var gotErr error
+	for _, err := range w.Recover(newMockCtx(t), cp) {
+		gotErr = err
+	}
+	if !errors.Is(gotErr, ErrVersionMismatch) {
+		t.Errorf("Recover() error = %v, want %v", gotErr, ErrVersionMismatch)
+	}
+}
@@ -15,9 +15,11 @@
 package workflow
 
 import (
+	"context"
 	"fmt"
 	"iter"
 	"strings"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 
@@ -63,6 +65,30 @@ type Route interface {
 	Matches(event *session.Event) bool
 }
 
+// RouteContext is what a ContextualRoute is evaluated against when the
+// scheduler routes a node's completion.
+type RouteContext struct {
+	// Output is the output of the completed node; for Start, the
+	// workflow's input.
+	Output any
+	// State is the session state, including the state deltas of the
+	// events emitted so far. Nil when the run has no session.
+	State session.ReadonlyState
+	// Event is the node's routing event, the event carrying its
+	// Routes. Nil when the node set no routes, e.g. for Start or a
+	// node resumed by a handoff.
+	Event *session.Event
+}
+
+// ContextualRoute is a Route the scheduler matches against the whole
+// RouteContext rather than the routing event alone. Unlike other
+// routes, it is evaluated even when the completed node emitted no
+// routing event.
+type ContextualRoute interface {
+	Route
+	MatchesContext(rc RouteContext) bool
+}
+
 func matchRoute(routeValue string, event *session.Event) bool {
 	for _, v := range event.Routes {
 		if v == routeValue {
@@ -105,6 +131,15 @@ func (r MultiRoute[T]) Matches(event *session.Event) bool {
 	return false
 }
 
+// values returns the route values as matched against Event.Routes.
+func (r MultiRoute[T]) values() []string {
+	values := make([]string, len(r))
+	for i, route := range r {
+		values[i] = fmt.Sprint(route)
+	}
+	return values
+}
+
 // Default is a special route that matches when no other concrete routes match.
 var Default Route = &defaultRoute{}
 
@@ -158,6 +193,29 @@ type Workflow struct {
 	// isRootWrapper marks this workflow as a synthetic single-node wrapper
 	// created by Runner.runNode to drive a standalone agent.
 	isRootWrapper bool
+
+	// durable enables checkpointing of runs to the session. Set via
+	// WithDurableExecution.
+	durable bool
+
+	// timeout bounds each run; 0 disables it. Set via WithTimeout.
+	timeout time.Duration
+
+	// branchTimeout bounds each forked branch; 0 disables it. Set via
+	// WithBranchTimeout.
+	branchTimeout time.Duration
+
+	// version is the version of the graph, recorded with runs; set via
+	// WithVersion. migration adapts runs recorded by other versions;
+	// set via WithMigration.
+	version   string
+	migration MigrationFunc
+
+	// inputSchema and outputSchema are the workflow's contract: the
+	// schemas of its input and of its output. Declared via
+	// WithInputSchema and WithOutputSchema, or derived from the graph.
+	inputSchema  *jsonschema.Resolved
+	outputSchema *jsonschema.Resolved
 }
 
 // Option configures a Workflow at construction time. Pass options
@@ -171,6 +229,13 @@ type workflowOptions struct {
 	maxConcurrency int
 	stateSchema    *jsonschema.Resolved
 	isRootWrapper  bool
+	durable        bool
+	timeout        time.Duration
+	branchTimeout  time.Duration
+	version        string
+	migration      MigrationFunc
+	inputSchema    *jsonschema.Resolved
+	outputSchema   *jsonschema.Resolved
 }
 
 // WithRootWrapper marks this workflow as a synthetic single-node wrapper
@@ -211,6 +276,32 @@ func WithStateSchema(s *jsonschema.Resolved) Option {
 	}
 }
 
+// WithInputSchema declares the schema of the workflow's input. New
+// checks that the nodes following Start which declare an input schema
+// declare this one. The schema is the input schema of the workflow's
+// WorkflowNode, which validates its input against it.
+//
+// Without it, the input schema is the one declared by every node
+// following Start, if they all declare the same.
+func WithInputSchema(s *jsonschema.Resolved) Option {
+	return func(o *workflowOptions) {
+		o.inputSchema = s
+	}
+}
+
+// WithOutputSchema declares the schema of the workflow's output. New
+// checks that the terminal nodes which declare an output schema declare
+// this one. The schema is the output schema of the workflow's
+// WorkflowNode, which validates its output against it.
+//
+// Without it, the output schema is the one declared by every terminal
+// node, if they all declare the same.
+func WithOutputSchema(s *jsonschema.Resolved) Option {
+	return func(o *workflowOptions) {
+		o.outputSchema = s
+	}
+}
+
 // New creates a new Workflow engine with the given name and edges.
 //
 // The name forms part of the session.State key under which this
@@ -245,17 +336,31 @@ func New(name string, edges []Edge, opts ...Option) (*Workflow, error) {
 	for _, opt := range opts {
 		opt(&o)
 	}
+	if o.durable && name == "" {
+		return nil, fmt.Errorf("durable workflow requires a name")
+	}
 	graph := newGraph(edges)
 	graph.isRootWrapper = o.isRootWrapper
 	if err := validateWorkflow(graph, o.stateSchema); err != nil {
 		return nil, err
 	}
+	inputSchema, outputSchema, err := validateContract(graph, o.inputSchema, o.outputSchema)
+	if err != nil {
+		return nil, err
+	}
 	return &Workflow{
 		graph:          graph,
 		name:           name,
 		maxConcurrency: o.maxConcurrency,
 		stateSchema:    o.stateSchema,
 		isRootWrapper:  o.isRootWrapper,
+		durable:        o.durable,
+		timeout:        o.timeout,
+		branchTimeout:  o.branchTimeout,
+		version:        o.version,
+		migration:      o.migration,
+		inputSchema:    inputSchema,
+		outputSchema:   outputSchema,
 	}, nil
 }
 
@@ -266,6 +371,18 @@ func (w *Workflow) Name() string {
 	return w.name
 }
 
+// InputSchema returns the schema of the workflow's input, or nil when
+// it has none. See WithInputSchema.
+func (w *Workflow) InputSchema() *jsonschema.Resolved {
+	return w.inputSchema
+}
+
+// OutputSchema returns the schema of the workflow's output, or nil
+// when it has none. See WithOutputSchema.
+func (w *Workflow) OutputSchema() *jsonschema.Resolved {
+	return w.outputSchema
+}
+
 // Run drives the workflow to completion or to a graceful pause
 // when any node enters NodeWaiting. It returns an iter.Seq2 that
 // yields events from per-node goroutines in arrival order; the
@@ -279,6 +396,13 @@ func (w *Workflow) Name() string {
 // when nodes complete. The consumer is the only mutator of the
 // per-node lifecycle map and of session state.
 func (w *Workflow) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+	c := w.runContext(ctx)
+	return w.RunNode(c, userInput(c))
+}
+
+// runContext promotes the invocation context of a top-level run to the
+// context the workflow's nodes run under.
+func (w *Workflow) runContext(ctx agent.InvocationContext) agent.Context {
 	var c agent.Context
 	if !w.isRootWrapper && w.Name() != "" {
 		wfPath := w.Name() + "@1"
@@ -301,14 +425,15 @@ func (w *Workflow) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, er
 	} else {
 		c = agent.Promote(ctx)
 	}
-	return w.RunNode(c, userInput(c))
+	return c
 }
 
 // RunNode drives the workflow with the given input.
 // This is used by WorkflowNode to run nested workflows.
 func (w *Workflow) RunNode(ctx agent.Context, input any) iter.Seq2[*session.Event, error] {
 	return func(yield func(*session.Event, error) bool) {
-		s := newScheduler(ctx, w.graph, w.maxConcurrency)
+		s, ctx, cancel := w.newScheduler(ctx)
+		defer cancel()
 		// Seed: schedule START with the supplied input.
 		startState := s.state.EnsureNode(Start.Name())
 		startState.Input = input
@@ -321,6 +446,28 @@ func (w *Workflow) RunNode(ctx agent.Context, input any) iter.Seq2[*session.Even
 	}
 }
 
+// newScheduler returns a scheduler for one run of w under ctx, and the
+// context the run is bounded by. The caller must call cancel once the
+// run has returned.
+func (w *Workflow) newScheduler(ctx agent.Context) (s *scheduler, runCtx agent.Context, cancel context.CancelFunc) {
+	cancel = func() {}
+	if w.timeout > 0 {
+		var c context.Context
+		c, cancel = context.WithTimeoutCause(ctx, w.timeout, ErrWorkflowTimeout)
+		ctx = ctx.WithAgentContext(c)
+	}
+	s = newScheduler(ctx, w.graph, w.maxConcurrency)
+	if !w.isRootWrapper {
+		s.name = w.name
+	}
+	if w.durable {
+		s.durableName = w.name
+	}
+	s.branchTimeout = w.branchTimeout
+	s.version = w.version
+	return s, ctx, cancel
+}
+
 // userInput extracts the workflow's seed input from the
 // InvocationContext's UserContent. Concatenates all text parts;
 // returns nil for an empty UserContent.
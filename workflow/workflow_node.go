@@ -31,13 +31,19 @@ type WorkflowNode struct {
 
 // NewWorkflowNode creates a new node that runs a nested workflow.
 // It uses the same arguments as New to construct the inner workflow.
-func NewWorkflowNode(name string, edges []Edge) (*WorkflowNode, error) {
-	wf, err := New(name, edges)
+//
+// The node's input and output schemas are the inner workflow's
+// contract (see WithInputSchema and WithOutputSchema): the node
+// validates its input and output against them, and New checks that
+// the edges of the parent workflow into and out of the node carry the
+// same schemas.
+func NewWorkflowNode(name string, edges []Edge, opts ...Option) (*WorkflowNode, error) {
+	wf, err := New(name, edges, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return &WorkflowNode{
-		BaseNode:    NewBaseNode(name, "", NodeConfig{}),
+		BaseNode:    NewBaseNodeWithSchemas(name, "", NodeConfig{}, wf.InputSchema(), wf.OutputSchema()),
 		subWorkflow: wf,
 	}, nil
 }
@@ -16,6 +16,7 @@ package workflow
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"iter"
 	"strings"
@@ -555,6 +556,88 @@ func TestWorkflow_StateSchemaConsistency(t *testing.T) {
 	}
 }
 
+func TestWorkflow_StateKeys(t *testing.T) {
+	schema, err := (&jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"count":  {Type: "number"},
+			"labels": {Type: "array", Items: &jsonschema.Schema{Type: "string"}},
+		},
+	}).Resolve(nil)
+	if err != nil {
+		t.Fatalf("failed to resolve schema: %v", err)
+	}
+	keyNode := func(name string, keys ...agent.StateKeyDescriptor) Node {
+		return &testNode{BaseNode: NewBaseNode(name, "", NodeConfig{StateKeys: keys})}
+	}
+
+	tests := []struct {
+		name    string
+		nodes   []Node
+		schema  *jsonschema.Resolved
+		wantErr bool
+	}{
+		{
+			name: "keys declared by the schema",
+			nodes: []Node{
+				keyNode("a", agent.NewStateKey[int]("count"), agent.NewStateKey[[]string]("labels")),
+				keyNode("b", agent.NewStateKey[int]("count")),
+			},
+			schema: schema,
+		},
+		{
+			name:   "prefixed keys need not be declared",
+			nodes:  []Node{keyNode("a", agent.NewStateKey[string]("user:name"), agent.NewStateKey[bool]("temp:seen"))},
+			schema: schema,
+		},
+		{
+			name:    "undeclared key",
+			nodes:   []Node{keyNode("a", agent.NewStateKey[int]("cnt"))},
+			schema:  schema,
+			wantErr: true,
+		},
+		{
+			name:    "type not accepted by the schema",
+			nodes:   []Node{keyNode("a", agent.NewStateKey[string]("count"))},
+			schema:  schema,
+			wantErr: true,
+		},
+		{
+			name:    "array items not accepted by the schema",
+			nodes:   []Node{keyNode("a", agent.NewStateKey[[]int]("labels"))},
+			schema:  schema,
+			wantErr: true,
+		},
+		{
+			name:   "without schema keys are not checked against it",
+			nodes:  []Node{keyNode("a", agent.NewStateKey[string]("anything"))},
+			schema: nil,
+		},
+		{
+			name: "nodes disagree on the type of a key",
+			nodes: []Node{
+				keyNode("a", agent.NewStateKey[string]("shared")),
+				keyNode("b", agent.NewStateKey[int]("shared")),
+			},
+			schema:  nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			edges, _ := fanOutToJoin(tc.nodes)
+			_, err := New("wf", edges, WithStateSchema(tc.schema))
+			if tc.wantErr && !errors.Is(err, ErrStateKeyMismatch) {
+				t.Errorf("New() error = %v, want %v", err, ErrStateKeyMismatch)
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("New() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
 type validParams struct {
 	Foo       string `state:"Foo"`
 	NodeInput string `state:"node_input"`